@@ -0,0 +1,561 @@
+// Package alert implements price and indicator alerts persisted at
+// ~/.sec/alerts.json, next to the watchlist.
+//
+// Subcommands:
+//
+//	sec alert                                 list alert rules
+//	sec alert add SH600036 --above 40         add rule(s) for a stock
+//	sec alert add 600036 --rsi-below 30 --macd-cross golden
+//	sec alert remove <id...>                  remove rules
+//	sec alert run --hook 'notify-send sec "$SEC_ALERT_MESSAGE"'
+package alert
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/cache"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewAlertCLI returns the alert command with subcommands.
+func NewAlertCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "alert",
+		Short:         "Manage price and indicator alerts",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		RunE: runList,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+
+	addCmd := &cobra.Command{
+		Use:   "add <code>",
+		Short: "Add alert rules for a security, one rule per condition flag",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAdd,
+	}
+	addCmd.Flags().Float64("above", 0, "Alert when price rises to or above")
+	addCmd.Flags().Float64("below", 0, "Alert when price falls to or below")
+	addCmd.Flags().Float64("change-pct", 0, "Alert when absolute change percent reaches")
+	addCmd.Flags().Float64("rsi-below", 0, "Alert when daily RSI falls to or below")
+	addCmd.Flags().Float64("rsi-above", 0, "Alert when daily RSI rises to or above")
+	addCmd.Flags().Int("rsi-period", 14, "RSI period")
+	addCmd.Flags().String("macd-cross", "", "Alert on daily MACD cross: golden or death")
+
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Watch quotes and deliver alerts",
+		Args:  cobra.NoArgs,
+		RunE:  runRun,
+	}
+	runCmd.Flags().Duration("interval", 30*time.Second, "Polling interval, or how often rules are reloaded when streaming")
+	runCmd.Flags().Bool("poll", false, "Poll quotes over HTTP instead of the realtime stream")
+	runCmd.Flags().Duration("cooldown", 30*time.Minute, "Minimum time between two alerts of the same rule")
+	runCmd.Flags().Bool("once", false, "Check once and exit")
+	runCmd.Flags().String("hook", "", "Shell command run on alert, with SEC_ALERT_* environment variables")
+	runCmd.Flags().String("webhook", "", "URL to POST alert events as JSON")
+	runCmd.Flags().Bool("no-cache", false, "Disable the local K-line cache for indicator rules")
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List alert rules",
+		Args:    cobra.NoArgs,
+		RunE:    runList,
+	}
+	output.Enable(cmd, listCmd)
+
+	cmd.AddCommand(
+		addCmd,
+		listCmd,
+		&cobra.Command{
+			Use:     "remove <id...>",
+			Aliases: []string{"rm"},
+			Short:   "Remove alert rules by id",
+			Args:    cobra.MinimumNArgs(1),
+			RunE:    runRemove,
+		},
+		runCmd,
+	)
+	return cmd
+}
+
+// rulesFromFlags builds rules from the condition flags of the add command.
+func rulesFromFlags(cmd *cobra.Command) ([]*Rule, error) {
+	var rules []*Rule
+	flags := cmd.Flags()
+	for _, kind := range []string{KindAbove, KindBelow, KindChangePct, KindRSIBelow, KindRSIAbove} {
+		if !flags.Changed(kind) {
+			continue
+		}
+		v, _ := flags.GetFloat64(kind)
+		if v <= 0 {
+			return nil, fmt.Errorf("--%s must be positive", kind)
+		}
+		r := &Rule{Kind: kind, Value: v}
+		if kind == KindRSIBelow || kind == KindRSIAbove {
+			if v >= 100 {
+				return nil, fmt.Errorf("--%s must be between 0 and 100", kind)
+			}
+			r.Period, _ = flags.GetInt("rsi-period")
+			if r.Period <= 1 {
+				return nil, fmt.Errorf("--rsi-period must be greater than 1")
+			}
+		}
+		rules = append(rules, r)
+	}
+	if cross, _ := flags.GetString(KindMACDCross); cross != "" {
+		cross = strings.ToLower(cross)
+		if cross != CrossGolden && cross != CrossDeath {
+			return nil, fmt.Errorf("--macd-cross must be %s or %s", CrossGolden, CrossDeath)
+		}
+		rules = append(rules, &Rule{Kind: KindMACDCross, Cross: cross})
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no condition given, use --above, --below, --change-pct, --rsi-below, --rsi-above or --macd-cross")
+	}
+	return rules, nil
+}
+
+func runAdd(cmd *cobra.Command, args []string) error {
+	newRules, err := rulesFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	secs := src.Searcher.Search(cmd.Context(), strings.TrimSpace(args[0]))
+	if len(secs) == 0 {
+		return fmt.Errorf("未找到证券: %s", args[0])
+	}
+	sec := secs[0]
+
+	rules, err := loadRules()
+	if err != nil {
+		return fmt.Errorf("读取提醒规则失败: %w", err)
+	}
+	id := nextID(rules)
+	out := cmd.OutOrStdout()
+	for _, r := range newRules {
+		r.ID, r.Code, r.ExCode, r.Name = id, sec.Code, sec.ExCode, sec.Name
+		r.CreatedAt = time.Now().Format("2006-01-02")
+		if _, ok := eastmoney.MarketOf(r.ExCode); r.Indicator() && !ok {
+			return fmt.Errorf("%s 不支持指标提醒，仅支持沪深证券", r.ExCode)
+		}
+		rules = append(rules, r)
+		id++
+		fmt.Fprintf(out, "  ✓ #%d %s %s %s\n", r.ID, r.ExCode, r.Name, r.String())
+	}
+	if err := saveRules(rules); err != nil {
+		return fmt.Errorf("保存失败: %w", err)
+	}
+	return nil
+}
+
+func nextID(rules []*Rule) int {
+	id := 0
+	for _, r := range rules {
+		id = max(id, r.ID)
+	}
+	return id + 1
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	rules, err := loadRules()
+	if err != nil {
+		return fmt.Errorf("读取提醒规则失败: %w", err)
+	}
+	out := cmd.OutOrStdout()
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(out, f, ruleRecords(rules))
+	}
+	if len(rules) == 0 {
+		fmt.Fprintf(out, "提醒列表为空。使用 sec alert add <代码> --above <价格> 添加提醒\n")
+		return nil
+	}
+	printRules(out, rules)
+	return nil
+}
+
+// ruleRecord 提醒规则的结构化输出
+type ruleRecord struct {
+	ID        int      `json:"id"`
+	Code      string   `json:"code"`
+	ExCode    string   `json:"excode"`
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Value     *float64 `json:"value"`  // 价格、涨跌幅 % 或 RSI 阈值
+	Cross     string   `json:"cross"`  // macd-cross 的方向
+	Period    *int     `json:"period"` // RSI 周期
+	Condition string   `json:"condition"`
+	CreatedAt string   `json:"created_at"`
+	LastFired string   `json:"last_fired"` // 2006-01-02 15:04:05，未触发时为空
+}
+
+func ruleRecords(rules []*Rule) []ruleRecord {
+	res := make([]ruleRecord, 0, len(rules))
+	for _, r := range rules {
+		rec := ruleRecord{
+			ID:        r.ID,
+			Code:      r.Code,
+			ExCode:    r.ExCode,
+			Name:      r.Name,
+			Kind:      r.Kind,
+			Value:     output.NonZero(r.Value),
+			Cross:     r.Cross,
+			Condition: r.String(),
+			CreatedAt: r.CreatedAt,
+		}
+		if r.Period > 0 {
+			period := r.Period
+			rec.Period = &period
+		}
+		if !r.LastFired.IsZero() {
+			rec.LastFired = r.LastFired.Format(time.DateTime)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+func printRules(out io.Writer, rules []*Rule) {
+	fmt.Fprintf(out, "\n提醒规则 (%d 条)\n\n", len(rules))
+
+	headers := []string{"ID", "代码", "名称", "条件", "创建日期", "上次触发"}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+
+	for _, r := range rules {
+		fired := "-"
+		if !r.LastFired.IsZero() {
+			fired = r.LastFired.Format("2006-01-02 15:04")
+		}
+		table.Append([]string{strconv.Itoa(r.ID), r.ExCode, r.Name, r.String(), r.CreatedAt, fired})
+	}
+	table.Render()
+	fmt.Fprintln(out)
+}
+
+func runRemove(cmd *cobra.Command, args []string) error {
+	ids := make(map[int]bool, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			return fmt.Errorf("invalid rule id %q", arg)
+		}
+		ids[id] = true
+	}
+
+	rules, err := loadRules()
+	if err != nil {
+		return fmt.Errorf("读取提醒规则失败: %w", err)
+	}
+	out := cmd.OutOrStdout()
+	kept := make([]*Rule, 0, len(rules))
+	for _, r := range rules {
+		if ids[r.ID] {
+			fmt.Fprintf(out, "  ✗ #%d %s %s %s\n", r.ID, r.ExCode, r.Name, r.String())
+			continue
+		}
+		kept = append(kept, r)
+	}
+
+	if removed := len(rules) - len(kept); removed > 0 {
+		if err := saveRules(kept); err != nil {
+			return fmt.Errorf("保存失败: %w", err)
+		}
+	}
+	fmt.Fprintf(out, "\n已移除 %d 条，剩余 %d 条\n", len(rules)-len(kept), len(kept))
+	return nil
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+		src.History = cache.NewHistory(src.History)
+	}
+
+	out := cmd.OutOrStdout()
+	notifiers := []Notifier{stdoutNotifier{out: out}}
+	if hook, _ := cmd.Flags().GetString("hook"); hook != "" {
+		notifiers = append(notifiers, hookNotifier{command: hook})
+	}
+	if url, _ := cmd.Flags().GetString("webhook"); url != "" {
+		notifiers = append(notifiers, webhookNotifier{url: url})
+	}
+	cooldown, _ := cmd.Flags().GetDuration("cooldown")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	once, _ := cmd.Flags().GetBool("once")
+	poll, _ := cmd.Flags().GetBool("poll")
+
+	c := newChecker(src.Quote, src.History, notifiers, cooldown)
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	if !once && !poll && src.Stream != nil {
+		fmt.Fprintf(out, "提醒已启动，使用实时行情推送，Ctrl+C 退出\n")
+		return c.runStream(ctx, src.Stream, interval)
+	}
+	if !once {
+		fmt.Fprintf(out, "提醒已启动，每 %s 检查一次，Ctrl+C 退出\n", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.checkOnce(ctx); err != nil {
+			if once {
+				return err
+			}
+			slog.WarnContext(ctx, "alert check failed", "error", err)
+		}
+		if once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runStream evaluates rules of a security whenever its quote is pushed. Rules
+// are reloaded every interval and the subscription follows them.
+func (c *checker) runStream(ctx context.Context, st provider.StreamSource, interval time.Duration) error {
+	rules, err := loadRules()
+	if err != nil {
+		return fmt.Errorf("读取提醒规则失败: %w", err)
+	}
+	stream := st.QuoteStream(ctx, ruleCodes(rules))
+	defer stream.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			latest, err := loadRules()
+			if err != nil {
+				slog.WarnContext(ctx, "failed to reload alert rules", "error", err)
+				continue
+			}
+			rules = latest
+			stream.Set(ruleCodes(rules)...)
+		case q, ok := <-stream.Quotes():
+			if !ok {
+				return nil
+			}
+			matched := make([]*Rule, 0, len(rules))
+			for _, r := range rules {
+				if r.ExCode == q.ExCode {
+					matched = append(matched, r)
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			if c.evaluate(ctx, matched, map[string]*sina.SecurityQuote{q.ExCode: q}) {
+				if err := saveState(rules); err != nil {
+					slog.WarnContext(ctx, "failed to save alert state", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// ruleCodes returns the distinct codes of rules in order.
+func ruleCodes(rules []*Rule) []string {
+	exCodes := make([]string, 0, len(rules))
+	seen := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		if !seen[r.ExCode] {
+			seen[r.ExCode] = true
+			exCodes = append(exCodes, r.ExCode)
+		}
+	}
+	return exCodes
+}
+
+// history 某只证券缓存的日 K 线，每天最多拉取一次
+type history struct {
+	day    string
+	quotes []*eastmoney.Quote
+}
+
+// checker 定期评估规则并投递提醒
+type checker struct {
+	quote     provider.QuoteSource
+	history   provider.HistorySource
+	notifiers []Notifier
+	cooldown  time.Duration
+	now       func() time.Time
+
+	hist map[string]*history // key: ExCode
+}
+
+func newChecker(quote provider.QuoteSource, hist provider.HistorySource, notifiers []Notifier, cooldown time.Duration) *checker {
+	return &checker{
+		quote:     quote,
+		history:   hist,
+		notifiers: notifiers,
+		cooldown:  cooldown,
+		now:       time.Now,
+		hist:      make(map[string]*history),
+	}
+}
+
+// checkOnce loads rules, evaluates them and persists the dedup state.
+func (c *checker) checkOnce(ctx context.Context) error {
+	rules, err := loadRules()
+	if err != nil {
+		return fmt.Errorf("读取提醒规则失败: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	changed, err := c.check(ctx, rules)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	return saveState(rules)
+}
+
+// check evaluates rules against the latest quotes, delivers alerts and
+// reports whether any rule state changed.
+func (c *checker) check(ctx context.Context, rules []*Rule) (bool, error) {
+	list, err := c.quote.QueryQuoteList(ctx, ruleCodes(rules))
+	if err != nil {
+		return false, fmt.Errorf("获取行情失败: %w", err)
+	}
+	quotes := make(map[string]*sina.SecurityQuote, len(list))
+	for _, q := range list {
+		quotes[q.ExCode] = q
+	}
+	return c.evaluate(ctx, rules, quotes), nil
+}
+
+// evaluate evaluates rules against quotes keyed by ExCode, delivers alerts and
+// reports whether any rule state changed.
+func (c *checker) evaluate(ctx context.Context, rules []*Rule, quotes map[string]*sina.SecurityQuote) bool {
+	now := c.now()
+	changed := false
+	for _, r := range rules {
+		snap := Snapshot{Quote: quotes[r.ExCode]}
+		if r.Indicator() && snap.Quote != nil {
+			hist, err := c.dailyHistory(ctx, r, now)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to get history", "code", r.ExCode, "error", err)
+				continue
+			}
+			snap.Quotes = withLive(hist, snap.Quote)
+		}
+
+		ok, value, err := r.Eval(snap)
+		if err != nil {
+			slog.DebugContext(ctx, "skip alert rule", "id", r.ID, "error", err)
+			continue
+		}
+		active, fired := r.Active, r.LastFired
+		if r.Update(ok, now, c.cooldown) {
+			c.notify(ctx, newEvent(r, snap.Quote.Current, value, now))
+		}
+		if r.Active != active || !r.LastFired.Equal(fired) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// dailyHistory returns daily quotes of the rule's security over the past
+// year, fetching them at most once a day.
+func (c *checker) dailyHistory(ctx context.Context, r *Rule, now time.Time) ([]*eastmoney.Quote, error) {
+	day := now.Format(eastmoney.TimeYYMMDD)
+	if h, ok := c.hist[r.ExCode]; ok && h.day == day {
+		return h.quotes, nil
+	}
+	market, ok := eastmoney.MarketOf(r.ExCode)
+	if !ok {
+		return nil, fmt.Errorf("不支持的交易所: %s", r.ExCode)
+	}
+	req := &eastmoney.GetQuoteHistoryReq{
+		Code:       r.Code,
+		MarketCode: int(market),
+		Begin:      now.AddDate(-1, 0, 0).Format(eastmoney.TimeYYMMDD),
+		End:        day,
+	}
+	quotes, err := c.history.GetQuoteHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.hist[r.ExCode] = &history{day: day, quotes: quotes}
+	return quotes, nil
+}
+
+func (c *checker) notify(ctx context.Context, e Event) {
+	for _, n := range c.notifiers {
+		if err := n.Notify(ctx, e); err != nil {
+			slog.WarnContext(ctx, "failed to deliver alert", "rule", e.RuleID, "error", err)
+		}
+	}
+}
+
+// saveState writes the dedup state of rules back to the alerts file. The file
+// is reloaded first so rules added or removed while running are kept.
+func saveState(rules []*Rule) error {
+	latest, err := loadRules()
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]*Rule, len(rules))
+	for _, r := range rules {
+		byID[r.ID] = r
+	}
+	for _, r := range latest {
+		if s, ok := byID[r.ID]; ok {
+			r.Active, r.LastFired = s.Active, s.LastFired
+		}
+	}
+	return saveRules(latest)
+}
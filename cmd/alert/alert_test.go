@@ -0,0 +1,269 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/cmd/strategy"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
+)
+
+func makeQuotes(prices []float64) []*eastmoney.Quote {
+	base := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	quotes := make([]*eastmoney.Quote, len(prices))
+	for i, p := range prices {
+		quotes[i] = &eastmoney.Quote{Date: base.AddDate(0, 0, i), Close: p}
+	}
+	return quotes
+}
+
+func TestRuleEvalPrice(t *testing.T) {
+	q := &sina.SecurityQuote{ExCode: "SH600036", Current: 41, YClose: 38}
+
+	ok, v, err := (&Rule{Kind: KindAbove, Value: 40}).Eval(Snapshot{Quote: q})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 41.0, v)
+
+	ok, _, err = (&Rule{Kind: KindBelow, Value: 40}).Eval(Snapshot{Quote: q})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, v, err = (&Rule{Kind: KindChangePct, Value: 5}).Eval(Snapshot{Quote: q})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.InDelta(t, 7.89, v, 0.01)
+
+	// 跌幅同样触发
+	down := &sina.SecurityQuote{Current: 35, YClose: 38}
+	ok, v, err = (&Rule{Kind: KindChangePct, Value: 5}).Eval(Snapshot{Quote: down})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Less(t, v, 0.0)
+
+	_, _, err = (&Rule{Kind: KindAbove, Value: 40}).Eval(Snapshot{})
+	require.Error(t, err)
+}
+
+func TestRuleEvalRSI(t *testing.T) {
+	prices := make([]float64, 30)
+	for i := range prices {
+		prices[i] = 20 - float64(i)*0.3
+		if i%5 == 0 {
+			prices[i] += 0.2
+		}
+	}
+	snap := Snapshot{Quote: &sina.SecurityQuote{Current: prices[29]}, Quotes: makeQuotes(prices)}
+
+	ok, rsi, err := (&Rule{Kind: KindRSIBelow, Value: 30, Period: 14}).Eval(snap)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Less(t, rsi, 30.0)
+
+	ok, _, err = (&Rule{Kind: KindRSIAbove, Value: 70, Period: 14}).Eval(snap)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// 持续下跌，RSI 为 0
+	falling := make([]float64, 20)
+	for i := range falling {
+		falling[i] = 20 - float64(i)
+	}
+	rsi, ok = lastRSI(makeQuotes(falling), 14)
+	require.True(t, ok)
+	require.Equal(t, 0.0, rsi)
+
+	_, _, err = (&Rule{Kind: KindRSIBelow, Value: 30, Period: 14}).Eval(Snapshot{Quote: snap.Quote, Quotes: makeQuotes(prices[:10])})
+	require.Error(t, err)
+}
+
+func TestMACDCrossed(t *testing.T) {
+	prices := make([]float64, 80)
+	for i := range prices {
+		prices[i] = 10 + 2*math.Sin(float64(i)/6)
+	}
+	quotes := makeQuotes(prices)
+	_, _, signals := strategy.ComputeMACD(quotes, 12, 26, 9)
+	require.NotEmpty(t, signals)
+
+	for _, s := range signals {
+		idx := -1
+		for i, q := range quotes {
+			if q.Date.Equal(s.Date) {
+				idx = i
+			}
+		}
+		require.GreaterOrEqual(t, idx, 0)
+
+		golden := s.Type == "buy"
+		require.Equal(t, golden, macdCrossed(quotes[:idx+1], CrossGolden), s.Date)
+		require.Equal(t, !golden, macdCrossed(quotes[:idx+1], CrossDeath), s.Date)
+		// 交叉之后的下一根 K 线不再触发
+		if idx+1 < len(quotes) {
+			require.False(t, macdCrossed(quotes[:idx+2], CrossGolden))
+			require.False(t, macdCrossed(quotes[:idx+2], CrossDeath))
+		}
+	}
+}
+
+func TestRuleUpdate(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	r := &Rule{Kind: KindAbove, Value: 40}
+
+	require.True(t, r.Update(true, now, time.Hour))
+	// 条件持续满足不重复提醒
+	require.False(t, r.Update(true, now.Add(time.Minute), time.Hour))
+	// 条件解除后再次满足，但仍在冷却期内
+	require.False(t, r.Update(false, now.Add(2*time.Minute), time.Hour))
+	require.False(t, r.Update(true, now.Add(3*time.Minute), time.Hour))
+	require.False(t, r.Active)
+	// 冷却期过后仍满足则提醒
+	require.True(t, r.Update(true, now.Add(61*time.Minute), time.Hour))
+	require.Equal(t, now.Add(61*time.Minute), r.LastFired)
+}
+
+func TestRuleRecords(t *testing.T) {
+	fired := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	recs := ruleRecords([]*Rule{
+		{ID: 1, ExCode: "SH600036", Kind: KindRSIBelow, Value: 30, Period: 14, LastFired: fired},
+		{ID: 2, ExCode: "SH600036", Kind: KindMACDCross, Cross: CrossGolden},
+	})
+	require.Len(t, recs, 2)
+	require.InDelta(t, 30, *recs[0].Value, 1e-9)
+	require.Equal(t, 14, *recs[0].Period)
+	require.Equal(t, "RSI(14) ≤ 30", recs[0].Condition)
+	require.Equal(t, "2026-03-02 10:00:00", recs[0].LastFired)
+	require.Nil(t, recs[1].Value)
+	require.Nil(t, recs[1].Period)
+	require.Empty(t, recs[1].LastFired)
+}
+
+func TestWithLive(t *testing.T) {
+	hist := makeQuotes([]float64{10, 11, 12})
+	q := &sina.SecurityQuote{TradeDate: "2026-01-07", Current: 13}
+	res := withLive(hist, q)
+	require.Len(t, res, 3)
+	require.Equal(t, 13.0, res[2].Close)
+	require.Equal(t, 12.0, hist[2].Close)
+
+	q.TradeDate = "2026-01-08"
+	res = withLive(hist, q)
+	require.Len(t, res, 4)
+	require.Equal(t, 13.0, res[3].Close)
+}
+
+type fakeQuote struct {
+	quotes map[string]*sina.SecurityQuote
+}
+
+func (f *fakeQuote) QueryQuoteList(ctx context.Context, exCodes []string) ([]*sina.SecurityQuote, error) {
+	var res []*sina.SecurityQuote
+	for _, c := range exCodes {
+		if q, ok := f.quotes[c]; ok {
+			res = append(res, q)
+		}
+	}
+	return res, nil
+}
+
+type fakeHistory struct {
+	quotes []*eastmoney.Quote
+	calls  int
+}
+
+func (f *fakeHistory) GetQuoteHistory(ctx context.Context, req *eastmoney.GetQuoteHistoryReq) ([]*eastmoney.Quote, error) {
+	f.calls++
+	return f.quotes, nil
+}
+
+type recorder struct {
+	events []Event
+}
+
+func (r *recorder) Notify(ctx context.Context, e Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func TestCheckerCheck(t *testing.T) {
+	prices := make([]float64, 30)
+	for i := range prices {
+		prices[i] = 20 - float64(i)*0.3
+	}
+	quote := &fakeQuote{quotes: map[string]*sina.SecurityQuote{
+		"SH600036": {ExCode: "SH600036", TradeDate: "2026-02-04", Current: 41, YClose: 40},
+	}}
+	hist := &fakeHistory{quotes: makeQuotes(prices)}
+	rec := &recorder{}
+	c := newChecker(quote, hist, []Notifier{rec}, time.Hour)
+	now := time.Date(2026, 2, 4, 10, 0, 0, 0, time.Local)
+	c.now = func() time.Time { return now }
+
+	rules := []*Rule{
+		{ID: 1, Code: "600036", ExCode: "SH600036", Kind: KindAbove, Value: 40},
+		{ID: 2, Code: "600036", ExCode: "SH600036", Kind: KindBelow, Value: 30},
+		{ID: 3, Code: "600036", ExCode: "SH600036", Kind: KindRSIAbove, Value: 50, Period: 14},
+		{ID: 4, Code: "000001", ExCode: "SZ000001", Kind: KindAbove, Value: 1},
+	}
+	changed, err := c.check(context.Background(), rules)
+	require.NoError(t, err)
+	require.True(t, changed)
+	// 当日价格 41 远高于历史，RSI 大幅回升
+	require.Len(t, rec.events, 2)
+	require.Equal(t, 1, rec.events[0].RuleID)
+	require.Equal(t, 3, rec.events[1].RuleID)
+	require.Contains(t, rec.events[0].Message, "SH600036")
+
+	// 同一天再次检查不重复提醒，也不重复拉取 K 线
+	now = now.Add(time.Minute)
+	changed, err = c.check(context.Background(), rules)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Len(t, rec.events, 2)
+	require.Equal(t, 1, hist.calls)
+}
+
+func TestHookNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hook.txt")
+	n := hookNotifier{command: `printf '%s|%s' "$SEC_ALERT_CODE" "$SEC_ALERT_MESSAGE" > ` + path}
+	e := newEvent(&Rule{ID: 1, ExCode: "SH600036", Name: "招商银行", Kind: KindAbove, Value: 40}, 41, 41, time.Now())
+	require.NoError(t, n.Notify(context.Background(), e))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), "SH600036|SH600036 招商银行 现价 41.00"))
+
+	require.Error(t, hookNotifier{command: "exit 3"}.Notify(context.Background(), e))
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	e := newEvent(&Rule{ID: 2, ExCode: "SH600036", Kind: KindRSIBelow, Value: 30, Period: 14}, 35, 28.5, time.Now())
+	require.NoError(t, webhookNotifier{url: srv.URL}.Notify(context.Background(), e))
+	require.Equal(t, 2, got.RuleID)
+	require.Equal(t, KindRSIBelow, got.Kind)
+	require.Equal(t, 28.5, got.Value)
+
+	fail := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer fail.Close()
+	require.Error(t, webhookNotifier{url: fail.URL}.Notify(context.Background(), e))
+}
@@ -0,0 +1,110 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/alwqx/sec/utils"
+)
+
+// Event 一次触发的提醒
+type Event struct {
+	RuleID  int       `json:"rule_id"`
+	Code    string    `json:"code"`
+	ExCode  string    `json:"excode"`
+	Name    string    `json:"name"`
+	Kind    string    `json:"kind"`
+	Rule    string    `json:"rule"`
+	Price   float64   `json:"price"`
+	Value   float64   `json:"value"` // 触发时的观测值：价格、涨跌幅或 RSI
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+func newEvent(r *Rule, price, value float64, now time.Time) Event {
+	e := Event{
+		RuleID: r.ID,
+		Code:   r.Code,
+		ExCode: r.ExCode,
+		Name:   r.Name,
+		Kind:   r.Kind,
+		Rule:   r.String(),
+		Price:  price,
+		Value:  value,
+		Time:   now,
+	}
+	switch r.Kind {
+	case KindChangePct:
+		e.Message = fmt.Sprintf("%s %s 涨跌幅 %+.2f%%，现价 %.2f（%s）", r.ExCode, r.Name, value, price, e.Rule)
+	case KindRSIBelow, KindRSIAbove:
+		e.Message = fmt.Sprintf("%s %s RSI(%d) %.1f，现价 %.2f（%s）", r.ExCode, r.Name, r.Period, value, price, e.Rule)
+	default:
+		e.Message = fmt.Sprintf("%s %s 现价 %.2f（%s）", r.ExCode, r.Name, price, e.Rule)
+	}
+	return e
+}
+
+// Notifier 提醒的投递方式
+type Notifier interface {
+	Notify(ctx context.Context, e Event) error
+}
+
+// stdoutNotifier 输出到终端
+type stdoutNotifier struct {
+	out io.Writer
+}
+
+func (n stdoutNotifier) Notify(ctx context.Context, e Event) error {
+	_, err := fmt.Fprintf(n.out, "[%s] 🔔 %s\n", utils.StandardTimeString(e.Time), e.Message)
+	return err
+}
+
+// hookNotifier 执行 shell 命令，如 notify-send，提醒内容通过环境变量传入
+type hookNotifier struct {
+	command string
+}
+
+func (n hookNotifier) Notify(ctx context.Context, e Event) error {
+	c := exec.CommandContext(ctx, "sh", "-c", n.command)
+	c.Env = append(os.Environ(),
+		"SEC_ALERT_CODE="+e.ExCode,
+		"SEC_ALERT_NAME="+e.Name,
+		"SEC_ALERT_RULE="+e.Rule,
+		fmt.Sprintf("SEC_ALERT_PRICE=%.2f", e.Price),
+		fmt.Sprintf("SEC_ALERT_VALUE=%.2f", e.Value),
+		"SEC_ALERT_MESSAGE="+e.Message,
+	)
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("hook failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// webhookNotifier 以 JSON POST 到指定 URL
+type webhookNotifier struct {
+	url string
+}
+
+func (n webhookNotifier) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	headers := http.Header{"Content-Type": {"application/json"}}
+	resp, err := utils.MakeRequest(ctx, http.MethodPost, n.url, headers, bytes.NewReader(body), 10*time.Second)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s returned %s", n.url, resp.Status)
+	}
+	return nil
+}
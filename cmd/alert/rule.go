@@ -0,0 +1,234 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/alwqx/sec/cmd/strategy"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/utils"
+)
+
+// 规则类型
+const (
+	KindAbove     = "above"      // 价格高于
+	KindBelow     = "below"      // 价格低于
+	KindChangePct = "change-pct" // 涨跌幅绝对值超过
+	KindRSIBelow  = "rsi-below"  // RSI 低于
+	KindRSIAbove  = "rsi-above"  // RSI 高于
+	KindMACDCross = "macd-cross" // MACD 金叉/死叉
+)
+
+// MACD 交叉方向
+const (
+	CrossGolden = "golden"
+	CrossDeath  = "death"
+)
+
+// Rule 一条提醒规则
+type Rule struct {
+	ID        int     `json:"id"`
+	Code      string  `json:"code"`
+	ExCode    string  `json:"excode"`
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	Value     float64 `json:"value,omitempty"`
+	Cross     string  `json:"cross,omitempty"`  // macd-cross 的方向
+	Period    int     `json:"period,omitempty"` // RSI 周期
+	CreatedAt string  `json:"created_at"`
+
+	// 以下为运行状态，用于去重和冷却
+	Active    bool      `json:"active,omitempty"`    // 条件当前是否处于满足状态
+	LastFired time.Time `json:"last_fired,omitzero"` // 上次触发时间
+}
+
+// Indicator 判断是否需要历史 K 线
+func (r *Rule) Indicator() bool {
+	return r.Kind == KindRSIBelow || r.Kind == KindRSIAbove || r.Kind == KindMACDCross
+}
+
+// String returns a short description of the rule condition.
+func (r *Rule) String() string {
+	switch r.Kind {
+	case KindAbove:
+		return fmt.Sprintf("价格 ≥ %g", r.Value)
+	case KindBelow:
+		return fmt.Sprintf("价格 ≤ %g", r.Value)
+	case KindChangePct:
+		return fmt.Sprintf("涨跌幅 ≥ ±%g%%", r.Value)
+	case KindRSIBelow:
+		return fmt.Sprintf("RSI(%d) ≤ %g", r.Period, r.Value)
+	case KindRSIAbove:
+		return fmt.Sprintf("RSI(%d) ≥ %g", r.Period, r.Value)
+	case KindMACDCross:
+		if r.Cross == CrossDeath {
+			return "MACD 死叉"
+		}
+		return "MACD 金叉"
+	}
+	return r.Kind
+}
+
+// Snapshot 评估规则时的行情，Quotes 仅指标规则需要
+type Snapshot struct {
+	Quote  *sina.SecurityQuote
+	Quotes []*eastmoney.Quote // 含当日实时价的日 K 线
+}
+
+// Eval reports whether the rule condition holds for snap and the observed
+// value (price, change percent or RSI) for the notification.
+func (r *Rule) Eval(snap Snapshot) (bool, float64, error) {
+	q := snap.Quote
+	if q == nil || q.Current <= 0 {
+		return false, 0, fmt.Errorf("%s 无有效行情", r.ExCode)
+	}
+
+	switch r.Kind {
+	case KindAbove:
+		return q.Current >= r.Value, q.Current, nil
+	case KindBelow:
+		return q.Current <= r.Value, q.Current, nil
+	case KindChangePct:
+		if q.YClose <= 0 {
+			return false, 0, fmt.Errorf("%s 无昨收价", r.ExCode)
+		}
+		pct := (q.Current - q.YClose) / q.YClose * 100
+		return math.Abs(pct) >= r.Value, pct, nil
+	case KindRSIBelow, KindRSIAbove:
+		rsi, ok := lastRSI(snap.Quotes, r.Period)
+		if !ok {
+			return false, 0, fmt.Errorf("%s K 线数据不足以计算 RSI(%d)", r.ExCode, r.Period)
+		}
+		if r.Kind == KindRSIBelow {
+			return rsi <= r.Value, rsi, nil
+		}
+		return rsi >= r.Value, rsi, nil
+	case KindMACDCross:
+		return macdCrossed(snap.Quotes, r.Cross), q.Current, nil
+	}
+	return false, 0, fmt.Errorf("unknown rule kind %q", r.Kind)
+}
+
+// lastRSI returns the RSI of the last bar.
+func lastRSI(quotes []*eastmoney.Quote, period int) (float64, bool) {
+	_, data, _ := strategy.ComputeRSI(quotes, period, 70, 30)
+	if len(data) == 0 {
+		return 0, false
+	}
+	// 最后一根 K 线的 RSI 总是有值，"-" 表示周期内没有上涨，RSI 为 0
+	s := data[len(data)-1][2]
+	if s == "-" {
+		return 0, true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// macdCrossed reports whether MACD(12,26,9) crosses in direction cross on the
+// last bar.
+func macdCrossed(quotes []*eastmoney.Quote, cross string) bool {
+	if len(quotes) == 0 {
+		return false
+	}
+	_, _, signals := strategy.ComputeMACD(quotes, 12, 26, 9)
+	if len(signals) == 0 {
+		return false
+	}
+	last := signals[len(signals)-1]
+	if !last.Date.Equal(quotes[len(quotes)-1].Date) {
+		return false
+	}
+	if cross == CrossDeath {
+		return last.Type == "sell"
+	}
+	return last.Type == "buy"
+}
+
+// Update records the evaluation result and reports whether the alert should
+// fire. An alert fires only when the condition turns true, so a price that
+// stays above the line is reported once, and not again within cooldown of
+// the last notification. A condition suppressed by the cooldown is retried
+// on later evaluations while it still holds.
+func (r *Rule) Update(ok bool, now time.Time, cooldown time.Duration) bool {
+	if !ok {
+		r.Active = false
+		return false
+	}
+	if r.Active {
+		return false
+	}
+	if !r.LastFired.IsZero() && now.Sub(r.LastFired) < cooldown {
+		return false
+	}
+	r.Active = true
+	r.LastFired = now
+	return true
+}
+
+// withLive returns daily quotes with the realtime quote q as the last bar,
+// replacing the bar of the same day if history already has it.
+func withLive(hist []*eastmoney.Quote, q *sina.SecurityQuote) []*eastmoney.Quote {
+	day, err := time.ParseInLocation(utils.LayoutYYMMDD, q.TradeDate, time.Local)
+	if err != nil || q.Current <= 0 {
+		return hist
+	}
+	live := &eastmoney.Quote{Date: day, Code: q.Code, Name: q.Name, Open: q.Open, Close: q.Current, High: q.High, Low: q.Low}
+
+	res := make([]*eastmoney.Quote, 0, len(hist)+1)
+	res = append(res, hist...)
+	if n := len(res); n > 0 && utils.TimeYYMMDDString(res[n-1].Date) == q.TradeDate {
+		res[n-1] = live
+	} else {
+		res = append(res, live)
+	}
+	return res
+}
+
+// alertsPath returns the path to the alerts file next to the watchlist.
+func alertsPath() (string, error) {
+	dir, err := utils.SecDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "alerts.json"), nil
+}
+
+func loadRules() ([]*Rule, error) {
+	path, err := alertsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var rules []*Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func saveRules(rules []*Rule) error {
+	path, err := alertsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/cninfo"
 	"github.com/alwqx/sec/provider/sina"
 	"github.com/olekukonko/tablewriter"
@@ -26,6 +27,7 @@ func NewAnnouncementsCLI() *cobra.Command {
 	cmd.Flags().StringP("type", "t", "", "Filter by type: annual, halfyear, q1, q3")
 	cmd.Flags().Bool("latest", false, "Show latest market-wide announcements (no stock filter)")
 	cmd.Flags().IntP("page", "p", 1, "Page number")
+	output.Enable(cmd)
 	return cmd
 }
 
@@ -91,6 +93,13 @@ func runAnnouncements(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("查询公告失败: %w", err)
 	}
 
+	if f := output.FormatOf(cmd); f.Structured() {
+		var anns []*cninfo.Announcement
+		if resp != nil {
+			anns = resp.Data
+		}
+		return output.Write(cmd.OutOrStdout(), f, announcementRecords(anns))
+	}
 	if resp == nil || len(resp.Data) == 0 {
 		fmt.Fprintf(cmd.OutOrStdout(), "未找到公告\n")
 		return nil
@@ -157,6 +166,38 @@ func runAnnouncements(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// announcementRecord 公告的结构化输出
+type announcementRecord struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	Type  string `json:"type"`
+	Size  int64  `json:"size"` // PDF 大小，单位：字节
+	URL   string `json:"url"`  // PDF 下载链接
+}
+
+// announcementRecords skips the announcements hidden from the table, which
+// were withdrawn or invalidated.
+func announcementRecords(anns []*cninfo.Announcement) []announcementRecord {
+	res := make([]announcementRecord, 0, len(anns))
+	for _, a := range anns {
+		if a.ExistFlag != 0 || a.InvalidationFlag != 0 {
+			continue
+		}
+		res = append(res, announcementRecord{
+			Date:  time.Unix(a.Time/1000, 0).Format("2006-01-02"),
+			Code:  a.SecCode,
+			Name:  a.SecName,
+			Title: a.Title,
+			Type:  a.TypeName,
+			Size:  a.AdjunctSize,
+			URL:   a.Link(),
+		})
+	}
+	return res
+}
+
 func formatSize(bytes float64) string {
 	if bytes <= 0 {
 		return "-"
@@ -0,0 +1,333 @@
+// Package backtest implements the sec backtest command, which replays
+// quotes and executes the signals of a strategy from cmd/strategy.
+//
+// Subcommands:
+//
+//	sec backtest ma <code>     dual moving average crossover
+//	sec backtest macd <code>   MACD golden/death cross
+//	sec backtest rsi <code>    RSI overbought/oversold
+//	sec backtest boll <code>   Bollinger Bands
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alwqx/sec/cmd/strategy"
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// defaultDays 未指定 --begin 时的回测区间
+const defaultDays = 3 * 365
+
+// NewBacktestCLI returns the backtest command with one subcommand per strategy.
+func NewBacktestCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "backtest",
+		Aliases:       []string{"bt"},
+		Short:         "Backtest technical analysis strategies",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Print(cmd.UsageString())
+		},
+	}
+
+	def := DefaultConfig()
+	cmd.PersistentFlags().StringP("begin", "b", "", "Begin date 20230101")
+	cmd.PersistentFlags().StringP("end", "e", "", "End date 20251231")
+	cmd.PersistentFlags().Float64("capital", def.Capital, "Initial capital")
+	cmd.PersistentFlags().Float64("commission", def.Commission, "Commission rate of both buy and sell")
+	cmd.PersistentFlags().Float64("min-commission", def.MinCommission, "Minimum commission per trade")
+	cmd.PersistentFlags().Float64("stamp-duty", def.StampDuty, "Stamp duty rate of sell")
+	cmd.PersistentFlags().Float64("slippage", def.Slippage, "Slippage ratio of fill price")
+	cmd.PersistentFlags().Int("lot", def.LotSize, "Shares per lot")
+	cmd.PersistentFlags().Bool("t1", def.TPlusOne, "Apply T+1, shares bought today can be sold from the next day")
+	cmd.PersistentFlags().Float64("rf", def.RiskFree, "Annual risk-free rate for Sharpe ratio")
+	cmd.PersistentFlags().Bool("no-cache", false, "Bypass local K-line history cache")
+	cmd.PersistentFlags().String("fq", "qfq", strategy.FQFlagUsage)
+	cmd.PersistentFlags().String("period", "day", "K-line period: "+strings.Join(eastmoney.PeriodNames(), ", "))
+
+	ma := newStrategyCLI("ma", "Backtest dual moving average crossover", func(cmd *cobra.Command, quotes []*eastmoney.Quote) (string, []strategy.Signal) {
+		fast, _ := cmd.Flags().GetInt("fast")
+		slow, _ := cmd.Flags().GetInt("slow")
+		_, _, signals := strategy.ComputeMA(quotes, fast, slow)
+		return fmt.Sprintf("双均线(%d,%d)", fast, slow), signals
+	})
+	ma.Flags().IntP("fast", "f", 5, "Fast MA period")
+	ma.Flags().IntP("slow", "s", 20, "Slow MA period")
+
+	macd := newStrategyCLI("macd", "Backtest MACD cross", func(cmd *cobra.Command, quotes []*eastmoney.Quote) (string, []strategy.Signal) {
+		fast, _ := cmd.Flags().GetInt("fast")
+		slow, _ := cmd.Flags().GetInt("slow")
+		signal, _ := cmd.Flags().GetInt("signal")
+		_, _, signals := strategy.ComputeMACD(quotes, fast, slow, signal)
+		return fmt.Sprintf("MACD(%d,%d,%d)", fast, slow, signal), signals
+	})
+	macd.Flags().IntP("fast", "f", 12, "Fast EMA period")
+	macd.Flags().IntP("slow", "s", 26, "Slow EMA period")
+	macd.Flags().IntP("signal", "g", 9, "Signal line period")
+
+	rsi := newStrategyCLI("rsi", "Backtest RSI overbought/oversold", func(cmd *cobra.Command, quotes []*eastmoney.Quote) (string, []strategy.Signal) {
+		period, _ := cmd.Flags().GetInt("window")
+		overbought, _ := cmd.Flags().GetFloat64("overbought")
+		oversold, _ := cmd.Flags().GetFloat64("oversold")
+		_, _, signals := strategy.ComputeRSI(quotes, period, overbought, oversold)
+		return fmt.Sprintf("RSI(%d)", period), signals
+	})
+	rsi.Flags().IntP("window", "p", 14, "RSI window in bars")
+	rsi.Flags().Float64("oversold", 30, "Oversold threshold")
+	rsi.Flags().Float64("overbought", 70, "Overbought threshold")
+
+	boll := newStrategyCLI("boll", "Backtest Bollinger Bands", func(cmd *cobra.Command, quotes []*eastmoney.Quote) (string, []strategy.Signal) {
+		period, _ := cmd.Flags().GetInt("window")
+		k, _ := cmd.Flags().GetFloat64("k")
+		_, _, signals := strategy.ComputeBollinger(quotes, period, k)
+		return fmt.Sprintf("布林带(%d,%.1f)", period, k), signals
+	})
+	boll.Flags().IntP("window", "p", 20, "MA window in bars")
+	boll.Flags().Float64P("k", "k", 2.0, "Standard deviation multiplier")
+
+	output.Enable(ma, macd, rsi, boll)
+	cmd.AddCommand(ma, macd, rsi, boll)
+	return cmd
+}
+
+// signalFunc 计算策略信号，返回策略描述和信号
+type signalFunc func(cmd *cobra.Command, quotes []*eastmoney.Quote) (string, []strategy.Signal)
+
+func newStrategyCLI(use, short string, fn signalFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:           use + " <code>",
+		Short:         short,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBacktest(cmd, args[0], fn)
+		},
+	}
+}
+
+func runBacktest(cmd *cobra.Command, code string, fn signalFunc) error {
+	cfg, err := configFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	beginStr, _ := cmd.Flags().GetString("begin")
+	endStr, _ := cmd.Flags().GetString("end")
+	begin, end, err := utils.ParseBeginEnd(beginStr, endStr, defaultDays, eastmoney.TimeYYMMDD, eastmoney.TimeYYMMDD)
+	if err != nil {
+		return err
+	}
+
+	exCode, name, quotes, err := strategy.FetchOHLCV(cmd, code, begin, end, cfg.Period)
+	if err != nil {
+		return err
+	}
+
+	desc, signals := fn(cmd, quotes)
+	res, err := Run(quotes, signals, cfg)
+	if err != nil {
+		return fmt.Errorf("回测失败: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(out, f, newResultRecord(exCode, name, desc, res, cfg))
+	}
+	fmt.Fprintf(out, "\n证券代码: %s  证券名称: %s  策略: %s\n", exCode, name, desc)
+	printSummary(out, res, cfg)
+	printTrades(out, res.Trades)
+	return nil
+}
+
+func configFromFlags(cmd *cobra.Command) (Config, error) {
+	cfg := DefaultConfig()
+	cfg.Capital, _ = cmd.Flags().GetFloat64("capital")
+	cfg.Commission, _ = cmd.Flags().GetFloat64("commission")
+	cfg.MinCommission, _ = cmd.Flags().GetFloat64("min-commission")
+	cfg.StampDuty, _ = cmd.Flags().GetFloat64("stamp-duty")
+	cfg.Slippage, _ = cmd.Flags().GetFloat64("slippage")
+	cfg.LotSize, _ = cmd.Flags().GetInt("lot")
+	cfg.TPlusOne, _ = cmd.Flags().GetBool("t1")
+	cfg.RiskFree, _ = cmd.Flags().GetFloat64("rf")
+	periodStr, _ := cmd.Flags().GetString("period")
+	period, err := eastmoney.ParsePeriod(periodStr)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Period = period
+	return cfg, nil
+}
+
+func printSummary(out io.Writer, res *Result, cfg Config) {
+	fmt.Fprintf(out, "回测区间: %s ~ %s  初始资金: %.2f  期末资产: %.2f\n\n",
+		utils.TimeYYMMDDString(res.Begin), utils.TimeYYMMDDString(res.End), res.Capital, res.FinalEquity)
+
+	data := [][]string{
+		{"总收益率", pct(res.TotalReturn)},
+		{"年化收益率", pct(res.AnnualReturn)},
+		{"基准收益率(买入持有)", pct(res.Benchmark)},
+		{"最大回撤", pct(-res.MaxDrawdown)},
+		{"夏普比率", fmt.Sprintf("%.2f", res.Sharpe)},
+		{"胜率", fmt.Sprintf("%.2f%% (%d 次平仓)", res.WinRate*100, res.RoundTrips)},
+		{"交易次数", fmt.Sprintf("%d", len(res.Trades))},
+		{"总费用", fmt.Sprintf("%.2f", res.Fees)},
+		{"期末持仓", fmt.Sprintf("%d 股", res.Position)},
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+	for _, row := range data {
+		colors := make([]tablewriter.Colors, len(row))
+		colors[0] = tablewriter.Colors{tablewriter.Bold}
+		table.Rich(row, colors)
+	}
+	table.Render()
+	fmt.Fprintf(out, "\n费率: 佣金 %.4f%%(最低 %.0f)  印花税 %.4f%%  滑点 %.2f%%  T+1: %v\n",
+		cfg.Commission*100, cfg.MinCommission, cfg.StampDuty*100, cfg.Slippage*100, cfg.TPlusOne)
+}
+
+func printTrades(out io.Writer, trades []Trade) {
+	if len(trades) == 0 {
+		fmt.Fprintf(out, "\n回测区间内无成交\n\n")
+		return
+	}
+	fmt.Fprintf(out, "\n交易记录\n\n")
+
+	headers := []string{"日期", "方向", "成交价", "股数", "成交额", "费用", "盈亏", "信号"}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+
+	for _, t := range trades {
+		side, profit := "买入", "-"
+		colors := make([]tablewriter.Colors, len(headers))
+		colors[1] = tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold}
+		if t.Side == "sell" {
+			side = "卖出"
+			profit = fmt.Sprintf("%+.2f", t.Profit)
+			colors[1] = tablewriter.Colors{tablewriter.FgGreenColor, tablewriter.Bold}
+			if t.Profit > 0 {
+				colors[6] = tablewriter.Colors{tablewriter.FgRedColor}
+			} else if t.Profit < 0 {
+				colors[6] = tablewriter.Colors{tablewriter.FgGreenColor}
+			}
+		}
+		table.Rich([]string{
+			utils.TimeYYMMDDString(t.Date),
+			side,
+			fmt.Sprintf("%.3f", t.Price),
+			fmt.Sprintf("%d", t.Shares),
+			fmt.Sprintf("%.2f", t.Amount),
+			fmt.Sprintf("%.2f", t.Fee),
+			profit,
+			t.Reason,
+		}, colors)
+	}
+	table.Render()
+	fmt.Fprintln(out)
+}
+
+// resultRecord 回测结果的结构化输出，收益率、回撤和胜率为百分数
+type resultRecord struct {
+	ExCode          string        `json:"excode"`
+	Name            string        `json:"name"`
+	Strategy        string        `json:"strategy"`
+	Period          string        `json:"period"`
+	Begin           string        `json:"begin"`
+	End             string        `json:"end"`
+	Capital         float64       `json:"capital"`
+	FinalEquity     float64       `json:"final_equity"`
+	TotalReturn     float64       `json:"total_return"`
+	AnnualReturn    *float64      `json:"annual_return"`
+	BenchmarkReturn float64       `json:"benchmark_return"` // 同期买入持有
+	MaxDrawdown     float64       `json:"max_drawdown"`
+	Sharpe          *float64      `json:"sharpe"`
+	WinRate         *float64      `json:"win_rate"` // 没有平仓时为空
+	RoundTrips      int           `json:"round_trips"`
+	Fees            float64       `json:"fees"`
+	Position        int           `json:"position"` // 期末持仓股数
+	Trades          []tradeRecord `json:"trades"`
+}
+
+// tradeRecord 一笔成交，盈亏仅卖出时有
+type tradeRecord struct {
+	Date   string   `json:"date"`
+	Side   string   `json:"side"` // buy 或 sell
+	Price  float64  `json:"price"`
+	Shares int      `json:"shares"`
+	Amount float64  `json:"amount"`
+	Fee    float64  `json:"fee"`
+	Profit *float64 `json:"profit"`
+	Reason string   `json:"reason"`
+}
+
+func newResultRecord(exCode, name, desc string, res *Result, cfg Config) *resultRecord {
+	rec := &resultRecord{
+		ExCode:          exCode,
+		Name:            name,
+		Strategy:        desc,
+		Period:          cfg.Period.String(),
+		Begin:           strategy.DateString(res.Begin),
+		End:             strategy.DateString(res.End),
+		Capital:         res.Capital,
+		FinalEquity:     res.FinalEquity,
+		TotalReturn:     res.TotalReturn * 100,
+		AnnualReturn:    output.Num(res.AnnualReturn * 100),
+		BenchmarkReturn: res.Benchmark * 100,
+		MaxDrawdown:     res.MaxDrawdown * 100,
+		Sharpe:          output.Num(res.Sharpe),
+		RoundTrips:      res.RoundTrips,
+		Fees:            res.Fees,
+		Position:        res.Position,
+		Trades:          make([]tradeRecord, 0, len(res.Trades)),
+	}
+	if res.RoundTrips > 0 {
+		rec.WinRate = output.Num(res.WinRate * 100)
+	}
+	for _, t := range res.Trades {
+		trade := tradeRecord{
+			Date:   strategy.DateString(t.Date),
+			Side:   t.Side,
+			Price:  t.Price,
+			Shares: t.Shares,
+			Amount: t.Amount,
+			Fee:    t.Fee,
+			Reason: t.Reason,
+		}
+		if t.Side == "sell" {
+			profit := t.Profit
+			trade.Profit = &profit
+		}
+		rec.Trades = append(rec.Trades, trade)
+	}
+	return rec
+}
+
+func pct(v float64) string {
+	return fmt.Sprintf("%+.2f%%", v*100)
+}
@@ -0,0 +1,289 @@
+package backtest
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/alwqx/sec/cmd/strategy"
+	"github.com/alwqx/sec/provider/eastmoney"
+)
+
+// tradingDaysPerYear 年化夏普比率使用的年交易日数
+const tradingDaysPerYear = 252
+
+// Config 回测参数
+type Config struct {
+	Capital       float64          // 初始资金
+	Commission    float64          // 佣金费率，买卖双向收取
+	MinCommission float64          // 单笔最低佣金
+	StampDuty     float64          // 印花税率，仅卖出收取
+	Slippage      float64          // 滑点比例，买入价上浮、卖出价下浮
+	LotSize       int              // 每手股数
+	TPlusOne      bool             // T+1：当日买入的股票次日才能卖出
+	RiskFree      float64          // 年化无风险利率，用于计算夏普比率
+	Period        eastmoney.Period // K 线周期，用于年化夏普比率，零值为日 K
+}
+
+// DefaultConfig returns the A-share defaults: 0.025% commission with a
+// minimum of 5 yuan, 0.05% stamp duty on sells, 0.1% slippage, 100-share lots
+// and T+1.
+func DefaultConfig() Config {
+	return Config{
+		Capital:       100000,
+		Commission:    0.00025,
+		MinCommission: 5,
+		StampDuty:     0.0005,
+		Slippage:      0.001,
+		LotSize:       100,
+		TPlusOne:      true,
+	}
+}
+
+// Trade 成交记录
+type Trade struct {
+	Date   time.Time
+	Side   string // "buy", "sell"
+	Price  float64
+	Shares int
+	Amount float64 // 成交金额
+	Fee    float64 // 佣金 + 印花税
+	Profit float64 // 卖出时本轮交易的盈亏（含双边费用）
+	Reason string
+}
+
+// Result 回测结果
+type Result struct {
+	Begin        time.Time
+	End          time.Time
+	Capital      float64
+	FinalEquity  float64
+	TotalReturn  float64 // 总收益率
+	AnnualReturn float64 // 年化收益率
+	Benchmark    float64 // 同期买入持有收益率
+	MaxDrawdown  float64 // 最大回撤，正数
+	Sharpe       float64 // 年化夏普比率
+	WinRate      float64 // 盈利交易占已平仓交易的比例
+	RoundTrips   int     // 已平仓交易次数
+	Fees         float64 // 总费用
+	Position     int     // 期末持仓股数
+	Trades       []Trade
+	Equity       []float64 // 每个交易日收盘后的总资产
+}
+
+// Run replays quotes in date order and executes signals with a full-position
+// long-only policy: a buy signal invests all available cash in whole lots and
+// a sell signal closes the whole position.
+//
+// A signal is generated on the close of its day, so it is filled at the open
+// of the next bar (or its close when open is missing) to avoid look-ahead.
+// Under T+1 a sell that would fill on the buy date waits for the first bar of
+// a later day, which only happens with intraday bars.
+func Run(quotes []*eastmoney.Quote, signals []strategy.Signal, cfg Config) (*Result, error) {
+	if len(quotes) < 2 {
+		return nil, errors.New("not enough quotes")
+	}
+	if cfg.Capital <= 0 {
+		return nil, errors.New("capital must be positive")
+	}
+	if cfg.LotSize <= 0 {
+		cfg.LotSize = 1
+	}
+
+	sigMap := make(map[int64]strategy.Signal, len(signals))
+	for _, s := range signals {
+		sigMap[s.Date.Unix()] = s
+	}
+
+	res := &Result{
+		Begin:   quotes[0].Date,
+		End:     quotes[len(quotes)-1].Date,
+		Capital: cfg.Capital,
+		Equity:  make([]float64, len(quotes)),
+	}
+
+	var (
+		cash     = cfg.Capital
+		shares   int
+		cost     float64 // 本轮持仓的买入成本（含费用）
+		buyDate  time.Time
+		pending  *strategy.Signal
+		wins     int
+		fillable = func(q *eastmoney.Quote) float64 {
+			if q.Open > 0 {
+				return q.Open
+			}
+			return q.Close
+		}
+	)
+
+	for i, q := range quotes {
+		if pending != nil {
+			switch pending.Type {
+			case "buy":
+				if shares == 0 {
+					price := fillable(q) * (1 + cfg.Slippage)
+					n, amount, fee := buyShares(cash, price, cfg)
+					if n > 0 {
+						cash -= amount + fee
+						shares = n
+						cost = amount + fee
+						buyDate = q.Date
+						res.Fees += fee
+						res.Trades = append(res.Trades, Trade{
+							Date: q.Date, Side: "buy", Price: price, Shares: n,
+							Amount: amount, Fee: fee, Reason: pending.Reason,
+						})
+					}
+				}
+				pending = nil
+			case "sell":
+				if shares == 0 {
+					pending = nil
+				} else if !cfg.TPlusOne || !sameDay(q.Date, buyDate) {
+					price := fillable(q) * (1 - cfg.Slippage)
+					amount := price * float64(shares)
+					fee := commission(amount, cfg) + amount*cfg.StampDuty
+					profit := amount - fee - cost
+					cash += amount - fee
+					res.Fees += fee
+					res.RoundTrips++
+					if profit > 0 {
+						wins++
+					}
+					res.Trades = append(res.Trades, Trade{
+						Date: q.Date, Side: "sell", Price: price, Shares: shares,
+						Amount: amount, Fee: fee, Profit: profit, Reason: pending.Reason,
+					})
+					shares, cost = 0, 0
+					pending = nil
+				}
+			}
+		}
+
+		if s, ok := sigMap[q.Date.Unix()]; ok {
+			if (s.Type == "buy" && shares == 0) || (s.Type == "sell" && shares > 0) {
+				pending = &s
+			}
+		}
+
+		res.Equity[i] = cash + float64(shares)*q.Close
+	}
+
+	first, last := quotes[0], quotes[len(quotes)-1]
+	res.FinalEquity = res.Equity[len(res.Equity)-1]
+	res.Position = shares
+	res.TotalReturn = res.FinalEquity/cfg.Capital - 1
+	if days := last.Date.Sub(first.Date).Hours() / 24; days > 0 && res.TotalReturn > -1 {
+		res.AnnualReturn = math.Pow(1+res.TotalReturn, 365/days) - 1
+	}
+	if first.Close > 0 {
+		res.Benchmark = last.Close/first.Close - 1
+	}
+	res.MaxDrawdown = maxDrawdown(res.Equity)
+	res.Sharpe = sharpe(res.Equity, cfg.RiskFree, barsPerYear(cfg.Period))
+	if res.RoundTrips > 0 {
+		res.WinRate = float64(wins) / float64(res.RoundTrips)
+	}
+	return res, nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// buyShares 计算可用资金能买入的整手股数，返回股数、成交金额和佣金
+func buyShares(cash, price float64, cfg Config) (int, float64, float64) {
+	if price <= 0 {
+		return 0, 0, 0
+	}
+	lot := float64(cfg.LotSize)
+	lots := int(cash / (price * lot * (1 + cfg.Commission)))
+	for ; lots > 0; lots-- {
+		amount := price * lot * float64(lots)
+		fee := commission(amount, cfg)
+		if amount+fee <= cash {
+			return lots * cfg.LotSize, amount, fee
+		}
+	}
+	return 0, 0, 0
+}
+
+func commission(amount float64, cfg Config) float64 {
+	return math.Max(amount*cfg.Commission, cfg.MinCommission)
+}
+
+// maxDrawdown 最大回撤
+func maxDrawdown(equity []float64) float64 {
+	var peak, mdd float64
+	for _, v := range equity {
+		if v > peak {
+			peak = v
+		}
+		if peak > 0 {
+			mdd = math.Max(mdd, (peak-v)/peak)
+		}
+	}
+	return mdd
+}
+
+// sharpe 基于日收益率的年化夏普比率
+// barsPerYear returns the number of bars of p in a year. A trading day has 4
+// hours of continuous trading, i.e. 240 one-minute bars.
+func barsPerYear(p eastmoney.Period) float64 {
+	switch p {
+	case eastmoney.Period1Min, eastmoney.PeriodTimeShare:
+		return tradingDaysPerYear * 240
+	case eastmoney.Period5Min:
+		return tradingDaysPerYear * 48
+	case eastmoney.Period15Min:
+		return tradingDaysPerYear * 16
+	case eastmoney.Period30Min:
+		return tradingDaysPerYear * 8
+	case eastmoney.Period60Min:
+		return tradingDaysPerYear * 4
+	case eastmoney.PeriodWeek:
+		return 52
+	case eastmoney.PeriodMonth:
+		return 12
+	case eastmoney.PeriodQuarter:
+		return 4
+	case eastmoney.PeriodYear:
+		return 1
+	}
+	return tradingDaysPerYear
+}
+
+func sharpe(equity []float64, riskFree, periods float64) float64 {
+	if len(equity) < 3 {
+		return 0
+	}
+	rf := riskFree / periods
+	rets := make([]float64, 0, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		if equity[i-1] > 0 {
+			rets = append(rets, equity[i]/equity[i-1]-1-rf)
+		}
+	}
+	if len(rets) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, r := range rets {
+		mean += r
+	}
+	mean /= float64(len(rets))
+
+	var variance float64
+	for _, r := range rets {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(len(rets)-1))
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(periods)
+}
@@ -0,0 +1,165 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/cmd/strategy"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+func makeQuotes(prices []float64) []*eastmoney.Quote {
+	quotes := make([]*eastmoney.Quote, len(prices))
+	for i, p := range prices {
+		quotes[i] = &eastmoney.Quote{Date: base.AddDate(0, 0, i), Open: p, Close: p}
+	}
+	return quotes
+}
+
+func signal(day int, typ string) strategy.Signal {
+	return strategy.Signal{Date: base.AddDate(0, 0, day), Type: typ}
+}
+
+func noCost() Config {
+	cfg := DefaultConfig()
+	cfg.Capital = 10000
+	cfg.Commission, cfg.MinCommission, cfg.StampDuty, cfg.Slippage = 0, 0, 0, 0
+	return cfg
+}
+
+func TestRunRoundTrip(t *testing.T) {
+	quotes := makeQuotes([]float64{10, 10, 11, 12, 12, 12})
+	signals := []strategy.Signal{signal(0, "buy"), signal(3, "sell")}
+
+	res, err := Run(quotes, signals, noCost())
+	require.NoError(t, err)
+	require.Len(t, res.Trades, 2)
+
+	// buy filled at the next open in whole lots
+	require.Equal(t, base.AddDate(0, 0, 1), res.Trades[0].Date)
+	require.Equal(t, 1000, res.Trades[0].Shares)
+	require.Equal(t, base.AddDate(0, 0, 4), res.Trades[1].Date)
+	require.InDelta(t, 2000, res.Trades[1].Profit, 1e-9)
+
+	require.InDelta(t, 12000, res.FinalEquity, 1e-9)
+	require.InDelta(t, 0.2, res.TotalReturn, 1e-9)
+	require.InDelta(t, 0.2, res.Benchmark, 1e-9)
+	require.Equal(t, 1, res.RoundTrips)
+	require.Equal(t, 1.0, res.WinRate)
+	require.Zero(t, res.Position)
+	require.Zero(t, res.MaxDrawdown)
+}
+
+func TestRunFees(t *testing.T) {
+	quotes := makeQuotes([]float64{10, 10, 10, 10})
+	signals := []strategy.Signal{signal(0, "buy"), signal(1, "sell")}
+
+	cfg := DefaultConfig()
+	cfg.Capital = 10000
+	cfg.Slippage = 0
+	res, err := Run(quotes, signals, cfg)
+	require.NoError(t, err)
+	require.Len(t, res.Trades, 2)
+
+	// 9900 yuan leaves room for the 5 yuan minimum commission
+	buy, sell := res.Trades[0], res.Trades[1]
+	require.Equal(t, 900, buy.Shares)
+	require.InDelta(t, 5, buy.Fee, 1e-9)
+	require.InDelta(t, 5+9000*0.0005, sell.Fee, 1e-9)
+	require.InDelta(t, -(10 + 4.5), sell.Profit, 1e-9)
+	require.Equal(t, 0.0, res.WinRate)
+	require.InDelta(t, 10000-14.5, res.FinalEquity, 1e-9)
+}
+
+func TestRunTPlusOne(t *testing.T) {
+	// hourly bars of two days, the sell signal fills on the buy date
+	quotes := makeQuotes([]float64{10, 10, 11, 12, 12})
+	for i, q := range quotes {
+		q.Date = base.Add(time.Duration(10+i) * time.Hour)
+	}
+	quotes[4].Date = base.AddDate(0, 0, 1).Add(10 * time.Hour)
+	signals := []strategy.Signal{
+		{Date: quotes[0].Date, Type: "buy"},
+		{Date: quotes[1].Date, Type: "sell"},
+	}
+
+	res, err := Run(quotes, signals, noCost())
+	require.NoError(t, err)
+	require.Len(t, res.Trades, 2)
+	require.Equal(t, quotes[4].Date, res.Trades[1].Date)
+
+	cfg := noCost()
+	cfg.TPlusOne = false
+	res, err = Run(quotes, signals, cfg)
+	require.NoError(t, err)
+	require.Len(t, res.Trades, 2)
+	require.Equal(t, quotes[2].Date, res.Trades[1].Date)
+}
+
+func TestRunOpenPosition(t *testing.T) {
+	quotes := makeQuotes([]float64{10, 10, 8, 9})
+	res, err := Run(quotes, []strategy.Signal{signal(0, "buy")}, noCost())
+	require.NoError(t, err)
+	require.Len(t, res.Trades, 1)
+	require.Equal(t, 1000, res.Position)
+	require.Zero(t, res.RoundTrips)
+	require.InDelta(t, 9000, res.FinalEquity, 1e-9)
+	require.InDelta(t, 0.2, res.MaxDrawdown, 1e-9)
+}
+
+func TestRunInvalid(t *testing.T) {
+	_, err := Run(makeQuotes([]float64{10}), nil, noCost())
+	require.Error(t, err)
+
+	cfg := noCost()
+	cfg.Capital = 0
+	_, err = Run(makeQuotes([]float64{10, 11}), nil, cfg)
+	require.Error(t, err)
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	require.Zero(t, maxDrawdown(nil))
+	require.InDelta(t, 0.5, maxDrawdown([]float64{100, 120, 60, 130, 90}), 1e-9)
+}
+
+func TestSharpe(t *testing.T) {
+	require.Zero(t, sharpe([]float64{100, 100, 100}, 0, tradingDaysPerYear))
+
+	equity := []float64{100, 101, 100.5, 102, 103}
+	got := sharpe(equity, 0, tradingDaysPerYear)
+	require.Greater(t, got, 0.0)
+	require.False(t, math.IsNaN(got))
+
+	// 按 K 线周期年化：周线的年化倍数是日线的 sqrt(52/252)
+	weekly := sharpe(equity, 0, barsPerYear(eastmoney.PeriodWeek))
+	require.InDelta(t, got*math.Sqrt(52.0/tradingDaysPerYear), weekly, 1e-9)
+	require.Equal(t, float64(tradingDaysPerYear), barsPerYear(eastmoney.PeriodDay))
+	require.Equal(t, float64(tradingDaysPerYear*48), barsPerYear(eastmoney.Period5Min))
+}
+
+func TestResultRecord(t *testing.T) {
+	quotes := makeQuotes([]float64{10, 10, 11, 12, 12, 12})
+	signals := []strategy.Signal{signal(0, "buy"), signal(3, "sell")}
+	cfg := noCost()
+	res, err := Run(quotes, signals, cfg)
+	require.NoError(t, err)
+
+	rec := newResultRecord("SH600000", "浦发银行", "MA(5,20)", res, cfg)
+	require.Equal(t, "2026-01-05", rec.Begin)
+	require.InDelta(t, 20, rec.TotalReturn, 1e-9)
+	require.NotNil(t, rec.WinRate)
+	require.InDelta(t, 100, *rec.WinRate, 1e-9)
+	require.Len(t, rec.Trades, 2)
+	require.Equal(t, "2026-01-06", rec.Trades[0].Date)
+	require.Nil(t, rec.Trades[0].Profit)
+	require.InDelta(t, 2000, *rec.Trades[1].Profit, 1e-9)
+
+	// 没有平仓时胜率为空
+	res, err = Run(quotes, signals[:1], cfg)
+	require.NoError(t, err)
+	require.Nil(t, newResultRecord("SH600000", "浦发银行", "MA(5,20)", res, cfg).WinRate)
+}
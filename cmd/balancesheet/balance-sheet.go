@@ -31,7 +31,7 @@ func NewBalanceSheetCLI() *cobra.Command {
 	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
 	cmd.Flags().StringP("type", "t", "", "Report type: balance, income, cashflow")
 	cmd.Flags().StringP("period", "p", "annual", "Period filter: annual, halfyear, q1, q3")
-	cmd.Flags().StringP("output", "o", "", "Export to file (.csv or .json)")
+	cmd.Flags().StringP("export", "o", "", "Export to file (.csv or .json)")
 
 	return cmd
 }
@@ -102,7 +102,7 @@ func BalanceSheetHandler(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check output flag
-	outputPath, _ := cmd.Flags().GetString("output")
+	outputPath, _ := cmd.Flags().GetString("export")
 	if outputPath != "" {
 		return exportToFile(results, outputPath)
 	}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"time"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/bond"
 	"github.com/alwqx/sec/utils"
 	"github.com/olekukonko/tablewriter"
@@ -16,100 +18,193 @@ func NewBondCLI() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:     "bond",
 		Aliases: []string{"b"},
-		Short:   "Print US Treasury yield curve (1M/3M/6M/5Y/10Y)",
+		Short:   "Print US Treasury or China government bond yield curve",
 		RunE:    BondHandler,
 	}
 	rootCmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	addCurveFlags(rootCmd)
+	output.Enable(rootCmd)
+	rootCmd.AddCommand(NewBondSpreadCLI(), NewBondCurveCLI())
 
 	return rootCmd
 }
 
-// BondHandler 打印美国国债最新收益率曲线
+// addCurveFlags adds the flags choosing the country and tenors of the curve.
+func addCurveFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("country", "c", string(bond.CountryUS), "Country of the yield curve: us or cn")
+	cmd.Flags().StringP("tenors", "t", "", "Comma-separated tenors to print, e.g. 3M,2Y,10Y, default all")
+}
+
+// parseCurveFlags parses the flags added by addCurveFlags.
+func parseCurveFlags(cmd *cobra.Command) (bond.Country, []bond.Tenor, error) {
+	countryStr, _ := cmd.Flags().GetString("country")
+	country, err := bond.ParseCountry(countryStr)
+	if err != nil {
+		return "", nil, err
+	}
+	tenorsStr, _ := cmd.Flags().GetString("tenors")
+	tenors, err := bond.ParseTenors(tenorsStr)
+	if err != nil {
+		return "", nil, err
+	}
+	return country, tenors, nil
+}
+
+// BondHandler 打印最新国债收益率曲线
 func BondHandler(cmd *cobra.Command, args []string) error {
+	country, tenors, err := parseCurveFlags(cmd)
+	if err != nil {
+		return err
+	}
 	end := time.Now()
-	req := &bond.QueryBondReq{
-		Start: end.Add(-10 * 24 * time.Hour).Format(utils.LayoutYYMMDD),
-		End:   end.Format(utils.LayoutYYMMDD),
+	req := &bond.QueryCurveReq{
+		Country: country,
+		Start:   end.Add(-10 * 24 * time.Hour).Format(utils.LayoutYYMMDD),
+		End:     end.Format(utils.LayoutYYMMDD),
 	}
-	resp, err := bond.QueryBond(cmd.Context(), req)
+	curves, err := bond.QueryCurves(cmd.Context(), req)
 	if err != nil {
 		return err
 	}
-	num := len(resp.Data)
-	if num == 0 {
+	if len(curves) == 0 {
 		slog.Warn("no data")
-	} else {
-		printBondYield(cmd.OutOrStdout(), resp.Data[num-1:])
+		return nil
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, yieldRecords(country, curves, tenors, len(curves)-1))
 	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s收益率曲线\n", country.Label())
+	printBondYield(cmd.OutOrStdout(), curves, tenors)
 
 	return nil
 }
 
-// printBondYield 打印美国国债收益率曲线
-func printBondYield(out io.Writer, items []*bond.BondYieldItem) {
-	num := len(items)
+// benchmarkTenor 前值、变动和着色使用的基准期限
+const benchmarkTenor = bond.Tenor10Y
+
+// printBondYield 打印最新一日收益率曲线，前值取倒数第二日
+func printBondYield(out io.Writer, curves []*bond.YieldCurve, tenors []bond.Tenor) {
+	num := len(curves)
 	if num == 0 {
 		return
 	}
+	if len(tenors) == 0 {
+		tenors = curveTenors(curves)
+	}
+	last := curves[num-1]
 
-	headers := []string{"日期", "1个月", "3个月", "6个月", "5年", "10年", "前值", "变动(bp)"}
-	columnsStyles := make([][]tablewriter.Colors, 0, len(headers))
+	headers := []string{"日期"}
+	for _, t := range tenors {
+		headers = append(headers, t.Label())
+	}
+	headers = append(headers, "前值", "变动(bp)")
 
-	data := make([][]string, 0, num)
-	for _, item := range items {
-		yCloseStr := "-"
-		changeBpStr := "-"
-		if item.YClose != -1 {
-			yCloseStr = fmt.Sprintf("%.2f%%", item.YClose)
-			changeBpStr = fmt.Sprintf("%+.1f", item.Change*100)
+	row, styles := curveRow(last, tenors)
+	yCloseStr, changeBpStr := "-", "-"
+	if num > 1 {
+		prev, ok1 := curves[num-2].At(benchmarkTenor)
+		cur, ok2 := last.At(benchmarkTenor)
+		if ok1 && ok2 {
+			yCloseStr = fmt.Sprintf("%.2f%%", prev)
+			changeBpStr = fmt.Sprintf("%+.1f", (cur-prev)*100)
+			colorBenchmark(styles, tenors, cur-prev)
 		}
+	}
+	row = append(row, yCloseStr, changeBpStr)
+	styles = append(styles, tablewriter.Colors{}, tablewriter.Colors{})
 
-		row := []string{
-			item.Date,
-			fmt.Sprintf("%.2f%%", item.BC1Month),
-			fmt.Sprintf("%.2f%%", item.BC3Month),
-			fmt.Sprintf("%.2f%%", item.BC6Month),
-			fmt.Sprintf("%.2f%%", item.BC5Year),
-			fmt.Sprintf("%.2f%%", item.BC10Year),
-			yCloseStr,
-			changeBpStr,
-		}
-		data = append(data, row)
-
-		styles := make([]tablewriter.Colors, 0, len(headers))
-		for _, title := range headers {
-			var itemStyle tablewriter.Colors = tablewriter.Colors{}
-			// 10年收益率列着色
-			if title == "10年" {
-				if item.ChangeRate > 0 {
-					itemStyle = tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle, tablewriter.FgRedColor}
-				} else if item.ChangeRate < 0 {
-					itemStyle = tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle, tablewriter.FgGreenColor}
-				}
-			}
-			styles = append(styles, itemStyle)
+	table := utils.NewTable(out, headers)
+	table.Rich(row, styles)
+	table.Render()
+}
+
+// curveTenors returns the union of the tenors of curves in ascending order.
+func curveTenors(curves []*bond.YieldCurve) []bond.Tenor {
+	union := &bond.YieldCurve{Yields: make(map[bond.Tenor]float64)}
+	for _, c := range curves {
+		for t := range c.Yields {
+			union.Yields[t] = 0
 		}
-		columnsStyles = append(columnsStyles, styles)
 	}
+	return union.Tenors()
+}
 
-	table := tablewriter.NewWriter(out)
-	table.SetHeader(headers)
-
-	for i, row := range data {
-		table.Rich(row, columnsStyles[i])
+// curveRow returns the date and yields of c for tenors, "-" for a missing
+// tenor, with empty styles.
+func curveRow(c *bond.YieldCurve, tenors []bond.Tenor) ([]string, []tablewriter.Colors) {
+	row := []string{c.Date.Format(utils.LayoutYYMMDD)}
+	styles := []tablewriter.Colors{{}}
+	for _, t := range tenors {
+		v, ok := c.Yields[t]
+		if ok {
+			row = append(row, fmt.Sprintf("%.2f%%", v))
+		} else {
+			row = append(row, "-")
+		}
+		styles = append(styles, tablewriter.Colors{})
 	}
+	return row, styles
+}
 
-	headerStyles := make([]tablewriter.Colors, 0, len(headers))
-	for range headers {
-		headerStyles = append(headerStyles, tablewriter.Colors{tablewriter.Bold})
+// colorBenchmark colors the benchmark tenor column red when the yield rises
+// and green when it falls.
+func colorBenchmark(styles []tablewriter.Colors, tenors []bond.Tenor, change float64) {
+	if math.Abs(change) < 1e-9 {
+		return
 	}
-	table.SetHeaderColor(headerStyles...)
+	for i, t := range tenors {
+		if t != benchmarkTenor {
+			continue
+		}
+		if change > 0 {
+			styles[i+1] = tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle, tablewriter.FgRedColor}
+		} else {
+			styles[i+1] = tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle, tablewriter.FgGreenColor}
+		}
+	}
+}
 
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetHeaderLine(false)
-	table.SetBorder(false)
-	table.SetNoWhiteSpace(false)
-	table.SetTablePadding("\t")
-	table.Render()
+// yieldRecord 收益率曲线的结构化输出，每个日期、期限一条
+type yieldRecord struct {
+	Date      string   `json:"date"`
+	Country   string   `json:"country"`
+	Tenor     string   `json:"tenor"`      // 期限，如 3M、10Y
+	Months    int      `json:"months"`     // 期限月数
+	Yield     float64  `json:"yield"`      // 收益率 %
+	PrevYield *float64 `json:"prev_yield"` // 前一日收益率 %，没有时为空
+	ChangeBP  *float64 `json:"change_bp"`  // 较前一日变动 bp
+}
+
+// yieldRecords returns the yields of curves[from:] for tenors, all tenors of
+// curves when empty, with the change from the previous curve. Missing yields
+// are skipped.
+func yieldRecords(country bond.Country, curves []*bond.YieldCurve, tenors []bond.Tenor, from int) []yieldRecord {
+	if len(tenors) == 0 {
+		tenors = curveTenors(curves)
+	}
+	res := make([]yieldRecord, 0, (len(curves)-from)*len(tenors))
+	for i := max(from, 0); i < len(curves); i++ {
+		c := curves[i]
+		for _, t := range tenors {
+			v, ok := c.Yields[t]
+			if !ok {
+				continue
+			}
+			rec := yieldRecord{
+				Date:    c.Date.Format(utils.LayoutYYMMDD),
+				Country: string(country),
+				Tenor:   t.String(),
+				Months:  int(t),
+				Yield:   v,
+			}
+			if i > 0 {
+				if prev, ok := curves[i-1].Yields[t]; ok {
+					change := math.Round((v-prev)*1000) / 10
+					rec.PrevYield, rec.ChangeBP = &prev, &change
+				}
+			}
+			res = append(res, rec)
+		}
+	}
+	return res
 }
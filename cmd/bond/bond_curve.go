@@ -0,0 +1,181 @@
+package bond
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/alwqx/sec/provider/bond"
+	"github.com/alwqx/sec/render"
+	"github.com/alwqx/sec/utils"
+	"github.com/spf13/cobra"
+)
+
+func NewBondCurveCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "curve",
+		Short: "Plot the yield curve against past dates with level, slope and curvature",
+		Long: `Plot the latest government bond yield curve with tenor on the x-axis,
+overlaid with the curves 1 week, 1 month and 1 year ago by default. Below the
+chart the level ((3M+2Y+10Y)/3), slope (10Y-3M) and curvature (2*2Y-3M-10Y)
+factors of each curve are printed, followed by the change of the factors over
+--window, flagged as bull/bear steepening or flattening when the slope moved
+more than 5bp.`,
+		Args: cobra.NoArgs,
+		RunE: BondCurveHandler,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	addCurveFlags(cmd)
+	cmd.Flags().String("compare", "1w,1m,1y", "Comma-separated lookbacks to overlay, e.g. 1w,1m,1y; d/w/m/y units")
+	cmd.Flags().StringP("window", "w", "1m", "Lookback window of the steepening/flattening check, e.g. 1m")
+	cmd.Flags().Int("height", 15, "Chart height in rows")
+
+	return cmd
+}
+
+// datedCurve 带标签的历史曲线，如 1周前
+type datedCurve struct {
+	Label string
+	Curve *bond.YieldCurve
+}
+
+// BondCurveHandler 绘制收益率曲线并打印形态因子
+func BondCurveHandler(cmd *cobra.Command, args []string) error {
+	country, tenors, err := parseCurveFlags(cmd)
+	if err != nil {
+		return err
+	}
+	compareStr, _ := cmd.Flags().GetString("compare")
+	compares, err := bond.ParseLookbacks(compareStr)
+	if err != nil {
+		return err
+	}
+	windowStr, _ := cmd.Flags().GetString("window")
+	window, err := bond.ParseLookback(windowStr)
+	if err != nil {
+		return err
+	}
+	height, _ := cmd.Flags().GetInt("height")
+	if height <= 0 {
+		return fmt.Errorf("invalid height %d: must be > 0", height)
+	}
+
+	// 多取 10 天，保证最早的对比日之前有交易日
+	now := time.Now()
+	start := window.Before(now)
+	for _, lb := range compares {
+		if d := lb.Before(now); d.Before(start) {
+			start = d
+		}
+	}
+	curves, err := bond.QueryCurves(cmd.Context(), &bond.QueryCurveReq{
+		Country: country,
+		Start:   start.AddDate(0, 0, -10).Format(utils.LayoutYYMMDD),
+		End:     now.Format(utils.LayoutYYMMDD),
+	})
+	if err != nil {
+		return err
+	}
+	if len(curves) == 0 {
+		return fmt.Errorf("no %s yield data", country.Label())
+	}
+
+	latest := curves[len(curves)-1]
+	list := []datedCurve{{Label: "最新", Curve: latest}}
+	for _, lb := range compares {
+		if c := bond.CurveOn(curves, lb.Before(latest.Date)); c != nil {
+			list = append(list, datedCurve{Label: lb.Label(), Curve: c})
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if err := printCurveChart(out, country, list, tenors, height); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	printFactors(out, list)
+	fmt.Fprintln(out)
+	printShapeChange(out, window.Label(), bond.CurveOn(curves, window.Before(latest.Date)), latest)
+	return nil
+}
+
+// curveColors 依次用于最新曲线和各对比曲线
+var curveColors = []string{render.AnsiYellow, render.AnsiCyan, render.AnsiBlue, render.AnsiWhite}
+
+// printCurveChart plots the curves with tenor on the x-axis; tenors default
+// to those of the latest curve. Missing tenors are left out of the line.
+func printCurveChart(out io.Writer, country bond.Country, list []datedCurve, tenors []bond.Tenor, height int) error {
+	if len(list) == 0 {
+		return nil
+	}
+	if len(tenors) == 0 {
+		tenors = list[0].Curve.Tenors()
+	}
+	labels := make([]string, len(tenors))
+	for i, t := range tenors {
+		labels[i] = t.String()
+	}
+
+	series := make([]render.LineSeries, 0, len(list))
+	for i, dc := range list {
+		values := make([]float64, len(tenors))
+		for j, t := range tenors {
+			v, ok := dc.Curve.Yields[t]
+			if !ok {
+				v = math.NaN()
+			}
+			values[j] = v
+		}
+		s := render.LineSeries{
+			Values: values,
+			Color:  curveColors[i%len(curveColors)],
+			Label:  fmt.Sprintf("%s %s", dc.Label, dc.Curve.Date.Format(utils.LayoutYYMMDD)),
+		}
+		if i > 0 {
+			s.Style = '○'
+		}
+		series = append(series, s)
+	}
+	title := fmt.Sprintf("%s收益率曲线（%%）", country.Label())
+	return render.RenderLines(out, series, render.LineConfig{Height: height, Title: title, XLabels: labels})
+}
+
+// printFactors 打印各曲线的水平、斜率和曲率
+func printFactors(out io.Writer, list []datedCurve) {
+	table := utils.NewTable(out, []string{"曲线", "日期", "水平", "斜率(bp)", "曲率(bp)"})
+	for _, dc := range list {
+		f, ok := bond.CurveFactors(dc.Curve)
+		if !ok {
+			table.Append([]string{dc.Label, dc.Curve.Date.Format(utils.LayoutYYMMDD), "-", "-", "-"})
+			continue
+		}
+		table.Append([]string{
+			dc.Label,
+			f.Date.Format(utils.LayoutYYMMDD),
+			fmt.Sprintf("%.2f%%", f.Level),
+			fmt.Sprintf("%.1f", f.Slope),
+			fmt.Sprintf("%.1f", f.Curvature),
+		})
+	}
+	table.Render()
+}
+
+// printShapeChange prints the change of the factors from the curve at the
+// start of the window to the latest one and the resulting shape.
+func printShapeChange(out io.Writer, window string, from, to *bond.YieldCurve) {
+	if from == nil {
+		fmt.Fprintf(out, "形态变化（%s至今）：数据不足\n", window)
+		return
+	}
+	f1, ok1 := bond.CurveFactors(from)
+	f2, ok2 := bond.CurveFactors(to)
+	if !ok1 || !ok2 {
+		fmt.Fprintf(out, "形态变化（%s至今）：曲线缺少 3 月至 10 年期限\n", window)
+		return
+	}
+	s := bond.CompareFactors(f1, f2)
+	fmt.Fprintf(out, "形态变化（%s至今）：%s ~ %s 水平 %+.1fbp 斜率 %+.1fbp 曲率 %+.1fbp → %s\n",
+		window, f1.Date.Format(utils.LayoutYYMMDD), f2.Date.Format(utils.LayoutYYMMDD),
+		s.Level, s.Slope, s.Curvature, s.Shape())
+}
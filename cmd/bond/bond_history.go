@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/bond"
 	"github.com/alwqx/sec/utils"
 	"github.com/olekukonko/tablewriter"
@@ -14,20 +15,25 @@ func NewBondHistoryCLI() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:     "bond-history",
 		Aliases: []string{"bh"},
-		Short:   "Print US Treasury yield curve history",
+		Short:   "Print US Treasury or China government bond yield curve history",
 		RunE:    BondHistoryHandler,
 	}
 	rootCmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
 	rootCmd.Flags().StringP("begin", "b", "", "Begin date 20260101")
 	rootCmd.Flags().StringP("end", "e", "", "End date 20260131")
+	addCurveFlags(rootCmd)
+	output.Enable(rootCmd)
 
 	return rootCmd
 }
 
-// BondHistoryHandler 打印美国国债收益率历史数据
+// BondHistoryHandler 打印国债收益率历史数据
 func BondHistoryHandler(cmd *cobra.Command, args []string) error {
-	req := &bond.QueryBondReq{}
-	var err error
+	country, tenors, err := parseCurveFlags(cmd)
+	if err != nil {
+		return err
+	}
+	req := &bond.QueryCurveReq{Country: country}
 	beginStr, _ := cmd.Flags().GetString("begin")
 	endStr, _ := cmd.Flags().GetString("end")
 	req.Start, req.End, err = utils.ParseBeginEnd(beginStr, endStr, 30, utils.ParseMetalCmdArgTimeLayout, utils.LayoutYYMMDD)
@@ -35,76 +41,49 @@ func BondHistoryHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	resp, err := bond.QueryBond(cmd.Context(), req)
+	curves, err := bond.QueryCurves(cmd.Context(), req)
 	if err != nil {
 		return err
 	}
-	printBondHistory(cmd.OutOrStdout(), resp.Data)
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, yieldRecords(country, curves, tenors, 0))
+	}
+	printBondHistory(cmd.OutOrStdout(), curves, tenors)
 
 	return nil
 }
 
-// printBondHistory 打印美国国债收益率历史数据
-func printBondHistory(out io.Writer, items []*bond.BondYieldItem) {
-	num := len(items)
+// printBondHistory 打印国债收益率历史数据，变动为基准期限较前一日的变化
+func printBondHistory(out io.Writer, curves []*bond.YieldCurve, tenors []bond.Tenor) {
+	num := len(curves)
 	if num == 0 {
 		return
 	}
+	if len(tenors) == 0 {
+		tenors = curveTenors(curves)
+	}
 
-	headers := []string{"日期", "1个月", "3个月", "6个月", "5年", "10年", "变动(bp)"}
-	columnsStyles := make([][]tablewriter.Colors, 0, len(headers))
+	headers := []string{"日期"}
+	for _, t := range tenors {
+		headers = append(headers, t.Label())
+	}
+	headers = append(headers, "变动(bp)")
 
-	data := make([][]string, 0, num)
-	for _, item := range items {
+	table := utils.NewTable(out, headers)
+	for i, c := range curves {
+		row, styles := curveRow(c, tenors)
 		changeBpStr := "-"
-		if item.YClose != -1 {
-			changeBpStr = fmt.Sprintf("%+.1f", item.Change*100)
-		}
-
-		row := []string{
-			item.Date,
-			fmt.Sprintf("%.2f%%", item.BC1Month),
-			fmt.Sprintf("%.2f%%", item.BC3Month),
-			fmt.Sprintf("%.2f%%", item.BC6Month),
-			fmt.Sprintf("%.2f%%", item.BC5Year),
-			fmt.Sprintf("%.2f%%", item.BC10Year),
-			changeBpStr,
-		}
-		data = append(data, row)
-
-		styles := make([]tablewriter.Colors, 0, len(headers))
-		for _, title := range headers {
-			var itemStyle tablewriter.Colors = tablewriter.Colors{}
-			if title == "10年" {
-				if item.ChangeRate > 0 {
-					itemStyle = tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle, tablewriter.FgRedColor}
-				} else if item.ChangeRate < 0 {
-					itemStyle = tablewriter.Colors{tablewriter.Bold, tablewriter.UnderlineSingle, tablewriter.FgGreenColor}
-				}
+		if i > 0 {
+			prev, ok1 := curves[i-1].At(benchmarkTenor)
+			cur, ok2 := c.At(benchmarkTenor)
+			if ok1 && ok2 {
+				changeBpStr = fmt.Sprintf("%+.1f", (cur-prev)*100)
+				colorBenchmark(styles, tenors, cur-prev)
 			}
-			styles = append(styles, itemStyle)
 		}
-		columnsStyles = append(columnsStyles, styles)
-	}
-
-	table := tablewriter.NewWriter(out)
-	table.SetHeader(headers)
-
-	for i, row := range data {
-		table.Rich(row, columnsStyles[i])
-	}
-
-	headerStyles := make([]tablewriter.Colors, 0, len(headers))
-	for range headers {
-		headerStyles = append(headerStyles, tablewriter.Colors{tablewriter.Bold})
+		row = append(row, changeBpStr)
+		styles = append(styles, tablewriter.Colors{})
+		table.Rich(row, styles)
 	}
-	table.SetHeaderColor(headerStyles...)
-
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetHeaderLine(false)
-	table.SetBorder(false)
-	table.SetNoWhiteSpace(false)
-	table.SetTablePadding("\t")
 	table.Render()
 }
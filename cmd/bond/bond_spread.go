@@ -0,0 +1,110 @@
+package bond
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/alwqx/sec/provider/bond"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func NewBondSpreadCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spread",
+		Short: "Print CN-US 10Y spread and term spreads with inversion and percentile",
+		Long: `Print the China-US 10Y government bond spread and the 10Y-2Y and 10Y-3M term
+spreads of both countries: latest value in bp, change from the previous day,
+mean, min and max over the range, the historical percentile of the latest
+value and whether the spread is inverted (negative). The China 2Y yield is
+interpolated from 1Y and 3Y since ChinaBond does not publish a 2Y key tenor.`,
+		Args: cobra.NoArgs,
+		RunE: BondSpreadHandler,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("begin", "b", "", "Begin date 20230101, default 3 years ago")
+	cmd.Flags().StringP("end", "e", "", "End date 20260131, default today")
+
+	return cmd
+}
+
+// spreadRow 利差名称与序列
+type spreadRow struct {
+	Name   string
+	Points []bond.SpreadPoint
+}
+
+// BondSpreadHandler 打印中美利差和期限利差
+func BondSpreadHandler(cmd *cobra.Command, args []string) error {
+	beginStr, _ := cmd.Flags().GetString("begin")
+	endStr, _ := cmd.Flags().GetString("end")
+	start, end, err := utils.ParseBeginEnd(beginStr, endStr, 3*365, utils.ParseMetalCmdArgTimeLayout, utils.LayoutYYMMDD)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		curves = make(map[bond.Country][]*bond.YieldCurve, 2)
+		errs   = make(map[bond.Country]error, 2)
+		mu     sync.Mutex
+	)
+	for _, country := range []bond.Country{bond.CountryCN, bond.CountryUS} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			list, err := bond.QueryCurves(cmd.Context(), &bond.QueryCurveReq{Country: country, Start: start, End: end})
+			mu.Lock()
+			defer mu.Unlock()
+			curves[country], errs[country] = list, err
+		}()
+	}
+	wg.Wait()
+	for _, country := range []bond.Country{bond.CountryCN, bond.CountryUS} {
+		if errs[country] != nil {
+			return fmt.Errorf("%s: %w", country.Label(), errs[country])
+		}
+	}
+
+	cn, us := curves[bond.CountryCN], curves[bond.CountryUS]
+	rows := []spreadRow{
+		{Name: "中美10年", Points: bond.CountrySpread(cn, us, bond.Tenor10Y)},
+		{Name: "中国10Y-2Y", Points: bond.TermSpread(cn, bond.Tenor10Y, bond.Tenor2Y)},
+		{Name: "中国10Y-3M", Points: bond.TermSpread(cn, bond.Tenor10Y, bond.Tenor3M)},
+		{Name: "美国10Y-2Y", Points: bond.TermSpread(us, bond.Tenor10Y, bond.Tenor2Y)},
+		{Name: "美国10Y-3M", Points: bond.TermSpread(us, bond.Tenor10Y, bond.Tenor3M)},
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "国债利差 %s ~ %s\n", start, end)
+	printSpreads(cmd.OutOrStdout(), rows)
+	return nil
+}
+
+// printSpreads 打印利差最新值、历史统计、分位和倒挂标记
+func printSpreads(out io.Writer, rows []spreadRow) {
+	table := utils.NewTable(out, []string{"利差", "日期", "最新(bp)", "变动(bp)", "均值", "最低", "最高", "历史分位", "倒挂"})
+	for _, r := range rows {
+		s := bond.Stats(r.Points)
+		if s == nil {
+			table.Append([]string{r.Name, "-", "-", "-", "-", "-", "-", "-", "-"})
+			continue
+		}
+		inverted, style := "否", tablewriter.Colors{}
+		if s.Inverted() {
+			inverted, style = "是", tablewriter.Colors{tablewriter.Bold, tablewriter.FgGreenColor}
+		}
+		table.Rich([]string{
+			r.Name,
+			s.Date.Format(utils.LayoutYYMMDD),
+			fmt.Sprintf("%.1f", s.Latest),
+			fmt.Sprintf("%+.1f", s.Change),
+			fmt.Sprintf("%.1f", s.Mean),
+			fmt.Sprintf("%.1f", s.Min),
+			fmt.Sprintf("%.1f", s.Max),
+			fmt.Sprintf("%.0f%%", s.Percentile),
+			inverted,
+		}, []tablewriter.Colors{{}, {}, style, {}, {}, {}, {}, {}, style})
+	}
+	table.Render()
+}
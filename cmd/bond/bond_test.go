@@ -1,26 +1,49 @@
 package bond
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/alwqx/sec/provider/bond"
 	"github.com/alwqx/sec/utils"
+	"github.com/stretchr/testify/require"
 )
 
+// printBondYieldItems prints US Treasury items with all tenors.
+func printBondYieldItems(items []*bond.BondYieldItem) {
+	printBondYield(os.Stdout, toCurves(items), nil)
+}
+
+// printBondHistoryItems prints US Treasury items with all tenors.
+func printBondHistoryItems(items []*bond.BondYieldItem) {
+	printBondHistory(os.Stdout, toCurves(items), nil)
+}
+
+func toCurves(items []*bond.BondYieldItem) []*bond.YieldCurve {
+	curves := make([]*bond.YieldCurve, 0, len(items))
+	for _, item := range items {
+		curves = append(curves, item.Curve())
+	}
+	return curves
+}
+
 func TestPrintBondYield(t *testing.T) {
 	// 1. nil data
-	printBondYield(os.Stdout, nil)
+	printBondYieldItems(nil)
 
 	// 2. empty data
-	printBondYield(os.Stdout, []*bond.BondYieldItem{})
+	printBondYieldItems([]*bond.BondYieldItem{})
 
 	// 3. first trading day (no previous data, YClose = -1)
 	date1, _ := time.Parse(utils.LayoutYYMMDD, "2026-05-01")
 	fmt.Println("first day (no previous):")
-	printBondYield(os.Stdout, []*bond.BondYieldItem{
+	printBondYieldItems([]*bond.BondYieldItem{
 		{
 			Date:     "2026-05-01",
 			DateTime: date1,
@@ -36,7 +59,7 @@ func TestPrintBondYield(t *testing.T) {
 	// 4. yield up (red)
 	date2, _ := time.Parse(utils.LayoutYYMMDD, "2026-05-04")
 	fmt.Println("yield up (red):")
-	printBondYield(os.Stdout, []*bond.BondYieldItem{
+	printBondYieldItems([]*bond.BondYieldItem{
 		{
 			Date:       "2026-05-04",
 			DateTime:   date2,
@@ -54,7 +77,7 @@ func TestPrintBondYield(t *testing.T) {
 	// 5. yield down (green)
 	date3, _ := time.Parse(utils.LayoutYYMMDD, "2026-05-06")
 	fmt.Println("yield down (green):")
-	printBondYield(os.Stdout, []*bond.BondYieldItem{
+	printBondYieldItems([]*bond.BondYieldItem{
 		{
 			Date:       "2026-05-06",
 			DateTime:   date3,
@@ -72,15 +95,15 @@ func TestPrintBondYield(t *testing.T) {
 
 func TestPrintBondHistory(t *testing.T) {
 	// 1. nil data
-	printBondHistory(os.Stdout, nil)
+	printBondHistoryItems(nil)
 
 	// 2. empty data
-	printBondHistory(os.Stdout, []*bond.BondYieldItem{})
+	printBondHistoryItems([]*bond.BondYieldItem{})
 
 	// 3. single item with no previous data
 	date1, _ := time.Parse(utils.LayoutYYMMDD, "2026-05-01")
 	fmt.Println("single item (no previous):")
-	printBondHistory(os.Stdout, []*bond.BondYieldItem{
+	printBondHistoryItems([]*bond.BondYieldItem{
 		{
 			Date:     "2026-05-01",
 			DateTime: date1,
@@ -140,7 +163,7 @@ func TestPrintBondHistory(t *testing.T) {
 		},
 	}
 	fmt.Println("multi-day history (up/down/no-prev):")
-	printBondHistory(os.Stdout, data)
+	printBondHistoryItems(data)
 }
 
 // TestPrintBondHistoryEdgeCases 覆盖边界场景
@@ -154,7 +177,7 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 	// 1. flat: yield unchanged from previous day (ChangeRate == 0, no color)
 	t.Run("unchanged yield (flat)", func(t *testing.T) {
 		fmt.Println("--- unchanged yield (flat, no color) ---")
-		printBondHistory(os.Stdout, []*bond.BondYieldItem{
+		printBondHistoryItems([]*bond.BondYieldItem{
 			{
 				Date: "2026-05-04", DateTime: date1,
 				BC1Month: 3.70, BC3Month: 3.69, BC6Month: 3.74,
@@ -167,7 +190,7 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 	// 2. very small increase (borderline positive ChangeRate)
 	t.Run("tiny increase", func(t *testing.T) {
 		fmt.Println("--- tiny increase (0.1 bp, red) ---")
-		printBondHistory(os.Stdout, []*bond.BondYieldItem{
+		printBondHistoryItems([]*bond.BondYieldItem{
 			{
 				Date: "2026-05-04", DateTime: date1,
 				BC1Month: 3.70, BC3Month: 3.69, BC6Month: 3.74,
@@ -180,7 +203,7 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 	// 3. very small decrease (borderline negative ChangeRate)
 	t.Run("tiny decrease", func(t *testing.T) {
 		fmt.Println("--- tiny decrease (0.1 bp, green) ---")
-		printBondHistory(os.Stdout, []*bond.BondYieldItem{
+		printBondHistoryItems([]*bond.BondYieldItem{
 			{
 				Date: "2026-05-04", DateTime: date1,
 				BC1Month: 3.70, BC3Month: 3.69, BC6Month: 3.74,
@@ -193,7 +216,7 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 	// 4. large change (+50 bp)
 	t.Run("large increase", func(t *testing.T) {
 		fmt.Println("--- large increase (+50 bp, red) ---")
-		printBondHistory(os.Stdout, []*bond.BondYieldItem{
+		printBondHistoryItems([]*bond.BondYieldItem{
 			{
 				Date: "2026-05-05", DateTime: date2,
 				BC1Month: 4.20, BC3Month: 4.19, BC6Month: 4.24,
@@ -206,7 +229,7 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 	// 5. large change (-50 bp)
 	t.Run("large decrease", func(t *testing.T) {
 		fmt.Println("--- large decrease (-50 bp, green) ---")
-		printBondHistory(os.Stdout, []*bond.BondYieldItem{
+		printBondHistoryItems([]*bond.BondYieldItem{
 			{
 				Date: "2026-05-06", DateTime: date3,
 				BC1Month: 3.20, BC3Month: 3.19, BC6Month: 3.24,
@@ -219,7 +242,7 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 	// 6. mixed: first day (no prev) + flat + up + down together
 	t.Run("mixed all states", func(t *testing.T) {
 		fmt.Println("--- mixed: no-prev + flat + up + down ---")
-		printBondHistory(os.Stdout, []*bond.BondYieldItem{
+		printBondHistoryItems([]*bond.BondYieldItem{
 			{
 				Date: "2026-05-01", DateTime: date0,
 				BC1Month: 3.71, BC3Month: 3.68, BC6Month: 3.71,
@@ -256,7 +279,7 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 	// 7. zero yield values (edge case, should not panic)
 	t.Run("zero yields", func(t *testing.T) {
 		fmt.Println("--- zero yields ---")
-		printBondHistory(os.Stdout, []*bond.BondYieldItem{
+		printBondHistoryItems([]*bond.BondYieldItem{
 			{
 				Date: "2026-05-04", DateTime: date1,
 				BC1Month: 0, BC3Month: 0, BC6Month: 0,
@@ -272,3 +295,154 @@ func TestPrintBondHistoryEdgeCases(t *testing.T) {
 		})
 	})
 }
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// cells splits a rendered table line into cells, dropping colors and column
+// separators.
+func cells(line string) []string {
+	var res []string
+	for _, f := range strings.Fields(ansiRe.ReplaceAllString(line, "")) {
+		if f != "|" {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+func TestPrintCurveTenors(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse(utils.LayoutYYMMDD, s)
+		return d
+	}
+	curves := []*bond.YieldCurve{
+		{Date: day("2026-05-07"), Yields: map[bond.Tenor]float64{bond.Tenor3M: 1.40, bond.Tenor1Y: 1.45, bond.Tenor10Y: 1.80, bond.Tenor30Y: 2.05}},
+		{Date: day("2026-05-08"), Yields: map[bond.Tenor]float64{bond.Tenor3M: 1.38, bond.Tenor1Y: 1.44, bond.Tenor10Y: 1.75, bond.Tenor30Y: 2.01}},
+	}
+
+	var buf bytes.Buffer
+	printBondYield(&buf, curves, nil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, []string{"日期", "3个月", "1年", "10年", "30年", "前值", "变动(BP)"}, cells(lines[0]))
+	require.Contains(t, lines[1], "2026-05-08")
+	require.Contains(t, lines[1], "1.80%")
+	require.Contains(t, lines[1], "-5.0")
+
+	// 指定期限，曲线中没有的期限显示 "-"
+	buf.Reset()
+	printBondHistory(&buf, curves, []bond.Tenor{bond.Tenor2Y, bond.Tenor10Y})
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, []string{"日期", "2年", "10年", "变动(BP)"}, cells(lines[0]))
+	require.Equal(t, []string{"2026-05-07", "-", "1.80%", "-"}, cells(lines[1]))
+	require.Equal(t, []string{"2026-05-08", "-", "1.75%", "-5.0"}, cells(lines[2]))
+}
+
+func TestPrintSpreads(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse(utils.LayoutYYMMDD, s)
+		return d
+	}
+	rows := []spreadRow{
+		{Name: "中美10年", Points: []bond.SpreadPoint{{Date: day("2026-05-07"), Value: -250}, {Date: day("2026-05-08"), Value: -260}}},
+		{Name: "中国10Y-2Y", Points: []bond.SpreadPoint{{Date: day("2026-05-08"), Value: 30}}},
+		{Name: "美国10Y-3M"},
+	}
+	var buf bytes.Buffer
+	printSpreads(&buf, rows)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, []string{"中美10年", "2026-05-08", "-260.0", "-10.0", "-255.0", "-260.0", "-250.0", "50%", "是"}, cells(lines[1]))
+	require.Equal(t, "否", cells(lines[2])[8])
+	require.Equal(t, []string{"美国10Y-3M", "-", "-", "-", "-", "-", "-", "-", "-"}, cells(lines[3]))
+}
+
+func TestBondCLI(t *testing.T) {
+	cmd := NewBondCLI()
+	require.NotNil(t, cmd.Flags().Lookup("country"))
+	sub, _, err := cmd.Find([]string{"spread"})
+	require.NoError(t, err)
+	require.Equal(t, "spread", sub.Name())
+	sub, _, err = cmd.Find([]string{"curve"})
+	require.NoError(t, err)
+	require.Equal(t, "curve", sub.Name())
+	require.NotNil(t, sub.Flags().Lookup("window"))
+
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--country", "jp"})
+	require.ErrorContains(t, cmd.Execute(), "invalid country")
+}
+
+func testCurveList() []datedCurve {
+	day := time.Date(2026, 5, 8, 0, 0, 0, 0, time.UTC)
+	return []datedCurve{
+		{Label: "最新", Curve: &bond.YieldCurve{Date: day, Yields: map[bond.Tenor]float64{bond.Tenor3M: 1.4, bond.Tenor1Y: 1.5, bond.Tenor3Y: 1.7, bond.Tenor10Y: 2.0}}},
+		{Label: "1月前", Curve: &bond.YieldCurve{Date: day.AddDate(0, -1, 0), Yields: map[bond.Tenor]float64{bond.Tenor3M: 1.5, bond.Tenor1Y: 1.6, bond.Tenor3Y: 1.75, bond.Tenor10Y: 1.95}}},
+		{Label: "1年前", Curve: &bond.YieldCurve{Date: day.AddDate(-1, 0, 0), Yields: map[bond.Tenor]float64{bond.Tenor1Y: 2.1, bond.Tenor10Y: 2.3}}},
+	}
+}
+
+func TestPrintCurveChart(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, printCurveChart(&buf, bond.CountryCN, nil, nil, 8))
+	require.Empty(t, buf.String())
+
+	require.NoError(t, printCurveChart(&buf, bond.CountryCN, testCurveList(), nil, 8))
+	lines := strings.Split(strings.TrimRight(ansiRe.ReplaceAllString(buf.String(), ""), "\n"), "\n")
+	// 标题 + 8 行图表 + 期限 + 图例
+	require.Len(t, lines, 11)
+	require.Contains(t, lines[0], "中国国债收益率曲线")
+	require.Equal(t, []string{"3M", "1Y", "3Y", "10Y"}, strings.Fields(lines[9]))
+	require.Contains(t, lines[10], "● 最新 2026-05-08")
+	require.Contains(t, lines[10], "○ 1年前 2025-05-08")
+	require.Contains(t, buf.String(), "2.30")
+}
+
+func TestPrintFactors(t *testing.T) {
+	var buf bytes.Buffer
+	printFactors(&buf, testCurveList())
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, []string{"曲线", "日期", "水平", "斜率(BP)", "曲率(BP)"}, cells(lines[0]))
+	// 2Y 由 1Y 和 3Y 插值为 1.6
+	require.Equal(t, []string{"最新", "2026-05-08", "1.67%", "60.0", "-20.0"}, cells(lines[1]))
+	require.Equal(t, []string{"1年前", "2025-05-08", "-", "-", "-"}, cells(lines[3]))
+}
+
+func TestPrintShapeChange(t *testing.T) {
+	list := testCurveList()
+	var buf bytes.Buffer
+	printShapeChange(&buf, "1月前", list[1].Curve, list[0].Curve)
+	require.Equal(t, "形态变化（1月前至今）：2026-04-08 ~ 2026-05-08 水平 -4.2bp 斜率 +15.0bp 曲率 -10.0bp → 牛陡\n", buf.String())
+
+	buf.Reset()
+	printShapeChange(&buf, "1年前", list[2].Curve, list[0].Curve)
+	require.Contains(t, buf.String(), "缺少")
+	buf.Reset()
+	printShapeChange(&buf, "1年前", nil, list[0].Curve)
+	require.Contains(t, buf.String(), "数据不足")
+}
+
+func TestYieldRecords(t *testing.T) {
+	day := time.Date(2026, 5, 6, 0, 0, 0, 0, time.UTC)
+	curves := []*bond.YieldCurve{
+		{Date: day, Yields: map[bond.Tenor]float64{bond.Tenor3M: 1.40, bond.Tenor10Y: 1.70}},
+		{Date: day.AddDate(0, 0, 1), Yields: map[bond.Tenor]float64{bond.Tenor10Y: 1.73}},
+	}
+
+	recs := yieldRecords(bond.CountryCN, curves, nil, 0)
+	require.Len(t, recs, 3)
+	require.Equal(t, yieldRecord{Date: "2026-05-06", Country: "cn", Tenor: "3M", Months: 3, Yield: 1.40}, recs[0])
+	require.Nil(t, recs[1].ChangeBP)
+	require.Equal(t, "10Y", recs[2].Tenor)
+	require.Equal(t, 1.70, *recs[2].PrevYield)
+	require.Equal(t, 3.0, *recs[2].ChangeBP)
+
+	// 只取最新一日，缺少的期限跳过
+	recs = yieldRecords(bond.CountryCN, curves, []bond.Tenor{bond.Tenor3M, bond.Tenor10Y}, 1)
+	require.Len(t, recs, 1)
+	require.Equal(t, "2026-05-07", recs[0].Date)
+	require.Equal(t, 120, recs[0].Months)
+}
@@ -0,0 +1,275 @@
+package breadth
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewBreadthCLI returns the breadth command.
+func NewBreadthCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "breadth",
+		Short: "Print market breadth and limit-up/limit-down statistics",
+		Long: `Print advancers and decliners, limit-up and limit-down counts, consecutive
+limit-up boards and total turnover of the whole A-share market.
+
+Every run saves the day's statistics to ~/.sec/breadth.json; --history charts
+the saved days.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.NoArgs,
+		RunE: runBreadth,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().Bool("history", false, "Chart the locally saved daily history")
+	cmd.Flags().IntP("days", "n", 60, "Number of days to chart with --history")
+	cmd.Flags().Int("height", 8, "Chart height in rows with --history")
+	cmd.Flags().Bool("no-save", false, "Do not save today's statistics to local history")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runBreadth(cmd *cobra.Command, args []string) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	f := output.FormatOf(cmd)
+	if history, _ := cmd.Flags().GetBool("history"); history {
+		days, _ := cmd.Flags().GetInt("days")
+		height, _ := cmd.Flags().GetInt("height")
+		if days <= 0 || height <= 0 {
+			return fmt.Errorf("invalid --days %d or --height %d: must be > 0", days, height)
+		}
+		records, err := loadHistory(path)
+		if err != nil {
+			return err
+		}
+		records = recent(records, days)
+		if f.Structured() {
+			return output.Write(cmd.OutOrStdout(), f, records)
+		}
+		return printHistory(cmd.OutOrStdout(), records, height)
+	}
+
+	ctx := cmd.Context()
+	snap, err := eastmoney.QueryMarketSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	date := snap.Time
+	if date.IsZero() {
+		date = time.Now()
+	}
+	pool, err := eastmoney.QueryLimitUpPool(ctx, date)
+	if err != nil {
+		// 连板数据缺失不影响涨跌统计
+		slog.WarnContext(ctx, "获取涨停股池失败", "error", err)
+	}
+
+	b := summarize(snap, pool)
+	if f.Structured() {
+		if err := output.Write(cmd.OutOrStdout(), f, b); err != nil {
+			return err
+		}
+	} else {
+		printBreadth(cmd.OutOrStdout(), b, snap, pool)
+	}
+
+	if noSave, _ := cmd.Flags().GetBool("no-save"); noSave || snap.Time.IsZero() {
+		return nil
+	}
+	records, err := loadHistory(path)
+	if err != nil {
+		return err
+	}
+	return saveHistory(path, upsert(records, b))
+}
+
+// Breadth 单日市场宽度统计，同时作为本地历史记录
+type Breadth struct {
+	Date      string  `json:"date"` // 2006-01-02
+	Up        int     `json:"up"`
+	Down      int     `json:"down"`
+	Flat      int     `json:"flat"`
+	Suspended int     `json:"suspended"`
+	LimitUp   int     `json:"limit_up"`
+	LimitDown int     `json:"limit_down"`
+	MaxStreak int     `json:"max_streak"` // 最高连板数
+	Amount    float64 `json:"amount"`     // 成交额，单位元
+}
+
+// summarize counts advancers, decliners and limit moves of snap. The highest
+// consecutive limit-up streak comes from pool.
+func summarize(snap *eastmoney.MarketSnapshot, pool []*eastmoney.LimitUpStock) Breadth {
+	var b Breadth
+	if !snap.Time.IsZero() {
+		b.Date = snap.Time.Format(time.DateOnly)
+	}
+	for _, s := range snap.Stocks {
+		if s.Suspended() {
+			b.Suspended++
+			continue
+		}
+		b.Amount += s.Amount
+		switch {
+		case s.ChangeRate > 0:
+			b.Up++
+		case s.ChangeRate < 0:
+			b.Down++
+		default:
+			b.Flat++
+		}
+		if s.AtLimitUp() {
+			b.LimitUp++
+		} else if s.AtLimitDown() {
+			b.LimitDown++
+		}
+	}
+	for _, s := range pool {
+		b.MaxStreak = max(b.MaxStreak, s.Streak)
+	}
+	return b
+}
+
+// bucket 涨跌幅分布区间
+type bucket struct {
+	Label string
+	Count int
+}
+
+// distribution groups traded stocks by change rate. Limit moves get their own
+// buckets since their thresholds differ between boards.
+func distribution(snap *eastmoney.MarketSnapshot) []bucket {
+	res := []bucket{
+		{Label: "涨停"}, {Label: ">7%"}, {Label: "5~7%"}, {Label: "3~5%"}, {Label: "0~3%"},
+		{Label: "平"},
+		{Label: "-3~0%"}, {Label: "-5~-3%"}, {Label: "-7~-5%"}, {Label: "<-7%"}, {Label: "跌停"},
+	}
+	for _, s := range snap.Stocks {
+		if s.Suspended() {
+			continue
+		}
+		v := s.ChangeRate
+		var i int
+		switch {
+		case s.AtLimitUp():
+			i = 0
+		case s.AtLimitDown():
+			i = 10
+		case v > 7:
+			i = 1
+		case v > 5:
+			i = 2
+		case v > 3:
+			i = 3
+		case v > 0:
+			i = 4
+		case v == 0:
+			i = 5
+		case v >= -3:
+			i = 6
+		case v >= -5:
+			i = 7
+		case v >= -7:
+			i = 8
+		default:
+			i = 9
+		}
+		res[i].Count++
+	}
+	return res
+}
+
+// ladder 连板梯队中的一级
+type ladder struct {
+	Streak int
+	Stocks []*eastmoney.LimitUpStock
+}
+
+// ladders groups the limit-up pool by consecutive boards, highest first.
+func ladders(pool []*eastmoney.LimitUpStock) []ladder {
+	groups := make(map[int][]*eastmoney.LimitUpStock)
+	for _, s := range pool {
+		streak := max(s.Streak, 1)
+		groups[streak] = append(groups[streak], s)
+	}
+	res := make([]ladder, 0, len(groups))
+	for streak, stocks := range groups {
+		res = append(res, ladder{Streak: streak, Stocks: stocks})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Streak > res[j].Streak })
+	return res
+}
+
+func printBreadth(out io.Writer, b Breadth, snap *eastmoney.MarketSnapshot, pool []*eastmoney.LimitUpStock) {
+	traded := b.Up + b.Down + b.Flat
+	upRatio := 0.0
+	if traded > 0 {
+		upRatio = float64(b.Up) / float64(traded) * 100
+	}
+	updated := "-"
+	if !snap.Time.IsZero() {
+		updated = snap.Time.Format("2006-01-02 15:04:05")
+	}
+	fmt.Fprintf(out, "行情时间\t%s\n上涨家数\t%d (%.1f%%)\n下跌家数\t%d\n平盘家数\t%d\n停牌家数\t%d\n涨停家数\t%d\n跌停家数\t%d\n最高连板\t%d\n成交额  \t%s\n",
+		updated, b.Up, upRatio, b.Down, b.Flat, b.Suspended, b.LimitUp, b.LimitDown, b.MaxStreak, utils.HumanNum(b.Amount))
+
+	fmt.Fprintln(out)
+	dist := distribution(snap)
+	headers := make([]string, 0, len(dist))
+	row := make([]string, 0, len(dist))
+	styles := make([]tablewriter.Colors, 0, len(dist))
+	for i, d := range dist {
+		headers = append(headers, d.Label)
+		row = append(row, strconv.Itoa(d.Count))
+		switch {
+		case i < 5:
+			styles = append(styles, tablewriter.Colors{tablewriter.FgRedColor})
+		case i > 5:
+			styles = append(styles, tablewriter.Colors{tablewriter.FgGreenColor})
+		default:
+			styles = append(styles, tablewriter.Colors{})
+		}
+	}
+	table := utils.NewTable(out, headers)
+	table.Rich(row, styles)
+	table.Render()
+
+	if len(pool) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	table = utils.NewTable(out, []string{"连板", "家数", "个股"})
+	for _, l := range ladders(pool) {
+		names := make([]string, 0, len(l.Stocks))
+		for _, s := range l.Stocks {
+			names = append(names, s.Name)
+		}
+		// 首板家数多，只列出前几只
+		if len(names) > 8 {
+			names = append(names[:8], "...")
+		}
+		label := fmt.Sprintf("%d连板", l.Streak)
+		if l.Streak == 1 {
+			label = "首板"
+		}
+		table.Append([]string{label, strconv.Itoa(len(l.Stocks)), strings.Join(names, " ")})
+	}
+	table.Render()
+}
@@ -0,0 +1,100 @@
+package breadth
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func testSnapshot() *eastmoney.MarketSnapshot {
+	stock := func(code string, price, chg float64) *eastmoney.MarketStock {
+		return &eastmoney.MarketStock{Code: code, Price: price, ChangeRate: chg, Amount: 1e8, LimitUp: 11, LimitDown: 9}
+	}
+	return &eastmoney.MarketSnapshot{
+		Time: time.Date(2026, 10, 16, 15, 0, 0, 0, time.Local),
+		Stocks: []*eastmoney.MarketStock{
+			stock("000001", 11, 10),
+			stock("000002", 10.8, 8),
+			stock("000003", 10.1, 1),
+			stock("000004", 10, 0),
+			stock("000005", 9.6, -4),
+			stock("000006", 9, -10),
+			stock("000007", 0, 0), // 停牌
+		},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	pool := []*eastmoney.LimitUpStock{{Code: "000001", Name: "甲", Streak: 3}, {Code: "000008", Name: "乙", Streak: 1}}
+	b := summarize(testSnapshot(), pool)
+	require.Equal(t, Breadth{
+		Date: "2026-10-16", Up: 3, Down: 2, Flat: 1, Suspended: 1,
+		LimitUp: 1, LimitDown: 1, MaxStreak: 3, Amount: 6e8,
+	}, b)
+
+	b = summarize(&eastmoney.MarketSnapshot{}, nil)
+	require.Equal(t, Breadth{}, b)
+}
+
+func TestDistribution(t *testing.T) {
+	counts := make(map[string]int)
+	for _, d := range distribution(testSnapshot()) {
+		counts[d.Label] = d.Count
+	}
+	require.Equal(t, map[string]int{
+		"涨停": 1, ">7%": 1, "5~7%": 0, "3~5%": 0, "0~3%": 1, "平": 1,
+		"-3~0%": 0, "-5~-3%": 1, "-7~-5%": 0, "<-7%": 0, "跌停": 1,
+	}, counts)
+}
+
+func TestLadders(t *testing.T) {
+	pool := []*eastmoney.LimitUpStock{
+		{Name: "甲", Streak: 1}, {Name: "乙", Streak: 3}, {Name: "丙", Streak: 1}, {Name: "丁", Streak: 0},
+	}
+	ls := ladders(pool)
+	require.Len(t, ls, 2)
+	require.Equal(t, 3, ls[0].Streak)
+	require.Equal(t, 1, ls[1].Streak)
+	require.Len(t, ls[1].Stocks, 3)
+
+	var buf bytes.Buffer
+	printBreadth(&buf, summarize(testSnapshot(), pool), testSnapshot(), pool)
+	require.Contains(t, buf.String(), "上涨家数\t3 (50.0%)")
+	require.Contains(t, buf.String(), "3连板")
+	require.Contains(t, buf.String(), "首板")
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breadth.json")
+	records, err := loadHistory(path)
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	records = upsert(records, Breadth{Date: "2026-10-16", Up: 1})
+	records = upsert(records, Breadth{Date: "2026-10-14", Up: 2})
+	records = upsert(records, Breadth{Date: "2026-10-16", Up: 3})
+	records = upsert(records, Breadth{})
+	require.Equal(t, []Breadth{{Date: "2026-10-14", Up: 2}, {Date: "2026-10-16", Up: 3}}, records)
+
+	require.NoError(t, saveHistory(path, records))
+	loaded, err := loadHistory(path)
+	require.NoError(t, err)
+	require.Equal(t, records, loaded)
+
+	var buf bytes.Buffer
+	require.NoError(t, printHistory(&buf, nil, 5))
+	require.Contains(t, buf.String(), "暂无本地历史")
+
+	buf.Reset()
+	require.Equal(t, loaded[1:], recent(loaded, 1))
+	require.Equal(t, loaded, recent(loaded, 60))
+	require.NoError(t, printHistory(&buf, recent(loaded, 1), 5))
+	require.Contains(t, buf.String(), "2026-10-16 ~ 2026-10-16 (1 天)")
+	require.Contains(t, buf.String(), "成交额(亿)")
+
+	require.Error(t, printHistory(&buf, []Breadth{{Date: "bad"}}, 5))
+}
@@ -0,0 +1,113 @@
+package breadth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/alwqx/sec/render"
+	"github.com/alwqx/sec/utils"
+)
+
+// historyPath returns the path to the local breadth history file.
+func historyPath() (string, error) {
+	dir, err := utils.SecDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "breadth.json"), nil
+}
+
+func loadHistory(path string) ([]Breadth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var records []Breadth
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func saveHistory(path string, records []Breadth) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// upsert replaces the record of b's date, or adds it, keeping records sorted
+// by date.
+func upsert(records []Breadth, b Breadth) []Breadth {
+	if b.Date == "" {
+		return records
+	}
+	for i := range records {
+		if records[i].Date == b.Date {
+			records[i] = b
+			return records
+		}
+	}
+	records = append(records, b)
+	sort.Slice(records, func(i, j int) bool { return records[i].Date < records[j].Date })
+	return records
+}
+
+// recent returns the last days records.
+func recent(records []Breadth, days int) []Breadth {
+	if len(records) > days {
+		return records[len(records)-days:]
+	}
+	return records
+}
+
+// printHistory charts records: net advancers, net limit-up count and
+// turnover.
+func printHistory(out io.Writer, records []Breadth, height int) error {
+	if len(records) == 0 {
+		fmt.Fprintln(out, "暂无本地历史，运行 sec breadth 后会自动保存当日统计")
+		return nil
+	}
+
+	net := make([]render.Bar, 0, len(records))
+	limits := make([]render.Bar, 0, len(records))
+	amount := make([]render.Bar, 0, len(records))
+	for _, r := range records {
+		date, err := time.Parse(time.DateOnly, r.Date)
+		if err != nil {
+			return fmt.Errorf("invalid date %q in breadth history: %w", r.Date, err)
+		}
+		net = append(net, render.Bar{Date: date, Value: float64(r.Up - r.Down)})
+		limits = append(limits, render.Bar{Date: date, Value: float64(r.LimitUp - r.LimitDown)})
+		amount = append(amount, render.Bar{Date: date, Value: r.Amount / 1e8})
+	}
+
+	last := records[len(records)-1]
+	fmt.Fprintf(out, "市场宽度历史 %s ~ %s (%d 天)\n\n", records[0].Date, last.Date, len(records))
+	charts := []struct {
+		title string
+		bars  []render.Bar
+	}{
+		{"上涨-下跌家数", net},
+		{"涨停-跌停家数", limits},
+		{"成交额(亿)", amount},
+	}
+	for i, c := range charts {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		if err := render.RenderBars(out, c.bars, render.BarConfig{Height: height, Title: c.title}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
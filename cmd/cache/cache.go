@@ -0,0 +1,189 @@
+// Package cache implements the sec cache command to inspect and clear the
+// local K-line history cache at ~/.sec/cache/kline.
+//
+// Subcommands:
+//
+//	sec cache stats          show cached securities
+//	sec cache clear [code]   remove all cache files or only those of code
+package cache
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/cache"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewCacheCLI returns the cache command with subcommands.
+func NewCacheCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "cache",
+		Short:         "Manage local K-line history cache",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		RunE: runCacheStats,
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show cached K-line history",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheStats,
+	}
+	clearCmd := &cobra.Command{
+		Use:   "clear [code]",
+		Short: "Clear K-line history cache, all or of a specific code",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runCacheClear,
+	}
+	output.Enable(cmd, statsCmd, clearCmd)
+	cmd.AddCommand(statsCmd, clearCmd)
+	return cmd
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	dir, err := cache.Dir()
+	if err != nil {
+		return err
+	}
+	infos, err := cache.Stats(dir)
+	if err != nil {
+		return err
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, entryRecords(infos))
+	}
+	if len(infos) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "缓存为空: %s\n", dir)
+		return nil
+	}
+
+	printCacheStats(cmd.OutOrStdout(), infos)
+	var total int64
+	for _, info := range infos {
+		total += info.Size
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\n缓存目录: %s  共 %d 个  占用 %s\n", dir, len(infos), utils.HumanByte(float64(total)))
+	return nil
+}
+
+func printCacheStats(out io.Writer, infos []*cache.EntryInfo) {
+	headers := []string{"证券代码", "复权", "K线数", "起始日期", "结束日期", "更新时间", "大小"}
+	data := make([][]string, 0, len(infos))
+	for _, info := range infos {
+		data = append(data, []string{
+			info.Market.String() + info.Code,
+			fqtName(int(info.FQT)),
+			strconv.Itoa(info.Bars),
+			info.First,
+			info.Last,
+			utils.StandardTimeString(info.UpdatedAt),
+			utils.HumanByte(float64(info.Size)),
+		})
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+	table.AppendBulk(data)
+	table.Render()
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	dir, err := cache.Dir()
+	if err != nil {
+		return err
+	}
+
+	var code string
+	if len(args) == 1 {
+		code = args[0]
+	}
+	removed, err := cache.Clear(dir, code)
+	if err != nil {
+		return err
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, clearRecord{Dir: dir, Code: code, Removed: removed})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "已删除 %d 个缓存文件\n", removed)
+	return nil
+}
+
+// entryRecord 缓存文件的结构化输出，大小单位字节
+type entryRecord struct {
+	ExCode    string `json:"excode"`
+	FQ        string `json:"fq"` // 同 --fq 取值：bfq、qfq、hfq
+	Bars      int    `json:"bars"`
+	First     string `json:"first"`
+	Last      string `json:"last"`
+	UpdatedAt string `json:"updated_at"`
+	Size      int64  `json:"size"`
+	Path      string `json:"path"`
+}
+
+func entryRecords(infos []*cache.EntryInfo) []entryRecord {
+	records := make([]entryRecord, 0, len(infos))
+	for _, info := range infos {
+		records = append(records, entryRecord{
+			ExCode:    info.Market.String() + info.Code,
+			FQ:        fqtKey(int(info.FQT)),
+			Bars:      info.Bars,
+			First:     info.First,
+			Last:      info.Last,
+			UpdatedAt: utils.StandardTimeString(info.UpdatedAt),
+			Size:      info.Size,
+			Path:      info.Path,
+		})
+	}
+	return records
+}
+
+// clearRecord 清理缓存的结构化输出，Code 为空表示清理全部
+type clearRecord struct {
+	Dir     string `json:"dir"`
+	Code    string `json:"code"`
+	Removed int    `json:"removed"`
+}
+
+// fqtName 复权类型显示名称
+func fqtName(fqt int) string {
+	switch fqt {
+	case 1:
+		return "前复权"
+	case 2:
+		return "后复权"
+	default:
+		return "不复权"
+	}
+}
+
+// fqtKey 复权类型在结构化输出中的取值
+func fqtKey(fqt int) string {
+	switch fqt {
+	case 1:
+		return "qfq"
+	case 2:
+		return "hfq"
+	default:
+		return "bfq"
+	}
+}
@@ -0,0 +1,319 @@
+package cb
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/convertible"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewCBCLI returns the cb command.
+func NewCBCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cb [code]",
+		Aliases: []string{"kzz"},
+		Short:   "List convertible bonds with conversion premium, YTM and double-low",
+		Long: `List all listed Shanghai and Shenzhen convertible bonds (可转债) with price,
+conversion price, conversion value, conversion premium, yield to maturity,
+remaining years, outstanding balance, credit rating and double-low score (价格 + 转股溢价率),
+sorted by double-low by default.
+
+With a bond code or name, print the details of the bond: conversion terms,
+trigger prices, coupons and the realtime quote of the underlying stock.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: runCB,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("sort", "s", "dblow", "Sort key: "+strings.Join(convertible.SortKeys(), ", "))
+	cmd.Flags().Bool("desc", false, "Sort in descending order")
+	cmd.Flags().Float64("price-max", 0, "Only bonds priced at or below this")
+	cmd.Flags().Float64("premium-max", 0, "Only bonds with conversion premium (%) at or below this")
+	cmd.Flags().Float64("ytm-min", 0, "Only bonds with YTM (%) at or above this")
+	cmd.Flags().String("rating", "", "Only bonds rated at or above this, e.g. AA-")
+	cmd.Flags().IntP("limit", "n", 30, "Number of bonds to print")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runCB(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	now := time.Now()
+	f := output.FormatOf(cmd)
+	if len(args) == 1 {
+		b, err := convertible.Get(ctx, args[0], now)
+		if err != nil {
+			return err
+		}
+		if f.Structured() {
+			return output.Write(out, f, newBondRecord(b))
+		}
+		printDetail(out, b)
+		quotes, err := sina.QueryQuoteList(ctx, []string{b.StockExCode()})
+		if err != nil || len(quotes) == 0 {
+			// 正股行情仅作补充，失败时不影响转债信息
+			return nil
+		}
+		fmt.Fprintln(out)
+		printStockQuote(out, quotes[0])
+		return nil
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit <= 0 {
+		return fmt.Errorf("invalid --limit %d: must be > 0", limit)
+	}
+	filter := &convertible.Filter{}
+	filter.Rating, _ = cmd.Flags().GetString("rating")
+	// 只有显式指定的条件才生效，0 也是合法的阈值
+	for name, dst := range map[string]**float64{
+		"price-max":   &filter.PriceMax,
+		"premium-max": &filter.PremiumMax,
+		"ytm-min":     &filter.YTMMin,
+	} {
+		if cmd.Flags().Changed(name) {
+			v, _ := cmd.Flags().GetFloat64(name)
+			*dst = &v
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	key, _ := cmd.Flags().GetString("sort")
+	desc, _ := cmd.Flags().GetBool("desc")
+
+	list, err := convertible.List(ctx, now)
+	if err != nil {
+		return err
+	}
+	matched := make([]*convertible.Bond, 0, len(list))
+	for _, b := range list {
+		if filter.Match(b) {
+			matched = append(matched, b)
+		}
+	}
+	if err := convertible.Sort(matched, key, desc); err != nil {
+		return err
+	}
+	matched = matched[:min(len(matched), limit)]
+	if f.Structured() {
+		return output.Write(out, f, bondRecords(matched))
+	}
+	fmt.Fprintf(out, "可转债 %d 只，符合条件 %d 只\n", len(list), len(matched))
+	printList(out, matched)
+	return nil
+}
+
+func printList(out io.Writer, list []*convertible.Bond) {
+	table := utils.NewTable(out, []string{"代码", "名称", "价格", "涨跌幅", "正股", "正股涨跌", "转股价", "转股价值", "溢价率", "YTM", "剩余年限", "规模", "评级", "双低"})
+	for _, b := range list {
+		table.Rich([]string{
+			b.Code,
+			b.Name,
+			fmt.Sprintf("%.3f", b.Price),
+			fmt.Sprintf("%+.2f%%", b.ChangeRate),
+			b.StockName,
+			fmt.Sprintf("%+.2f%%", b.StockChangeRate),
+			fmt.Sprintf("%.2f", b.ConvertPrice),
+			fmt.Sprintf("%.2f", b.ConvertValue),
+			fmt.Sprintf("%.2f%%", b.Premium),
+			percent(b.YTM),
+			years(b.YearsLeft),
+			listScale(b),
+			orDash(b.Rating),
+			fmt.Sprintf("%.2f", b.DoubleLow),
+		}, []tablewriter.Colors{{}, {}, {}, utils.ChangeColor(b.ChangeRate), {}, utils.ChangeColor(b.StockChangeRate), {}, {}, {}, {}, {}, {}, {}, {}})
+	}
+	table.Render()
+}
+
+func printDetail(out io.Writer, b *convertible.Bond) {
+	fmt.Fprintf(out, "转债代码\t%s\n转债名称\t%s\n最新价格\t%.3f (%+.2f%%)\n正股\t\t%s %s\n",
+		b.ExCode(), b.Name, b.Price, b.ChangeRate, b.StockExCode(), b.StockName)
+	fmt.Fprintf(out, "转股价\t\t%.2f\n转股价值\t%.2f\n转股溢价率\t%.2f%%\n纯债价值\t%.2f\n纯债溢价率\t%.2f%%\n",
+		b.ConvertPrice, b.ConvertValue, b.Premium, b.BondValue, b.BondPremium)
+	fmt.Fprintf(out, "到期收益率\t%s\n双低值\t\t%.2f\n", percent(b.YTM), b.DoubleLow)
+	fmt.Fprintf(out, "强赎触发价\t%s\n回售触发价\t%s\n到期赎回价\t%s\n",
+		price(b.CallTrigger), price(b.PutTrigger), price(b.RedeemPrice))
+	fmt.Fprintf(out, "信用评级\t%s\n发行规模\t%s\n剩余规模\t%s\n起息日\t\t%s\n到期日\t\t%s\n剩余年限\t%s\n",
+		orDash(b.Rating), scale(b.IssueScale), scale(b.Balance), date(b.ValueDate), date(b.ExpireDate), years(b.YearsLeft))
+	if len(b.Coupons) > 0 {
+		coupons := make([]string, len(b.Coupons))
+		for i, c := range b.Coupons {
+			coupons[i] = fmt.Sprintf("%.2f%%", c)
+		}
+		fmt.Fprintf(out, "票面利率\t%s\n", strings.Join(coupons, " "))
+	}
+}
+
+// printStockQuote prints the realtime quote of the underlying stock.
+func printStockQuote(out io.Writer, q *sina.SecurityQuote) {
+	var change float64
+	if q.YClose > 0 {
+		change = (q.Current/q.YClose - 1) * 100
+	}
+	fmt.Fprintf(out, "正股行情 %s %s %s %s\n", q.ExCode, q.Name, q.TradeDate, q.Time)
+	table := utils.NewTable(out, []string{"最新", "涨跌幅", "开盘", "最高", "最低", "昨收", "成交额"})
+	table.Rich([]string{
+		fmt.Sprintf("%.2f", q.Current),
+		fmt.Sprintf("%+.2f%%", change),
+		fmt.Sprintf("%.2f", q.Open),
+		fmt.Sprintf("%.2f", q.High),
+		fmt.Sprintf("%.2f", q.Low),
+		fmt.Sprintf("%.2f", q.YClose),
+		utils.HumanNum(q.Volume),
+	}, []tablewriter.Colors{utils.ChangeColor(change), utils.ChangeColor(change), {}, {}, {}, {}, {}})
+	table.Render()
+}
+
+// bondRecord 可转债的结构化输出，溢价率、YTM 和票面利率为百分数，规模单位为元。
+// 缺失的指标为空
+type bondRecord struct {
+	Code            string    `json:"code"`
+	ExCode          string    `json:"excode"`
+	Name            string    `json:"name"`
+	Price           float64   `json:"price"`
+	ChangeRate      float64   `json:"change_rate"`
+	StockCode       string    `json:"stock_code"`
+	StockExCode     string    `json:"stock_excode"`
+	StockName       string    `json:"stock_name"`
+	StockPrice      float64   `json:"stock_price"`
+	StockChangeRate float64   `json:"stock_change_rate"`
+	ConvertPrice    float64   `json:"convert_price"`
+	ConvertValue    float64   `json:"convert_value"`
+	PremiumRate     float64   `json:"premium_rate"` // 转股溢价率
+	BondValue       float64   `json:"bond_value"`
+	BondPremiumRate float64   `json:"bond_premium_rate"`
+	YTM             *float64  `json:"ytm"`
+	DoubleLow       float64   `json:"double_low"`
+	YearsLeft       *float64  `json:"years_left"`
+	Balance         *float64  `json:"balance"` // 剩余规模，未设置集思录 Cookie 时通常为空
+	IssueScale      *float64  `json:"issue_scale"`
+	Rating          string    `json:"rating"`
+	CallTrigger     *float64  `json:"call_trigger"`
+	PutTrigger      *float64  `json:"put_trigger"`
+	RedeemPrice     *float64  `json:"redeem_price"`
+	ValueDate       string    `json:"value_date"`
+	ExpireDate      string    `json:"expire_date"`
+	Coupons         []float64 `json:"coupons"`
+}
+
+func newBondRecord(b *convertible.Bond) bondRecord {
+	rec := bondRecord{
+		Code:            b.Code,
+		ExCode:          b.ExCode(),
+		Name:            b.Name,
+		Price:           b.Price,
+		ChangeRate:      b.ChangeRate,
+		StockCode:       b.StockCode,
+		StockExCode:     b.StockExCode(),
+		StockName:       b.StockName,
+		StockPrice:      b.StockPrice,
+		StockChangeRate: b.StockChangeRate,
+		ConvertPrice:    b.ConvertPrice,
+		ConvertValue:    b.ConvertValue,
+		PremiumRate:     b.Premium,
+		BondValue:       b.BondValue,
+		BondPremiumRate: b.BondPremium,
+		YTM:             output.Num(b.YTM),
+		DoubleLow:       b.DoubleLow,
+		Balance:         output.Num(b.Balance),
+		Rating:          b.Rating,
+		Coupons:         b.Coupons,
+	}
+	for dst, v := range map[**float64]float64{
+		&rec.YearsLeft:   b.YearsLeft,
+		&rec.IssueScale:  b.IssueScale,
+		&rec.CallTrigger: b.CallTrigger,
+		&rec.PutTrigger:  b.PutTrigger,
+		&rec.RedeemPrice: b.RedeemPrice,
+	} {
+		if v > 0 {
+			*dst = output.Num(v)
+		}
+	}
+	if !b.ValueDate.IsZero() {
+		rec.ValueDate = b.ValueDate.Format(time.DateOnly)
+	}
+	if !b.ExpireDate.IsZero() {
+		rec.ExpireDate = b.ExpireDate.Format(time.DateOnly)
+	}
+	if rec.Coupons == nil {
+		rec.Coupons = []float64{}
+	}
+	return rec
+}
+
+func bondRecords(list []*convertible.Bond) []bondRecord {
+	res := make([]bondRecord, 0, len(list))
+	for _, b := range list {
+		res = append(res, newBondRecord(b))
+	}
+	return res
+}
+
+func percent(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", v)
+}
+
+func price(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+func years(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+func scale(v float64) string {
+	if math.IsNaN(v) || v <= 0 {
+		return "-"
+	}
+	return utils.HumanNum(v)
+}
+
+// listScale returns the outstanding balance of b, or the issue size marked
+// with "*" when the balance is unknown.
+func listScale(b *convertible.Bond) string {
+	v, outstanding := b.Scale()
+	if s := scale(v); s != "-" && !outstanding {
+		return s + "*"
+	}
+	return scale(v)
+}
+
+func date(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.DateOnly)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
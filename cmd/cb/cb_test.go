@@ -0,0 +1,85 @@
+package cb
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/convertible"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintList(t *testing.T) {
+	list := []*convertible.Bond{
+		{
+			ConvertibleQuote: &eastmoney.ConvertibleQuote{Code: "113011", Name: "光大转债", Market: eastmoney.MarketTypeSse, Price: 105.2, ChangeRate: 0.5, StockName: "光大银行", Premium: 10.3, ConvertValue: 95.4},
+			Rating:           "AAA",
+			IssueScale:       3e10,
+			Balance:          6.2e9,
+			YearsLeft:        1.5,
+			YTM:              2.1,
+			DoubleLow:        115.5,
+		},
+		{
+			ConvertibleQuote: &eastmoney.ConvertibleQuote{Code: "127001", Name: "无评级转债", Price: 140},
+			Balance:          math.NaN(),
+			YTM:              math.NaN(),
+		},
+		{
+			ConvertibleQuote: &eastmoney.ConvertibleQuote{Code: "128001", Name: "发行规模转债", Price: 110},
+			IssueScale:       5e8,
+			Balance:          math.NaN(),
+			YTM:              math.NaN(),
+		},
+	}
+	var buf bytes.Buffer
+	printList(&buf, list)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "双低")
+	require.Contains(t, lines[1], "2.10%")
+	require.Contains(t, lines[1], "62.00亿")
+	// YTM、剩余年限、剩余规模、评级缺失时显示 "-"
+	fields := strings.Fields(lines[2])
+	require.Equal(t, []string{"-", "-", "-", "-"}, fields[len(fields)-5:len(fields)-1])
+	// 剩余规模未知时显示发行规模并标注 "*"
+	require.Contains(t, lines[3], "5.00亿*")
+
+	recs := bondRecords(list)
+	require.Equal(t, "SH113011", recs[0].ExCode)
+	require.Equal(t, 6.2e9, *recs[0].Balance)
+	require.Equal(t, 2.1, *recs[0].YTM)
+	require.Nil(t, recs[1].YTM)
+	require.Nil(t, recs[1].Balance)
+	require.Nil(t, recs[1].YearsLeft)
+	require.Equal(t, 5e8, *recs[2].IssueScale)
+	require.Empty(t, recs[2].ExpireDate)
+}
+
+func TestPrintDetail(t *testing.T) {
+	b := &convertible.Bond{
+		ConvertibleQuote: &eastmoney.ConvertibleQuote{Code: "113011", Name: "光大转债", Market: eastmoney.MarketTypeSse, StockCode: "601818", StockName: "光大银行", Price: 105.2, CallTrigger: 4.29},
+		ExpireDate:       time.Date(2027, 3, 16, 0, 0, 0, 0, time.Local),
+		Coupons:          []float64{0.2, 0.5},
+		YTM:              math.NaN(),
+	}
+	var buf bytes.Buffer
+	printDetail(&buf, b)
+	out := buf.String()
+	require.Contains(t, out, "SH113011")
+	require.Contains(t, out, "SH601818 光大银行")
+	require.Contains(t, out, "强赎触发价\t4.29")
+	require.Contains(t, out, "回售触发价\t-")
+	require.Contains(t, out, "到期日\t\t2027-03-16")
+	require.Contains(t, out, "0.20% 0.50%")
+
+	rec := newBondRecord(b)
+	require.Equal(t, "SH601818", rec.StockExCode)
+	require.Equal(t, 4.29, *rec.CallTrigger)
+	require.Nil(t, rec.PutTrigger)
+	require.Equal(t, "2027-03-16", rec.ExpireDate)
+	require.Equal(t, []float64{0.2, 0.5}, rec.Coupons)
+}
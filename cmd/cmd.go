@@ -6,18 +6,39 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/alwqx/sec/cmd/alert"
 	"github.com/alwqx/sec/cmd/announcements"
+	"github.com/alwqx/sec/cmd/backtest"
 	"github.com/alwqx/sec/cmd/balancesheet"
 	"github.com/alwqx/sec/cmd/bond"
+	"github.com/alwqx/sec/cmd/breadth"
+	"github.com/alwqx/sec/cmd/cache"
+	"github.com/alwqx/sec/cmd/cb"
+	"github.com/alwqx/sec/cmd/compare"
+	"github.com/alwqx/sec/cmd/connect"
+	"github.com/alwqx/sec/cmd/dashboard"
+	"github.com/alwqx/sec/cmd/flow"
+	"github.com/alwqx/sec/cmd/fund"
+	"github.com/alwqx/sec/cmd/fx"
+	"github.com/alwqx/sec/cmd/holders"
+	"github.com/alwqx/sec/cmd/index"
 	"github.com/alwqx/sec/cmd/insider"
 	"github.com/alwqx/sec/cmd/ipo"
 	"github.com/alwqx/sec/cmd/kline"
+	"github.com/alwqx/sec/cmd/lhb"
+	"github.com/alwqx/sec/cmd/margin"
 	"github.com/alwqx/sec/cmd/metal"
+	"github.com/alwqx/sec/cmd/portfolio"
 	"github.com/alwqx/sec/cmd/quote"
+	"github.com/alwqx/sec/cmd/screen"
+	"github.com/alwqx/sec/cmd/sector"
 	"github.com/alwqx/sec/cmd/strategy"
 	"github.com/alwqx/sec/cmd/upgrade"
 	"github.com/alwqx/sec/cmd/valuation"
 	"github.com/alwqx/sec/cmd/watch"
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/eastmoney"
 	"github.com/alwqx/sec/provider/sina"
 	"github.com/alwqx/sec/types"
 	"github.com/alwqx/sec/utils"
@@ -35,7 +56,7 @@ func NewCLI() *cobra.Command {
 		CompletionOptions: cobra.CompletionOptions{
 			DisableDefaultCmd: true,
 		},
-		PersistentPreRun: debugHandler,
+		PersistentPreRunE: preRunHandler,
 		Run: func(cmd *cobra.Command, args []string) {
 			if version, _ := cmd.Flags().GetBool("version"); version {
 				versionHandler(cmd, args)
@@ -48,6 +69,8 @@ func NewCLI() *cobra.Command {
 
 	rootCmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
 	rootCmd.Flags().BoolP("version", "v", false, "Show version information")
+	output.AddFlag(rootCmd)
+	provider.AddFlag(rootCmd)
 
 	searchCmd := &cobra.Command{
 		Use:     "search",
@@ -67,6 +90,7 @@ func NewCLI() *cobra.Command {
 	}
 	infoCmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
 	infoCmd.Flags().BoolP("dividends", "d", false, "show dividend info")
+	output.Enable(searchCmd, infoCmd)
 
 	rootCmd.AddCommand(
 		searchCmd, infoCmd,
@@ -83,6 +107,24 @@ func NewCLI() *cobra.Command {
 		announcements.NewAnnouncementsCLI(),
 		insider.NewInsiderCLI(),
 		ipo.NewIPOCLI(),
+		cache.NewCacheCLI(),
+		backtest.NewBacktestCLI(),
+		screen.NewScreenCLI(),
+		compare.NewCompareCLI(),
+		portfolio.NewPortfolioCLI(),
+		alert.NewAlertCLI(),
+		dashboard.NewDashboardCLI(),
+		index.NewIndexCLI(),
+		breadth.NewBreadthCLI(),
+		connect.NewConnectCLI(),
+		sector.NewSectorCLI(),
+		flow.NewFlowCLI(),
+		lhb.NewLHBCLI(),
+		margin.NewMarginCLI(),
+		holders.NewHoldersCLI(),
+		fund.NewFundCLI(),
+		cb.NewCBCLI(),
+		fx.NewFXCLI(),
 	)
 
 	return rootCmd
@@ -96,6 +138,16 @@ func versionHandler(cmd *cobra.Command, _ []string) {
 	fmt.Printf("  git commit: %s\n", version.GitCommit)
 }
 
+// preRunHandler runs before every command: it sets debug mode and validates
+// the global --source and --output flags.
+func preRunHandler(cmd *cobra.Command, args []string) error {
+	debugHandler(cmd, args)
+	if _, err := provider.FromFlags(cmd); err != nil {
+		return err
+	}
+	return output.Check(cmd)
+}
+
 // debugHandler set debug mode
 func debugHandler(cmd *cobra.Command, args []string) {
 	if debug, _ := cmd.Flags().GetBool("debug"); debug {
@@ -105,11 +157,37 @@ func debugHandler(cmd *cobra.Command, args []string) {
 
 func SearchHandler(cmd *cobra.Command, args []string) error {
 	secs := sina.Search(cmd.Context(), args[0])
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, secRecords(secs))
+	}
 	printSecs(cmd.OutOrStdout(), secs)
 
 	return nil
 }
 
+// secRecord 搜索结果的结构化输出
+type secRecord struct {
+	Code     string `json:"code"`
+	ExCode   string `json:"excode"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Exchange string `json:"exchange"`
+}
+
+func secRecords(secs []*sina.BasicSecurity) []secRecord {
+	res := make([]secRecord, 0, len(secs))
+	for _, sec := range secs {
+		res = append(res, secRecord{
+			Code:     sec.Code,
+			ExCode:   sec.ExCode,
+			Name:     sec.Name,
+			Type:     string(sec.SecurityType),
+			Exchange: sec.ExChange,
+		})
+	}
+	return res
+}
+
 func InfoHandler(cmd *cobra.Command, args []string) error {
 	opts := new(types.InfoOptions)
 
@@ -135,24 +213,130 @@ func InfoHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "证券代码\t%s\n简称历史\t%s\n公司名称\t%s\n上市日期\t%s\n发行价格\t%.2f\n行业分类\t%s\n主营业务\t%s\n办公地址\t%s\n公司网址\t%s\n当前价格\t%.2f\n市净率PB\t%.2f\n市盈率TTM\t%.2f\n总市值  \t%s\n流通市值\t%s\n",
-		sec.ExCode, profile.HistoryName, profile.Name, profile.ListingDate, profile.ListingPrice,
-		profile.Category, profile.MainBusiness, profile.BusinessAddress, profile.WebSite,
-		profile.Current, profile.PB, profile.PeTTM, utils.HumanNum(profile.MarketCap), utils.HumanNum(profile.TradedMarketCap))
+	// 所属板块仅支持沪深 A 股
+	var boards []string
+	var market eastmoney.MarketType = -1
+	switch sec.ExChange {
+	case "sh":
+		market = eastmoney.MarketTypeSse
+	case "sz":
+		market = eastmoney.MarketTypeSzSe
+	}
+	if market >= 0 {
+		list, err := eastmoney.QueryStockBoards(cmd.Context(), market, sec.Code)
+		if err != nil {
+			slog.Warn("failed query boards", "code", sec.Code, "error", err)
+		}
+		for _, b := range list {
+			boards = append(boards, b.Name)
+		}
+	}
 
+	var dids []sina.Dividend
 	if opts.Dividend {
-		dids, err := sina.QueryDividends(cmd.Context(), opts.Code)
+		dids, err = sina.QueryDividends(cmd.Context(), opts.Code)
 		if err != nil {
 			slog.Error("failed query dividends", "code", opts.Code, "error", err)
-		} else {
-			fmt.Fprintln(cmd.OutOrStdout())
-			printDividends(cmd.OutOrStdout(), dids)
+			opts.Dividend = false
 		}
 	}
 
+	out := cmd.OutOrStdout()
+	if f := output.FormatOf(cmd); f.Structured() {
+		rec := newInfoRecord(sec, profile, boards)
+		if opts.Dividend {
+			rec.Dividends = dividendRecords(dids)
+		}
+		return output.Write(out, f, rec)
+	}
+
+	fmt.Fprintf(out, "证券代码\t%s\n简称历史\t%s\n公司名称\t%s\n上市日期\t%s\n发行价格\t%.2f\n行业分类\t%s\n主营业务\t%s\n办公地址\t%s\n公司网址\t%s\n当前价格\t%.2f\n市净率PB\t%.2f\n市盈率TTM\t%.2f\n总市值  \t%s\n流通市值\t%s\n",
+		sec.ExCode, profile.HistoryName, profile.Name, profile.ListingDate, profile.ListingPrice,
+		profile.Category, profile.MainBusiness, profile.BusinessAddress, profile.WebSite,
+		profile.Current, profile.PB, profile.PeTTM, utils.HumanNum(profile.MarketCap), utils.HumanNum(profile.TradedMarketCap))
+	if len(boards) > 0 {
+		fmt.Fprintf(out, "所属板块\t%s\n", strings.Join(boards, " "))
+	}
+	if opts.Dividend {
+		fmt.Fprintln(out)
+		printDividends(out, dids)
+	}
+
 	return nil
 }
 
+// infoRecord 证券基本信息的结构化输出
+type infoRecord struct {
+	Code         string           `json:"code"`
+	ExCode       string           `json:"excode"`
+	Name         string           `json:"name"`         // 证券简称
+	CorpName     string           `json:"corp_name"`    // 公司名称
+	HistoryName  string           `json:"history_name"` // 简称历史
+	ListingDate  string           `json:"listing_date"`
+	ListingPrice float64          `json:"listing_price"`
+	Industry     string           `json:"industry"`
+	MainBusiness string           `json:"main_business"`
+	Address      string           `json:"address"`
+	Website      string           `json:"website"`
+	Price        float64          `json:"price"`
+	PB           float64          `json:"pb"`
+	PETTM        float64          `json:"pe_ttm"`
+	MktCap       float64          `json:"mktcap"`        // 总市值，单位：元
+	TradedMktCap float64          `json:"traded_mktcap"` // 流通市值，单位：元
+	Boards       []string         `json:"boards"`
+	Dividends    []dividendRecord `json:"dividends,omitempty"` // 仅 --dividends 时输出
+}
+
+// dividendRecord 分红送配，送股、转增和派息均为每 10 股
+type dividendRecord struct {
+	PublicDate string  `json:"public_date"`
+	Shares     float64 `json:"shares"`
+	AddShares  float64 `json:"add_shares"`
+	Bonus      float64 `json:"bonus"`
+	ExDate     string  `json:"ex_date"`
+	RecordDate string  `json:"record_date"`
+}
+
+func newInfoRecord(sec *sina.BasicSecurity, profile *sina.CorpProfile, boards []string) *infoRecord {
+	if boards == nil {
+		boards = []string{}
+	}
+	return &infoRecord{
+		Code:         sec.Code,
+		ExCode:       sec.ExCode,
+		Name:         sec.Name,
+		CorpName:     profile.Name,
+		HistoryName:  profile.HistoryName,
+		ListingDate:  profile.ListingDate,
+		ListingPrice: profile.ListingPrice,
+		Industry:     profile.Category,
+		MainBusiness: profile.MainBusiness,
+		Address:      profile.BusinessAddress,
+		Website:      profile.WebSite,
+		Price:        profile.Current,
+		PB:           profile.PB,
+		PETTM:        profile.PeTTM,
+		MktCap:       profile.MarketCap,
+		TradedMktCap: profile.TradedMarketCap,
+		Boards:       boards,
+	}
+}
+
+func dividendRecords(dids []sina.Dividend) []dividendRecord {
+	res := make([]dividendRecord, 0, len(dids))
+	for _, did := range dids {
+		res = append(res, dividendRecord{
+			PublicDate: did.PublicDate,
+			Shares:     did.Shares,
+			AddShares:  did.AddShares,
+			Bonus:      did.Bonus,
+			ExDate:     did.DividendedDate,
+			RecordDate: did.RecordDate,
+		})
+	}
+	return res
+}
+
 func printSecs(out io.Writer, secs []*sina.BasicSecurity) {
 	num := len(secs)
 	if num == 0 {
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/types"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPrintSecs(t *testing.T) {
@@ -56,3 +60,34 @@ func TestVersionHandler(t *testing.T) {
 	// version.Version=
 	versionHandler(nil, nil)
 }
+
+func TestSecRecords(t *testing.T) {
+	require.Empty(t, secRecords(nil))
+
+	secs := []*sina.BasicSecurity{
+		{Code: "688047", ExCode: "SH688047", Name: "龙芯中科", SecurityType: types.SecurityTypeStock, ExChange: "sh"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, output.Write(&buf, output.FormatCSV, secRecords(secs)))
+	require.Equal(t, "code,excode,name,type,exchange\n688047,SH688047,龙芯中科,stock,sh\n", buf.String())
+}
+
+func TestInfoRecord(t *testing.T) {
+	sec := &sina.BasicSecurity{Code: "600036", ExCode: "SH600036", Name: "招商银行"}
+	profile := &sina.CorpProfile{Name: "招商银行股份有限公司", Current: 39.12, PB: 0.98, MarketCap: 9.8e11}
+	rec := newInfoRecord(sec, profile, nil)
+	require.Equal(t, "SH600036", rec.ExCode)
+	require.Equal(t, "招商银行股份有限公司", rec.CorpName)
+	require.Equal(t, 39.12, rec.Price)
+	require.NotNil(t, rec.Boards)
+
+	var buf bytes.Buffer
+	require.NoError(t, output.Write(&buf, output.FormatJSON, rec))
+	require.Contains(t, buf.String(), `"boards": []`)
+	require.NotContains(t, buf.String(), `"dividends"`)
+
+	rec.Dividends = dividendRecords([]sina.Dividend{
+		{PublicDate: "2024-07-04", RecordDate: "2024-07-10", DividendedDate: "2024-07-11", Bonus: 19.38},
+	})
+	require.Equal(t, dividendRecord{PublicDate: "2024-07-04", Bonus: 19.38, ExDate: "2024-07-11", RecordDate: "2024-07-10"}, rec.Dividends[0])
+}
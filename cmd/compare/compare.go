@@ -0,0 +1,339 @@
+// Package compare implements the sec compare command, which shows valuation
+// metrics of several securities side by side.
+//
+//	sec compare 600036 601398 601939
+//	sec compare 600036 601398 --export banks.csv
+//	sec compare 600036 601398 --output json
+package compare
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alwqx/sec/cmd/valuation"
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// Item 单只证券的对比指标
+type Item struct {
+	Code      string  `json:"code"`
+	ExCode    string  `json:"excode"`
+	Name      string  `json:"name"`
+	Price     float64 `json:"price"`
+	MktCap    float64 `json:"mktcap"` // 总市值，单位：亿元
+	PE        float64 `json:"pe"`
+	PB        float64 `json:"pb"`
+	PS        float64 `json:"ps"`
+	ROE       float64 `json:"roe"`        // %
+	DY        float64 `json:"dy"`         // 近 12 个月股息率 %
+	RevGrowth float64 `json:"rev_growth"` // 营收同比增速 %
+	Graham    float64 `json:"graham"`     // 格雷厄姆数
+}
+
+// metric 对比表中的一行
+type metric struct {
+	name   string
+	key    string
+	value  func(it *Item) float64
+	format func(v float64) string
+	// score 用于比较优劣的值，0 表示不参与比较；为 nil 时使用 value
+	score func(it *Item) float64
+	// better 1 表示越大越好，-1 表示越小越好，0 表示不比较
+	better int
+	// signed 为 true 时负值有效（如营收负增长），否则只比较正值
+	signed bool
+}
+
+var metrics = []metric{
+	{name: "现价", key: "price", value: func(it *Item) float64 { return it.Price }, format: ff},
+	{name: "总市值(亿)", key: "mktcap", value: func(it *Item) float64 { return it.MktCap }, format: ff, better: 1},
+	{name: "市盈率 PE", key: "pe", value: func(it *Item) float64 { return it.PE }, format: ff, better: -1},
+	{name: "市净率 PB", key: "pb", value: func(it *Item) float64 { return it.PB }, format: ff, better: -1},
+	{name: "市销率 PS", key: "ps", value: func(it *Item) float64 { return it.PS }, format: ff, better: -1},
+	{name: "ROE", key: "roe", value: func(it *Item) float64 { return it.ROE }, format: fpct, better: 1},
+	{name: "股息率", key: "dy", value: func(it *Item) float64 { return it.DY }, format: fpct, better: 1},
+	{name: "营收增速", key: "rev_growth", value: func(it *Item) float64 { return it.RevGrowth }, format: fpct, better: 1, signed: true},
+	{
+		name: "格雷厄姆数", key: "graham", value: func(it *Item) float64 { return it.Graham }, format: ff, better: 1,
+		// 格雷厄姆数相对股价越高，安全边际越大
+		score: func(it *Item) float64 {
+			if it.Graham <= 0 || it.Price <= 0 {
+				return 0
+			}
+			return it.Graham / it.Price
+		},
+	},
+}
+
+// NewCompareCLI returns the compare command.
+func NewCompareCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "compare <codes...>",
+		Aliases:       []string{"cmp"},
+		Short:         "Compare valuation metrics of multiple securities",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.RangeArgs(2, sina.MAX_KEY_NUM),
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		RunE: runCompare,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().String("export", "", "Export to file (.csv, .tsv, .json or .md)")
+	output.Enable(cmd)
+	return cmd
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	secs := provider.MultiSearch(cmd.Context(), src.Searcher, args)
+	if len(secs) < 2 {
+		return fmt.Errorf("至少需要 2 只有效证券，找到 %d 只", len(secs))
+	}
+
+	var (
+		items = make([]*Item, len(secs))
+		errs  = make([]error, len(secs))
+		wg    sync.WaitGroup
+	)
+	for i, sec := range secs {
+		wg.Add(1)
+		go func(i int, sec *sina.BasicSecurity) {
+			defer wg.Done()
+			items[i], errs[i] = fetchItem(cmd, sec)
+		}(i, sec)
+	}
+	wg.Wait()
+
+	valid := make([]*Item, 0, len(items))
+	for i, it := range items {
+		if errs[i] != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s 获取数据失败: %v\n", secs[i].ExCode, errs[i])
+			continue
+		}
+		valid = append(valid, it)
+	}
+	if len(valid) == 0 {
+		return fmt.Errorf("全部证券获取数据失败")
+	}
+
+	out, f := cmd.OutOrStdout(), output.FormatOf(cmd)
+	if path, _ := cmd.Flags().GetString("export"); path != "" {
+		if err := output.WriteFile(path, compareRecords(valid)); err != nil {
+			return err
+		}
+		// 结构化输出时 stdout 只有结果
+		if f.Structured() {
+			fmt.Fprintf(cmd.ErrOrStderr(), "已导出到 %s\n", path)
+		} else {
+			fmt.Fprintf(out, "已导出到 %s\n", path)
+		}
+	}
+	if f.Structured() {
+		return output.Write(out, f, compareRecords(valid))
+	}
+
+	printCompare(out, valid)
+	return nil
+}
+
+// fetchItem fetches valuation metrics and dividends of sec concurrently.
+func fetchItem(cmd *cobra.Command, sec *sina.BasicSecurity) (*Item, error) {
+	var (
+		m      *valuation.Metrics
+		dids   []sina.Dividend
+		mErr   error
+		divErr error
+		wg     sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m, mErr = valuation.FetchMetrics(cmd.Context(), sec.Code, sec.ExCode, sec.Name)
+	}()
+	go func() {
+		defer wg.Done()
+		dids, divErr = sina.QueryDividends(cmd.Context(), sec.Code)
+	}()
+	wg.Wait()
+
+	if mErr != nil {
+		return nil, mErr
+	}
+	if divErr != nil {
+		slog.WarnContext(cmd.Context(), "failed query dividends", "code", sec.ExCode, "error", divErr)
+	}
+	return newItem(sec, m, valuation.TrailingDPS(dids, time.Now())), nil
+}
+
+func newItem(sec *sina.BasicSecurity, m *valuation.Metrics, dps float64) *Item {
+	it := &Item{
+		Code:      sec.Code,
+		ExCode:    sec.ExCode,
+		Name:      sec.Name,
+		Price:     m.Price,
+		MktCap:    m.MktCap / 1e8,
+		PE:        m.PE,
+		PB:        m.PB,
+		PS:        m.PS,
+		ROE:       m.ROE,
+		RevGrowth: m.RevGrowth,
+		Graham:    m.Graham,
+	}
+	if dps > 0 && m.Price > 0 {
+		it.DY = dps / m.Price * 100
+	}
+	return it
+}
+
+// rank returns the indexes of the best and worst item of a metric, or -1 when
+// fewer than two items have a valid score. Zero scores are missing data and
+// negative ones are invalid unless the metric is signed, e.g. PE of a
+// loss-making company.
+func rank(items []*Item, m metric) (best, worst int) {
+	best, worst = -1, -1
+	if m.better == 0 {
+		return
+	}
+	score := m.score
+	if score == nil {
+		score = m.value
+	}
+
+	var bestV, worstV float64
+	n := 0
+	for i, it := range items {
+		v := score(it)
+		if v == 0 || (v < 0 && !m.signed) {
+			continue
+		}
+		n++
+		if best < 0 || float64(m.better)*(v-bestV) > 0 {
+			best, bestV = i, v
+		}
+		if worst < 0 || float64(m.better)*(v-worstV) < 0 {
+			worst, worstV = i, v
+		}
+	}
+	if n < 2 || bestV == worstV {
+		return -1, -1
+	}
+	return
+}
+
+func printCompare(out io.Writer, items []*Item) {
+	headers := make([]string, 0, len(items)+1)
+	headers = append(headers, "指标")
+	for _, it := range items {
+		headers = append(headers, it.Name)
+	}
+
+	fmt.Fprintf(out, "\n")
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+
+	codeRow := []string{"代码"}
+	for _, it := range items {
+		codeRow = append(codeRow, it.ExCode)
+	}
+	table.Append(codeRow)
+
+	for _, m := range metrics {
+		row := make([]string, 0, len(items)+1)
+		row = append(row, m.name)
+		for _, it := range items {
+			row = append(row, m.format(m.value(it)))
+		}
+
+		colors := make([]tablewriter.Colors, len(row))
+		if best, worst := rank(items, m); best >= 0 {
+			colors[best+1] = tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold}
+			colors[worst+1] = tablewriter.Colors{tablewriter.FgGreenColor, tablewriter.Bold}
+		}
+		table.Rich(row, colors)
+	}
+	table.Render()
+	fmt.Fprintf(out, "\n红色为该项最优，绿色为该项最差；格雷厄姆数按相对股价的安全边际比较\n\n")
+}
+
+// compareRecord 对比结果的结构化输出，每只证券一条，缺失的指标为空
+type compareRecord struct {
+	Code      string   `json:"code"`
+	ExCode    string   `json:"excode"`
+	Name      string   `json:"name"`
+	Price     *float64 `json:"price"`
+	MarketCap *float64 `json:"market_cap"` // 总市值，单位元
+	PE        *float64 `json:"pe"`
+	PB        *float64 `json:"pb"`
+	PS        *float64 `json:"ps"`
+	ROE       *float64 `json:"roe"`        // %
+	DY        *float64 `json:"dy"`         // 近 12 个月股息率 %
+	RevGrowth *float64 `json:"rev_growth"` // 营收同比增速 %
+	Graham    *float64 `json:"graham"`     // 格雷厄姆数
+}
+
+// compareRecords converts items. Zero metrics are missing, and so are
+// non-positive PE, PB and PS as in rank.
+func compareRecords(items []*Item) []compareRecord {
+	positive := func(v float64) *float64 {
+		if v <= 0 {
+			return nil
+		}
+		return &v
+	}
+	res := make([]compareRecord, 0, len(items))
+	for _, it := range items {
+		res = append(res, compareRecord{
+			Code:      it.Code,
+			ExCode:    it.ExCode,
+			Name:      it.Name,
+			Price:     output.NonZero(it.Price),
+			MarketCap: output.NonZero(it.MktCap * 1e8),
+			PE:        positive(it.PE),
+			PB:        positive(it.PB),
+			PS:        positive(it.PS),
+			ROE:       output.NonZero(it.ROE),
+			DY:        output.NonZero(it.DY),
+			RevGrowth: output.NonZero(it.RevGrowth),
+			Graham:    output.NonZero(it.Graham),
+		})
+	}
+	return res
+}
+
+func ff(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+func fpct(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", v)
+}
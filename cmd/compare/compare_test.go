@@ -0,0 +1,103 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alwqx/sec/cmd/valuation"
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
+)
+
+func testItems() []*Item {
+	return []*Item{
+		{Code: "600036", ExCode: "SH600036", Name: "招商银行", Price: 40, PE: 6.5, PB: 0.95, ROE: 14, RevGrowth: -2, Graham: 60},
+		{Code: "601398", ExCode: "SH601398", Name: "工商银行", Price: 7, PE: 6.8, PB: 0.6, ROE: 10, RevGrowth: -5, Graham: 12},
+		{Code: "601939", ExCode: "SH601939", Name: "建设银行", Price: 9, PE: 0, PB: 0.65, ROE: 11, RevGrowth: 1, Graham: 10},
+	}
+}
+
+func metricByKey(key string) metric {
+	for _, m := range metrics {
+		if m.key == key {
+			return m
+		}
+	}
+	panic(key)
+}
+
+func TestRank(t *testing.T) {
+	items := testItems()
+	tests := []struct {
+		key         string
+		best, worst int
+	}{
+		{"price", -1, -1},    // not compared
+		{"pe", 0, 1},         // lower is better, zero PE skipped
+		{"pb", 1, 0},         // lower is better
+		{"roe", 0, 1},        // higher is better
+		{"rev_growth", 2, 1}, // negative growth is valid
+		{"graham", 1, 2},     // graham/price: 1.5, 1.71, 1.11
+		{"dy", -1, -1},       // all missing
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			best, worst := rank(items, metricByKey(tt.key))
+			require.Equal(t, tt.best, best)
+			require.Equal(t, tt.worst, worst)
+		})
+	}
+
+	// equal values have no best or worst
+	items[1].ROE = 14
+	items[2].ROE = 14
+	best, worst := rank(items, metricByKey("roe"))
+	require.Equal(t, -1, best)
+	require.Equal(t, -1, worst)
+}
+
+func TestNewItem(t *testing.T) {
+	sec := &sina.BasicSecurity{Code: "600036", ExCode: "SH600036", Name: "招商银行"}
+	m := &valuation.Metrics{Price: 40, MktCap: 1e11, PE: 6.5, RevGrowth: 3.2}
+	it := newItem(sec, m, 2)
+	require.Equal(t, 1000.0, it.MktCap)
+	require.InDelta(t, 5.0, it.DY, 1e-9)
+	require.Equal(t, 3.2, it.RevGrowth)
+
+	require.Zero(t, newItem(sec, m, 0).DY)
+}
+
+func TestPrintCompare(t *testing.T) {
+	var buf bytes.Buffer
+	printCompare(&buf, testItems())
+	out := buf.String()
+	for _, s := range []string{"招商银行", "SH601939", "市盈率 PE", "格雷厄姆数", "-5.0%"} {
+		require.Contains(t, out, s)
+	}
+}
+
+func TestCompareRecords(t *testing.T) {
+	items := testItems()
+	items[0].MktCap = 1000
+	items[1].PB = -0.5
+	recs := compareRecords(items)
+	require.Len(t, recs, 3)
+	require.Equal(t, 1e11, *recs[0].MarketCap)
+	require.Equal(t, -2.0, *recs[0].RevGrowth)
+	require.Nil(t, recs[1].PB)
+	require.Nil(t, recs[2].PE)
+
+	path := filepath.Join(t.TempDir(), "cmp.json")
+	require.NoError(t, output.WriteFile(path, recs))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Len(t, got, 3)
+	require.Equal(t, "SH601939", got[2]["excode"])
+	require.Nil(t, got[2]["pe"])
+}
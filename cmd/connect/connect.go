@@ -0,0 +1,488 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/render"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewConnectCLI returns the connect command.
+func NewConnectCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "connect [code]",
+		Aliases: []string{"hsgt"},
+		Short:   "Print northbound and southbound Stock Connect flows",
+		Long: `Print daily net buying of Shanghai/Shenzhen-Hong Kong Stock Connect in both
+directions. With a code, print the northbound holdings of that stock.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: runConnect,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().BoolP("intraday", "i", false, "Show today's minute net inflows")
+	cmd.Flags().Bool("history", false, "Chart daily and cumulative net buying")
+	cmd.Flags().StringP("direction", "d", "north", "Flow direction for --intraday and --history: north, south")
+	cmd.Flags().IntP("days", "n", 0, "Number of trading days, default 10, or 120 with --history")
+	cmd.Flags().Int("height", 8, "Chart height in rows")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runConnect(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	f := output.FormatOf(cmd)
+
+	if len(args) == 1 {
+		return runHoldings(ctx, out, f, args[0])
+	}
+
+	direction, _ := cmd.Flags().GetString("direction")
+	var north bool
+	switch strings.ToLower(direction) {
+	case "north", "n", "北向":
+		north = true
+	case "south", "s", "南向":
+	default:
+		return fmt.Errorf("invalid --direction %q: expected north or south", direction)
+	}
+	height, _ := cmd.Flags().GetInt("height")
+	if height <= 0 {
+		return fmt.Errorf("invalid height %d: must be > 0", height)
+	}
+	days, _ := cmd.Flags().GetInt("days")
+	if days < 0 {
+		return fmt.Errorf("invalid --days %d: must be > 0", days)
+	}
+
+	if intraday, _ := cmd.Flags().GetBool("intraday"); intraday {
+		data, err := eastmoney.QueryConnectIntraday(ctx)
+		if err != nil {
+			return err
+		}
+		if f.Structured() {
+			return output.Write(out, f, minuteRecords(data))
+		}
+		return printIntraday(out, data, north, height)
+	}
+
+	if history, _ := cmd.Flags().GetBool("history"); history {
+		if days == 0 {
+			days = 120
+		}
+		ch := eastmoney.ChannelSouth
+		if north {
+			ch = eastmoney.ChannelNorth
+		}
+		flows, err := eastmoney.QueryConnectHistory(ctx, ch, lookback(days))
+		if err != nil {
+			return err
+		}
+		if len(flows) > days {
+			flows = flows[len(flows)-days:]
+		}
+		if f.Structured() {
+			return output.Write(out, f, historyRecords(ch, flows))
+		}
+		return printHistory(out, ch, flows, height)
+	}
+
+	if days == 0 {
+		days = 10
+	}
+	channels := append(eastmoney.NorthChannels(), eastmoney.SouthChannels()...)
+	flows, err := queryChannels(ctx, channels, lookback(days))
+	if err != nil {
+		return err
+	}
+	rows := alignFlows(flows, days)
+	if f.Structured() {
+		return output.Write(out, f, dailyRecords(channels, rows))
+	}
+	printDaily(out, channels, rows)
+	return nil
+}
+
+// lookback returns a begin date covering about days trading days.
+func lookback(days int) time.Time {
+	return time.Now().AddDate(0, 0, -days*7/5-15)
+}
+
+func queryChannels(ctx context.Context, channels []eastmoney.ConnectChannel, begin time.Time) (map[eastmoney.ConnectChannel][]*eastmoney.ConnectFlow, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		res      = make(map[eastmoney.ConnectChannel][]*eastmoney.ConnectFlow, len(channels))
+	)
+	for _, ch := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			flows, err := eastmoney.QueryConnectHistory(ctx, ch, begin)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("获取%s成交失败: %w", ch, err)
+				}
+				return
+			}
+			res[ch] = flows
+		}()
+	}
+	wg.Wait()
+	return res, firstErr
+}
+
+// flowRow 某一交易日各通道的净买额
+type flowRow struct {
+	Date time.Time
+	Net  map[eastmoney.ConnectChannel]float64
+}
+
+// alignFlows merges the flows of each channel by date and returns the latest
+// days rows, newest first.
+func alignFlows(flows map[eastmoney.ConnectChannel][]*eastmoney.ConnectFlow, days int) []flowRow {
+	byDate := make(map[time.Time]*flowRow)
+	for ch, list := range flows {
+		for _, f := range list {
+			row, ok := byDate[f.Date]
+			if !ok {
+				row = &flowRow{Date: f.Date, Net: make(map[eastmoney.ConnectChannel]float64)}
+				byDate[f.Date] = row
+			}
+			row.Net[ch] = f.NetBuy
+		}
+	}
+	rows := make([]flowRow, 0, len(byDate))
+	for _, row := range byDate {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Date.After(rows[j].Date) })
+	if len(rows) > days {
+		rows = rows[:days]
+	}
+	return rows
+}
+
+func printDaily(out io.Writer, channels []eastmoney.ConnectChannel, rows []flowRow) {
+	headers := []string{"日期"}
+	for _, ch := range channels {
+		headers = append(headers, ch.String())
+	}
+	table := utils.NewTable(out, headers)
+	for _, row := range rows {
+		cells := []string{row.Date.Format(time.DateOnly)}
+		styles := []tablewriter.Colors{{}}
+		for _, ch := range channels {
+			v, ok := row.Net[ch]
+			if !ok {
+				cells = append(cells, "-")
+				styles = append(styles, tablewriter.Colors{})
+				continue
+			}
+			cells = append(cells, yi(v))
+			styles = append(styles, utils.ChangeColor(v))
+		}
+		table.Rich(cells, styles)
+	}
+	fmt.Fprintln(out, "沪深港通成交净买额（亿元）")
+	table.Render()
+}
+
+// sampleMinutes keeps every step-th minute and the latest one.
+func sampleMinutes(mins []*eastmoney.ConnectMinute, step int) []*eastmoney.ConnectMinute {
+	if step <= 1 || len(mins) == 0 {
+		return mins
+	}
+	res := make([]*eastmoney.ConnectMinute, 0, len(mins)/step+1)
+	for i := 0; i < len(mins); i += step {
+		res = append(res, mins[i])
+	}
+	if last := mins[len(mins)-1]; res[len(res)-1] != last {
+		res = append(res, last)
+	}
+	return res
+}
+
+func printIntraday(out io.Writer, data *eastmoney.ConnectIntraday, north bool, height int) error {
+	table := utils.NewTable(out, []string{"方向", "时间", "沪", "深", "合计"})
+	for _, d := range []struct {
+		name string
+		mins []*eastmoney.ConnectMinute
+	}{{"北向", data.North}, {"南向", data.South}} {
+		if len(d.mins) == 0 {
+			table.Append([]string{d.name, "-", "-", "-", "-"})
+			continue
+		}
+		last := d.mins[len(d.mins)-1]
+		table.Rich([]string{d.name, last.Time.Format("15:04"), yi(last.Shanghai), yi(last.Shenzhen), yi(last.Total)},
+			[]tablewriter.Colors{{}, {}, utils.ChangeColor(last.Shanghai), utils.ChangeColor(last.Shenzhen), utils.ChangeColor(last.Total)})
+	}
+	fmt.Fprintln(out, "沪深港通当日累计净流入（亿元）")
+	table.Render()
+
+	mins, title := data.North, "北向累计净流入（亿元）"
+	if !north {
+		mins, title = data.South, "南向累计净流入（亿元）"
+	}
+	if len(mins) == 0 {
+		return nil
+	}
+	bars := make([]render.Bar, 0, len(mins))
+	for _, m := range sampleMinutes(mins, 5) {
+		bars = append(bars, render.Bar{Date: m.Time, Value: m.Total / 1e8})
+	}
+	fmt.Fprintln(out)
+	return render.RenderBars(out, bars, render.BarConfig{Height: height, Title: title})
+}
+
+// cumulative returns the running sum of net buying over flows.
+func cumulative(flows []*eastmoney.ConnectFlow) []float64 {
+	res := make([]float64, len(flows))
+	sum := 0.0
+	for i, f := range flows {
+		sum += f.NetBuy
+		res[i] = sum
+	}
+	return res
+}
+
+func printHistory(out io.Writer, ch eastmoney.ConnectChannel, flows []*eastmoney.ConnectFlow, height int) error {
+	if len(flows) == 0 {
+		fmt.Fprintf(out, "暂无%s成交数据\n", ch)
+		return nil
+	}
+	cum := cumulative(flows)
+	maxIn, maxOut := flows[0], flows[0]
+	daily := make([]render.Bar, 0, len(flows))
+	total := make([]render.Bar, 0, len(flows))
+	for i, f := range flows {
+		if f.NetBuy > maxIn.NetBuy {
+			maxIn = f
+		}
+		if f.NetBuy < maxOut.NetBuy {
+			maxOut = f
+		}
+		daily = append(daily, render.Bar{Date: f.Date, Value: f.NetBuy / 1e8})
+		total = append(total, render.Bar{Date: f.Date, Value: cum[i] / 1e8})
+	}
+	first, last := flows[0], flows[len(flows)-1]
+
+	fmt.Fprintf(out, "%s %s ~ %s (%d 个交易日)\n", ch, first.Date.Format(time.DateOnly), last.Date.Format(time.DateOnly), len(flows))
+	fmt.Fprintf(out, "区间净买额\t%s亿\n最大净流入\t%s亿 (%s)\n最大净流出\t%s亿 (%s)\n历史累计  \t%s亿\n\n",
+		yi(cum[len(cum)-1]), yi(maxIn.NetBuy), maxIn.Date.Format(time.DateOnly),
+		yi(maxOut.NetBuy), maxOut.Date.Format(time.DateOnly), yi(last.Accum))
+
+	if err := render.RenderBars(out, daily, render.BarConfig{Height: height, Title: "每日净买额（亿元）"}); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	return render.RenderBars(out, total, render.BarConfig{Height: height, Title: "区间累计净买额（亿元）"})
+}
+
+func runHoldings(ctx context.Context, out io.Writer, f output.Format, key string) error {
+	secs := sina.Search(ctx, key)
+	if len(secs) == 0 {
+		if f.Structured() {
+			return output.Write(out, f, []holdingRecord{})
+		}
+		fmt.Fprintf(out, "未找到证券: %s\n", key)
+		return nil
+	}
+	sec := secs[0]
+	if sec.ExChange != "sh" && sec.ExChange != "sz" {
+		return fmt.Errorf("%s %s 不是沪深 A 股，没有北向持股数据", sec.ExCode, sec.Name)
+	}
+	holdings, err := eastmoney.QueryNorthHoldings(ctx, sec.Code, 20)
+	if err != nil {
+		return err
+	}
+	if f.Structured() {
+		return output.Write(out, f, holdingRecords(sec, holdings))
+	}
+	if len(holdings) == 0 {
+		fmt.Fprintf(out, "%s %s 暂无北向持股数据\n", sec.ExCode, sec.Name)
+		return nil
+	}
+	fmt.Fprintf(out, "%s %s 北向持股\n", sec.ExCode, sec.Name)
+	printHoldings(out, holdings)
+	return nil
+}
+
+// printHoldings prints holdings, newest first, with the change of shares from
+// the previous record.
+func printHoldings(out io.Writer, holdings []*eastmoney.NorthHolding) {
+	table := utils.NewTable(out, []string{"日期", "收盘价", "涨跌幅", "持股数量", "持股市值", "占A股比例", "持股变动"})
+	for i, h := range holdings {
+		change, changeStyle := "-", tablewriter.Colors{}
+		if i+1 < len(holdings) {
+			d := h.Shares - holdings[i+1].Shares
+			switch {
+			case d > 0:
+				change = "+" + utils.HumanNum(d)
+			case d < 0:
+				change = "-" + utils.HumanNum(-d)
+			default:
+				change = "0"
+			}
+			changeStyle = utils.ChangeColor(d)
+		}
+		table.Rich([]string{
+			h.Date.Format(time.DateOnly),
+			fmt.Sprintf("%.2f", h.Close),
+			fmt.Sprintf("%+.2f%%", h.ChangeRate),
+			utils.HumanNum(h.Shares),
+			utils.HumanNum(h.MarketCap),
+			fmt.Sprintf("%.2f%%", h.Ratio),
+			change,
+		}, []tablewriter.Colors{{}, {}, utils.ChangeColor(h.ChangeRate), {}, {}, {}, changeStyle})
+	}
+	table.Render()
+}
+
+// channelKeys 通道在结构化输出中的英文名
+var channelKeys = map[eastmoney.ConnectChannel]string{
+	eastmoney.ChannelShanghai:   "shanghai",
+	eastmoney.ChannelHKShanghai: "hk_shanghai",
+	eastmoney.ChannelShenzhen:   "shenzhen",
+	eastmoney.ChannelHKShenzhen: "hk_shenzhen",
+	eastmoney.ChannelNorth:      "north",
+	eastmoney.ChannelSouth:      "south",
+}
+
+// dailyRecord 某一交易日某一通道的成交净买额
+type dailyRecord struct {
+	Date    string  `json:"date"`
+	Channel string  `json:"channel"` // shanghai、shenzhen、north、hk_shanghai、hk_shenzhen、south
+	NetBuy  float64 `json:"net_buy"` // 单位：元
+}
+
+// dailyRecords flattens rows into one record per date and channel, skipping
+// channels without data.
+func dailyRecords(channels []eastmoney.ConnectChannel, rows []flowRow) []dailyRecord {
+	res := make([]dailyRecord, 0, len(rows)*len(channels))
+	for _, row := range rows {
+		for _, ch := range channels {
+			if v, ok := row.Net[ch]; ok {
+				res = append(res, dailyRecord{Date: row.Date.Format(time.DateOnly), Channel: channelKeys[ch], NetBuy: v})
+			}
+		}
+	}
+	return res
+}
+
+// historyRecord --history 的一个交易日
+type historyRecord struct {
+	Date       string  `json:"date"`
+	Channel    string  `json:"channel"`
+	NetBuy     float64 `json:"net_buy"`
+	Buy        float64 `json:"buy"`
+	Sell       float64 `json:"sell"`
+	Cumulative float64 `json:"cumulative"` // 区间累计净买额
+	Accum      float64 `json:"accum"`      // 历史累计净买额
+}
+
+func historyRecords(ch eastmoney.ConnectChannel, flows []*eastmoney.ConnectFlow) []historyRecord {
+	cum := cumulative(flows)
+	res := make([]historyRecord, 0, len(flows))
+	for i, fl := range flows {
+		res = append(res, historyRecord{
+			Date:       fl.Date.Format(time.DateOnly),
+			Channel:    channelKeys[ch],
+			NetBuy:     fl.NetBuy,
+			Buy:        fl.Buy,
+			Sell:       fl.Sell,
+			Cumulative: cum[i],
+			Accum:      fl.Accum,
+		})
+	}
+	return res
+}
+
+// minuteRecord --intraday 的一分钟累计净流入
+type minuteRecord struct {
+	Direction string  `json:"direction"` // north、south
+	Time      string  `json:"time"`      // 2006-01-02 15:04
+	Shanghai  float64 `json:"shanghai"`  // 沪股通或港股通(沪)
+	Shenzhen  float64 `json:"shenzhen"`  // 深股通或港股通(深)
+	Total     float64 `json:"total"`
+}
+
+func minuteRecords(data *eastmoney.ConnectIntraday) []minuteRecord {
+	res := make([]minuteRecord, 0, len(data.North)+len(data.South))
+	for _, d := range []struct {
+		direction string
+		mins      []*eastmoney.ConnectMinute
+	}{{"north", data.North}, {"south", data.South}} {
+		for _, m := range d.mins {
+			res = append(res, minuteRecord{
+				Direction: d.direction,
+				Time:      m.Time.Format("2006-01-02 15:04"),
+				Shanghai:  m.Shanghai,
+				Shenzhen:  m.Shenzhen,
+				Total:     m.Total,
+			})
+		}
+	}
+	return res
+}
+
+// holdingRecord 北向持股的一个交易日
+type holdingRecord struct {
+	Date         string   `json:"date"`
+	Code         string   `json:"code"`
+	ExCode       string   `json:"excode"`
+	Name         string   `json:"name"`
+	Close        float64  `json:"close"`
+	ChangeRate   float64  `json:"change_rate"` // 涨跌幅 %
+	Shares       float64  `json:"shares"`
+	MarketValue  float64  `json:"market_value"`
+	Ratio        float64  `json:"ratio"`         // 占 A 股比例 %
+	SharesChange *float64 `json:"shares_change"` // 较上一条记录的持股变动，最早一条为空
+}
+
+func holdingRecords(sec *sina.BasicSecurity, holdings []*eastmoney.NorthHolding) []holdingRecord {
+	res := make([]holdingRecord, 0, len(holdings))
+	for i, h := range holdings {
+		rec := holdingRecord{
+			Date:        h.Date.Format(time.DateOnly),
+			Code:        sec.Code,
+			ExCode:      sec.ExCode,
+			Name:        sec.Name,
+			Close:       h.Close,
+			ChangeRate:  h.ChangeRate,
+			Shares:      h.Shares,
+			MarketValue: h.MarketCap,
+			Ratio:       h.Ratio,
+		}
+		if i+1 < len(holdings) {
+			rec.SharesChange = output.Num(h.Shares - holdings[i+1].Shares)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// yi formats an amount in yuan as 100 million yuan with a sign.
+func yi(v float64) string {
+	return fmt.Sprintf("%+.2f", v/1e8)
+}
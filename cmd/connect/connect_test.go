@@ -0,0 +1,120 @@
+package connect
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestAlignFlows(t *testing.T) {
+	flows := map[eastmoney.ConnectChannel][]*eastmoney.ConnectFlow{
+		eastmoney.ChannelNorth: {{Date: day(14), NetBuy: 1e8}, {Date: day(15), NetBuy: -2e8}},
+		eastmoney.ChannelSouth: {{Date: day(15), NetBuy: 3e8}, {Date: day(16), NetBuy: 4e8}},
+	}
+	rows := alignFlows(flows, 2)
+	require.Len(t, rows, 2)
+	require.Equal(t, day(16), rows[0].Date)
+	_, ok := rows[0].Net[eastmoney.ChannelNorth]
+	require.False(t, ok)
+	require.Equal(t, -2e8, rows[1].Net[eastmoney.ChannelNorth])
+	require.Equal(t, 3e8, rows[1].Net[eastmoney.ChannelSouth])
+
+	var buf bytes.Buffer
+	printDaily(&buf, []eastmoney.ConnectChannel{eastmoney.ChannelNorth, eastmoney.ChannelSouth}, rows)
+	require.Contains(t, buf.String(), "北向合计")
+	require.Contains(t, buf.String(), "-2.00")
+	require.Contains(t, buf.String(), "+4.00")
+
+	recs := dailyRecords([]eastmoney.ConnectChannel{eastmoney.ChannelNorth, eastmoney.ChannelSouth}, rows)
+	require.Equal(t, []dailyRecord{
+		{Date: "2026-10-16", Channel: "south", NetBuy: 4e8},
+		{Date: "2026-10-15", Channel: "north", NetBuy: -2e8},
+		{Date: "2026-10-15", Channel: "south", NetBuy: 3e8},
+	}, recs)
+}
+
+func TestSampleMinutes(t *testing.T) {
+	mins := make([]*eastmoney.ConnectMinute, 12)
+	for i := range mins {
+		mins[i] = &eastmoney.ConnectMinute{Total: float64(i)}
+	}
+	got := sampleMinutes(mins, 5)
+	require.Len(t, got, 4)
+	require.Equal(t, 11.0, got[3].Total)
+	require.Len(t, sampleMinutes(mins[:11], 5), 3)
+	require.Empty(t, sampleMinutes(nil, 5))
+}
+
+func TestHistory(t *testing.T) {
+	flows := []*eastmoney.ConnectFlow{
+		{Date: day(14), NetBuy: 10e8, Accum: 100e8},
+		{Date: day(15), NetBuy: -30e8, Accum: 70e8},
+		{Date: day(16), NetBuy: 5e8, Accum: 75e8},
+	}
+	require.Equal(t, []float64{10e8, -20e8, -15e8}, cumulative(flows))
+
+	var buf bytes.Buffer
+	require.NoError(t, printHistory(&buf, eastmoney.ChannelNorth, flows, 5))
+	out := buf.String()
+	require.Contains(t, out, "区间净买额\t-15.00亿")
+	require.Contains(t, out, "最大净流入\t+10.00亿 (2026-10-14)")
+	require.Contains(t, out, "最大净流出\t-30.00亿 (2026-10-15)")
+	require.Contains(t, out, "历史累计  \t+75.00亿")
+
+	recs := historyRecords(eastmoney.ChannelNorth, flows)
+	require.Len(t, recs, 3)
+	require.Equal(t, "north", recs[2].Channel)
+	require.Equal(t, -15e8, recs[2].Cumulative)
+	require.Equal(t, 75e8, recs[2].Accum)
+
+	buf.Reset()
+	require.NoError(t, printHistory(&buf, eastmoney.ChannelSouth, nil, 5))
+	require.Contains(t, buf.String(), "暂无南向合计成交数据")
+}
+
+func TestPrintHoldings(t *testing.T) {
+	holdings := []*eastmoney.NorthHolding{
+		{Date: day(16), Close: 40, Shares: 1.2e8, MarketCap: 4.8e9, Ratio: 5},
+		{Date: day(15), Close: 41, Shares: 1.5e8, MarketCap: 6.15e9, Ratio: 6},
+	}
+	var buf bytes.Buffer
+	printHoldings(&buf, holdings)
+	require.Contains(t, buf.String(), "-3000.00万")
+	require.Contains(t, buf.String(), "5.00%")
+
+	recs := holdingRecords(&sina.BasicSecurity{Code: "600036", ExCode: "SH600036"}, holdings)
+	require.Equal(t, "2026-10-16", recs[0].Date)
+	require.Equal(t, "SH600036", recs[0].ExCode)
+	require.Equal(t, -3e7, *recs[0].SharesChange)
+	require.Nil(t, recs[1].SharesChange)
+}
+
+func TestMinuteRecords(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 31, 0, 0, time.Local)
+	recs := minuteRecords(&eastmoney.ConnectIntraday{
+		North: []*eastmoney.ConnectMinute{{Time: at, Shanghai: 1e8, Shenzhen: 2e8, Total: 3e8}},
+		South: []*eastmoney.ConnectMinute{{Time: at, Total: -1e8}},
+	})
+	require.Equal(t, []minuteRecord{
+		{Direction: "north", Time: "2026-10-16 09:31", Shanghai: 1e8, Shenzhen: 2e8, Total: 3e8},
+		{Direction: "south", Time: "2026-10-16 09:31", Total: -1e8},
+	}, recs)
+}
+
+func TestInvalidFlags(t *testing.T) {
+	cmd := NewConnectCLI()
+	cmd.SetArgs([]string{"--direction", "east"})
+	require.Error(t, cmd.Execute())
+
+	cmd = NewConnectCLI()
+	cmd.SetArgs([]string{"--height", "0"})
+	require.Error(t, cmd.Execute())
+}
@@ -0,0 +1,427 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alwqx/sec/cmd/watch"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/bond"
+	"github.com/alwqx/sec/provider/cache"
+	"github.com/alwqx/sec/provider/cninfo"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/metal"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/types"
+	"github.com/alwqx/sec/utils"
+	"github.com/spf13/cobra"
+)
+
+// 顶部行情条中的指数
+var indexCodes = []string{"SH000001", "SZ399001", "SZ399006"}
+
+const (
+	pollInterval   = 3 * time.Second
+	tickerInterval = 30 * time.Second
+	selectDelay    = 300 * time.Millisecond // 连续翻动时只加载最终选中的证券
+	historyYears   = 3
+	annCount       = 20
+)
+
+// NewDashboardCLI returns the full-screen dashboard command.
+func NewDashboardCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "dashboard [codes...]",
+		Aliases:       []string{"dash"},
+		Short:         "Full-screen dashboard of watchlist quotes, K-line and announcements",
+		Long:          "Full-screen dashboard of watchlist quotes, K-line and announcements. Without codes the watchlist is shown.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		RunE: runDashboard,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().Bool("no-cache", false, "Bypass the local K-line cache")
+
+	return cmd
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+		src.History = cache.NewHistory(src.History)
+	}
+
+	items, missing, err := loadItems(cmd.Context(), src.Searcher, args)
+	if err != nil {
+		return err
+	}
+	for _, code := range missing {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s 未找到\n", code)
+	}
+	if len(items) == 0 && len(missing) > 0 {
+		return fmt.Errorf("未找到证券: %s", strings.Join(missing, ", "))
+	}
+	if len(items) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "自选列表为空。使用 sec watch add <代码> 添加股票，或 sec dashboard <代码...>\n")
+		return nil
+	}
+
+	t, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer t.restore()
+
+	// 日志会破坏画面，运行期间丢弃
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer slog.SetDefault(logger)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprint(out, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(out, "\033[?25h\033[?1049l")
+
+	m := newModel(items)
+	if len(missing) > 0 {
+		m.status = "未找到: " + strings.Join(missing, ", ")
+	}
+	d := &dashboard{src: src, out: out, term: t, model: m, msgs: make(chan func(*model), 16)}
+	return d.run(cmd.Context())
+}
+
+// loadItems resolves codes to watch items in order, or reads the watchlist if
+// none given. Codes that cannot be found are returned in missing.
+func loadItems(ctx context.Context, searcher provider.Searcher, codes []string) (items []watch.WatchItem, missing []string, err error) {
+	if len(codes) == 0 {
+		items, err := watch.Load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取自选列表失败: %w", err)
+		}
+		return items, nil, nil
+	}
+
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		secs := searcher.Search(ctx, code)
+		if len(secs) == 0 {
+			missing = append(missing, code)
+			continue
+		}
+		sec := secs[0]
+		items = append(items, watch.WatchItem{Code: sec.Code, ExCode: sec.ExCode, Name: sec.Name})
+	}
+	return items, missing, nil
+}
+
+// dashboard 事件循环，所有对 model 的修改都在 run 中进行，
+// 异步加载的结果通过 msgs 回到事件循环
+type dashboard struct {
+	src   *provider.Source
+	out   io.Writer
+	term  *terminal
+	model *model
+	msgs  chan func(*model)
+}
+
+func (d *dashboard) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	defer signal.Stop(sig)
+	resize := make(chan os.Signal, 1)
+	if len(resizeSignals) > 0 {
+		signal.Notify(resize, resizeSignals...)
+		defer signal.Stop(resize)
+	}
+
+	m := d.model
+	if err := d.resize(); err != nil {
+		return err
+	}
+	keys := readKeys(ctx, d.term)
+
+	codes := itemCodes(m.items)
+	var quotes <-chan *sina.SecurityQuote
+	if d.src.Stream != nil {
+		stream := d.src.Stream.QuoteStream(ctx, codes)
+		defer stream.Close()
+		quotes = stream.Quotes()
+	}
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	ticker := time.NewTicker(tickerInterval)
+	defer ticker.Stop()
+	selectTimer := time.NewTimer(0)
+	defer selectTimer.Stop()
+
+	d.async(ctx, d.loadQuotes)
+	d.async(ctx, d.loadTicker)
+	for {
+		d.draw()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sig:
+			return nil
+		case <-resize:
+			if err := d.resize(); err != nil {
+				return err
+			}
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch m.handleKey(k) {
+			case actionQuit:
+				return nil
+			case actionSelect:
+				selectTimer.Reset(selectDelay)
+			case actionInfo:
+				if it, ok := m.current(); ok {
+					d.async(ctx, func(ctx context.Context) func(*model) { return d.loadInfo(ctx, it) })
+				}
+			case actionRefresh:
+				m.status = ""
+				d.async(ctx, d.loadQuotes)
+				d.async(ctx, d.loadTicker)
+				if it, ok := m.current(); ok {
+					delete(m.history, m.historyKey(it))
+					delete(m.anns, it.ExCode)
+					d.loadSelected(ctx)
+				}
+			}
+		case <-selectTimer.C:
+			d.loadSelected(ctx)
+		case q, ok := <-quotes:
+			if !ok {
+				quotes = nil
+				continue
+			}
+			m.quotes[q.ExCode] = q
+		case <-poll.C:
+			if quotes == nil {
+				d.async(ctx, d.loadQuotes)
+			}
+		case <-ticker.C:
+			d.async(ctx, d.loadTicker)
+		case f := <-d.msgs:
+			f(m)
+		}
+	}
+}
+
+func (d *dashboard) resize() error {
+	w, h, err := d.term.size()
+	if err != nil {
+		return err
+	}
+	d.model.width, d.model.height = w, h
+	// 尺寸变化后清屏，避免残留旧画面
+	fmt.Fprint(d.out, "\033[2J")
+	return nil
+}
+
+func (d *dashboard) draw() {
+	lines := d.model.view()
+	fmt.Fprint(d.out, "\033[H"+strings.Join(lines, "\r\n"))
+}
+
+// async runs load in the background and hands its result to the event loop.
+func (d *dashboard) async(ctx context.Context, load func(context.Context) func(*model)) {
+	go func() {
+		f := load(ctx)
+		if f == nil {
+			return
+		}
+		select {
+		case d.msgs <- f:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// loadSelected loads the K-line of the current period and the announcements
+// of the selected item if they have not been loaded yet.
+func (d *dashboard) loadSelected(ctx context.Context) {
+	it, ok := d.model.current()
+	if !ok {
+		return
+	}
+	key := d.model.historyKey(it)
+	if _, ok := d.model.history[key]; !ok {
+		d.async(ctx, func(ctx context.Context) func(*model) { return d.loadHistory(ctx, key, it.Code) })
+	}
+	if _, ok := d.model.anns[it.ExCode]; !ok {
+		d.async(ctx, func(ctx context.Context) func(*model) { return d.loadAnnouncements(ctx, it) })
+	}
+}
+
+func (d *dashboard) loadQuotes(ctx context.Context) func(*model) {
+	codes := itemCodes(d.model.items)
+	qlist, err := d.src.Quote.QueryQuoteList(ctx, codes)
+	return func(m *model) {
+		if err != nil {
+			m.status = "获取行情失败: " + err.Error()
+			return
+		}
+		for _, q := range qlist {
+			m.quotes[q.ExCode] = q
+		}
+	}
+}
+
+func (d *dashboard) loadHistory(ctx context.Context, key historyKey, code string) func(*model) {
+	market, ok := eastmoney.MarketOf(key.ExCode)
+	if !ok {
+		return func(m *model) { m.history[key] = nil }
+	}
+	now := time.Now()
+	req := &eastmoney.GetQuoteHistoryReq{
+		Code:       code,
+		MarketCode: int(market),
+		Begin:      now.AddDate(-historyYears, 0, 0).Format(eastmoney.TimeYYMMDD),
+		End:        now.Format(eastmoney.TimeYYMMDD),
+		Period:     key.Period,
+	}
+	quotes, err := d.src.History.GetQuoteHistory(ctx, req)
+	return func(m *model) {
+		if err != nil {
+			m.status = "获取 K 线失败: " + err.Error()
+			return
+		}
+		m.history[key] = quotes
+	}
+}
+
+func (d *dashboard) loadAnnouncements(ctx context.Context, it watch.WatchItem) func(*model) {
+	anns, err := queryAnnouncements(ctx, it.Code)
+	return func(m *model) {
+		if err != nil {
+			// 港美股等不在巨潮资讯的证券没有公告
+			m.anns[it.ExCode] = nil
+			return
+		}
+		m.anns[it.ExCode] = anns
+	}
+}
+
+func queryAnnouncements(ctx context.Context, code string) ([]*cninfo.Announcement, error) {
+	orgID, _, err := cninfo.LookupOrgID(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cninfo.QueryAnnouncements(ctx, &cninfo.QueryRequest{
+		StockCode: code + "," + orgID,
+		PageNum:   1,
+		PageSize:  annCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (d *dashboard) loadInfo(ctx context.Context, it watch.WatchItem) func(*model) {
+	profile, err := sina.Profile(ctx, &types.InfoOptions{Code: it.Code, ExCode: it.ExCode})
+	return func(m *model) {
+		if m.info == nil {
+			// 加载期间已返回
+			return
+		}
+		if err != nil {
+			m.info = []string{"获取详情失败: " + err.Error()}
+			return
+		}
+		m.info = []string{
+			ansiBold + it.ExCode + " " + it.Name + ansiReset,
+			"",
+			"简称历史  " + profile.HistoryName,
+			"公司名称  " + profile.Name,
+			"上市日期  " + profile.ListingDate,
+			fmt.Sprintf("发行价格  %.2f", profile.ListingPrice),
+			"行业分类  " + profile.Category,
+			"主营业务  " + profile.MainBusiness,
+			"办公地址  " + profile.BusinessAddress,
+			"公司网址  " + profile.WebSite,
+			fmt.Sprintf("当前价格  %.2f", profile.Current),
+			fmt.Sprintf("市净率PB  %.2f", profile.PB),
+			fmt.Sprintf("市盈率TTM %.2f", profile.PeTTM),
+			"总市值    " + utils.HumanNum(profile.MarketCap),
+			"流通市值  " + utils.HumanNum(profile.TradedMarketCap),
+		}
+	}
+}
+
+// loadTicker queries the indexes, the US 10-year Treasury yield and Au99.99.
+func (d *dashboard) loadTicker(ctx context.Context) func(*model) {
+	var ticks []tick
+	var errs []error
+
+	qlist, err := d.src.Quote.QueryQuoteList(ctx, indexCodes)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, q := range qlist {
+		t := tick{Name: q.Name, Value: q.Current}
+		if q.YClose > 0 {
+			t.ChgPct = (q.Current - q.YClose) / q.YClose * 100
+		}
+		ticks = append(ticks, t)
+	}
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -10)
+	if resp, err := bond.QueryBond(ctx, &bond.QueryBondReq{
+		Start: start.Format(utils.LayoutYYMMDD),
+		End:   end.Format(utils.LayoutYYMMDD),
+	}); err != nil {
+		errs = append(errs, err)
+	} else if n := len(resp.Data); n > 0 {
+		last := resp.Data[n-1]
+		ticks = append(ticks, tick{Name: "美债10Y", Value: last.BC10Year, ChgPct: last.Change * 100, Unit: "%"})
+	}
+
+	if resp, err := metal.QueryAu999(ctx, &metal.QueryAu999Req{
+		Start: start.Format(utils.LayoutYYMMDD),
+		End:   end.Format(utils.LayoutYYMMDD),
+	}); err != nil {
+		errs = append(errs, err)
+	} else if n := len(resp.Data); n > 0 {
+		last := resp.Data[n-1]
+		ticks = append(ticks, tick{Name: "Au99.99", Value: last.Close, ChgPct: last.ChangeRate * 100})
+	}
+
+	return func(m *model) {
+		if len(ticks) > 0 {
+			m.ticks = ticks
+		}
+		if err := errors.Join(errs...); err != nil {
+			m.status = "获取行情条失败"
+		}
+	}
+}
+
+func itemCodes(items []watch.WatchItem) []string {
+	codes := make([]string, len(items))
+	for i, it := range items {
+		codes[i] = it.ExCode
+	}
+	return codes
+}
@@ -0,0 +1,176 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/cmd/watch"
+	"github.com/alwqx/sec/provider/cninfo"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeys(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []key
+	}{
+		{"\x1b[A", []key{keyUp}},
+		{"\x1bOB", []key{keyDown}},
+		{"\x1b[5~\x1b[6~", []key{keyPageUp, keyPageDown}},
+		{"\x1b", []key{keyEsc}},
+		{"\r", []key{keyEnter}},
+		{"kKrq\x03", []key{keyPeriod, keyPeriod, keyRefresh, keyQuit, keyQuit}},
+		{"\x1b[Cx", []key{keyUnknown, keyUnknown}},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.want, parseKeys([]byte(c.in)), "%q", c.in)
+	}
+}
+
+// visible strips ANSI escapes from s.
+func visible(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b {
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e || s[i] == '[') {
+				i++
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func TestFit(t *testing.T) {
+	require.Equal(t, "ab   ", fit("ab", 5))
+	require.Equal(t, "招商", visible(fit("招商银行", 5))[:6])
+	require.Equal(t, 5, runewidth.StringWidth(visible(fit("招商银行", 5))))
+
+	s := fit(ansiRed+"+1.23%"+ansiReset+" tail", 8)
+	require.Equal(t, "+1.23% t", visible(s))
+	require.True(t, strings.HasPrefix(s, ansiRed))
+	require.True(t, strings.HasSuffix(s, ansiReset))
+}
+
+func dailyQuotes(start time.Time, n int) []*eastmoney.Quote {
+	var res []*eastmoney.Quote
+	for i := range n {
+		d := start.AddDate(0, 0, i)
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		p := float64(10 + i)
+		res = append(res, &eastmoney.Quote{Date: d, Open: p, Close: p + 0.5, High: p + 1, Low: p - 1, Volume: 100})
+	}
+	return res
+}
+
+func testModel(n int) *model {
+	items := make([]watch.WatchItem, n)
+	for i := range items {
+		items[i] = watch.WatchItem{Code: fmt.Sprintf("6000%02d", i), ExCode: fmt.Sprintf("SH6000%02d", i), Name: "招商银行"}
+	}
+	m := newModel(items)
+	m.width, m.height = 100, 30
+	return m
+}
+
+func TestHandleKey(t *testing.T) {
+	m := testModel(3)
+	require.Equal(t, actionNone, m.handleKey(keyUp))
+	require.Equal(t, actionSelect, m.handleKey(keyDown))
+	require.Equal(t, 1, m.selected)
+	require.Equal(t, actionSelect, m.handleKey(keyPageDown))
+	require.Equal(t, 2, m.selected)
+	require.Equal(t, actionNone, m.handleKey(keyDown))
+
+	// 切换周期后加载新周期的 K 线
+	require.Equal(t, actionSelect, m.handleKey(keyPeriod))
+	require.Equal(t, historyKey{ExCode: "SH600002", Period: eastmoney.PeriodWeek}, m.historyKey(m.items[m.selected]))
+	m.handleKey(keyPeriod)
+	m.handleKey(keyPeriod)
+	require.Equal(t, eastmoney.PeriodDay, periods[m.period])
+
+	// 详情页只响应返回和退出
+	require.Equal(t, actionInfo, m.handleKey(keyEnter))
+	require.NotNil(t, m.info)
+	require.Equal(t, actionNone, m.handleKey(keyUp))
+	require.Equal(t, 2, m.selected)
+	require.Equal(t, actionNone, m.handleKey(keyEsc))
+	require.Nil(t, m.info)
+
+	require.Equal(t, actionRefresh, m.handleKey(keyRefresh))
+	require.Equal(t, actionQuit, m.handleKey(keyQuit))
+}
+
+func requireScreen(t *testing.T, m *model) []string {
+	t.Helper()
+	lines := m.view()
+	require.Len(t, lines, m.height)
+	for i, l := range lines {
+		require.Equal(t, m.width, runewidth.StringWidth(visible(l)), "line %d: %q", i, visible(l))
+	}
+	return lines
+}
+
+func TestView(t *testing.T) {
+	m := testModel(40)
+	m.quotes["SH600001"] = &sina.SecurityQuote{ExCode: "SH600001", Current: 11, YClose: 10}
+	m.history[historyKey{ExCode: "SH600000"}] = dailyQuotes(time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), 300)
+	m.anns["SH600000"] = []*cninfo.Announcement{{Title: "2024年半年度报告", Time: time.Date(2024, 8, 30, 0, 0, 0, 0, time.Local).UnixMilli()}}
+	m.ticks = []tick{{Name: "上证指数", Value: 3336.5, ChgPct: 8.06}, {Name: "美债10Y", Value: 3.81, ChgPct: -2, Unit: "%"}}
+
+	screen := strings.Join(requireScreen(t, m), "\n")
+	require.Contains(t, visible(screen), "+10.00%")
+	require.Contains(t, visible(screen), "2024年半年度报告")
+	require.Contains(t, visible(screen), "上证指数 3336.50 +8.06%")
+	require.Contains(t, visible(screen), "美债10Y 3.81% -2.0bp")
+
+	// 选中行滚出屏幕时列表跟随滚动
+	for range 39 {
+		m.handleKey(keyDown)
+	}
+	require.Contains(t, visible(strings.Join(requireScreen(t, m), "\n")), "SH600039")
+
+	m.period = 2
+	m.selected = 0
+	require.Contains(t, visible(strings.Join(requireScreen(t, m), "\n")), "SH600000 招商银行 月K")
+
+	m.info = []string{"公司名称  招商银行股份有限公司"}
+	require.Contains(t, visible(strings.Join(requireScreen(t, m), "\n")), "招商银行股份有限公司")
+
+	// 窗口缩小
+	m.width, m.height = 40, 10
+	lines := requireScreen(t, m)
+	require.Contains(t, visible(lines[0]), "终端窗口太小")
+}
+
+// fakeSearcher 只能找到 secs 中的证券
+type fakeSearcher map[string]*sina.BasicSecurity
+
+func (f fakeSearcher) Search(ctx context.Context, key string) []*sina.BasicSecurity {
+	if sec, ok := f[key]; ok {
+		return []*sina.BasicSecurity{sec}
+	}
+	return nil
+}
+
+func TestLoadItems(t *testing.T) {
+	s := fakeSearcher{
+		"600036": {Code: "600036", ExCode: "SH600036", Name: "招商银行"},
+		"lxjm":   {Code: "002475", ExCode: "SZ002475", Name: "立讯精密"},
+	}
+	items, missing, err := loadItems(context.Background(), s, []string{"foo", "600036", " bar ", "lxjm"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo", "bar"}, missing)
+	require.Len(t, items, 2)
+	require.Equal(t, "SH600036", items[0].ExCode)
+	require.Equal(t, "立讯精密", items[1].Name)
+}
@@ -0,0 +1,110 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// key 键盘按键
+type key int
+
+const (
+	keyUnknown key = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyEsc
+	keyPeriod  // k 切换 K 线周期
+	keyRefresh // r 刷新
+	keyQuit    // q 或 Ctrl+C
+)
+
+// parseKeys decodes the bytes of one terminal read into keys. Arrow keys are
+// accepted in both CSI (ESC [ A) and SS3 (ESC O A) form.
+func parseKeys(buf []byte) []key {
+	var keys []key
+	for len(buf) > 0 {
+		if buf[0] == 0x1b {
+			if len(buf) == 1 {
+				keys = append(keys, keyEsc)
+				break
+			}
+			if buf[1] == '[' || buf[1] == 'O' {
+				n, k := parseEscape(buf)
+				keys = append(keys, k)
+				buf = buf[n:]
+				continue
+			}
+			keys = append(keys, keyEsc)
+			buf = buf[1:]
+			continue
+		}
+
+		switch buf[0] {
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case 'k', 'K':
+			keys = append(keys, keyPeriod)
+		case 'r', 'R':
+			keys = append(keys, keyRefresh)
+		case 'q', 'Q', 0x03:
+			keys = append(keys, keyQuit)
+		default:
+			keys = append(keys, keyUnknown)
+		}
+		buf = buf[1:]
+	}
+	return keys
+}
+
+// parseEscape decodes an escape sequence at the start of buf and returns its
+// length.
+func parseEscape(buf []byte) (int, key) {
+	// 以 0x40-0x7e 之间的字节结束
+	end := 2
+	for end < len(buf) && (buf[end] < 0x40 || buf[end] > 0x7e) {
+		end++
+	}
+	if end >= len(buf) {
+		return len(buf), keyUnknown
+	}
+
+	seq := buf[:end+1]
+	switch {
+	case bytes.Equal(seq, []byte("\x1b[A")), bytes.Equal(seq, []byte("\x1bOA")):
+		return len(seq), keyUp
+	case bytes.Equal(seq, []byte("\x1b[B")), bytes.Equal(seq, []byte("\x1bOB")):
+		return len(seq), keyDown
+	case bytes.Equal(seq, []byte("\x1b[5~")):
+		return len(seq), keyPageUp
+	case bytes.Equal(seq, []byte("\x1b[6~")):
+		return len(seq), keyPageDown
+	}
+	return len(seq), keyUnknown
+}
+
+// readKeys reads r until ctx is done or r fails and sends the decoded keys.
+func readKeys(ctx context.Context, r io.Reader) <-chan key {
+	ch := make(chan key, 16)
+	go func() {
+		defer close(ch)
+		buf := make([]byte, 64)
+		for {
+			n, err := r.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, k := range parseKeys(buf[:n]) {
+				select {
+				case ch <- k:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,408 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alwqx/sec/cmd/watch"
+	"github.com/alwqx/sec/provider/cninfo"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/render"
+	"github.com/mattn/go-runewidth"
+)
+
+// periods 按 k 键依次切换的 K 线周期
+var periods = []eastmoney.Period{eastmoney.PeriodDay, eastmoney.PeriodWeek, eastmoney.PeriodMonth}
+
+// 布局参数
+const (
+	minWidth   = 60
+	minHeight  = 16
+	listWidth  = 44 // 自选列表宽度
+	maxAnnRows = 8  // 公告区最多显示行数
+)
+
+const (
+	ansiReset   = "\033[0m"
+	ansiBold    = "\033[1m"
+	ansiReverse = "\033[7m"
+	ansiRed     = "\033[31m"
+	ansiGreen   = "\033[32m"
+	ansiDim     = "\033[2m"
+)
+
+// tick 顶部行情条中的一项
+type tick struct {
+	Name   string
+	Value  float64
+	ChgPct float64
+	Unit   string // 如 "%"，表示 Value 为收益率
+}
+
+// action 按键触发的数据加载
+type action int
+
+const (
+	actionNone   action = iota
+	actionSelect        // 选中的证券变化，需要加载 K 线和公告
+	actionInfo          // 加载选中证券的详情
+	actionRefresh
+	actionQuit
+)
+
+// historyKey 已加载 K 线的索引
+type historyKey struct {
+	ExCode string
+	Period eastmoney.Period
+}
+
+// model 仪表盘状态，只在事件循环中读写
+type model struct {
+	width, height int
+
+	items    []watch.WatchItem
+	quotes   map[string]*sina.SecurityQuote    // key: ExCode
+	history  map[historyKey][]*eastmoney.Quote // 各周期 K 线
+	anns     map[string][]*cninfo.Announcement // key: ExCode
+	ticks    []tick
+	selected int
+	offset   int // 自选列表滚动位置
+	period   int // 当前周期在 periods 中的下标
+
+	info   []string // 非 nil 时显示详情
+	status string   // 最近一次错误或提示
+}
+
+func newModel(items []watch.WatchItem) *model {
+	return &model{
+		items:   items,
+		quotes:  make(map[string]*sina.SecurityQuote),
+		history: make(map[historyKey][]*eastmoney.Quote),
+		anns:    make(map[string][]*cninfo.Announcement),
+	}
+}
+
+// current returns the selected item.
+func (m *model) current() (watch.WatchItem, bool) {
+	if m.selected < 0 || m.selected >= len(m.items) {
+		return watch.WatchItem{}, false
+	}
+	return m.items[m.selected], true
+}
+
+// historyKey returns the key of the K-line of it in the current period.
+func (m *model) historyKey(it watch.WatchItem) historyKey {
+	return historyKey{ExCode: it.ExCode, Period: periods[m.period]}
+}
+
+// handleKey updates the state for k and returns the data to load.
+func (m *model) handleKey(k key) action {
+	if m.info != nil {
+		switch k {
+		case keyEsc, keyEnter:
+			m.info = nil
+		case keyQuit:
+			return actionQuit
+		}
+		return actionNone
+	}
+
+	switch k {
+	case keyUp:
+		return m.move(-1)
+	case keyDown:
+		return m.move(1)
+	case keyPageUp:
+		return m.move(-m.listRows())
+	case keyPageDown:
+		return m.move(m.listRows())
+	case keyEnter:
+		if _, ok := m.current(); ok {
+			m.info = []string{"加载中..."}
+			return actionInfo
+		}
+	case keyPeriod:
+		m.period = (m.period + 1) % len(periods)
+		return actionSelect
+	case keyRefresh:
+		return actionRefresh
+	case keyQuit:
+		return actionQuit
+	}
+	return actionNone
+}
+
+func (m *model) move(delta int) action {
+	n := len(m.items)
+	if n == 0 {
+		return actionNone
+	}
+	sel := min(max(m.selected+delta, 0), n-1)
+	if sel == m.selected {
+		return actionNone
+	}
+	m.selected = sel
+	return actionSelect
+}
+
+// layout 各区域尺寸
+type layout struct {
+	body      int // 主体高度
+	right     int // 右侧宽度
+	chart     int // K 线区高度
+	annRows   int // 公告区高度
+	listRows  int // 自选列表行数（不含表头）
+	listWidth int
+}
+
+func (m *model) layout() layout {
+	var l layout
+	l.body = m.height - 3 // 行情条、分隔线、帮助行
+	l.listWidth = min(listWidth, m.width/2)
+	l.right = m.width - l.listWidth - 1
+	l.annRows = min(maxAnnRows, l.body/3)
+	l.chart = l.body - l.annRows - 1
+	l.listRows = l.body - 1
+	return l
+}
+
+func (m *model) listRows() int {
+	return max(m.layout().listRows, 1)
+}
+
+// view renders the whole screen as exactly m.height lines of m.width columns.
+func (m *model) view() []string {
+	if m.width < minWidth || m.height < minHeight {
+		lines := make([]string, max(m.height, 1))
+		lines[0] = fit(fmt.Sprintf("终端窗口太小 (%dx%d)，至少需要 %dx%d，按 q 退出", m.width, m.height, minWidth, minHeight), m.width)
+		for i := 1; i < len(lines); i++ {
+			lines[i] = fit("", m.width)
+		}
+		return lines
+	}
+
+	l := m.layout()
+	lines := make([]string, 0, m.height)
+	lines = append(lines, fit(m.tickerLine(), m.width))
+	lines = append(lines, ansiDim+strings.Repeat("─", m.width)+ansiReset)
+
+	left := m.listView(l)
+	var right []string
+	if m.info != nil {
+		right = m.infoView(l.right, l.body)
+	} else {
+		right = append(m.chartView(l.right, l.chart), ansiDim+strings.Repeat("─", l.right)+ansiReset)
+		right = append(right, m.annView(l.right, l.annRows)...)
+	}
+	for i := range l.body {
+		var a, b string
+		if i < len(left) {
+			a = left[i]
+		}
+		if i < len(right) {
+			b = right[i]
+		}
+		lines = append(lines, fit(a, l.listWidth)+ansiDim+"│"+ansiReset+fit(b, l.right))
+	}
+
+	help := "↑/↓ 选择  Enter 详情  k 切换周期  r 刷新  q 退出"
+	if m.info != nil {
+		help = "Esc/Enter 返回  q 退出"
+	}
+	if m.status != "" {
+		help += "  " + ansiRed + m.status + ansiReset
+	}
+	lines = append(lines, fit(ansiDim+help+ansiReset, m.width))
+	return lines
+}
+
+func (m *model) tickerLine() string {
+	parts := []string{ansiBold + "SEC" + ansiReset + " " + time.Now().Format("15:04:05")}
+	for _, t := range m.ticks {
+		var s string
+		if t.Unit == "%" {
+			s = fmt.Sprintf("%s %.2f%% %s", t.Name, t.Value, colored(fmt.Sprintf("%+.1fbp", t.ChgPct), t.ChgPct))
+		} else {
+			s = fmt.Sprintf("%s %.2f %s", t.Name, t.Value, colored(fmt.Sprintf("%+.2f%%", t.ChgPct), t.ChgPct))
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "  ")
+}
+
+func (m *model) listView(l layout) []string {
+	lines := []string{ansiBold + padRight("代码", 10) + padRight("名称", 10) + padLeft("现价", 10) + padLeft("涨跌幅", 9) + ansiReset}
+	if len(m.items) == 0 {
+		return append(lines, "自选列表为空")
+	}
+
+	rows := max(l.listRows, 1)
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if m.selected >= m.offset+rows {
+		m.offset = m.selected - rows + 1
+	}
+	end := min(m.offset+rows, len(m.items))
+	for i := m.offset; i < end; i++ {
+		it := m.items[i]
+		price, pct := "-", "-"
+		var chg float64
+		if q, ok := m.quotes[it.ExCode]; ok && q.Current > 0 {
+			price = fmt.Sprintf("%.2f", q.Current)
+			if q.YClose > 0 {
+				chg = (q.Current - q.YClose) / q.YClose * 100
+				pct = fmt.Sprintf("%+.2f%%", chg)
+			}
+		}
+		row := fmt.Sprintf("%-10s%s%10s", it.ExCode, padRight(truncate(it.Name, 10), 10), price)
+		pctCell := fmt.Sprintf("%9s", pct)
+		if i == m.selected {
+			lines = append(lines, ansiReverse+padRight(row+pctCell, l.listWidth)+ansiReset)
+		} else {
+			lines = append(lines, row+colored(pctCell, chg))
+		}
+	}
+	return lines
+}
+
+func (m *model) chartView(width, height int) []string {
+	it, ok := m.current()
+	if !ok {
+		return nil
+	}
+	title := fmt.Sprintf("%s %s %s", it.ExCode, it.Name, periods[m.period].Label())
+	lines := []string{ansiBold + title + ansiReset}
+
+	quotes, ok := m.history[m.historyKey(it)]
+	if !ok {
+		return append(lines, "加载中...")
+	}
+	if len(quotes) == 0 {
+		return append(lines, "无 K 线数据")
+	}
+
+	// 每根 K 线至少占 2 列
+	chartH := height - 2 // 标题、日期
+	if chartH < 3 {
+		return lines
+	}
+	if n := (width - 10) / 2; n > 0 && len(quotes) > n {
+		quotes = quotes[len(quotes)-n:]
+	}
+	candles := make([]render.Candle, 0, len(quotes))
+	for _, q := range quotes {
+		candles = append(candles, render.Candle{Date: q.Date, Open: q.Open, Close: q.Close, High: q.High, Low: q.Low, Volume: q.Volume})
+	}
+
+	var buf bytes.Buffer
+	cfg := render.CandlestickConfig{Width: width, Height: chartH, HalfBlock: true}
+	if err := render.Render(&buf, candles, cfg); err != nil {
+		return append(lines, err.Error())
+	}
+	return append(lines, strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")...)
+}
+
+func (m *model) annView(width, height int) []string {
+	it, ok := m.current()
+	if !ok || height <= 0 {
+		return nil
+	}
+	lines := []string{ansiBold + "最新公告" + ansiReset}
+	anns, ok := m.anns[it.ExCode]
+	if !ok {
+		return append(lines, "加载中...")
+	}
+	if len(anns) == 0 {
+		return append(lines, "无公告")
+	}
+	for _, a := range anns {
+		if len(lines) >= height {
+			break
+		}
+		date := time.UnixMilli(a.Time).Format("2006-01-02")
+		lines = append(lines, ansiDim+date+ansiReset+" "+truncate(a.Title, width-11))
+	}
+	return lines
+}
+
+func (m *model) infoView(width, height int) []string {
+	lines := make([]string, 0, len(m.info))
+	for _, l := range m.info {
+		if len(lines) >= height {
+			break
+		}
+		lines = append(lines, truncate(l, width))
+	}
+	return lines
+}
+
+func colored(s string, v float64) string {
+	switch {
+	case v > 0:
+		return ansiRed + s + ansiReset
+	case v < 0:
+		return ansiGreen + s + ansiReset
+	}
+	return s
+}
+
+// fit truncates or pads s, which may contain ANSI escapes, to exactly width
+// display columns.
+func fit(s string, width int) string {
+	var sb strings.Builder
+	w := 0
+	hasEscape := false
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			j := i + 1
+			if j < len(s) && s[j] == '[' {
+				j++
+				for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(s))
+			sb.WriteString(s[i:j])
+			hasEscape = true
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := runewidth.RuneWidth(r)
+		if w+rw > width {
+			break
+		}
+		sb.WriteRune(r)
+		w += rw
+		i += size
+	}
+	if hasEscape {
+		sb.WriteString(ansiReset)
+	}
+	sb.WriteString(strings.Repeat(" ", width-w))
+	return sb.String()
+}
+
+// truncate cuts plain text s to at most width display columns.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(s, width, "…")
+}
+
+// padRight pads plain text s with spaces to width display columns.
+func padRight(s string, width int) string {
+	return runewidth.FillRight(s, width)
+}
+
+// padLeft pads plain text s with leading spaces to width display columns.
+func padLeft(s string, width int) string {
+	return runewidth.FillLeft(s, width)
+}
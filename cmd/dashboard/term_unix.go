@@ -0,0 +1,70 @@
+//go:build !windows
+
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// resizeSignals 终端尺寸变化时收到的信号
+var resizeSignals = []os.Signal{syscall.SIGWINCH}
+
+// terminal 通过 stty 切换终端的 raw 模式
+type terminal struct {
+	tty   *os.File
+	saved string // stty -g 保存的原始设置
+}
+
+// openTerminal switches the controlling terminal to raw mode without echo.
+func openTerminal() (*terminal, error) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return nil, fmt.Errorf("dashboard requires a terminal: %w", err)
+	}
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		tty.Close()
+		return nil, fmt.Errorf("dashboard requires a terminal: %w", err)
+	}
+	t := &terminal{tty: tty, saved: strings.TrimSpace(saved)}
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		tty.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// Read reads key presses from the terminal.
+func (t *terminal) Read(p []byte) (int, error) {
+	return t.tty.Read(p)
+}
+
+// size returns the terminal width and height.
+func (t *terminal) size() (int, int, error) {
+	out, err := stty(t.tty, "size")
+	if err != nil {
+		return 0, 0, err
+	}
+	var rows, cols int
+	if _, err := fmt.Sscan(out, &rows, &cols); err != nil {
+		return 0, 0, fmt.Errorf("invalid stty size %q: %w", out, err)
+	}
+	return cols, rows, nil
+}
+
+// restore restores the terminal settings saved by openTerminal.
+func (t *terminal) restore() {
+	stty(t.tty, t.saved)
+	t.tty.Close()
+}
+
+func stty(tty *os.File, args ...string) (string, error) {
+	c := exec.Command("stty", args...)
+	c.Stdin = tty
+	out, err := c.Output()
+	return string(out), err
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package dashboard
+
+import (
+	"errors"
+	"os"
+)
+
+var resizeSignals []os.Signal
+
+type terminal struct{}
+
+func openTerminal() (*terminal, error) {
+	return nil, errors.New("dashboard is not supported on Windows")
+}
+
+func (t *terminal) Read(p []byte) (int, error) { return 0, errors.ErrUnsupported }
+
+func (t *terminal) size() (int, int, error) { return 0, 0, errors.ErrUnsupported }
+
+func (t *terminal) restore() {}
@@ -0,0 +1,239 @@
+package flow
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/render"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewFlowCLI returns the flow command.
+func NewFlowCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "flow <code>",
+		Aliases: []string{"zjlx"},
+		Short:   "Print fund flow of a security by order size",
+		Long: `Print the net inflows of a Shanghai or Shenzhen security split by order size:
+super-large, large, medium and small orders, where main = super-large + large.
+By default print today's flow and its minute chart; with --history print the
+daily flows of recent trading days.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.ExactArgs(1),
+		RunE: runFlow,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().Bool("history", false, "Show daily flows of recent trading days")
+	cmd.Flags().IntP("days", "n", 20, "Number of trading days for --history, at most about 120")
+	cmd.Flags().Int("height", 8, "Chart height in rows")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runFlow(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	days, _ := cmd.Flags().GetInt("days")
+	if days <= 0 {
+		return fmt.Errorf("invalid --days %d: must be > 0", days)
+	}
+	height, _ := cmd.Flags().GetInt("height")
+	if height <= 0 {
+		return fmt.Errorf("invalid height %d: must be > 0", height)
+	}
+
+	f := output.FormatOf(cmd)
+	secs := sina.Search(ctx, args[0])
+	if len(secs) == 0 {
+		if f.Structured() {
+			return output.Write(out, f, []flowRecord{})
+		}
+		fmt.Fprintf(out, "未找到证券: %s\n", args[0])
+		return nil
+	}
+	sec := secs[0]
+	var market eastmoney.MarketType
+	switch sec.ExChange {
+	case "sh":
+		market = eastmoney.MarketTypeSse
+	case "sz":
+		market = eastmoney.MarketTypeSzSe
+	default:
+		return fmt.Errorf("%s %s 不是沪深证券，没有资金流向数据", sec.ExCode, sec.Name)
+	}
+
+	if history, _ := cmd.Flags().GetBool("history"); history {
+		flows, err := eastmoney.QueryFundFlowHistory(ctx, market, sec.Code)
+		if err != nil {
+			return err
+		}
+		if len(flows) > days {
+			flows = flows[len(flows)-days:]
+		}
+		if f.Structured() {
+			return output.Write(out, f, flowRecords(sec, flows, true))
+		}
+		if len(flows) == 0 {
+			fmt.Fprintf(out, "%s %s 暂无资金流向数据\n", sec.ExCode, sec.Name)
+			return nil
+		}
+		fmt.Fprintf(out, "%s %s 资金流向\n", sec.ExCode, sec.Name)
+		return printHistory(out, flows, height)
+	}
+
+	flows, err := eastmoney.QueryFundFlowIntraday(ctx, market, sec.Code)
+	if err != nil {
+		return err
+	}
+	if f.Structured() {
+		return output.Write(out, f, flowRecords(sec, flows, false))
+	}
+	if len(flows) == 0 {
+		fmt.Fprintf(out, "%s %s 暂无当日资金流向数据\n", sec.ExCode, sec.Name)
+		return nil
+	}
+	last := flows[len(flows)-1]
+	fmt.Fprintf(out, "%s %s 资金流向 (%s)\n", sec.ExCode, sec.Name, last.Time.Format("2006-01-02 15:04"))
+	return printIntraday(out, flows, height)
+}
+
+func printIntraday(out io.Writer, flows []*eastmoney.FundFlow, height int) error {
+	last := flows[len(flows)-1]
+	table := utils.NewTable(out, []string{"类型", "净流入"})
+	for _, row := range []struct {
+		name  string
+		value float64
+	}{
+		{"主力", last.Main},
+		{"超大单", last.Super},
+		{"大单", last.Large},
+		{"中单", last.Medium},
+		{"小单", last.Small},
+	} {
+		table.Rich([]string{row.name, utils.SignedNum(row.value)}, []tablewriter.Colors{{}, utils.ChangeColor(row.value)})
+	}
+	table.Render()
+
+	// 分时数据每分钟一条，每 5 分钟取一个点
+	sampled := make([]*eastmoney.FundFlow, 0, len(flows)/5+1)
+	for i := 0; i < len(flows); i += 5 {
+		sampled = append(sampled, flows[i])
+	}
+	if sampled[len(sampled)-1] != last {
+		sampled = append(sampled, last)
+	}
+	div, unit := flowUnit(sampled)
+	bars := make([]render.Bar, 0, len(sampled))
+	for _, f := range sampled {
+		bars = append(bars, render.Bar{Date: f.Time, Value: f.Main / div})
+	}
+	fmt.Fprintln(out)
+	return render.RenderBars(out, bars, render.BarConfig{Height: height, Title: "主力累计净流入（" + unit + "）"})
+}
+
+func printHistory(out io.Writer, flows []*eastmoney.FundFlow, height int) error {
+	var sum eastmoney.FundFlow
+	for _, f := range flows {
+		sum.Main += f.Main
+		sum.Super += f.Super
+		sum.Large += f.Large
+		sum.Medium += f.Medium
+		sum.Small += f.Small
+	}
+
+	table := utils.NewTable(out, []string{"日期", "收盘价", "涨跌幅", "主力", "超大单", "大单", "中单", "小单", "主力占比"})
+	for i := len(flows) - 1; i >= 0; i-- {
+		f := flows[i]
+		table.Rich([]string{
+			f.Time.Format(time.DateOnly),
+			fmt.Sprintf("%.2f", f.Close),
+			fmt.Sprintf("%+.2f%%", f.ChangeRate),
+			utils.SignedNum(f.Main),
+			utils.SignedNum(f.Super),
+			utils.SignedNum(f.Large),
+			utils.SignedNum(f.Medium),
+			utils.SignedNum(f.Small),
+			fmt.Sprintf("%+.2f%%", f.MainRatio),
+		}, []tablewriter.Colors{{}, {}, utils.ChangeColor(f.ChangeRate), utils.ChangeColor(f.Main), utils.ChangeColor(f.Super),
+			utils.ChangeColor(f.Large), utils.ChangeColor(f.Medium), utils.ChangeColor(f.Small), utils.ChangeColor(f.MainRatio)})
+	}
+	table.Rich([]string{
+		fmt.Sprintf("%d日合计", len(flows)), "", "",
+		utils.SignedNum(sum.Main), utils.SignedNum(sum.Super), utils.SignedNum(sum.Large), utils.SignedNum(sum.Medium), utils.SignedNum(sum.Small), "",
+	}, []tablewriter.Colors{{tablewriter.Bold}, {}, {}, utils.ChangeColor(sum.Main), utils.ChangeColor(sum.Super),
+		utils.ChangeColor(sum.Large), utils.ChangeColor(sum.Medium), utils.ChangeColor(sum.Small), {}})
+	table.Render()
+
+	div, unit := flowUnit(flows)
+	bars := make([]render.Bar, 0, len(flows))
+	for _, f := range flows {
+		bars = append(bars, render.Bar{Date: f.Time, Value: f.Main / div})
+	}
+	fmt.Fprintln(out)
+	return render.RenderBars(out, bars, render.BarConfig{Height: height, Title: "每日主力净流入（" + unit + "）"})
+}
+
+// flowRecord 资金流向的结构化输出，净流入单位为元。收盘价、涨跌幅和主力占比
+// 仅 --history 有
+type flowRecord struct {
+	Time       string   `json:"time"` // 分时为 2006-01-02 15:04，历史为 2006-01-02
+	Code       string   `json:"code"`
+	ExCode     string   `json:"excode"`
+	Name       string   `json:"name"`
+	Close      *float64 `json:"close"`
+	ChangeRate *float64 `json:"change_rate"` // 涨跌幅 %
+	Main       float64  `json:"main"`        // 主力 = 超大单 + 大单
+	Super      float64  `json:"super"`
+	Large      float64  `json:"large"`
+	Medium     float64  `json:"medium"`
+	Small      float64  `json:"small"`
+	MainRatio  *float64 `json:"main_ratio"` // 主力净占比 %
+}
+
+func flowRecords(sec *sina.BasicSecurity, flows []*eastmoney.FundFlow, daily bool) []flowRecord {
+	res := make([]flowRecord, 0, len(flows))
+	for _, f := range flows {
+		rec := flowRecord{
+			Time:   f.Time.Format("2006-01-02 15:04"),
+			Code:   sec.Code,
+			ExCode: sec.ExCode,
+			Name:   sec.Name,
+			Main:   f.Main,
+			Super:  f.Super,
+			Large:  f.Large,
+			Medium: f.Medium,
+			Small:  f.Small,
+		}
+		if daily {
+			rec.Time = f.Time.Format(time.DateOnly)
+			rec.Close, rec.ChangeRate, rec.MainRatio = output.Num(f.Close), output.Num(f.ChangeRate), output.Num(f.MainRatio)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// flowUnit picks 100 million or 10 thousand yuan as the chart unit so that
+// small caps still get readable axis labels.
+func flowUnit(flows []*eastmoney.FundFlow) (float64, string) {
+	maxAbs := 0.0
+	for _, f := range flows {
+		maxAbs = max(maxAbs, math.Abs(f.Main))
+	}
+	if maxAbs >= 1e8 {
+		return 1e8, "亿元"
+	}
+	return 1e4, "万元"
+}
@@ -0,0 +1,69 @@
+package flow
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowUnit(t *testing.T) {
+	div, unit := flowUnit([]*eastmoney.FundFlow{{Main: 3e7}, {Main: -1.5e8}})
+	require.Equal(t, 1e8, div)
+	require.Equal(t, "亿元", unit)
+
+	div, unit = flowUnit([]*eastmoney.FundFlow{{Main: 3e6}, {Main: -5e5}})
+	require.Equal(t, 1e4, div)
+	require.Equal(t, "万元", unit)
+}
+
+func TestPrintIntraday(t *testing.T) {
+	day := time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local)
+	flows := make([]*eastmoney.FundFlow, 0, 12)
+	for i := range 12 {
+		flows = append(flows, &eastmoney.FundFlow{Time: day.Add(time.Duration(i+1) * time.Minute), Main: float64(i) * 1e7, Super: float64(i) * 8e6})
+	}
+	flows[11].Small = -1.2e8
+
+	var buf bytes.Buffer
+	require.NoError(t, printIntraday(&buf, flows, 4))
+	out := buf.String()
+	require.Contains(t, out, "+1.10亿")
+	require.Contains(t, out, "-1.20亿")
+	require.Contains(t, out, "主力累计净流入（亿元）")
+}
+
+func TestPrintHistory(t *testing.T) {
+	flows := []*eastmoney.FundFlow{
+		{Time: time.Date(2026, 10, 15, 0, 0, 0, 0, time.Local), Main: -3.5e8, Super: -3e8, Large: -5e7, Close: 41.2, ChangeRate: -1.03, MainRatio: -8.5},
+		{Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local), Main: 1.2e8, Super: 1e8, Large: 2e7, Close: 41.8, ChangeRate: 1.46, MainRatio: 3.1},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, printHistory(&buf, flows, 4))
+	out := buf.String()
+	require.Contains(t, out, "2日合计")
+	require.Contains(t, out, "-2.30亿")
+	require.Contains(t, out, "+3.10%")
+	require.Less(t, bytes.Index(buf.Bytes(), []byte("2026-10-16")), bytes.Index(buf.Bytes(), []byte("2026-10-15")))
+}
+
+func TestFlowRecords(t *testing.T) {
+	sec := &sina.BasicSecurity{Code: "600036", ExCode: "SH600036", Name: "招商银行"}
+	at := time.Date(2026, 10, 16, 9, 31, 0, 0, time.Local)
+	flow := &eastmoney.FundFlow{Time: at, Main: 1.2e8, Super: 1e8, Large: 2e7, Close: 41.8, ChangeRate: 1.46, MainRatio: 3.1}
+
+	recs := flowRecords(sec, []*eastmoney.FundFlow{flow}, false)
+	require.Equal(t, "2026-10-16 09:31", recs[0].Time)
+	require.Equal(t, "SH600036", recs[0].ExCode)
+	require.Equal(t, 1.2e8, recs[0].Main)
+	require.Nil(t, recs[0].Close)
+	require.Nil(t, recs[0].MainRatio)
+
+	recs = flowRecords(sec, []*eastmoney.FundFlow{flow}, true)
+	require.Equal(t, "2026-10-16", recs[0].Time)
+	require.Equal(t, 41.8, *recs[0].Close)
+	require.Equal(t, 3.1, *recs[0].MainRatio)
+}
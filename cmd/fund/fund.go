@@ -0,0 +1,338 @@
+package fund
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/types"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewFundCLI returns the fund command.
+func NewFundCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "fund <code>",
+		Aliases: []string{"jj"},
+		Short:   "Print NAV, fees, holdings and premium of a mutual fund or ETF",
+		Long: `Print the profile of a mutual fund (公募基金) or exchange-traded fund: type,
+company, manager, scale and fees, followed by the daily NAV and accumulated
+NAV history, top stock holdings of a quarterly report and the asset
+allocation. For ETFs and LOFs listed in Shanghai or Shenzhen the exchange
+close price and the premium (溢价) or discount against NAV are printed too.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.ExactArgs(1),
+		RunE: runFund,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().IntP("days", "n", 10, "Number of NAV days to print")
+	cmd.Flags().String("date", "", "Report date of holdings, YYYYMMDD, default the latest")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runFund(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	days, _ := cmd.Flags().GetInt("days")
+	if days <= 0 {
+		return fmt.Errorf("invalid --days %d: must be > 0", days)
+	}
+	var date time.Time
+	if s, _ := cmd.Flags().GetString("date"); s != "" {
+		var err error
+		if date, err = time.ParseInLocation(eastmoney.TimeYYMMDD, s, time.Local); err != nil {
+			return fmt.Errorf("invalid --date %q: %w", s, err)
+		}
+	}
+
+	f := output.FormatOf(cmd)
+	secs := sina.Search(ctx, args[0])
+	if len(secs) == 0 {
+		if f.Structured() {
+			return fmt.Errorf("未找到证券: %s", args[0])
+		}
+		fmt.Fprintf(out, "未找到证券: %s\n", args[0])
+		return nil
+	}
+	sec := secs[0]
+	listed := types.IsExchangeFund(sec.ExCode)
+	if sec.SecurityType != types.SecurityTypeFund && !listed {
+		return fmt.Errorf("%s %s 不是基金", sec.ExCode, sec.Name)
+	}
+
+	info, err := eastmoney.QueryFundInfo(ctx, sec.Code)
+	if err != nil {
+		return err
+	}
+	navs, err := eastmoney.QueryFundNAV(ctx, sec.Code, days)
+	if err != nil {
+		return err
+	}
+	var prices map[string]float64
+	if listed && len(navs) > 0 {
+		if prices, err = queryPrices(ctx, sec, navs); err != nil {
+			return err
+		}
+	}
+	reportDate, holdings, err := eastmoney.QueryFundHoldings(ctx, sec.Code, date)
+	if err != nil {
+		return err
+	}
+	allocs, err := eastmoney.QueryFundAllocation(ctx, sec.Code)
+	if err != nil {
+		return err
+	}
+	allocs = allocs[:min(len(allocs), 4)]
+
+	if f.Structured() {
+		rec := newFundRecord(info, navs, prices, reportDate, holdings, allocs)
+		rec.ExCode = sec.ExCode
+		return output.Write(out, f, rec)
+	}
+
+	printInfo(out, info)
+	fmt.Fprintln(out)
+	printNAVs(out, navs, prices)
+	fmt.Fprintln(out)
+	if len(holdings) == 0 {
+		fmt.Fprintln(out, "暂无股票持仓数据")
+	} else {
+		fmt.Fprintf(out, "重仓股 (%s)\n", reportDate.Format(time.DateOnly))
+		printHoldings(out, holdings)
+	}
+
+	if len(allocs) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "资产配置")
+		printAllocations(out, allocs)
+	}
+	return nil
+}
+
+// queryPrices returns the exchange close prices of a listed fund keyed by
+// date over the range of navs, which is newest first.
+func queryPrices(ctx context.Context, sec *sina.BasicSecurity, navs []*eastmoney.FundNAV) (map[string]float64, error) {
+	req := &eastmoney.GetQuoteHistoryReq{
+		Code:  sec.Code,
+		Begin: navs[len(navs)-1].Date.Format(eastmoney.TimeYYMMDD),
+		End:   navs[0].Date.Format(eastmoney.TimeYYMMDD),
+	}
+	switch sec.ExChange {
+	case "sh":
+		req.MarketCode = int(eastmoney.MarketTypeSse)
+	case "sz":
+		req.MarketCode = int(eastmoney.MarketTypeSzSe)
+	default:
+		return nil, nil
+	}
+	quotes, err := eastmoney.GetQuoteHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[string]float64, len(quotes))
+	for _, q := range quotes {
+		prices[q.Date.Format(time.DateOnly)] = q.Close
+	}
+	return prices, nil
+}
+
+// premium returns the premium of the exchange price over NAV in percent,
+// negative for a discount.
+func premium(price, nav float64) float64 {
+	if nav == 0 {
+		return 0
+	}
+	return (price/nav - 1) * 100
+}
+
+func printInfo(out io.Writer, info *eastmoney.FundInfo) {
+	fmt.Fprintf(out, "基金代码\t%s\n基金名称\t%s\n基金类型\t%s\n基金公司\t%s\n基金经理\t%s\n成立日期\t%s\n资产规模\t%s\n业绩基准\t%s\n",
+		info.Code, info.Name, info.Type, info.Company, info.Manager, info.Established, utils.HumanNum(info.Scale), info.Benchmark)
+	fmt.Fprintf(out, "管理费率\t%s\n托管费率\t%s\n销售服务费\t%s\n申购费率\t%s\n",
+		orDash(info.ManageFee), orDash(info.CustodyFee), orDash(info.SaleFee), orDash(info.PurchaseFee))
+	if info.RiskLevel != "" {
+		fmt.Fprintf(out, "风险等级\t%s\n", info.RiskLevel)
+	}
+}
+
+// printNAVs prints navs, newest first. When prices is not nil the exchange
+// close price and premium of each day are printed too.
+func printNAVs(out io.Writer, navs []*eastmoney.FundNAV, prices map[string]float64) {
+	headers := []string{"日期", "单位净值", "累计净值", "日增长率"}
+	if prices != nil {
+		headers = append(headers, "收盘价", "溢价率")
+	}
+	table := utils.NewTable(out, headers)
+	for _, n := range navs {
+		row := []string{
+			n.Date.Format(time.DateOnly),
+			fmt.Sprintf("%.4f", n.NAV),
+			fmt.Sprintf("%.4f", n.AccNAV),
+			fmt.Sprintf("%+.2f%%", n.ChangeRate),
+		}
+		styles := []tablewriter.Colors{{}, {}, {}, utils.ChangeColor(n.ChangeRate)}
+		if prices != nil {
+			price, ok := prices[n.Date.Format(time.DateOnly)]
+			if ok {
+				p := premium(price, n.NAV)
+				row = append(row, fmt.Sprintf("%.3f", price), fmt.Sprintf("%+.2f%%", p))
+				styles = append(styles, tablewriter.Colors{}, utils.ChangeColor(p))
+			} else {
+				row = append(row, "-", "-")
+				styles = append(styles, tablewriter.Colors{}, tablewriter.Colors{})
+			}
+		}
+		table.Rich(row, styles)
+	}
+	table.Render()
+}
+
+func printHoldings(out io.Writer, holdings []*eastmoney.FundHolding) {
+	table := utils.NewTable(out, []string{"股票代码", "股票名称", "占净值", "较上期"})
+	for _, h := range holdings {
+		table.Append([]string{h.Code, h.Name, fmt.Sprintf("%.2f%%", h.Ratio), orDash(h.Change)})
+	}
+	table.Render()
+}
+
+func printAllocations(out io.Writer, allocs []*eastmoney.FundAllocation) {
+	table := utils.NewTable(out, []string{"报告期", "股票", "债券", "现金", "其他", "净资产"})
+	for _, a := range allocs {
+		table.Append([]string{
+			a.Date.Format(time.DateOnly),
+			fmt.Sprintf("%.2f%%", a.Stock),
+			fmt.Sprintf("%.2f%%", a.Bond),
+			fmt.Sprintf("%.2f%%", a.Cash),
+			fmt.Sprintf("%.2f%%", a.Other),
+			utils.HumanNum(a.NetAsset),
+		})
+	}
+	table.Render()
+}
+
+// fundRecord 基金的结构化输出，费率为百分数，净值、重仓股和资产配置为列表
+type fundRecord struct {
+	Code         string             `json:"code"`
+	ExCode       string             `json:"excode"`
+	Name         string             `json:"name"`
+	Type         string             `json:"type"`
+	Company      string             `json:"company"`
+	Manager      string             `json:"manager"`
+	Established  string             `json:"established"`
+	Scale        float64            `json:"scale"` // 资产规模，单位：元
+	Benchmark    string             `json:"benchmark"`
+	ManageFee    *float64           `json:"manage_fee"`
+	CustodyFee   *float64           `json:"custody_fee"`
+	SaleFee      *float64           `json:"sale_fee"`
+	PurchaseFee  *float64           `json:"purchase_fee"`
+	RiskLevel    string             `json:"risk_level"`
+	NAVs         []navRecord        `json:"navs"`          // 最近的交易日在前
+	HoldingsDate string             `json:"holdings_date"` // 重仓股报告期，没有持仓时为空
+	Holdings     []holdingRecord    `json:"holdings"`
+	Allocations  []allocationRecord `json:"allocations"`
+}
+
+// navRecord 单日净值，场内基金含收盘价和溢价率
+type navRecord struct {
+	Date        string   `json:"date"`
+	NAV         float64  `json:"nav"`
+	AccNAV      float64  `json:"acc_nav"`
+	ChangeRate  float64  `json:"change_rate"`
+	Close       *float64 `json:"close"`
+	PremiumRate *float64 `json:"premium_rate"` // 溢价率 %，负数为折价
+}
+
+// holdingRecord 重仓股
+type holdingRecord struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Ratio  float64 `json:"ratio"`  // 占净值 %
+	Change string  `json:"change"` // 较上期，如"新增"、"增持"
+}
+
+// allocationRecord 报告期资产配置，比例均为占净值百分数
+type allocationRecord struct {
+	Date     string  `json:"date"`
+	Stock    float64 `json:"stock"`
+	Bond     float64 `json:"bond"`
+	Cash     float64 `json:"cash"`
+	Other    float64 `json:"other"`
+	NetAsset float64 `json:"net_asset"`
+}
+
+func newFundRecord(info *eastmoney.FundInfo, navs []*eastmoney.FundNAV, prices map[string]float64,
+	reportDate time.Time, holdings []*eastmoney.FundHolding, allocs []*eastmoney.FundAllocation) *fundRecord {
+	rec := &fundRecord{
+		Code:        info.Code,
+		Name:        info.Name,
+		Type:        info.Type,
+		Company:     info.Company,
+		Manager:     info.Manager,
+		Established: info.Established,
+		Scale:       info.Scale,
+		Benchmark:   info.Benchmark,
+		ManageFee:   feeRate(info.ManageFee),
+		CustodyFee:  feeRate(info.CustodyFee),
+		SaleFee:     feeRate(info.SaleFee),
+		PurchaseFee: feeRate(info.PurchaseFee),
+		RiskLevel:   info.RiskLevel,
+		NAVs:        make([]navRecord, 0, len(navs)),
+		Holdings:    make([]holdingRecord, 0, len(holdings)),
+		Allocations: make([]allocationRecord, 0, len(allocs)),
+	}
+	for _, n := range navs {
+		nav := navRecord{Date: n.Date.Format(time.DateOnly), NAV: n.NAV, AccNAV: n.AccNAV, ChangeRate: n.ChangeRate}
+		if price, ok := prices[nav.Date]; ok {
+			nav.Close, nav.PremiumRate = output.Num(price), output.Num(premium(price, n.NAV))
+		}
+		rec.NAVs = append(rec.NAVs, nav)
+	}
+	if len(holdings) > 0 {
+		rec.HoldingsDate = reportDate.Format(time.DateOnly)
+	}
+	for _, h := range holdings {
+		rec.Holdings = append(rec.Holdings, holdingRecord{Code: h.Code, Name: h.Name, Ratio: h.Ratio, Change: h.Change})
+	}
+	for _, a := range allocs {
+		rec.Allocations = append(rec.Allocations, allocationRecord{
+			Date:     a.Date.Format(time.DateOnly),
+			Stock:    a.Stock,
+			Bond:     a.Bond,
+			Cash:     a.Cash,
+			Other:    a.Other,
+			NetAsset: a.NetAsset,
+		})
+	}
+	return rec
+}
+
+// feeRate parses a fee such as "0.50%" into a percent, nil when it is empty
+// or not a number.
+func feeRate(s string) *float64 {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil {
+		return nil
+	}
+	return output.Num(v)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package fund
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPremium(t *testing.T) {
+	require.InDelta(t, 1.0, premium(1.01, 1.0), 1e-9)
+	require.InDelta(t, -2.0, premium(0.98, 1.0), 1e-9)
+	require.Zero(t, premium(1.0, 0))
+}
+
+func TestPrintNAVs(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	navs := []*eastmoney.FundNAV{
+		{Date: day, NAV: 4.0, AccNAV: 1.8, ChangeRate: 1.02},
+		{Date: day.AddDate(0, 0, -1), NAV: 3.96, AccNAV: 1.78, ChangeRate: -0.35},
+	}
+
+	var buf bytes.Buffer
+	printNAVs(&buf, navs, nil)
+	require.NotContains(t, buf.String(), "溢价率")
+	require.Contains(t, buf.String(), "4.0000")
+
+	buf.Reset()
+	printNAVs(&buf, navs, map[string]float64{"2026-10-16": 4.04})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[0], "溢价率")
+	require.Contains(t, lines[1], "+1.00%")
+	// 没有场内收盘价的日期不计算溢价
+	require.True(t, strings.HasSuffix(strings.TrimSpace(lines[2]), "-"))
+}
+
+func TestFundRecord(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	info := &eastmoney.FundInfo{Code: "510300", Name: "沪深300ETF", ManageFee: "0.50%", PurchaseFee: "--"}
+	navs := []*eastmoney.FundNAV{
+		{Date: day, NAV: 4.0, AccNAV: 1.8, ChangeRate: 1.02},
+		{Date: day.AddDate(0, 0, -1), NAV: 3.96, AccNAV: 1.78, ChangeRate: -0.35},
+	}
+	rec := newFundRecord(info, navs, map[string]float64{"2026-10-16": 4.04}, day, nil, nil)
+	require.InDelta(t, 0.5, *rec.ManageFee, 1e-9)
+	require.Nil(t, rec.PurchaseFee)
+	require.Len(t, rec.NAVs, 2)
+	require.InDelta(t, 1.0, *rec.NAVs[0].PremiumRate, 1e-9)
+	require.Nil(t, rec.NAVs[1].Close)
+	require.Empty(t, rec.HoldingsDate)
+	require.NotNil(t, rec.Holdings)
+}
@@ -0,0 +1,363 @@
+package fx
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/fx"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewFXCLI returns the fx command.
+func NewFXCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fx",
+		Short: "Print CNY central parity and onshore/offshore USD, HKD and EUR rates",
+		Long: `Print the latest CNY central parity (中间价) and the onshore (CNY) and
+offshore (CNH) spot rates of USD, HKD and EUR, with the offshore-onshore
+spread in pips. Rates are CNY per unit of foreign currency.
+
+Use "sec fx history" for the daily history of one rate and "sec fx convert"
+to convert an amount between currencies.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.NoArgs,
+		RunE: runFX,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	history, convert := newHistoryCLI(), newConvertCLI()
+	output.Enable(cmd, history, convert)
+	cmd.AddCommand(history, convert)
+
+	return cmd
+}
+
+func newHistoryCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Print daily history of a CNY exchange rate",
+		Args:  cobra.NoArgs,
+		RunE:  runHistory,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("currency", "c", string(fx.CurrencyUSD), "Currency: USD, HKD or EUR")
+	cmd.Flags().StringP("kind", "k", string(fx.KindParity), "Rate kind: parity, onshore or offshore")
+	cmd.Flags().StringP("begin", "b", "", "Begin date 20260101, default 30 days ago")
+	cmd.Flags().StringP("end", "e", "", "End date 20260131, default today")
+
+	return cmd
+}
+
+func newConvertCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert <amount> <from> [to]",
+		Short: "Convert an amount between CNY, USD, HKD and EUR at the latest rate",
+		Example: `  sec fx convert 100 USD
+  sec fx convert 10000 HKD USD -k offshore`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runConvert,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("kind", "k", string(fx.KindParity), "Rate kind: parity, onshore or offshore")
+
+	return cmd
+}
+
+func runFX(cmd *cobra.Command, args []string) error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		rates = make(map[fx.Kind]*fx.Rates, len(fx.Kinds))
+	)
+	for _, kind := range fx.Kinds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := fx.Latest(cmd.Context(), kind)
+			if err != nil {
+				// 单个来源失败时只缺一列，不影响其他汇率
+				slog.Warn("failed query rates", "kind", kind, "error", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			rates[kind] = r
+		}()
+	}
+	wg.Wait()
+	if len(rates) == 0 {
+		return fmt.Errorf("no exchange rates")
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, latestRecords(rates))
+	}
+	printLatest(cmd.OutOrStdout(), rates)
+	return nil
+}
+
+// latestRecord 一个币种的最新汇率，缺失的汇率为空
+type latestRecord struct {
+	Currency   string   `json:"currency"`
+	Parity     *float64 `json:"parity"`
+	Onshore    *float64 `json:"onshore"`
+	Offshore   *float64 `json:"offshore"`
+	SpreadPips *float64 `json:"spread_pips"` // 离岸 - 在岸，单位：pips
+	Date       string   `json:"date"`        // 各汇率中最新的日期
+}
+
+// latestRecords returns the rates of every currency with the offshore-onshore
+// spread.
+func latestRecords(rates map[fx.Kind]*fx.Rates) []latestRecord {
+	res := make([]latestRecord, 0, len(fx.Currencies))
+	for _, c := range fx.Currencies {
+		rec := latestRecord{Currency: string(c)}
+		values := map[fx.Kind]**float64{
+			fx.KindParity:   &rec.Parity,
+			fx.KindOnshore:  &rec.Onshore,
+			fx.KindOffshore: &rec.Offshore,
+		}
+		var date time.Time
+		for _, kind := range fx.Kinds {
+			r, ok := rates[kind]
+			if !ok {
+				continue
+			}
+			if v, ok := r.Values[c]; ok {
+				*values[kind] = output.Num(v)
+				if d := r.Dates[c]; d.After(date) {
+					date = d
+				}
+			}
+		}
+		if rec.Onshore != nil && rec.Offshore != nil {
+			rec.SpreadPips = output.Num((*rec.Offshore - *rec.Onshore) * 10000)
+		}
+		if !date.IsZero() {
+			rec.Date = date.Format(utils.LayoutYYMMDD)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// printLatest 打印各币种的中间价、在岸和离岸汇率及离岸与在岸的价差
+func printLatest(out io.Writer, rates map[fx.Kind]*fx.Rates) {
+	headers := []string{"币种"}
+	for _, kind := range fx.Kinds {
+		headers = append(headers, kind.Label())
+	}
+	headers = append(headers, "离岸-在岸(pips)", "日期")
+
+	table := utils.NewTable(out, headers)
+	for _, rec := range latestRecords(rates) {
+		c := fx.Currency(rec.Currency)
+		row := []string{fmt.Sprintf("%s %s", c.Label(), c)}
+		for _, v := range []*float64{rec.Parity, rec.Onshore, rec.Offshore} {
+			if v == nil {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, formatRate(*v))
+		}
+		spread, style := "-", tablewriter.Colors{}
+		if rec.SpreadPips != nil {
+			spread, style = fmt.Sprintf("%+.0f", *rec.SpreadPips), utils.ChangeColor(*rec.SpreadPips)
+		}
+		date := rec.Date
+		if date == "" {
+			date = "-"
+		}
+		row = append(row, spread, date)
+
+		styles := make([]tablewriter.Colors, len(row))
+		styles[len(row)-2] = style
+		table.Rich(row, styles)
+	}
+	table.Render()
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	currencyStr, _ := cmd.Flags().GetString("currency")
+	c, err := fx.ParseCurrency(currencyStr)
+	if err != nil {
+		return err
+	}
+	if c == fx.CurrencyCNY {
+		return fmt.Errorf("invalid currency CNY, want USD, HKD or EUR")
+	}
+	kindStr, _ := cmd.Flags().GetString("kind")
+	kind, err := fx.ParseKind(kindStr)
+	if err != nil {
+		return err
+	}
+	beginStr, _ := cmd.Flags().GetString("begin")
+	endStr, _ := cmd.Flags().GetString("end")
+	start, end, err := utils.ParseBeginEnd(beginStr, endStr, 30, utils.ParseMetalCmdArgTimeLayout, utils.LayoutYYMMDD)
+	if err != nil {
+		return err
+	}
+
+	rates, err := fx.History(cmd.Context(), kind, c, start, end)
+	if err != nil {
+		return err
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, historyRecords(rates))
+	}
+	if len(rates) == 0 {
+		slog.Warn("no data")
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s %s ~ %s\n", kind.Label(), kind.Pair(c), start, end)
+	printHistory(cmd.OutOrStdout(), rates)
+	return nil
+}
+
+// printHistory 打印汇率历史，涨跌为较前一日的变化
+func printHistory(out io.Writer, rates []fx.Rate) {
+	table := utils.NewTable(out, []string{"日期", "汇率", "涨跌(pips)", "涨跌幅"})
+	for i, r := range rates {
+		change, rate, style := "-", "-", tablewriter.Colors{}
+		if i > 0 {
+			prev := rates[i-1].Value
+			pips := (r.Value - prev) * 10000
+			change = fmt.Sprintf("%+.0f", pips)
+			rate = fmt.Sprintf("%+.2f%%", (r.Value-prev)/prev*100)
+			style = utils.ChangeColor(pips)
+		}
+		table.Rich([]string{r.Date.Format(utils.LayoutYYMMDD), formatRate(r.Value), change, rate},
+			[]tablewriter.Colors{{}, style, style, style})
+	}
+	table.Render()
+}
+
+// historyRecord 汇率历史的一个交易日，涨跌为较前一日的变化，第一天为空
+type historyRecord struct {
+	Date       string   `json:"date"`
+	Currency   string   `json:"currency"`
+	Kind       string   `json:"kind"` // parity、onshore 或 offshore
+	Rate       float64  `json:"rate"`
+	ChangePips *float64 `json:"change_pips"`
+	ChangeRate *float64 `json:"change_rate"` // 涨跌幅 %
+}
+
+func historyRecords(rates []fx.Rate) []historyRecord {
+	res := make([]historyRecord, 0, len(rates))
+	for i, r := range rates {
+		rec := historyRecord{
+			Date:     r.Date.Format(utils.LayoutYYMMDD),
+			Currency: string(r.Currency),
+			Kind:     string(r.Kind),
+			Rate:     r.Value,
+		}
+		if i > 0 {
+			prev := rates[i-1].Value
+			rec.ChangePips = output.Num((r.Value - prev) * 10000)
+			rec.ChangeRate = output.Num((r.Value - prev) / prev * 100)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	amount, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", args[0], err)
+	}
+	from, err := fx.ParseCurrency(args[1])
+	if err != nil {
+		return err
+	}
+	to := fx.CurrencyCNY
+	if len(args) == 3 {
+		if to, err = fx.ParseCurrency(args[2]); err != nil {
+			return err
+		}
+	}
+	kindStr, _ := cmd.Flags().GetString("kind")
+	kind, err := fx.ParseKind(kindStr)
+	if err != nil {
+		return err
+	}
+
+	rates, err := fx.Latest(cmd.Context(), kind)
+	if err != nil {
+		return err
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		rec, err := newConvertRecord(rates, amount, from, to)
+		if err != nil {
+			return err
+		}
+		return output.Write(cmd.OutOrStdout(), f, rec)
+	}
+	return printConvert(cmd.OutOrStdout(), rates, amount, from, to)
+}
+
+// convertRecord 换算结果，汇率为 1 单位外币兑人民币，人民币一侧为空
+type convertRecord struct {
+	Amount   float64  `json:"amount"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Result   float64  `json:"result"`
+	Kind     string   `json:"kind"`
+	FromRate *float64 `json:"from_rate"`
+	ToRate   *float64 `json:"to_rate"`
+	Date     string   `json:"date"` // 所用汇率中最新的日期
+}
+
+func newConvertRecord(rates *fx.Rates, amount float64, from, to fx.Currency) (*convertRecord, error) {
+	v, err := rates.Convert(amount, from, to)
+	if err != nil {
+		return nil, err
+	}
+	rec := &convertRecord{Amount: amount, From: string(from), To: string(to), Result: v, Kind: string(rates.Kind)}
+	var date time.Time
+	for c, dst := range map[fx.Currency]**float64{from: &rec.FromRate, to: &rec.ToRate} {
+		if c == fx.CurrencyCNY {
+			continue
+		}
+		*dst = output.Num(rates.Values[c])
+		if d := rates.Dates[c]; d.After(date) {
+			date = d
+		}
+	}
+	if !date.IsZero() {
+		rec.Date = date.Format(utils.LayoutYYMMDD)
+	}
+	return rec, nil
+}
+
+// printConvert 打印换算结果及使用的汇率
+func printConvert(out io.Writer, rates *fx.Rates, amount float64, from, to fx.Currency) error {
+	v, err := rates.Convert(amount, from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s %s = %.2f %s\n", strconv.FormatFloat(amount, 'f', -1, 64), from, v, to)
+	for _, c := range fx.Currencies {
+		if c == from || c == to {
+			fmt.Fprintf(out, "%s %s %s（%s）\n", rates.Kind.Label(), rates.Kind.Pair(c), formatRate(rates.Values[c]), rates.Dates[c].Format(utils.LayoutYYMMDD))
+		}
+	}
+	return nil
+}
+
+// formatRate 港币等小于 1 的汇率多保留一位小数
+func formatRate(v float64) string {
+	if v < 1 {
+		return fmt.Sprintf("%.5f", v)
+	}
+	return fmt.Sprintf("%.4f", v)
+}
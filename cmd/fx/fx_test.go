@@ -0,0 +1,132 @@
+package fx
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/fx"
+	"github.com/stretchr/testify/require"
+)
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func lines(out string) [][]string {
+	var res [][]string
+	for _, line := range strings.Split(strings.TrimRight(ansiRe.ReplaceAllString(out, ""), "\n"), "\n") {
+		res = append(res, strings.Fields(line))
+	}
+	return res
+}
+
+func testRates(kind fx.Kind, usd, hkd float64, day string) *fx.Rates {
+	date, _ := time.Parse("2006-01-02", day)
+	return &fx.Rates{
+		Kind:   kind,
+		Values: map[fx.Currency]float64{fx.CurrencyUSD: usd, fx.CurrencyHKD: hkd},
+		Dates:  map[fx.Currency]time.Time{fx.CurrencyUSD: date, fx.CurrencyHKD: date},
+	}
+}
+
+func TestPrintLatest(t *testing.T) {
+	var buf bytes.Buffer
+	printLatest(&buf, map[fx.Kind]*fx.Rates{
+		fx.KindParity:   testRates(fx.KindParity, 7.1, 0.9079, "2026-05-08"),
+		fx.KindOnshore:  testRates(fx.KindOnshore, 7.2, 0.92, "2026-05-07"),
+		fx.KindOffshore: testRates(fx.KindOffshore, 7.2035, 0.9198, "2026-05-08"),
+	})
+	rows := lines(buf.String())
+	require.Len(t, rows, 4)
+	require.Equal(t, []string{"币种", "中间价", "在岸", "离岸", "离岸-在岸(PIPS)", "日期"}, rows[0])
+	require.Equal(t, []string{"美元", "USD", "7.1000", "7.2000", "7.2035", "+35", "2026-05-08"}, rows[1])
+	require.Equal(t, []string{"港币", "HKD", "0.90790", "0.92000", "0.91980", "-2", "2026-05-08"}, rows[2])
+	require.Equal(t, []string{"欧元", "EUR", "-", "-", "-", "-", "-"}, rows[3])
+
+	// 缺少离岸汇率时没有价差
+	buf.Reset()
+	printLatest(&buf, map[fx.Kind]*fx.Rates{fx.KindOnshore: testRates(fx.KindOnshore, 7.2, 0.92, "2026-05-07")})
+	require.Equal(t, []string{"美元", "USD", "-", "7.2000", "-", "-", "2026-05-07"}, lines(buf.String())[1])
+
+	recs := latestRecords(map[fx.Kind]*fx.Rates{
+		fx.KindOnshore:  testRates(fx.KindOnshore, 7.2, 0.92, "2026-05-07"),
+		fx.KindOffshore: testRates(fx.KindOffshore, 7.2035, 0.9198, "2026-05-08"),
+	})
+	require.Len(t, recs, 3)
+	require.Equal(t, "USD", recs[0].Currency)
+	require.Nil(t, recs[0].Parity)
+	require.InDelta(t, 35, *recs[0].SpreadPips, 1e-6)
+	require.Equal(t, "2026-05-08", recs[0].Date)
+	require.Equal(t, latestRecord{Currency: "EUR"}, recs[2])
+}
+
+func TestPrintHistory(t *testing.T) {
+	day := time.Date(2026, 5, 6, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	printHistory(&buf, []fx.Rate{
+		{Date: day, Currency: fx.CurrencyUSD, Kind: fx.KindParity, Value: 7.1},
+		{Date: day.AddDate(0, 0, 1), Currency: fx.CurrencyUSD, Kind: fx.KindParity, Value: 7.1071},
+		{Date: day.AddDate(0, 0, 2), Currency: fx.CurrencyUSD, Kind: fx.KindParity, Value: 7.1},
+	})
+	rows := lines(buf.String())
+	require.Len(t, rows, 4)
+	require.Equal(t, []string{"2026-05-06", "7.1000", "-", "-"}, rows[1])
+	require.Equal(t, []string{"2026-05-07", "7.1071", "+71", "+0.10%"}, rows[2])
+	require.Equal(t, []string{"2026-05-08", "7.1000", "-71", "-0.10%"}, rows[3])
+
+	recs := historyRecords([]fx.Rate{
+		{Date: day, Currency: fx.CurrencyUSD, Kind: fx.KindParity, Value: 7.1},
+		{Date: day.AddDate(0, 0, 1), Currency: fx.CurrencyUSD, Kind: fx.KindParity, Value: 7.1071},
+	})
+	require.Nil(t, recs[0].ChangePips)
+	require.Equal(t, "parity", recs[1].Kind)
+	require.InDelta(t, 71, *recs[1].ChangePips, 1e-6)
+	require.InDelta(t, 0.1, *recs[1].ChangeRate, 1e-9)
+}
+
+func TestPrintConvert(t *testing.T) {
+	rates := testRates(fx.KindParity, 7.2, 0.9, "2026-05-08")
+	var buf bytes.Buffer
+	require.NoError(t, printConvert(&buf, rates, 1000, fx.CurrencyHKD, fx.CurrencyUSD))
+	require.Equal(t, "1000 HKD = 125.00 USD\n中间价 USD/CNY 7.2000（2026-05-08）\n中间价 HKD/CNY 0.90000（2026-05-08）\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, printConvert(&buf, rates, 100, fx.CurrencyUSD, fx.CurrencyCNY))
+	require.True(t, strings.HasPrefix(buf.String(), "100 USD = 720.00 CNY\n"))
+
+	require.ErrorIs(t, printConvert(&buf, rates, 1, fx.CurrencyEUR, fx.CurrencyCNY), fx.ErrNoRate)
+
+	rec, err := newConvertRecord(rates, 100, fx.CurrencyUSD, fx.CurrencyCNY)
+	require.NoError(t, err)
+	require.InDelta(t, 720, rec.Result, 1e-9)
+	require.Equal(t, 7.2, *rec.FromRate)
+	require.Nil(t, rec.ToRate)
+	require.Equal(t, "2026-05-08", rec.Date)
+}
+
+func TestFXCLI(t *testing.T) {
+	cmd := NewFXCLI()
+	for _, name := range []string{"history", "convert"} {
+		sub, _, err := cmd.Find([]string{name})
+		require.NoError(t, err)
+		require.Equal(t, name, sub.Name())
+	}
+
+	for args, want := range map[string]string{
+		"history -c JPY":         "invalid currency",
+		"history -c CNY":         "invalid currency CNY",
+		"history -k spot":        "invalid rate kind",
+		"convert abc USD":        "invalid amount",
+		"convert 100 GBP":        "invalid currency",
+		"convert 100 USD -k pay": "invalid rate kind",
+	} {
+		// 每次用新的命令，避免上一次解析的 flag 残留
+		cmd := NewFXCLI()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(strings.Fields(args))
+		require.ErrorContains(t, cmd.Execute(), want, args)
+	}
+}
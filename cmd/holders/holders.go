@@ -0,0 +1,365 @@
+package holders
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/render"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewHoldersCLI returns the holders command.
+func NewHoldersCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "holders <code>",
+		Aliases: []string{"gd"},
+		Short:   "Print top-10 shareholders and shareholder count trend",
+		Long: `Print the top-10 shareholders and top-10 float shareholders of a Shanghai or
+Shenzhen stock per report period, marking holders that are new, increased,
+decreased or exited compared with the previous period, followed by the
+shareholder count (股东户数) trend and average holding per account.
+
+With --output, the shareholders are written one per row; add --counts for the
+shareholder counts instead.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.ExactArgs(1),
+		RunE: runHolders,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().IntP("periods", "p", 1, "Number of report periods to print")
+	cmd.Flags().BoolP("float", "f", false, "Only print top-10 float shareholders")
+	cmd.Flags().IntP("limit", "n", 12, "Number of shareholder count periods to print")
+	cmd.Flags().Int("height", 6, "Shareholder count chart height in rows")
+	cmd.Flags().Bool("counts", false, "Only print the shareholder count trend")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runHolders(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	periods, _ := cmd.Flags().GetInt("periods")
+	if periods <= 0 {
+		return fmt.Errorf("invalid --periods %d: must be > 0", periods)
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit <= 0 {
+		return fmt.Errorf("invalid --limit %d: must be > 0", limit)
+	}
+	height, _ := cmd.Flags().GetInt("height")
+	if height <= 0 {
+		return fmt.Errorf("invalid height %d: must be > 0", height)
+	}
+	onlyFloat, _ := cmd.Flags().GetBool("float")
+	onlyCounts, _ := cmd.Flags().GetBool("counts")
+	f := output.FormatOf(cmd)
+
+	secs := sina.Search(ctx, args[0])
+	if len(secs) == 0 {
+		if f.Structured() {
+			if onlyCounts {
+				return output.Write(out, f, []countRecord{})
+			}
+			return output.Write(out, f, []holderRecord{})
+		}
+		fmt.Fprintf(out, "未找到证券: %s\n", args[0])
+		return nil
+	}
+	sec := secs[0]
+	var market eastmoney.MarketType
+	switch sec.ExChange {
+	case "sh":
+		market = eastmoney.MarketTypeSse
+	case "sz":
+		market = eastmoney.MarketTypeSzSe
+	default:
+		return fmt.Errorf("%s %s 不是沪深 A 股，没有股东数据", sec.ExCode, sec.Name)
+	}
+
+	if !onlyCounts {
+		kinds := []bool{false, true}
+		if onlyFloat {
+			kinds = []bool{true}
+		}
+		var records []holderRecord
+		for _, float := range kinds {
+			groups, err := topHolders(ctx, market, sec.Code, float, periods)
+			if err != nil {
+				return err
+			}
+			if f.Structured() {
+				records = append(records, holderRecords(groups, float, periods)...)
+				continue
+			}
+			printTopHolders(out, sec.ExCode+" "+sec.Name, float, groups, periods)
+			fmt.Fprintln(out)
+		}
+		if f.Structured() {
+			return output.Write(out, f, records)
+		}
+	}
+
+	counts, err := eastmoney.QueryHolderCounts(ctx, sec.Code, limit)
+	if err != nil {
+		return err
+	}
+	if f.Structured() {
+		return output.Write(out, f, countRecords(counts))
+	}
+	if len(counts) == 0 {
+		fmt.Fprintln(out, "暂无股东户数数据")
+		return nil
+	}
+	return printCounts(out, counts, height)
+}
+
+// topHolders returns the top-10 holders of code grouped by report period,
+// newest first, with one more period than periods for comparison.
+func topHolders(ctx context.Context, market eastmoney.MarketType, code string, float bool, periods int) ([][]*eastmoney.Shareholder, error) {
+	list, err := eastmoney.QueryTopHolders(ctx, market, code, float, periods+1)
+	if err != nil {
+		return nil, err
+	}
+	return groupByPeriod(list), nil
+}
+
+func printTopHolders(out io.Writer, title string, float bool, groups [][]*eastmoney.Shareholder, periods int) {
+	label := "十大股东"
+	if float {
+		label = "十大流通股东"
+	}
+	if len(groups) == 0 {
+		fmt.Fprintf(out, "%s 暂无%s数据\n", title, label)
+		return
+	}
+	for i := 0; i < periods && i < len(groups); i++ {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		cur := groups[i]
+		var prev []*eastmoney.Shareholder
+		header := fmt.Sprintf("%s %s (%s)", title, label, cur[0].Date.Format(time.DateOnly))
+		if i+1 < len(groups) {
+			prev = groups[i+1]
+			header = fmt.Sprintf("%s %s (%s，较 %s)", title, label, cur[0].Date.Format(time.DateOnly), prev[0].Date.Format(time.DateOnly))
+		}
+		fmt.Fprintln(out, header)
+		printHolders(out, diffHolders(cur, prev), prev != nil)
+	}
+}
+
+// groupByPeriod splits holders, which are sorted newest period first, into
+// one group per report period.
+func groupByPeriod(list []*eastmoney.Shareholder) [][]*eastmoney.Shareholder {
+	var groups [][]*eastmoney.Shareholder
+	for i, h := range list {
+		if i == 0 || !h.Date.Equal(list[i-1].Date) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], h)
+	}
+	return groups
+}
+
+// holderStatus 股东较上期的变动
+type holderStatus string
+
+const (
+	statusNew       holderStatus = "新进"
+	statusIncreased holderStatus = "增持"
+	statusDecreased holderStatus = "减持"
+	statusUnchanged holderStatus = "不变"
+	statusExited    holderStatus = "退出"
+)
+
+// statusKeys 变动在结构化输出中的英文名
+var statusKeys = map[holderStatus]string{
+	statusNew:       "new",
+	statusIncreased: "increased",
+	statusDecreased: "decreased",
+	statusUnchanged: "unchanged",
+	statusExited:    "exited",
+}
+
+// holderRow 带变动的股东
+type holderRow struct {
+	*eastmoney.Shareholder
+	Status holderStatus
+	Change float64 // 较上期持股变动，单位股
+}
+
+// diffHolders compares cur with the previous period prev by holder name. The
+// holders of prev that are no longer in cur are appended as exited.
+func diffHolders(cur, prev []*eastmoney.Shareholder) []holderRow {
+	before := make(map[string]*eastmoney.Shareholder, len(prev))
+	for _, h := range prev {
+		before[h.Name] = h
+	}
+	rows := make([]holderRow, 0, len(cur)+len(prev))
+	seen := make(map[string]bool, len(cur))
+	for _, h := range cur {
+		seen[h.Name] = true
+		row := holderRow{Shareholder: h, Status: statusNew, Change: h.Shares}
+		if p, ok := before[h.Name]; ok {
+			row.Change = h.Shares - p.Shares
+			switch {
+			case row.Change > 0:
+				row.Status = statusIncreased
+			case row.Change < 0:
+				row.Status = statusDecreased
+			default:
+				row.Status = statusUnchanged
+			}
+		}
+		rows = append(rows, row)
+	}
+	for _, h := range prev {
+		if !seen[h.Name] {
+			rows = append(rows, holderRow{Shareholder: h, Status: statusExited, Change: -h.Shares})
+		}
+	}
+	return rows
+}
+
+func printHolders(out io.Writer, rows []holderRow, compared bool) {
+	table := utils.NewTable(out, []string{"排名", "股东名称", "股东性质", "持股数", "占比", "较上期", "变动"})
+	for _, r := range rows {
+		rank, ratio := fmt.Sprintf("%d", r.Rank), fmt.Sprintf("%.2f%%", r.Ratio)
+		change, status, style := "-", "-", tablewriter.Colors{}
+		if compared {
+			change, status, style = utils.SignedNum(r.Change), string(r.Status), utils.ChangeColor(r.Change)
+		}
+		if r.Status == statusExited {
+			// 退出的股东展示上期数据
+			rank, ratio = "-", "-"
+		}
+		table.Rich([]string{rank, r.Name, r.Type, utils.HumanNum(r.Shares), ratio, change, status},
+			[]tablewriter.Colors{{}, {}, {}, {}, {}, style, statusColor(r.Status, compared)})
+	}
+	table.Render()
+}
+
+func printCounts(out io.Writer, counts []*eastmoney.HolderCount, height int) error {
+	fmt.Fprintln(out, "股东户数")
+	table := utils.NewTable(out, []string{"截止日期", "股东户数", "较上期", "户均持股", "户均市值"})
+	for _, c := range counts {
+		table.Rich([]string{
+			c.Date.Format(time.DateOnly),
+			fmt.Sprintf("%.0f", c.Count),
+			fmt.Sprintf("%+.2f%%", c.ChangeRatio),
+			utils.HumanNum(c.AvgShares),
+			utils.HumanNum(c.AvgMarketCap),
+		}, []tablewriter.Colors{{}, {}, utils.ChangeColor(c.ChangeRatio), {}, {}})
+	}
+	table.Render()
+
+	if len(counts) < 2 {
+		return nil
+	}
+	bars := make([]render.Bar, 0, len(counts))
+	for i := len(counts) - 1; i >= 0; i-- {
+		bars = append(bars, render.Bar{Date: counts[i].Date, Value: counts[i].Count / 1e4})
+	}
+	fmt.Fprintln(out)
+	return render.RenderBars(out, bars, render.BarConfig{Height: height, Title: "股东户数（万户）"})
+}
+
+// statusColor colors new and increased holders red and decreased and exited
+// holders green.
+func statusColor(s holderStatus, compared bool) tablewriter.Colors {
+	if !compared {
+		return tablewriter.Colors{}
+	}
+	switch s {
+	case statusNew, statusIncreased:
+		return tablewriter.Colors{tablewriter.FgRedColor}
+	case statusDecreased, statusExited:
+		return tablewriter.Colors{tablewriter.FgGreenColor}
+	}
+	return tablewriter.Colors{}
+}
+
+// holderRecord 十大股东的结构化输出。退出的股东为上期数据，排名和占比为空；
+// 没有上期可比较时变动为空
+type holderRecord struct {
+	Period     string   `json:"period"` // 报告期
+	Kind       string   `json:"kind"`   // top10 或 float
+	Rank       *int     `json:"rank"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"` // 股东性质
+	SharesType string   `json:"shares_type"`
+	Shares     float64  `json:"shares"`
+	Ratio      *float64 `json:"ratio"`  // 占总股本或流通股本 %
+	Change     *float64 `json:"change"` // 较上期持股变动，单位：股
+	Status     string   `json:"status"` // new、increased、decreased、unchanged、exited
+}
+
+// holderRecords flattens the latest periods of groups like printTopHolders.
+func holderRecords(groups [][]*eastmoney.Shareholder, float bool, periods int) []holderRecord {
+	kind := "top10"
+	if float {
+		kind = "float"
+	}
+	var res []holderRecord
+	for i := 0; i < periods && i < len(groups); i++ {
+		var prev []*eastmoney.Shareholder
+		if i+1 < len(groups) {
+			prev = groups[i+1]
+		}
+		period := groups[i][0].Date.Format(time.DateOnly)
+		for _, r := range diffHolders(groups[i], prev) {
+			rec := holderRecord{
+				Period:     period,
+				Kind:       kind,
+				Name:       r.Name,
+				Type:       r.Type,
+				SharesType: r.SharesType,
+				Shares:     r.Shares,
+			}
+			if r.Status != statusExited {
+				rank := r.Rank
+				rec.Rank, rec.Ratio = &rank, output.Num(r.Ratio)
+			}
+			if prev != nil {
+				rec.Change, rec.Status = output.Num(r.Change), statusKeys[r.Status]
+			}
+			res = append(res, rec)
+		}
+	}
+	return res
+}
+
+// countRecord 报告期股东户数
+type countRecord struct {
+	Date           string  `json:"date"`
+	Count          float64 `json:"count"`
+	ChangeRate     float64 `json:"change_rate"` // 较上期 %
+	AvgShares      float64 `json:"avg_shares"`
+	AvgMarketValue float64 `json:"avg_market_value"`
+}
+
+func countRecords(counts []*eastmoney.HolderCount) []countRecord {
+	res := make([]countRecord, 0, len(counts))
+	for _, c := range counts {
+		res = append(res, countRecord{
+			Date:           c.Date.Format(time.DateOnly),
+			Count:          c.Count,
+			ChangeRate:     c.ChangeRatio,
+			AvgShares:      c.AvgShares,
+			AvgMarketValue: c.AvgMarketCap,
+		})
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package holders
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffHolders(t *testing.T) {
+	q2 := time.Date(2026, 6, 30, 0, 0, 0, 0, time.Local)
+	q1 := time.Date(2026, 3, 31, 0, 0, 0, 0, time.Local)
+	list := []*eastmoney.Shareholder{
+		{Date: q2, Rank: 1, Name: "A", Shares: 100},
+		{Date: q2, Rank: 2, Name: "B", Shares: 80},
+		{Date: q2, Rank: 3, Name: "C", Shares: 50},
+		{Date: q2, Rank: 4, Name: "D", Shares: 40},
+		{Date: q1, Rank: 1, Name: "A", Shares: 90},
+		{Date: q1, Rank: 2, Name: "B", Shares: 80},
+		{Date: q1, Rank: 3, Name: "C", Shares: 60},
+		{Date: q1, Rank: 4, Name: "E", Shares: 30},
+	}
+	groups := groupByPeriod(list)
+	require.Len(t, groups, 2)
+	require.Len(t, groups[1], 4)
+
+	rows := diffHolders(groups[0], groups[1])
+	require.Len(t, rows, 5)
+	require.Equal(t, statusIncreased, rows[0].Status)
+	require.Equal(t, 10.0, rows[0].Change)
+	require.Equal(t, statusUnchanged, rows[1].Status)
+	require.Equal(t, statusDecreased, rows[2].Status)
+	require.Equal(t, statusNew, rows[3].Status)
+	require.Equal(t, statusExited, rows[4].Status)
+	require.Equal(t, "E", rows[4].Name)
+	require.Equal(t, -30.0, rows[4].Change)
+
+	rows = diffHolders(groups[1], nil)
+	require.Len(t, rows, 4)
+	require.Equal(t, statusNew, rows[0].Status)
+
+	recs := holderRecords(groups, true, 2)
+	require.Len(t, recs, 9)
+	require.Equal(t, "2026-06-30", recs[0].Period)
+	require.Equal(t, "float", recs[0].Kind)
+	require.Equal(t, "increased", recs[0].Status)
+	require.Equal(t, 10.0, *recs[0].Change)
+	require.Equal(t, "exited", recs[4].Status)
+	require.Nil(t, recs[4].Rank)
+	require.Nil(t, recs[4].Ratio)
+	// 最早一期没有上期可比较
+	require.Equal(t, "2026-03-31", recs[5].Period)
+	require.Equal(t, 1, *recs[5].Rank)
+	require.Nil(t, recs[5].Change)
+	require.Empty(t, recs[5].Status)
+}
+
+func TestPrintCounts(t *testing.T) {
+	counts := []*eastmoney.HolderCount{
+		{Date: time.Date(2026, 6, 30, 0, 0, 0, 0, time.Local), Count: 512345, ChangeRatio: -3.21, AvgShares: 49100, AvgMarketCap: 2.01e6},
+		{Date: time.Date(2026, 3, 31, 0, 0, 0, 0, time.Local), Count: 529342, ChangeRatio: 1.5, AvgShares: 47500, AvgMarketCap: 1.9e6},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, printCounts(&buf, counts, 4))
+	out := buf.String()
+	require.Contains(t, out, "512345")
+	require.Contains(t, out, "-3.21%")
+	require.Contains(t, out, "股东户数（万户）")
+
+	recs := countRecords(counts)
+	require.Equal(t, countRecord{Date: "2026-06-30", Count: 512345, ChangeRate: -3.21, AvgShares: 49100, AvgMarketValue: 2.01e6}, recs[0])
+}
@@ -0,0 +1,477 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/cache"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/index"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewIndexCLI returns the index command.
+func NewIndexCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "index [code]",
+		Aliases: []string{"idx"},
+		Short:   "Print quotes of major market indexes",
+		Long: `Print quotes of major market indexes, or the detail of one index.
+
+The index can be given by code (000001, SH000001), name (上证指数) or alias
+(hs300, cyb, kc50, zz500, a50).`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: runIndex,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().BoolP("realtime", "r", false, "Keep refreshing quotes")
+	cmd.Flags().Bool("no-cache", false, "Bypass local K-line history cache")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runIndex(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+		src.History = cache.NewHistory(src.History)
+	}
+	realtime, _ := cmd.Flags().GetBool("realtime")
+	f := output.FormatOf(cmd)
+	if realtime && f.Structured() {
+		return fmt.Errorf("--realtime only supports table output, got --output %s", f)
+	}
+	out := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		ixs := index.Major()
+		draw := func(quotes map[string]*sina.SecurityQuote) {
+			printOverview(out, ixs, quotes)
+		}
+		if realtime {
+			return runRealtime(cmd.Context(), src, ixs, draw)
+		}
+		quotes, err := queryQuotes(cmd.Context(), src, ixs)
+		if err != nil {
+			return err
+		}
+		if f.Structured() {
+			return output.Write(out, f, indexRecords(ixs, quotes))
+		}
+		draw(quotes)
+		return nil
+	}
+
+	ix, ok := index.LookupCode(args[0])
+	if !ok {
+		names := make([]string, 0, len(index.All()))
+		for _, ix := range index.All() {
+			names = append(names, ix.Code+" "+ix.Name)
+		}
+		return fmt.Errorf("未知指数 %s，支持: %s", args[0], strings.Join(names, ", "))
+	}
+
+	end := time.Now()
+	req := &eastmoney.GetQuoteHistoryReq{
+		Code:       ix.Code,
+		MarketCode: int(ix.Market),
+		Begin:      end.AddDate(-1, 0, -7).Format(eastmoney.TimeYYMMDD),
+		End:        end.Format(eastmoney.TimeYYMMDD),
+	}
+	hist, err := src.History.GetQuoteHistory(cmd.Context(), req)
+	if err != nil {
+		slog.Warn("获取指数历史行情失败", "code", ix.ExCode, "error", err)
+	}
+
+	ixs := []*index.Index{ix}
+	draw := func(quotes map[string]*sina.SecurityQuote) {
+		printDetail(out, ix, quotes[ix.ExCode], hist)
+	}
+	if realtime && ix.HasQuote() {
+		return runRealtime(cmd.Context(), src, ixs, draw)
+	}
+	quotes, err := queryQuotes(cmd.Context(), src, ixs)
+	if err != nil && len(hist) == 0 {
+		return err
+	}
+	if f.Structured() {
+		return output.Write(out, f, newDetailRecord(ix, quotes[ix.ExCode], hist))
+	}
+	draw(quotes)
+	return nil
+}
+
+// queryQuotes returns the realtime quotes of ixs keyed by ExCode.
+func queryQuotes(ctx context.Context, src *provider.Source, ixs []*index.Index) (map[string]*sina.SecurityQuote, error) {
+	quotes := make(map[string]*sina.SecurityQuote, len(ixs))
+	codes := quoteCodes(ixs)
+	if len(codes) == 0 {
+		return quotes, nil
+	}
+	qlist, err := src.Quote.QueryQuoteList(ctx, codes)
+	if err != nil {
+		return quotes, fmt.Errorf("获取指数行情失败: %w", err)
+	}
+	for _, q := range qlist {
+		quotes[q.ExCode] = q
+	}
+	return quotes, nil
+}
+
+func quoteCodes(ixs []*index.Index) []string {
+	codes := make([]string, 0, len(ixs))
+	for _, ix := range ixs {
+		if ix.HasQuote() {
+			codes = append(codes, ix.ExCode)
+		}
+	}
+	return codes
+}
+
+// runRealtime 持续刷新指数行情，优先使用行情推送，否则每 3 秒轮询
+func runRealtime(ctx context.Context, src *provider.Source, ixs []*index.Index, draw func(map[string]*sina.SecurityQuote)) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	quotes, err := queryQuotes(ctx, src, ixs)
+	if err != nil {
+		slog.WarnContext(ctx, "获取指数行情失败", "error", err)
+	}
+
+	var pushed <-chan *sina.SecurityQuote
+	if src.Stream != nil {
+		stream := src.Stream.QuoteStream(ctx, quoteCodes(ixs))
+		defer stream.Close()
+		pushed = stream.Quotes()
+	}
+
+	redraw := time.NewTicker(time.Second)
+	defer redraw.Stop()
+	var lastPoll time.Time
+	dirty := true
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case q, ok := <-pushed:
+			if !ok {
+				return nil
+			}
+			quotes[q.ExCode] = q
+			dirty = true
+		case now := <-redraw.C:
+			if pushed == nil && now.Sub(lastPoll) >= 3*time.Second {
+				latest, err := queryQuotes(ctx, src, ixs)
+				if err != nil {
+					slog.WarnContext(ctx, "获取指数行情失败", "error", err)
+				}
+				for k, q := range latest {
+					quotes[k] = q
+				}
+				lastPoll, dirty = now, true
+			}
+			if !dirty {
+				continue
+			}
+			dirty = false
+			utils.ClearTerm()
+			draw(quotes)
+		}
+	}
+}
+
+func changeOf(q *sina.SecurityQuote) (float64, float64) {
+	if q == nil || q.YClose == 0 {
+		return 0, 0
+	}
+	chg := q.Current - q.YClose
+	return chg, chg / q.YClose * 100
+}
+
+func printOverview(out io.Writer, ixs []*index.Index, quotes map[string]*sina.SecurityQuote) {
+	fmt.Fprintf(out, "\n主要指数 %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	headers := []string{"代码", "名称", "最新", "涨跌幅", "涨跌", "最高", "最低", "成交额"}
+	data := make([][]string, 0, len(ixs))
+	styles := make([][]tablewriter.Colors, 0, len(ixs))
+	for _, ix := range ixs {
+		row := []string{ix.ExCode, ix.Name, "-", "-", "-", "-", "-", "-"}
+		style := make([]tablewriter.Colors, len(headers))
+		if q, ok := quotes[ix.ExCode]; ok {
+			chg, pct := changeOf(q)
+			row[2] = fmt.Sprintf("%.2f", q.Current)
+			row[3] = fmt.Sprintf("%+.2f%%", pct)
+			row[4] = fmt.Sprintf("%+.2f", chg)
+			row[5] = fmt.Sprintf("%.2f", q.High)
+			row[6] = fmt.Sprintf("%.2f", q.Low)
+			row[7] = utils.HumanNum(q.Volume)
+			style[3] = utils.ChangeColor(pct, tablewriter.Bold)
+		}
+		data = append(data, row)
+		styles = append(styles, style)
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+	for i, row := range data {
+		table.Rich(row, styles[i])
+	}
+	table.Render()
+	fmt.Fprintln(out)
+}
+
+// indexRecord 指数概览的结构化输出，没有实时行情时数值为空
+type indexRecord struct {
+	Code       string   `json:"code"`
+	ExCode     string   `json:"excode"`
+	Name       string   `json:"name"`
+	Price      *float64 `json:"price"`
+	Change     *float64 `json:"change"`
+	ChangeRate *float64 `json:"change_rate"` // 涨跌幅 %
+	High       *float64 `json:"high"`
+	Low        *float64 `json:"low"`
+	Amount     *float64 `json:"amount"` // 成交额，单位：元
+}
+
+func indexRecords(ixs []*index.Index, quotes map[string]*sina.SecurityQuote) []indexRecord {
+	res := make([]indexRecord, 0, len(ixs))
+	for _, ix := range ixs {
+		rec := indexRecord{Code: ix.Code, ExCode: ix.ExCode, Name: ix.Name}
+		if q, ok := quotes[ix.ExCode]; ok {
+			chg, pct := changeOf(q)
+			rec.Price = output.Num(q.Current)
+			rec.Change = output.Num(chg)
+			rec.ChangeRate = output.Num(pct)
+			rec.High = output.Num(q.High)
+			rec.Low = output.Num(q.Low)
+			rec.Amount = output.Num(q.Volume)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// detailRecord 单个指数的结构化输出，区间涨跌幅为百分数，历史数据不足时为空
+type detailRecord struct {
+	Code        string   `json:"code"`
+	ExCode      string   `json:"excode"`
+	Name        string   `json:"name"`
+	Date        string   `json:"date"` // 行情日期，实时行情为当日
+	Price       *float64 `json:"price"`
+	Change      *float64 `json:"change"`
+	ChangeRate  *float64 `json:"change_rate"`
+	Open        *float64 `json:"open"`
+	PrevClose   *float64 `json:"prev_close"`
+	High        *float64 `json:"high"`
+	Low         *float64 `json:"low"`
+	Amount      *float64 `json:"amount"`
+	Return5D    *float64 `json:"return_5d"`
+	Return20D   *float64 `json:"return_20d"`
+	Return60D   *float64 `json:"return_60d"`
+	ReturnYTD   *float64 `json:"return_ytd"`
+	Return1Y    *float64 `json:"return_1y"`
+	High52W     *float64 `json:"high_52w"`
+	High52WDate string   `json:"high_52w_date"`
+	Low52W      *float64 `json:"low_52w"`
+	Low52WDate  string   `json:"low_52w_date"`
+}
+
+func newDetailRecord(ix *index.Index, q *sina.SecurityQuote, hist []*eastmoney.Quote) detailRecord {
+	rec := detailRecord{Code: ix.Code, ExCode: ix.ExCode, Name: ix.Name}
+
+	var last float64
+	switch {
+	case q != nil:
+		last = q.Current
+		chg, pct := changeOf(q)
+		rec.Date = q.TradeDate
+		rec.Price, rec.Change, rec.ChangeRate = output.Num(q.Current), output.Num(chg), output.Num(pct)
+		rec.Open, rec.PrevClose = output.Num(q.Open), output.Num(q.YClose)
+		rec.High, rec.Low, rec.Amount = output.Num(q.High), output.Num(q.Low), output.Num(q.Volume)
+	case len(hist) > 0:
+		bar := hist[len(hist)-1]
+		last = bar.Close
+		rec.Date = bar.Date.Format("2006-01-02")
+		rec.Price, rec.Change, rec.ChangeRate = output.Num(bar.Close), output.Num(bar.Change), output.Num(bar.ChangeRate)
+		rec.Open, rec.PrevClose = output.Num(bar.Open), output.Num(bar.Close-bar.Change)
+		rec.High, rec.Low, rec.Amount = output.Num(bar.High), output.Num(bar.Low), output.Num(bar.TurnOver)
+	}
+
+	s := computeStats(hist, last)
+	if !s.HasHistory {
+		return rec
+	}
+	// 与 computeStats 的区间顺序一致
+	returns := []**float64{&rec.Return5D, &rec.Return20D, &rec.Return60D, &rec.ReturnYTD, &rec.Return1Y}
+	for i, r := range s.Returns {
+		if r.OK {
+			*returns[i] = output.Num(r.Pct)
+		}
+	}
+	if !s.HighDate.IsZero() {
+		rec.High52W, rec.High52WDate = output.Num(s.High52W), s.HighDate.Format("2006-01-02")
+		rec.Low52W, rec.Low52WDate = output.Num(s.Low52W), s.LowDate.Format("2006-01-02")
+	}
+	return rec
+}
+
+// rangeReturn 区间涨跌幅
+type rangeReturn struct {
+	Name string
+	Pct  float64
+	OK   bool // 历史数据足够时为 true
+}
+
+// indexStats 指数区间统计
+type indexStats struct {
+	Returns    []rangeReturn
+	High52W    float64
+	Low52W     float64
+	HighDate   time.Time
+	LowDate    time.Time
+	HasHistory bool
+}
+
+// computeStats derives range returns and the 52-week range from daily bars,
+// taking last as the latest level.
+func computeStats(hist []*eastmoney.Quote, last float64) indexStats {
+	var s indexStats
+	n := len(hist)
+	if n == 0 || last == 0 {
+		return s
+	}
+	s.HasHistory = true
+
+	back := func(name string, bars int) rangeReturn {
+		// 最后一根为当日 K 线，往前数 bars 根作为基准
+		if n-1-bars < 0 {
+			return rangeReturn{Name: name}
+		}
+		base := hist[n-1-bars].Close
+		return rangeReturn{Name: name, Pct: (last/base - 1) * 100, OK: base > 0}
+	}
+	s.Returns = append(s.Returns, back("5日", 5), back("20日", 20), back("60日", 60))
+
+	// 年初至今以上一年最后一个交易日收盘为基准
+	ytd := rangeReturn{Name: "年初至今"}
+	year := hist[n-1].Date.Year()
+	for i := n - 1; i >= 0; i-- {
+		if hist[i].Date.Year() < year {
+			ytd = rangeReturn{Name: ytd.Name, Pct: (last/hist[i].Close - 1) * 100, OK: hist[i].Close > 0}
+			break
+		}
+	}
+	s.Returns = append(s.Returns, ytd)
+
+	yearAgo := hist[n-1].Date.AddDate(-1, 0, 0)
+	oneYear := rangeReturn{Name: "近一年"}
+	for _, q := range hist {
+		if !q.Date.Before(yearAgo) {
+			if !q.Date.After(yearAgo.AddDate(0, 0, 7)) && q.Close > 0 {
+				oneYear = rangeReturn{Name: oneYear.Name, Pct: (last/q.Close - 1) * 100, OK: true}
+			}
+			break
+		}
+	}
+	s.Returns = append(s.Returns, oneYear)
+
+	for _, q := range hist {
+		if q.Date.Before(yearAgo) {
+			continue
+		}
+		if q.High > s.High52W {
+			s.High52W, s.HighDate = q.High, q.Date
+		}
+		if s.Low52W == 0 || q.Low < s.Low52W {
+			s.Low52W, s.LowDate = q.Low, q.Date
+		}
+	}
+	return s
+}
+
+func printDetail(out io.Writer, ix *index.Index, q *sina.SecurityQuote, hist []*eastmoney.Quote) {
+	fmt.Fprintf(out, "指数代码\t%s\n指数名称\t%s\n", ix.ExCode, ix.Name)
+
+	var last float64
+	switch {
+	case q != nil:
+		last = q.Current
+		chg, pct := changeOf(q)
+		fmt.Fprintf(out, "最新点位\t%.2f\n涨跌    \t%+.2f (%+.2f%%)\n今开    \t%.2f\n昨收    \t%.2f\n最高    \t%.2f\n最低    \t%.2f\n成交额  \t%s\n",
+			q.Current, chg, pct, q.Open, q.YClose, q.High, q.Low, utils.HumanNum(q.Volume))
+	case len(hist) > 0:
+		// 中证指数等没有实时行情，使用最新日 K
+		bar := hist[len(hist)-1]
+		last = bar.Close
+		fmt.Fprintf(out, "最新点位\t%.2f (%s 收盘)\n涨跌    \t%+.2f (%+.2f%%)\n成交额  \t%s\n",
+			bar.Close, bar.Date.Format("2006-01-02"), bar.Change, bar.ChangeRate, utils.HumanNum(bar.TurnOver))
+	}
+
+	s := computeStats(hist, last)
+	if !s.HasHistory {
+		return
+	}
+	fmt.Fprintln(out)
+
+	headers := make([]string, 0, len(s.Returns))
+	row := make([]string, 0, len(s.Returns))
+	styles := make([]tablewriter.Colors, 0, len(s.Returns))
+	for _, r := range s.Returns {
+		headers = append(headers, r.Name)
+		if !r.OK {
+			row = append(row, "-")
+			styles = append(styles, tablewriter.Colors{})
+			continue
+		}
+		row = append(row, fmt.Sprintf("%+.2f%%", r.Pct))
+		styles = append(styles, utils.ChangeColor(r.Pct, tablewriter.Bold))
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+	table.Rich(row, styles)
+	table.Render()
+
+	fmt.Fprintf(out, "\n52周最高\t%.2f (%s)\n52周最低\t%.2f (%s)\n",
+		s.High52W, s.HighDate.Format("2006-01-02"), s.Low52W, s.LowDate.Format("2006-01-02"))
+}
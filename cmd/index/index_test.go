@@ -0,0 +1,146 @@
+package index
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
+)
+
+// dailyBars 生成从 begin 开始、收盘价依次为 closes 的日 K
+func dailyBars(begin time.Time, closes ...float64) []*eastmoney.Quote {
+	res := make([]*eastmoney.Quote, 0, len(closes))
+	for i, c := range closes {
+		res = append(res, &eastmoney.Quote{Date: begin.AddDate(0, 0, i), Close: c, High: c + 1, Low: c - 1})
+	}
+	return res
+}
+
+func TestComputeStats(t *testing.T) {
+	s := computeStats(nil, 100)
+	require.False(t, s.HasHistory)
+
+	closes := make([]float64, 0, 70)
+	for i := 0; i < 70; i++ {
+		closes = append(closes, float64(100+i))
+	}
+	// 2025-11-20 起 70 个自然日，跨年
+	hist := dailyBars(time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC), closes...)
+	s = computeStats(hist, 169)
+	require.True(t, s.HasHistory)
+	require.Len(t, s.Returns, 5)
+
+	require.Equal(t, "5日", s.Returns[0].Name)
+	require.True(t, s.Returns[0].OK)
+	require.InDelta(t, (169.0/164-1)*100, s.Returns[0].Pct, 1e-9)
+	require.InDelta(t, (169.0/109-1)*100, s.Returns[2].Pct, 1e-9)
+
+	// 上一年最后一个交易日 2025-12-31 收盘 141
+	require.True(t, s.Returns[3].OK)
+	require.InDelta(t, (169.0/141-1)*100, s.Returns[3].Pct, 1e-9)
+
+	// 历史不足一年
+	require.False(t, s.Returns[4].OK)
+
+	require.Equal(t, 170.0, s.High52W)
+	require.Equal(t, 99.0, s.Low52W)
+	require.Equal(t, hist[69].Date, s.HighDate)
+}
+
+type fakeSource struct{}
+
+func (fakeSource) QueryQuote(ctx context.Context, exCode string) (*sina.SecurityQuote, error) {
+	return &sina.SecurityQuote{ExCode: exCode, Current: 3300, YClose: 3270, High: 3310, Low: 3260, Volume: 4.5e11}, nil
+}
+
+func (f fakeSource) QueryQuoteList(ctx context.Context, exCodes []string) ([]*sina.SecurityQuote, error) {
+	res := make([]*sina.SecurityQuote, 0, len(exCodes))
+	for _, c := range exCodes {
+		q, _ := f.QueryQuote(ctx, c)
+		res = append(res, q)
+	}
+	return res, nil
+}
+
+func (fakeSource) GetQuoteHistory(ctx context.Context, req *eastmoney.GetQuoteHistoryReq) ([]*eastmoney.Quote, error) {
+	return dailyBars(time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), 3200, 3220, 3250, 3240, 3270, 3280, 3290), nil
+}
+
+func TestIndexCLI(t *testing.T) {
+	require.Nil(t, provider.Register("fake", fakeSource{}))
+	t.Cleanup(func() { provider.Unregister("fake") })
+
+	var buf bytes.Buffer
+	cmd := NewIndexCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--source", "fake"})
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, buf.String(), "SH000001")
+	require.Contains(t, buf.String(), "沪深300")
+	require.Contains(t, buf.String(), "+0.92%")
+	require.NotContains(t, buf.String(), "中证A50")
+
+	buf.Reset()
+	cmd = NewIndexCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"000001", "--source", "fake", "--no-cache"})
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, buf.String(), "上证指数")
+	require.Contains(t, buf.String(), "最新点位\t3300.00")
+	require.Contains(t, buf.String(), "年初至今")
+	require.Contains(t, buf.String(), "52周最高")
+
+	// 中证指数没有实时行情，使用最新日 K
+	buf.Reset()
+	cmd = NewIndexCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"a50", "--source", "fake", "--no-cache"})
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, buf.String(), "3290.00 (2026-01-04 收盘)")
+
+	cmd = NewIndexCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"600036", "--source", "fake"})
+	require.NotNil(t, cmd.Execute())
+}
+
+func TestIndexCLIJSON(t *testing.T) {
+	require.Nil(t, provider.Register("fake", fakeSource{}))
+	t.Cleanup(func() { provider.Unregister("fake") })
+
+	run := func(args ...string) []byte {
+		var buf bytes.Buffer
+		cmd := NewIndexCLI()
+		provider.AddFlag(cmd)
+		output.AddFlag(cmd)
+		cmd.SetOut(&buf)
+		cmd.SetArgs(append(args, "--source", "fake", "--no-cache", "--output", "json"))
+		require.Nil(t, cmd.Execute())
+		return buf.Bytes()
+	}
+
+	var list []indexRecord
+	require.Nil(t, json.Unmarshal(run(), &list))
+	require.Equal(t, "SH000001", list[0].ExCode)
+	require.InDelta(t, 3300, *list[0].Price, 1e-9)
+	require.InDelta(t, 4.5e11, *list[0].Amount, 1e-9)
+
+	var detail detailRecord
+	require.Nil(t, json.Unmarshal(run("a50"), &detail))
+	require.Equal(t, "2026-01-04", detail.Date)
+	require.InDelta(t, 3290, *detail.Price, 1e-9)
+	require.InDelta(t, (3290.0/3250-1)*100, *detail.ReturnYTD, 1e-9)
+	require.Nil(t, detail.Return60D)
+	require.Equal(t, "2026-01-04", detail.High52WDate)
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/cninfo"
 	"github.com/alwqx/sec/provider/sina"
 	"github.com/olekukonko/tablewriter"
@@ -24,6 +25,7 @@ func NewInsiderCLI() *cobra.Command {
 		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
 		RunE:              runInsider,
 	}
+	output.Enable(cmd)
 	return cmd
 }
 
@@ -75,20 +77,20 @@ func runInsider(cmd *cobra.Command, args []string) error {
 	if err2 != nil {
 		return fmt.Errorf("查询高管变动公告失败: %w", err2)
 	}
+	f := output.FormatOf(cmd)
 	if zResp == nil && jResp == nil {
+		if f.Structured() {
+			return output.Write(cmd.OutOrStdout(), f, []insiderRecord{})
+		}
 		fmt.Fprintf(cmd.OutOrStdout(), "未找到 %s 的高管增减持公告\n", sec.Name)
 		return nil
 	}
 	num := len(zResp.Data) + len(jResp.Data)
-	if num == 0 {
+	if num == 0 && !f.Structured() {
 		fmt.Fprintf(cmd.OutOrStdout(), "未找到 %s 的高管增减持公告\n", sec.Name)
 		return nil
 	}
 
-	out := cmd.OutOrStdout()
-	fmt.Fprintf(out, "\n证券代码: %s  证券名称: %s\n", sec.ExCode, sec.Name)
-	fmt.Fprintf(out, "高管增减持公告 (共 %d 条)\n\n", num)
-
 	anns := make([]*cninfo.Announcement, 0, num)
 	if zResp != nil {
 		anns = append(anns, zResp.Data...)
@@ -99,6 +101,13 @@ func runInsider(cmd *cobra.Command, args []string) error {
 	sort.Slice(anns, func(i, j int) bool {
 		return anns[i].Time > anns[j].Time
 	})
+	if f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, insiderRecords(sec, anns))
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "\n证券代码: %s  证券名称: %s\n", sec.ExCode, sec.Name)
+	fmt.Fprintf(out, "高管增减持公告 (共 %d 条)\n\n", num)
 
 	headers := []string{"公告日期", "公告标题", "类型", "大小"}
 	data := make([][]string, 0, num)
@@ -145,6 +154,46 @@ func runInsider(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// insiderRecord 增减持公告的结构化输出
+type insiderRecord struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	Code   string `json:"code"`
+	ExCode string `json:"excode"`
+	Name   string `json:"name"`
+	Title  string `json:"title"`
+	Action string `json:"action"` // increase 增持，decrease 减持，标题无法判断时为空
+	Type   string `json:"type"`
+	Size   int64  `json:"size"` // PDF 大小，单位：字节
+	URL    string `json:"url"`  // PDF 下载链接
+}
+
+func insiderRecords(sec *sina.BasicSecurity, anns []*cninfo.Announcement) []insiderRecord {
+	res := make([]insiderRecord, 0, len(anns))
+	for _, a := range anns {
+		if a.ExistFlag != 0 || a.InvalidationFlag != 0 {
+			continue
+		}
+		action := ""
+		if strings.Contains(a.Title, "增持") {
+			action = "increase"
+		} else if strings.Contains(a.Title, "减持") {
+			action = "decrease"
+		}
+		res = append(res, insiderRecord{
+			Date:   time.Unix(a.Time/1000, 0).Format("2006-01-02"),
+			Code:   sec.Code,
+			ExCode: sec.ExCode,
+			Name:   sec.Name,
+			Title:  a.Title,
+			Action: action,
+			Type:   a.TypeName,
+			Size:   a.AdjunctSize,
+			URL:    a.Link(),
+		})
+	}
+	return res
+}
+
 func formatSize(bytes float64) string {
 	if bytes <= 0 {
 		return "-"
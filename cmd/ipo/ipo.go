@@ -12,6 +12,7 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/cninfo"
 	"github.com/alwqx/sec/provider/eastmoney"
 	"github.com/alwqx/sec/provider/sina"
@@ -41,10 +42,12 @@ func NewIPOCLI() *cobra.Command {
 		},
 	}
 
+	list, calendar, prospectus := newListCmd(), newCalendarCmd(), newProspectusCmd()
+	output.Enable(list, calendar, prospectus)
 	root.AddCommand(
-		newListCmd(),
-		newCalendarCmd(),
-		newProspectusCmd(),
+		list,
+		calendar,
+		prospectus,
 		newDownloadCmd(),
 	)
 
@@ -112,6 +115,9 @@ func runList(cmd *cobra.Command, args []string) error {
 		}
 		filtered = append(filtered, it)
 	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, listingRecords(filtered))
+	}
 	if len(filtered) == 0 {
 		fmt.Fprintln(cmd.OutOrStdout(), "未找到 IPO 记录（尝试放宽年份或 --size）")
 		return nil
@@ -122,6 +128,25 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// listingRecord 新股上市的结构化输出
+type listingRecord struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	ListingDate string `json:"listing_date"` // YYYY-MM-DD，未上市为空
+}
+
+func listingRecords(items []*eastmoney.IListing) []listingRecord {
+	res := make([]listingRecord, 0, len(items))
+	for _, it := range items {
+		rec := listingRecord{Code: it.Code, Name: it.Name}
+		if it.ListingDate != "-" {
+			rec.ListingDate = dateSafe(it.ListingDate)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
 func printIPOList(out io.Writer, items []*eastmoney.IListing) {
 	table := tablewriter.NewWriter(out)
 	// 仅保留确定性高的字段（东方财富各股发行价/PE 字段顺序会随 fs 改变，
@@ -197,6 +222,9 @@ func runCalendar(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("获取新股日历失败: %w", err)
 	}
 
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, announcementRecords(announcements))
+	}
 	if len(announcements) == 0 {
 		fmt.Fprintln(cmd.OutOrStdout(), "未找到 IPO 相关公告；请确认 --since/--until 范围正确")
 		return nil
@@ -208,6 +236,31 @@ func runCalendar(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// announcementRecord IPO 公告的结构化输出
+type announcementRecord struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	Size  int64  `json:"size"` // PDF 大小，单位：字节
+	URL   string `json:"url"`  // PDF 下载链接
+}
+
+func announcementRecords(announcements []*cninfo.Announcement) []announcementRecord {
+	res := make([]announcementRecord, 0, len(announcements))
+	for _, a := range announcements {
+		res = append(res, announcementRecord{
+			Date:  dateSafe(a.Date),
+			Code:  a.SecCode,
+			Name:  a.SecName,
+			Title: a.Title,
+			Size:  a.AdjunctSize,
+			URL:   a.PDFURL,
+		})
+	}
+	return res
+}
+
 // dateSafe 若 s 是 YYYYMMDD 格式则算成 YYYY-MM-DD；否则原样返回
 func dateSafe(s string) string {
 	if len(s) == 8 {
@@ -336,6 +389,9 @@ func runProspectus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("查询招股书失败: %w", err)
 	}
 
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, announcementRecords(filterByDate(announcements, since, until)))
+	}
 	if len(announcements) == 0 {
 		fmt.Fprintf(cmd.OutOrStdout(), "未找到 %s (%s) 的 IPO 公告（cninfo 可能未收录或代码有误）\n", secName, stockCode)
 		return nil
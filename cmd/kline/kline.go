@@ -8,8 +8,12 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/cache"
 	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/index"
 	"github.com/alwqx/sec/provider/sina"
 	"github.com/alwqx/sec/render"
 	"github.com/alwqx/sec/types"
@@ -32,11 +36,15 @@ func NewKLineCLI() *cobra.Command {
 	}
 	rootCmd.Flags().StringP("begin", "b", "", "Begin date 20260101")
 	rootCmd.Flags().StringP("end", "e", "", "End date 20260131")
+	rootCmd.Flags().StringP("period", "p", "day", "K-line period: "+strings.Join(eastmoney.PeriodNames(), ", "))
 	rootCmd.Flags().StringP("fq", "f", "", "FuQuan type: bfq none, qfq front, hfq post")
+	rootCmd.Flags().Bool("no-cache", false, "Bypass local K-line history cache")
 	rootCmd.Flags().IntP("height", "H", 20, "Chart height in rows")
 	rootCmd.Flags().Bool("half-block", false, "Use half-block chars for 2x resolution")
 	rootCmd.Flags().Bool("paging", false, "Fixed candle width instead of auto-scaling")
 	rootCmd.Flags().Bool("no-volume", false, "Hide volume subgraph")
+	rootCmd.Flags().Bool("flow", false, "Show daily main net inflow subgraph (A shares)")
+	rootCmd.Flags().Bool("margin", false, "Overlay financing balance scaled to the price range (A shares)")
 	// Indicator overlays
 	rootCmd.Flags().String("ma", "", "MA periods, comma-separated (e.g. 5,20,60)")
 	rootCmd.Flags().String("boll", "", "Bollinger Bands: period,k (e.g. 20,2.0)")
@@ -46,8 +54,17 @@ func NewKLineCLI() *cobra.Command {
 
 // KLineHandler is the handler for sec kline command.
 func KLineHandler(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+		src.History = cache.NewHistory(src.History)
+	}
+	src.Searcher = index.NewSearcher(src.Searcher)
+
 	key := args[0]
-	secs := sina.Search(cmd.Context(), key)
+	secs := src.Searcher.Search(cmd.Context(), key)
 	if len(secs) == 0 {
 		slog.Info("search no sec", "code", key)
 		return nil
@@ -55,6 +72,9 @@ func KLineHandler(cmd *cobra.Command, args []string) error {
 
 	sec := secs[0]
 	slog.Debug("KLineHandler", "excode", sec.ExCode, "code", sec.Code, "exchange", sec.ExChange)
+	if sec.SecurityType == types.SecurityTypeFund && sec.ExChange == "" {
+		return fmt.Errorf("%s %s 是场外基金，没有场内行情，请使用 sec fund 查看净值", sec.Code, sec.Name)
+	}
 	req := &eastmoney.GetQuoteHistoryReq{
 		Code: sec.Code,
 	}
@@ -67,6 +87,8 @@ func KLineHandler(cmd *cobra.Command, args []string) error {
 		req.MarketCode = 116
 	case types.ExChangeNasdaq:
 		req.MarketCode = 105
+	case types.ExChangeCSI:
+		req.MarketCode = int(eastmoney.MarketTypeCSI)
 	default:
 		return fmt.Errorf("unsupported exchange: %s", sec.ExChange)
 	}
@@ -86,9 +108,30 @@ func KLineHandler(cmd *cobra.Command, args []string) error {
 		req.FQT = eastmoney.QuoteFQTDefault
 	}
 
+	periodStr, _ := cmd.Flags().GetString("period")
+	req.Period, err = eastmoney.ParsePeriod(periodStr)
+	if err != nil {
+		return err
+	}
+
+	// 资金流向、融资余额只有沪深日线数据
+	showFlow, _ := cmd.Flags().GetBool("flow")
+	showMargin, _ := cmd.Flags().GetBool("margin")
+	for name, on := range map[string]bool{"flow": showFlow, "margin": showMargin} {
+		if !on {
+			continue
+		}
+		if req.Period != eastmoney.PeriodDay {
+			return fmt.Errorf("--%s only supports period day, got %s", name, periodStr)
+		}
+		if sec.ExChange != "sh" && sec.ExChange != "sz" {
+			return fmt.Errorf("--%s only supports Shanghai and Shenzhen securities, got %s", name, sec.ExCode)
+		}
+	}
+
 	beginStr, _ := cmd.Flags().GetString("begin")
 	endStr, _ := cmd.Flags().GetString("end")
-	req.Begin, req.End, err = utils.ParseBeginEnd(beginStr, endStr, 90, eastmoney.TimeYYMMDD, eastmoney.TimeYYMMDD)
+	req.Begin, req.End, err = utils.ParseBeginEnd(beginStr, endStr, req.Period.Lookback(90), eastmoney.TimeYYMMDD, eastmoney.TimeYYMMDD)
 	if err != nil {
 		return err
 	}
@@ -104,13 +147,17 @@ func KLineHandler(cmd *cobra.Command, args []string) error {
 	opts := new(types.InfoOptions)
 	opts.Code = sec.Code
 	opts.ExCode = sec.ExCode
+	// 指数和基金没有公司资料
+	isFund := sec.SecurityType == types.SecurityTypeFund || types.IsExchangeFund(sec.ExCode)
 	go func() {
 		defer wg.Done()
-		profile, err1 = sina.Profile(cmd.Context(), opts)
+		if sec.SecurityType != types.SecurityTypeIndex && !isFund {
+			profile, err1 = sina.Profile(cmd.Context(), opts)
+		}
 	}()
 	go func() {
 		defer wg.Done()
-		quotes, err2 = eastmoney.GetQuoteHistory(cmd.Context(), req)
+		quotes, err2 = src.History.GetQuoteHistory(cmd.Context(), req)
 	}()
 	wg.Wait()
 
@@ -129,10 +176,18 @@ func KLineHandler(cmd *cobra.Command, args []string) error {
 	}
 
 	// 打印基本信息
-	fmt.Fprintf(cmd.OutOrStdout(), "证券代码\t%s\n公司名称\t%s\n主营业务\t%s\n发行价格\t%.2f\n当前价格\t%.2f\n市净率PB\t%.2f\n市盈率TTM\t%.2f\n总市值  \t%s\n流通市值\t%s\n",
-		sec.ExCode, profile.Name, profile.MainBusiness,
-		profile.ListingPrice, profile.Current, profile.PB, profile.PeTTM,
-		utils.HumanNum(profile.MarketCap), utils.HumanNum(profile.TradedMarketCap))
+	if profile != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "证券代码\t%s\n公司名称\t%s\n主营业务\t%s\n发行价格\t%.2f\n当前价格\t%.2f\n市净率PB\t%.2f\n市盈率TTM\t%.2f\n总市值  \t%s\n流通市值\t%s\n",
+			sec.ExCode, profile.Name, profile.MainBusiness,
+			profile.ListingPrice, profile.Current, profile.PB, profile.PeTTM,
+			utils.HumanNum(profile.MarketCap), utils.HumanNum(profile.TradedMarketCap))
+	} else if isFund {
+		last := quotes[len(quotes)-1]
+		fmt.Fprintf(cmd.OutOrStdout(), "基金代码\t%s\n基金名称\t%s\n最新价格\t%.3f\n", sec.ExCode, sec.Name, last.Close)
+	} else {
+		last := quotes[len(quotes)-1]
+		fmt.Fprintf(cmd.OutOrStdout(), "指数代码\t%s\n指数名称\t%s\n最新点位\t%.2f\n", sec.ExCode, sec.Name, last.Close)
+	}
 
 	// 渲染蜡烛图
 	noVolume, _ := cmd.Flags().GetBool("no-volume")
@@ -148,6 +203,7 @@ func KLineHandler(cmd *cobra.Command, args []string) error {
 		Volume:    !noVolume,
 		Paging:    paging,
 		HalfBlock: halfBlock,
+		Flow:      showFlow,
 	}
 
 	// Compute indicator overlays
@@ -199,10 +255,86 @@ func KLineHandler(cmd *cobra.Command, args []string) error {
 		)
 	}
 
+	if showMargin {
+		// 按自然日估算覆盖 K 线区间所需的交易日数
+		limit := int(time.Since(quotes[0].Date).Hours()/24)*5/7 + 10
+		margins, err := eastmoney.QueryMarginHistory(cmd.Context(), req.Code, limit)
+		if err != nil {
+			return err
+		}
+		values, lo, hi := marginOverlay(quotes, margins)
+		if hi > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "融资余额\t%s ~ %s\n", utils.HumanNum(lo), utils.HumanNum(hi))
+			cfg.Overlays = append(cfg.Overlays, render.OverlayLine{Values: values, Color: render.AnsiBlue, Label: "MARGIN", Style: '+'})
+		}
+	}
+
 	candles := toCandles(quotes)
+	if showFlow {
+		flows, err := eastmoney.QueryFundFlowHistory(cmd.Context(), eastmoney.MarketType(req.MarketCode), req.Code)
+		if err != nil {
+			return err
+		}
+		applyFlows(candles, flows)
+	}
 	return render.Render(cmd.OutOrStdout(), candles, cfg)
 }
 
+// applyFlows sets the main net inflow of each candle from the daily flows of
+// the same date. Candles without flow data, which East Money only keeps for
+// recent months, are left at zero.
+func applyFlows(candles []render.Candle, flows []*eastmoney.FundFlow) {
+	byDate := make(map[string]float64, len(flows))
+	for _, f := range flows {
+		byDate[f.Time.Format(time.DateOnly)] = f.Main
+	}
+	for i := range candles {
+		candles[i].Flow = byDate[candles[i].Date.Format(time.DateOnly)]
+	}
+}
+
+// marginOverlay maps the financing balance of each quote's date linearly onto
+// the price range of quotes so it can be drawn as an overlay line. It also
+// returns the lowest and highest balance; dates without data are 0.
+func marginOverlay(quotes []*eastmoney.Quote, margins []*eastmoney.Margin) (values []float64, lo, hi float64) {
+	byDate := make(map[string]float64, len(margins))
+	for _, m := range margins {
+		if m.FinBalance > 0 {
+			byDate[m.Date.Format(time.DateOnly)] = m.FinBalance
+		}
+	}
+	values = make([]float64, len(quotes))
+	if len(quotes) == 0 {
+		return values, 0, 0
+	}
+	minLow, maxHigh := quotes[0].Low, quotes[0].High
+	for _, q := range quotes {
+		minLow = min(minLow, q.Low)
+		maxHigh = max(maxHigh, q.High)
+		if b, ok := byDate[q.Date.Format(time.DateOnly)]; ok {
+			if lo == 0 || b < lo {
+				lo = b
+			}
+			hi = max(hi, b)
+		}
+	}
+	if hi == 0 {
+		return values, 0, 0
+	}
+	for i, q := range quotes {
+		b, ok := byDate[q.Date.Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+		if hi == lo {
+			values[i] = (minLow + maxHigh) / 2
+			continue
+		}
+		values[i] = minLow + (b-lo)/(hi-lo)*(maxHigh-minLow)
+	}
+	return values, lo, hi
+}
+
 // toCandles converts eastmoney Quote slice to render Candle slice.
 func toCandles(quotes []*eastmoney.Quote) []render.Candle {
 	candles := make([]render.Candle, 0, len(quotes))
@@ -0,0 +1,39 @@
+package kline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/render"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarginOverlay(t *testing.T) {
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	quotes := []*eastmoney.Quote{
+		{Date: day, Low: 40, High: 41},
+		{Date: day.AddDate(0, 0, 1), Low: 41, High: 42},
+		{Date: day.AddDate(0, 0, 2), Low: 42, High: 44},
+	}
+	margins := []*eastmoney.Margin{
+		{Date: day.AddDate(0, 0, 2), FinBalance: 6.2e9},
+		{Date: day.AddDate(0, 0, 1), FinBalance: 6.0e9},
+	}
+	values, lo, hi := marginOverlay(quotes, margins)
+	require.Equal(t, 6.0e9, lo)
+	require.Equal(t, 6.2e9, hi)
+	require.Equal(t, []float64{0, 40, 44}, values)
+
+	values, _, hi = marginOverlay(quotes, nil)
+	require.Zero(t, hi)
+	require.Equal(t, []float64{0, 0, 0}, values)
+}
+
+func TestApplyFlows(t *testing.T) {
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.Local)
+	candles := []render.Candle{{Date: day}, {Date: day.AddDate(0, 0, 1)}}
+	applyFlows(candles, []*eastmoney.FundFlow{{Time: day.AddDate(0, 0, 1), Main: -3e7}})
+	require.Zero(t, candles[0].Flow)
+	require.Equal(t, -3e7, candles[1].Flow)
+}
@@ -0,0 +1,418 @@
+package lhb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewLHBCLI returns the lhb command.
+func NewLHBCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "lhb [code]",
+		Aliases: []string{"billboard"},
+		Short:   "Print dragon-tiger list and block trades",
+		Long: `Print the dragon-tiger list (龙虎榜) of the latest trading day with the reason
+each stock was listed. With a code, print the listings of that stock and the
+top buying and selling seats of its latest listing. Use --seat to follow a
+seat, and --block for block trades (大宗交易) with their premium or discount.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: runLHB,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("begin", "b", "", "Begin date 20260101, default the latest trading day, or 1 year ago with a code")
+	cmd.Flags().StringP("end", "e", "", "End date 20260131, default today")
+	cmd.Flags().String("seat", "", "Show trades of seats whose name contains this")
+	cmd.Flags().Bool("block", false, "Show block trades instead of the dragon-tiger list")
+	cmd.Flags().IntP("limit", "n", 50, "Number of rows to print, 0 for all")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runLHB(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return fmt.Errorf("invalid --limit %d: must be >= 0", limit)
+	}
+	beginStr, _ := cmd.Flags().GetString("begin")
+	endStr, _ := cmd.Flags().GetString("end")
+	seat, _ := cmd.Flags().GetString("seat")
+	block, _ := cmd.Flags().GetBool("block")
+	f := output.FormatOf(cmd)
+
+	var code, title string
+	if len(args) == 1 {
+		secs := sina.Search(ctx, args[0])
+		if len(secs) == 0 {
+			if f.Structured() {
+				return output.Write(out, f, []stockRecord{})
+			}
+			fmt.Fprintf(out, "未找到证券: %s\n", args[0])
+			return nil
+		}
+		sec := secs[0]
+		if sec.ExChange != "sh" && sec.ExChange != "sz" {
+			return fmt.Errorf("%s %s 不是沪深 A 股，没有龙虎榜数据", sec.ExCode, sec.Name)
+		}
+		code, title = sec.Code, sec.ExCode+" "+sec.Name
+	}
+
+	// 未指定起始日期时：个股查近一年，席位查近 30 天，全市场只看最近一个交易日
+	defaultDays := 10
+	switch {
+	case code != "":
+		defaultDays = 365
+	case seat != "":
+		defaultDays = 30
+	}
+	begin, end, err := parseRange(beginStr, endStr, defaultDays)
+	if err != nil {
+		return err
+	}
+	latestOnly := beginStr == "" && code == "" && seat == ""
+
+	switch {
+	case block:
+		trades, err := eastmoney.QueryBlockTrades(ctx, begin, end, code)
+		if err != nil {
+			return err
+		}
+		if latestOnly {
+			trades = latestDay(trades, func(t *eastmoney.BlockTrade) time.Time { return t.Date })
+		}
+		if f.Structured() {
+			return output.Write(out, f, blockRecords(head(trades, limit)))
+		}
+		if len(trades) == 0 {
+			fmt.Fprintln(out, "暂无大宗交易数据")
+			return nil
+		}
+		printBlockTrades(out, title, trades, limit)
+	case seat != "":
+		seats, err := eastmoney.QuerySeatTrades(ctx, begin, end, seat)
+		if err != nil {
+			return err
+		}
+		if code != "" {
+			seats = slices.DeleteFunc(seats, func(s *eastmoney.LHBSeat) bool { return s.Code != code })
+		}
+		if f.Structured() {
+			return output.Write(out, f, seatRecords(head(seats, limit)))
+		}
+		if len(seats) == 0 {
+			fmt.Fprintf(out, "%s ~ %s 未找到席位: %s\n", begin.Format(time.DateOnly), end.Format(time.DateOnly), seat)
+			return nil
+		}
+		printSeatTrades(out, seats, limit)
+	case code != "":
+		return runStock(ctx, out, f, title, code, begin, end, limit)
+	default:
+		list, err := eastmoney.QueryLHB(ctx, begin, end, "")
+		if err != nil {
+			return err
+		}
+		if latestOnly {
+			list = latestDay(list, func(s *eastmoney.LHBStock) time.Time { return s.Date })
+		}
+		if f.Structured() {
+			return output.Write(out, f, stockRecords(head(list, limit)))
+		}
+		if len(list) == 0 {
+			fmt.Fprintln(out, "暂无龙虎榜数据")
+			return nil
+		}
+		printList(out, list, limit)
+	}
+	return nil
+}
+
+// parseRange parses begin and end in YYYYMMDD, defaulting to defaultDays ago
+// and today.
+func parseRange(beginStr, endStr string, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	begin := end.AddDate(0, 0, -defaultDays)
+	var err error
+	if beginStr != "" {
+		if begin, err = time.ParseInLocation(eastmoney.TimeYYMMDD, beginStr, time.Local); err != nil {
+			return begin, end, fmt.Errorf("invalid --begin %q: %w", beginStr, err)
+		}
+	}
+	if endStr != "" {
+		if end, err = time.ParseInLocation(eastmoney.TimeYYMMDD, endStr, time.Local); err != nil {
+			return begin, end, fmt.Errorf("invalid --end %q: %w", endStr, err)
+		}
+	}
+	if end.Before(begin) {
+		return begin, end, fmt.Errorf("invalid time range: begin=%s end=%s", begin.Format(time.DateOnly), end.Format(time.DateOnly))
+	}
+	return begin, end, nil
+}
+
+// latestDay keeps the items of the latest date in list, which is sorted newest
+// first.
+func latestDay[T any](list []T, date func(T) time.Time) []T {
+	for i, item := range list {
+		if !date(item).Equal(date(list[0])) {
+			return list[:i]
+		}
+	}
+	return list
+}
+
+func head[T any](list []T, limit int) []T {
+	if limit > 0 && len(list) > limit {
+		return list[:limit]
+	}
+	return list
+}
+
+// runStock prints the listings of code and the seats of its latest listing.
+// Structured output only has the listings; use --seat for seat trades.
+func runStock(ctx context.Context, out io.Writer, f output.Format, title, code string, begin, end time.Time, limit int) error {
+	list, err := eastmoney.QueryLHB(ctx, begin, end, code)
+	if err != nil {
+		return err
+	}
+	if f.Structured() {
+		return output.Write(out, f, stockRecords(head(list, limit)))
+	}
+	if len(list) == 0 {
+		fmt.Fprintf(out, "%s %s ~ %s 未上龙虎榜\n", title, begin.Format(time.DateOnly), end.Format(time.DateOnly))
+		return nil
+	}
+	fmt.Fprintf(out, "%s 龙虎榜 (%d 次上榜)\n", title, len(list))
+	table := utils.NewTable(out, []string{"日期", "收盘价", "涨跌幅", "净买额", "买入额", "卖出额", "上榜原因"})
+	for _, s := range head(list, limit) {
+		table.Rich([]string{
+			s.Date.Format(time.DateOnly),
+			fmt.Sprintf("%.2f", s.Close),
+			fmt.Sprintf("%+.2f%%", s.ChangeRate),
+			utils.SignedNum(s.NetBuy),
+			utils.HumanNum(s.Buy),
+			utils.HumanNum(s.Sell),
+			s.Reason,
+		}, []tablewriter.Colors{{}, {}, utils.ChangeColor(s.ChangeRate), utils.ChangeColor(s.NetBuy), {}, {}, {}})
+	}
+	table.Render()
+
+	latest := list[0].Date
+	buys, sells, err := eastmoney.QueryLHBSeats(ctx, latest, code)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%s 买入前五\n", latest.Format(time.DateOnly))
+	printSeats(out, head(buys, 5))
+	fmt.Fprintf(out, "\n%s 卖出前五\n", latest.Format(time.DateOnly))
+	printSeats(out, head(sells, 5))
+	return nil
+}
+
+func printList(out io.Writer, list []*eastmoney.LHBStock, limit int) {
+	first, last := list[len(list)-1].Date, list[0].Date
+	if first.Equal(last) {
+		fmt.Fprintf(out, "%s 龙虎榜 (%d 条)\n", last.Format(time.DateOnly), len(list))
+	} else {
+		fmt.Fprintf(out, "%s ~ %s 龙虎榜 (%d 条)\n", first.Format(time.DateOnly), last.Format(time.DateOnly), len(list))
+	}
+	table := utils.NewTable(out, []string{"日期", "代码", "名称", "收盘价", "涨跌幅", "净买额", "龙虎榜成交占比", "解读", "上榜原因"})
+	for _, s := range head(list, limit) {
+		ratio := "-"
+		if s.Amount > 0 {
+			ratio = fmt.Sprintf("%.2f%%", (s.Buy+s.Sell)/s.Amount*100)
+		}
+		table.Rich([]string{
+			s.Date.Format(time.DateOnly),
+			s.Code,
+			s.Name,
+			fmt.Sprintf("%.2f", s.Close),
+			fmt.Sprintf("%+.2f%%", s.ChangeRate),
+			utils.SignedNum(s.NetBuy),
+			ratio,
+			s.Explain,
+			s.Reason,
+		}, []tablewriter.Colors{{}, {}, {}, {}, utils.ChangeColor(s.ChangeRate), utils.ChangeColor(s.NetBuy), {}, {}, {}})
+	}
+	table.Render()
+}
+
+func printSeats(out io.Writer, seats []*eastmoney.LHBSeat) {
+	table := utils.NewTable(out, []string{"席位", "买入额", "卖出额", "净额"})
+	for _, s := range seats {
+		table.Rich([]string{s.Seat, utils.HumanNum(s.Buy), utils.HumanNum(s.Sell), utils.SignedNum(s.Net)},
+			[]tablewriter.Colors{{}, {}, {}, utils.ChangeColor(s.Net)})
+	}
+	table.Render()
+}
+
+func printSeatTrades(out io.Writer, seats []*eastmoney.LHBSeat, limit int) {
+	var net float64
+	names := make(map[string]bool)
+	for _, s := range seats {
+		net += s.Net
+		names[s.Seat] = true
+	}
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	slices.Sort(list)
+	fmt.Fprintf(out, "匹配席位\t%s\n上榜次数\t%d\n合计净额\t%s\n\n", strings.Join(head(list, 5), ", "), len(seats), utils.SignedNum(net))
+
+	table := utils.NewTable(out, []string{"日期", "代码", "名称", "席位", "买入额", "卖出额", "净额"})
+	for _, s := range head(seats, limit) {
+		table.Rich([]string{s.Date.Format(time.DateOnly), s.Code, s.Name, s.Seat, utils.HumanNum(s.Buy), utils.HumanNum(s.Sell), utils.SignedNum(s.Net)},
+			[]tablewriter.Colors{{}, {}, {}, {}, {}, {}, utils.ChangeColor(s.Net)})
+	}
+	table.Render()
+}
+
+func printBlockTrades(out io.Writer, title string, trades []*eastmoney.BlockTrade, limit int) {
+	var amount float64
+	for _, t := range trades {
+		amount += t.Amount
+	}
+	if title == "" {
+		title = "全市场"
+	}
+	fmt.Fprintf(out, "%s 大宗交易 (%d 笔，成交额 %s)\n", title, len(trades), utils.HumanNum(amount))
+
+	table := utils.NewTable(out, []string{"日期", "代码", "名称", "收盘价", "成交价", "溢价率", "成交量", "成交额", "买方营业部", "卖方营业部"})
+	for _, t := range head(trades, limit) {
+		table.Rich([]string{
+			t.Date.Format(time.DateOnly),
+			t.Code,
+			t.Name,
+			fmt.Sprintf("%.2f", t.Close),
+			fmt.Sprintf("%.2f", t.Price),
+			fmt.Sprintf("%+.2f%%", t.Premium),
+			utils.HumanNum(t.Volume),
+			utils.HumanNum(t.Amount),
+			t.Buyer,
+			t.Seller,
+		}, []tablewriter.Colors{{}, {}, {}, {}, {}, utils.ChangeColor(t.Premium), {}, {}, {}, {}})
+	}
+	table.Render()
+}
+
+// stockRecord 龙虎榜上榜记录的结构化输出，金额单位为元
+type stockRecord struct {
+	Date        string   `json:"date"`
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	Close       float64  `json:"close"`
+	ChangeRate  float64  `json:"change_rate"` // 涨跌幅 %
+	NetBuy      float64  `json:"net_buy"`
+	Buy         float64  `json:"buy"`
+	Sell        float64  `json:"sell"`
+	Amount      float64  `json:"amount"`        // 市场总成交额
+	Turnover    float64  `json:"turnover_rate"` // 换手率 %
+	AmountRatio *float64 `json:"amount_ratio"`  // 龙虎榜成交占比 %
+	Explain     string   `json:"explain"`
+	Reason      string   `json:"reason"`
+}
+
+func stockRecords(list []*eastmoney.LHBStock) []stockRecord {
+	res := make([]stockRecord, 0, len(list))
+	for _, s := range list {
+		rec := stockRecord{
+			Date:       s.Date.Format(time.DateOnly),
+			Code:       s.Code,
+			Name:       s.Name,
+			Close:      s.Close,
+			ChangeRate: s.ChangeRate,
+			NetBuy:     s.NetBuy,
+			Buy:        s.Buy,
+			Sell:       s.Sell,
+			Amount:     s.Amount,
+			Turnover:   s.Turnover,
+			Explain:    s.Explain,
+			Reason:     s.Reason,
+		}
+		if s.Amount > 0 {
+			rec.AmountRatio = output.Num((s.Buy + s.Sell) / s.Amount * 100)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// seatRecord 营业部席位一次上榜的买卖
+type seatRecord struct {
+	Date string  `json:"date"`
+	Code string  `json:"code"`
+	Name string  `json:"name"`
+	Seat string  `json:"seat"`
+	Buy  float64 `json:"buy"`
+	Sell float64 `json:"sell"`
+	Net  float64 `json:"net"`
+}
+
+func seatRecords(seats []*eastmoney.LHBSeat) []seatRecord {
+	res := make([]seatRecord, 0, len(seats))
+	for _, s := range seats {
+		res = append(res, seatRecord{
+			Date: s.Date.Format(time.DateOnly),
+			Code: s.Code,
+			Name: s.Name,
+			Seat: s.Seat,
+			Buy:  s.Buy,
+			Sell: s.Sell,
+			Net:  s.Net,
+		})
+	}
+	return res
+}
+
+// blockRecord 一笔大宗交易
+type blockRecord struct {
+	Date       string  `json:"date"`
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Close      float64 `json:"close"`
+	ChangeRate float64 `json:"change_rate"`
+	Price      float64 `json:"price"`
+	Premium    float64 `json:"premium_rate"` // 溢价率 %，负数为折价
+	Volume     float64 `json:"volume"`       // 成交量，单位：股
+	Amount     float64 `json:"amount"`
+	Buyer      string  `json:"buyer"`
+	Seller     string  `json:"seller"`
+}
+
+func blockRecords(trades []*eastmoney.BlockTrade) []blockRecord {
+	res := make([]blockRecord, 0, len(trades))
+	for _, t := range trades {
+		res = append(res, blockRecord{
+			Date:       t.Date.Format(time.DateOnly),
+			Code:       t.Code,
+			Name:       t.Name,
+			Close:      t.Close,
+			ChangeRate: t.ChangeRate,
+			Price:      t.Price,
+			Premium:    t.Premium,
+			Volume:     t.Volume,
+			Amount:     t.Amount,
+			Buyer:      t.Buyer,
+			Seller:     t.Seller,
+		})
+	}
+	return res
+}
@@ -0,0 +1,82 @@
+package lhb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	begin, end, err := parseRange("20261010", "20261016", 30)
+	require.NoError(t, err)
+	require.Equal(t, "2026-10-10", begin.Format(time.DateOnly))
+	require.Equal(t, "2026-10-16", end.Format(time.DateOnly))
+
+	begin, end, err = parseRange("", "", 30)
+	require.NoError(t, err)
+	require.Equal(t, 30*24*time.Hour, end.Sub(begin).Round(time.Hour))
+
+	_, _, err = parseRange("20261016", "20261010", 30)
+	require.Error(t, err)
+	_, _, err = parseRange("2026-10-10", "", 30)
+	require.ErrorContains(t, err, "--begin")
+}
+
+func TestLatestDay(t *testing.T) {
+	d1 := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	d2 := d1.AddDate(0, 0, -1)
+	list := []*eastmoney.LHBStock{{Date: d1, Code: "600036"}, {Date: d1, Code: "000001"}, {Date: d2, Code: "600519"}}
+	got := latestDay(list, func(s *eastmoney.LHBStock) time.Time { return s.Date })
+	require.Len(t, got, 2)
+	require.Empty(t, latestDay([]*eastmoney.LHBStock{}, func(s *eastmoney.LHBStock) time.Time { return s.Date }))
+}
+
+func TestPrint(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	var buf bytes.Buffer
+	printList(&buf, []*eastmoney.LHBStock{
+		{Date: day, Code: "600036", Name: "招商银行", Reason: "日涨幅偏离值达到7%", Close: 44.1, ChangeRate: 10, NetBuy: -1.2e8, Buy: 1e8, Sell: 2.2e8, Amount: 1.6e9},
+	}, 0)
+	out := buf.String()
+	require.Contains(t, out, "2026-10-16 龙虎榜 (1 条)")
+	require.Contains(t, out, "-1.20亿")
+	require.Contains(t, out, "20.00%")
+
+	buf.Reset()
+	printBlockTrades(&buf, "", []*eastmoney.BlockTrade{
+		{Date: day, Code: "600036", Name: "招商银行", Close: 44.1, Price: 40, Premium: -9.3, Volume: 1e6, Amount: 4e7, Buyer: "机构专用", Seller: "中信证券"},
+	}, 0)
+	out = buf.String()
+	require.Contains(t, out, "全市场 大宗交易 (1 笔")
+	require.Contains(t, out, "-9.30%")
+
+	buf.Reset()
+	printSeatTrades(&buf, []*eastmoney.LHBSeat{
+		{Date: day, Code: "600036", Name: "招商银行", Seat: "华泰证券上海武定路", Buy: 5e7, Net: 5e7},
+		{Date: day, Code: "000001", Name: "平安银行", Seat: "华泰证券上海武定路", Sell: 2e7, Net: -2e7},
+	}, 0)
+	out = buf.String()
+	require.Contains(t, out, "上榜次数\t2")
+	require.Contains(t, out, "+3000.00万")
+}
+
+func TestRecords(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	stocks := stockRecords([]*eastmoney.LHBStock{
+		{Date: day, Code: "600036", Buy: 1e8, Sell: 2.2e8, Amount: 1.6e9},
+		{Date: day, Code: "000001"},
+	})
+	require.Equal(t, "2026-10-16", stocks[0].Date)
+	require.InDelta(t, 20, *stocks[0].AmountRatio, 1e-9)
+	require.Nil(t, stocks[1].AmountRatio)
+
+	seats := seatRecords([]*eastmoney.LHBSeat{{Date: day, Code: "600036", Seat: "机构专用", Buy: 5e7, Net: 5e7}})
+	require.Equal(t, seatRecord{Date: "2026-10-16", Code: "600036", Seat: "机构专用", Buy: 5e7, Net: 5e7}, seats[0])
+
+	blocks := blockRecords([]*eastmoney.BlockTrade{{Date: day, Code: "600036", Price: 40, Premium: -9.3, Amount: 4e7}})
+	require.Equal(t, -9.3, blocks[0].Premium)
+	require.Equal(t, 4e7, blocks[0].Amount)
+}
@@ -0,0 +1,166 @@
+package margin
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewMarginCLI returns the margin command.
+func NewMarginCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "margin [code]",
+		Aliases: []string{"rzrq"},
+		Short:   "Print margin trading and securities lending balances",
+		Long: `Print daily margin trading (融资) and securities lending (融券) data of the
+whole Shanghai and Shenzhen market, or of a stock with a code: financing
+balance, financing buy, short balance and their day-over-day changes.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: runMargin,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().IntP("days", "n", 20, "Number of trading days")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runMargin(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	days, _ := cmd.Flags().GetInt("days")
+	if days <= 0 {
+		return fmt.Errorf("invalid --days %d: must be > 0", days)
+	}
+
+	f := output.FormatOf(cmd)
+	code, title := "", "沪深两市"
+	if len(args) == 1 {
+		secs := sina.Search(ctx, args[0])
+		if len(secs) == 0 {
+			if f.Structured() {
+				return output.Write(out, f, []marginRecord{})
+			}
+			fmt.Fprintf(out, "未找到证券: %s\n", args[0])
+			return nil
+		}
+		sec := secs[0]
+		if sec.ExChange != "sh" && sec.ExChange != "sz" {
+			return fmt.Errorf("%s %s 不是沪深 A 股，没有融资融券数据", sec.ExCode, sec.Name)
+		}
+		code, title = sec.Code, sec.ExCode+" "+sec.Name
+	}
+
+	// 多取一天用于计算最早一天的变动
+	list, err := eastmoney.QueryMarginHistory(ctx, code, days+1)
+	if err != nil {
+		return err
+	}
+	if f.Structured() {
+		return output.Write(out, f, marginRecords(code, list, days))
+	}
+	if len(list) == 0 {
+		fmt.Fprintf(out, "%s 暂无融资融券数据\n", title)
+		return nil
+	}
+	fmt.Fprintf(out, "%s 融资融券\n", title)
+	printMargins(out, list, days, code == "")
+	return nil
+}
+
+// printMargins prints the latest days records of list, which is newest first,
+// with changes from the previous trading day.
+func printMargins(out io.Writer, list []*eastmoney.Margin, days int, market bool) {
+	closeHeader := "收盘价"
+	if market {
+		closeHeader = "沪深300"
+	}
+	table := utils.NewTable(out, []string{"日期", closeHeader, "涨跌幅", "融资余额", "融资余额变动", "融资买入额", "融资净买入", "融券余额", "融券余额变动", "两融余额"})
+	for i, m := range list {
+		if i >= days {
+			break
+		}
+		finChange, shortChange := "-", "-"
+		var finDiff, shortDiff float64
+		if i+1 < len(list) {
+			prev := list[i+1]
+			finDiff, shortDiff = m.FinBalance-prev.FinBalance, m.ShortBalance-prev.ShortBalance
+			finChange, shortChange = utils.SignedNum(finDiff), utils.SignedNum(shortDiff)
+		}
+		table.Rich([]string{
+			m.Date.Format(time.DateOnly),
+			fmt.Sprintf("%.2f", m.Close),
+			fmt.Sprintf("%+.2f%%", m.ChangeRate),
+			utils.HumanNum(m.FinBalance),
+			finChange,
+			utils.HumanNum(m.FinBuy),
+			utils.SignedNum(m.FinNetBuy),
+			utils.HumanNum(m.ShortBalance),
+			shortChange,
+			utils.HumanNum(m.Total),
+		}, []tablewriter.Colors{{}, {}, utils.ChangeColor(m.ChangeRate), {}, utils.ChangeColor(finDiff), {}, utils.ChangeColor(m.FinNetBuy), {}, utils.ChangeColor(shortDiff), {}})
+	}
+	table.Render()
+}
+
+// marginRecord 融资融券的一个交易日，金额单位为元
+type marginRecord struct {
+	Date               string   `json:"date"`
+	Code               string   `json:"code"`  // 沪深两市合计时为空
+	Close              float64  `json:"close"` // 沪深两市合计时为沪深 300 点位
+	ChangeRate         float64  `json:"change_rate"`
+	FinBalance         float64  `json:"fin_balance"`
+	FinBalanceChange   *float64 `json:"fin_balance_change"` // 较上一交易日，最早一天为空
+	FinBuy             float64  `json:"fin_buy"`
+	FinRepay           float64  `json:"fin_repay"`
+	FinNetBuy          float64  `json:"fin_net_buy"`
+	ShortBalance       float64  `json:"short_balance"`
+	ShortBalanceChange *float64 `json:"short_balance_change"`
+	ShortVolume        float64  `json:"short_volume"` // 融券余量，单位：股
+	ShortSell          float64  `json:"short_sell"`   // 融券卖出量，单位：股
+	Total              float64  `json:"total"`
+}
+
+// marginRecords converts the latest days records of list, which is newest
+// first, like printMargins.
+func marginRecords(code string, list []*eastmoney.Margin, days int) []marginRecord {
+	res := make([]marginRecord, 0, min(days, len(list)))
+	for i, m := range list {
+		if i >= days {
+			break
+		}
+		rec := marginRecord{
+			Date:         m.Date.Format(time.DateOnly),
+			Code:         code,
+			Close:        m.Close,
+			ChangeRate:   m.ChangeRate,
+			FinBalance:   m.FinBalance,
+			FinBuy:       m.FinBuy,
+			FinRepay:     m.FinRepay,
+			FinNetBuy:    m.FinNetBuy,
+			ShortBalance: m.ShortBalance,
+			ShortVolume:  m.ShortVolume,
+			ShortSell:    m.ShortSell,
+			Total:        m.Total,
+		}
+		if i+1 < len(list) {
+			prev := list[i+1]
+			rec.FinBalanceChange = output.Num(m.FinBalance - prev.FinBalance)
+			rec.ShortBalanceChange = output.Num(m.ShortBalance - prev.ShortBalance)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
@@ -0,0 +1,41 @@
+package margin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintMargins(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	list := []*eastmoney.Margin{
+		{Date: day, FinBalance: 6.2e9, FinBuy: 3e8, FinNetBuy: 5e7, ShortBalance: 1.2e7, Total: 6.212e9, Close: 41.8, ChangeRate: 1.46},
+		{Date: day.AddDate(0, 0, -1), FinBalance: 6.15e9, ShortBalance: 1.3e7, Total: 6.163e9, Close: 41.2},
+		{Date: day.AddDate(0, 0, -2), FinBalance: 6.1e9, ShortBalance: 1.3e7, Total: 6.113e9, Close: 41.0},
+	}
+	var buf bytes.Buffer
+	printMargins(&buf, list, 2, false)
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[0], "收盘价")
+	require.Contains(t, lines[1], "+5000.00万")
+	require.Contains(t, lines[1], "-100.00万")
+	require.NotContains(t, out, "2026-10-14")
+
+	buf.Reset()
+	printMargins(&buf, list[2:], 2, true)
+	require.Contains(t, buf.String(), "沪深300")
+
+	recs := marginRecords("600036", list, 2)
+	require.Len(t, recs, 2)
+	require.Equal(t, "2026-10-16", recs[0].Date)
+	require.Equal(t, "600036", recs[0].Code)
+	require.InDelta(t, 5e7, *recs[0].FinBalanceChange, 1e-6)
+	require.InDelta(t, -1e6, *recs[0].ShortBalanceChange, 1e-6)
+	require.Nil(t, marginRecords("", list[2:], 2)[0].FinBalanceChange)
+}
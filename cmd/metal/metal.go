@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/metal"
 	"github.com/alwqx/sec/utils"
 	"github.com/spf13/cobra"
@@ -17,27 +18,49 @@ func NewMetalCLI() *cobra.Command {
 		RunE:    MetalHandler,
 	}
 	rootCmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	addInstFlag(rootCmd)
+	output.Enable(rootCmd)
+	rootCmd.AddCommand(NewMetalConvertCLI())
 
 	return rootCmd
 }
 
+// addInstFlag adds the flag choosing the SGE instrument.
+func addInstFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("inst", "i", metal.InstAu9999, "SGE instrument, e.g. Au99.99, Au(T+D), mAu(T+D), Ag(T+D), Pt99.95")
+}
+
+// parseInstFlag parses the flag added by addInstFlag.
+func parseInstFlag(cmd *cobra.Command) (metal.Instrument, error) {
+	s, _ := cmd.Flags().GetString("inst")
+	return metal.ParseInstrument(s)
+}
+
 // MetalHandler 打印贵金属最新行情数据，默认 Au999
 func MetalHandler(cmd *cobra.Command, args []string) error {
+	inst, err := parseInstFlag(cmd)
+	if err != nil {
+		return err
+	}
 	end := time.Now()
-	req := &metal.QueryAu999Req{
+	req := &metal.QueryDailyHQReq{
+		Inst:  inst.ID,
 		Start: end.Add(-10 * 24 * time.Hour).Format(utils.LayoutYYMMDD),
 		End:   end.Format(utils.LayoutYYMMDD),
 	}
-	resp, err := metal.QueryAu999(cmd.Context(), req)
+	resp, err := metal.QueryDailyHQ(cmd.Context(), req)
 	if err != nil {
 		return err
 	}
 	num := len(resp.Data)
 	if num == 0 {
 		slog.Warn("no data")
-	} else {
-		printAu999History(cmd.OutOrStdout(), resp.Data[num-1:])
+		return nil
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, hqRecords(inst, resp.Data[num-1:]))
 	}
+	printDailyHQ(cmd.OutOrStdout(), inst, resp.Data[num-1:])
 
 	return nil
 }
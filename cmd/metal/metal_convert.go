@@ -0,0 +1,169 @@
+package metal
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/fx"
+	"github.com/alwqx/sec/provider/metal"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func NewMetalConvertCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Compare SGE gold with international gold converted to CNY per gram",
+		Long: `Compare the SGE gold price in CNY per gram with London spot gold in USD per
+ounce converted at the USD/CNY rate (USD/oz × rate ÷ 31.1034768), showing the
+domestic premium in CNY per gram and in percent. The rate is the onshore spot
+rate by default, or the central parity or offshore rate with --kind.`,
+		Args: cobra.NoArgs,
+		RunE: MetalConvertHandler,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("begin", "b", "", "Begin date 20250101, default 10 days ago")
+	cmd.Flags().StringP("end", "e", "", "End date 20250131, default today")
+	cmd.Flags().StringP("kind", "k", string(fx.KindOnshore), "USD/CNY rate kind: parity, onshore or offshore")
+	addInstFlag(cmd)
+	output.Enable(cmd)
+
+	return cmd
+}
+
+// MetalConvertHandler 打印上金所金价与国际金价折算价的对比
+func MetalConvertHandler(cmd *cobra.Command, args []string) error {
+	inst, err := parseInstFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if !inst.IsGold() {
+		return fmt.Errorf("%s is not a gold instrument, conversion only supports gold", inst.ID)
+	}
+	kindStr, _ := cmd.Flags().GetString("kind")
+	kind, err := fx.ParseKind(kindStr)
+	if err != nil {
+		return err
+	}
+	beginStr, _ := cmd.Flags().GetString("begin")
+	endStr, _ := cmd.Flags().GetString("end")
+	start, end, err := utils.ParseBeginEnd(beginStr, endStr, 10, utils.ParseMetalCmdArgTimeLayout, utils.LayoutYYMMDD)
+	if err != nil {
+		return err
+	}
+
+	// 国际金价和汇率多取 10 天，保证首日之前有数据
+	startTime, err := time.Parse(utils.LayoutYYMMDD, start)
+	if err != nil {
+		return err
+	}
+	from := startTime.AddDate(0, 0, -10).Format(utils.LayoutYYMMDD)
+	var (
+		wg          sync.WaitGroup
+		resp        *metal.QueryDailyHQResp
+		intl, rates []metal.DailyPrice
+		errs        [3]error
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		resp, errs[0] = metal.QueryDailyHQ(cmd.Context(), &metal.QueryDailyHQReq{Inst: inst.ID, Start: start, End: end})
+	}()
+	go func() {
+		defer wg.Done()
+		intl, errs[1] = metal.QueryIntlGold(cmd.Context(), from, end)
+	}()
+	go func() {
+		defer wg.Done()
+		rates, errs[2] = metal.QueryUSDCNY(cmd.Context(), kind, from, end)
+	}()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	list := metal.ConvertGold(resp.Data, intl, rates)
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, conversionRecords(inst, kind, list))
+	}
+	printConversions(cmd.OutOrStdout(), inst, kind, list)
+	return nil
+}
+
+// conversionRecord 国际金价折算对比的结构化输出
+type conversionRecord struct {
+	Date        string  `json:"date"`
+	Inst        string  `json:"inst"`
+	Price       float64 `json:"price"`        // 上金所收盘价，元/克
+	IntlPrice   float64 `json:"intl_price"`   // 伦敦金，美元/盎司
+	RateKind    string  `json:"rate_kind"`    // 汇率类型 parity/onshore/offshore
+	Rate        float64 `json:"rate"`         // 美元兑人民币汇率
+	Parity      float64 `json:"parity"`       // 折算价，元/克
+	Premium     float64 `json:"premium"`      // 溢价，元/克
+	PremiumRate float64 `json:"premium_rate"` // 溢价率 %
+}
+
+func conversionRecords(inst metal.Instrument, kind fx.Kind, list []*metal.Conversion) []conversionRecord {
+	res := make([]conversionRecord, 0, len(list))
+	for _, c := range list {
+		res = append(res, conversionRecord{
+			Date:        c.Date,
+			Inst:        inst.ID,
+			Price:       c.Price,
+			IntlPrice:   c.IntlPrice,
+			RateKind:    string(kind),
+			Rate:        c.Rate,
+			Parity:      c.Parity,
+			Premium:     c.Premium,
+			PremiumRate: c.PremiumRate * 100,
+		})
+	}
+	return res
+}
+
+// printConversions 打印上金所价格、国际金价、汇率、折算价和溢价
+func printConversions(out io.Writer, inst metal.Instrument, kind fx.Kind, list []*metal.Conversion) {
+	if len(list) == 0 {
+		return
+	}
+	headers := []string{"日期", inst.ID + "(元/克)", "伦敦金(美元/盎司)", kind.Pair(fx.CurrencyUSD) + kind.Label(), "折算(元/克)", "溢价(元/克)", "溢价率"}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	for _, c := range list {
+		style := tablewriter.Colors{}
+		if c.Premium > 0 {
+			style = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+		} else if c.Premium < 0 {
+			style = tablewriter.Colors{tablewriter.Bold, tablewriter.FgGreenColor}
+		}
+		table.Rich([]string{
+			c.Date,
+			fmt.Sprintf("%.2f", c.Price),
+			fmt.Sprintf("%.2f", c.IntlPrice),
+			fmt.Sprintf("%.4f", c.Rate),
+			fmt.Sprintf("%.2f", c.Parity),
+			fmt.Sprintf("%+.2f", c.Premium),
+			fmt.Sprintf("%+.2f%%", c.PremiumRate*100),
+		}, []tablewriter.Colors{{}, {}, {}, {}, {}, style, style})
+	}
+
+	headerStyles := make([]tablewriter.Colors, 0, len(headers))
+	for range headers {
+		headerStyles = append(headerStyles, tablewriter.Colors{tablewriter.Bold})
+	}
+	table.SetHeaderColor(headerStyles...)
+
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetNoWhiteSpace(false)
+	table.SetTablePadding("\t")
+	table.Render()
+}
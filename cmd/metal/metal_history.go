@@ -5,6 +5,7 @@ import (
 	"io"
 	"strconv"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/metal"
 	"github.com/alwqx/sec/utils"
 	"github.com/olekukonko/tablewriter"
@@ -21,14 +22,19 @@ func NewMetalHistoryCLI() *cobra.Command {
 	rootCmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
 	rootCmd.Flags().StringP("begin", "b", "", "Begin date 20250101")
 	rootCmd.Flags().StringP("end", "e", "", "End date 20250131")
+	addInstFlag(rootCmd)
+	output.Enable(rootCmd)
 
 	return rootCmd
 }
 
 // MetalHistoryHandler 打印贵金属历史数据，默认 Au999
 func MetalHistoryHandler(cmd *cobra.Command, args []string) error {
-	req := &metal.QueryAu999Req{}
-	var err error
+	inst, err := parseInstFlag(cmd)
+	if err != nil {
+		return err
+	}
+	req := &metal.QueryDailyHQReq{Inst: inst.ID}
 	beginStr, _ := cmd.Flags().GetString("begin")
 	endStr, _ := cmd.Flags().GetString("end")
 	req.Start, req.End, err = utils.ParseBeginEnd(beginStr, endStr, 30, utils.ParseMetalCmdArgTimeLayout, utils.LayoutYYMMDD)
@@ -36,23 +42,62 @@ func MetalHistoryHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	resp, err := metal.QueryAu999(cmd.Context(), req)
+	resp, err := metal.QueryDailyHQ(cmd.Context(), req)
 	if err != nil {
 		return err
 	}
-	printAu999History(cmd.OutOrStdout(), resp.Data)
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, hqRecords(inst, resp.Data))
+	}
+	printDailyHQ(cmd.OutOrStdout(), inst, resp.Data)
 
 	return nil
 }
 
-// printAu999History 打印 Au999 信息
-func printAu999History(out io.Writer, aus []*metal.DailyHQItem) {
+// hqRecord 合约日行情的结构化输出
+type hqRecord struct {
+	Date       string   `json:"date"`
+	Inst       string   `json:"inst"`
+	Unit       string   `json:"unit"` // 报价单位，如 元/克
+	Open       float64  `json:"open"`
+	Close      float64  `json:"close"`
+	High       float64  `json:"high"`
+	Low        float64  `json:"low"`
+	PrevClose  *float64 `json:"prev_close"`  // 没有前一日数据时为空
+	Change     *float64 `json:"change"`      // 较前一日涨跌
+	ChangeRate *float64 `json:"change_rate"` // 涨跌幅 %
+}
+
+func hqRecords(inst metal.Instrument, items []*metal.DailyHQItem) []hqRecord {
+	res := make([]hqRecord, 0, len(items))
+	for _, item := range items {
+		rec := hqRecord{
+			Date:  item.Date,
+			Inst:  inst.ID,
+			Unit:  inst.Unit,
+			Open:  item.Open,
+			Close: item.Close,
+			High:  item.High,
+			Low:   item.Low,
+		}
+		// YClose 为 -1 表示没有前一日数据
+		if item.YClose != -1 {
+			prev, change, rate := item.YClose, item.Change, item.ChangeRate*100
+			rec.PrevClose, rec.Change, rec.ChangeRate = &prev, &change, &rate
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// printDailyHQ 打印合约日行情
+func printDailyHQ(out io.Writer, inst metal.Instrument, aus []*metal.DailyHQItem) {
 	num := len(aus)
 	if num == 0 {
 		return
 	}
 
-	headers := []string{"日期", "名称", "收盘", "开盘", "最高", "最低"}
+	headers := []string{"日期", "名称", "收盘(" + inst.Unit + ")", "开盘", "最高", "最低"}
 	columnsStyles := make([][]tablewriter.Colors, 0, len(headers))
 
 	data := make([][]string, 0, num)
@@ -66,7 +111,7 @@ func printAu999History(out io.Writer, aus []*metal.DailyHQItem) {
 
 		row := []string{
 			au.Date,
-			"Au99.99",
+			inst.ID,
 			combineClose,
 			strconv.FormatFloat(au.Open, 'g', -1, 64),
 			strconv.FormatFloat(au.High, 'g', -1, 64),
@@ -1,18 +1,23 @@
 package metal
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"testing"
 
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/fx"
 	"github.com/alwqx/sec/provider/metal"
+	"github.com/stretchr/testify/require"
 )
 
-func TestPrintAu999History(t *testing.T) {
+func TestPrintDailyHQ(t *testing.T) {
 	// 1. nil data
-	printAu999History(os.Stdout, nil)
+	printDailyHQ(os.Stdout, metal.Instruments[0], nil)
 
 	// 2. empty data
-	printAu999History(os.Stdout, []*metal.DailyHQItem{})
+	printDailyHQ(os.Stdout, metal.Instruments[0], []*metal.DailyHQItem{})
 
 	// 3. common data with 1 item
 	data := []*metal.DailyHQItem{
@@ -45,5 +50,69 @@ func TestPrintAu999History(t *testing.T) {
 			ChangeRate: -0.016145,
 		},
 	}
-	printAu999History(os.Stdout, data)
+	printDailyHQ(os.Stdout, metal.Instruments[0], data)
+}
+
+func TestHQRecords(t *testing.T) {
+	recs := hqRecords(metal.Instruments[0], []*metal.DailyHQItem{
+		{Date: "2026-04-24", Open: 1040, Close: 1033.25, Low: 1028, High: 1044, YClose: -1},
+		{Date: "2026-04-27", Open: 1039.9, Close: 1037.21, Low: 1033, High: 1044, YClose: 1033.25, Change: 3.96, ChangeRate: 0.004},
+	})
+	require.Len(t, recs, 2)
+	require.Equal(t, "Au99.99", recs[0].Inst)
+	require.Equal(t, "元/克", recs[0].Unit)
+	require.Nil(t, recs[0].PrevClose)
+	require.Nil(t, recs[0].ChangeRate)
+	require.Equal(t, 1033.25, *recs[1].PrevClose)
+	require.InDelta(t, 0.4, *recs[1].ChangeRate, 1e-9)
+
+	var buf bytes.Buffer
+	require.NoError(t, output.Write(&buf, output.FormatCSV, recs[:1]))
+	require.Equal(t, "date,inst,unit,open,close,high,low,prev_close,change,change_rate\n"+
+		"2026-04-24,Au99.99,元/克,1040,1033.25,1044,1028,,,\n", buf.String())
+}
+
+func TestConversionRecords(t *testing.T) {
+	recs := conversionRecords(metal.Instruments[0], fx.KindOnshore, []*metal.Conversion{
+		{Date: "2026-05-06", Price: 780, IntlPrice: 3300, Rate: 7.0, Parity: 742.68, Premium: 37.32, PremiumRate: 0.05025},
+	})
+	require.Len(t, recs, 1)
+	require.Equal(t, "onshore", recs[0].RateKind)
+	require.InDelta(t, 5.025, recs[0].PremiumRate, 1e-9)
+}
+
+func TestPrintConversions(t *testing.T) {
+	var buf bytes.Buffer
+	printConversions(&buf, metal.Instruments[0], fx.KindOnshore, nil)
+	require.Empty(t, buf.String())
+
+	printConversions(&buf, metal.Instruments[0], fx.KindOnshore, []*metal.Conversion{
+		{Date: "2026-05-06", Price: 780, IntlPrice: 3300, Rate: 7.0, Parity: 742.68, Premium: 37.32, PremiumRate: 0.05025},
+	})
+	out := buf.String()
+	require.Contains(t, out, "AU99.99(元/克)")
+	require.Contains(t, out, "USD/CNY在岸")
+	require.Contains(t, out, "742.68")
+	require.Contains(t, out, "+37.32")
+	require.Contains(t, out, "+5.03%")
+}
+
+func TestMetalCLI(t *testing.T) {
+	cmd := NewMetalCLI()
+	require.NotNil(t, cmd.Flags().Lookup("inst"))
+	sub, _, err := cmd.Find([]string{"convert"})
+	require.NoError(t, err)
+	require.Equal(t, "convert", sub.Name())
+
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--inst", "Cu99"})
+	require.ErrorContains(t, cmd.Execute(), "unknown SGE instrument")
+	cmd.SetArgs([]string{"convert", "--inst", "Ag(T+D)"})
+	require.ErrorContains(t, cmd.Execute(), "not a gold instrument")
+	cmd = NewMetalCLI()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"convert", "--kind", "spot"})
+	require.ErrorContains(t, cmd.Execute(), "invalid rate kind")
 }
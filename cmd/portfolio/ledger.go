@@ -0,0 +1,235 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/utils"
+)
+
+// 交易类型，bonus/transfer/cash 由分红送转信息生成，不写入文件
+const (
+	TxBuy      = "buy"
+	TxSell     = "sell"
+	TxBonus    = "bonus"    // 送股
+	TxTransfer = "transfer" // 转增
+	TxCash     = "cash"     // 现金分红
+)
+
+// Transaction 一笔交易或权益事件
+type Transaction struct {
+	ID     int     `json:"id"`
+	Date   string  `json:"date"` // 2006-01-02
+	Type   string  `json:"type"`
+	Code   string  `json:"code"`
+	ExCode string  `json:"excode"`
+	Name   string  `json:"name"`
+	Shares int     `json:"shares"`
+	Price  float64 `json:"price"`
+	Fee    float64 `json:"fee"`
+
+	// 以下字段由 Replay 计算
+	Ratio    float64 `json:"-"` // 权益事件每 10 股送/转/派
+	Realized float64 `json:"-"` // 卖出实现盈亏（FIFO）或分红金额
+}
+
+// Lot 一笔买入形成的持仓批次
+type Lot struct {
+	Date   string
+	Shares int
+	Cost   float64 // 剩余股份的成本（含费用）
+}
+
+// Position 单只证券的持仓
+type Position struct {
+	Code   string
+	ExCode string
+	Name   string
+	Shares int
+	Lots   []Lot // FIFO 批次
+
+	AvgCost      float64 // 移动加权平均法下的剩余成本
+	RealizedAvg  float64 // 移动加权平均法已实现盈亏
+	RealizedFIFO float64 // FIFO 已实现盈亏
+	Dividends    float64 // 累计现金分红
+	Fees         float64 // 累计交易费用
+}
+
+// FIFOCost returns the remaining cost of the position under FIFO.
+func (p *Position) FIFOCost() float64 {
+	var cost float64
+	for _, l := range p.Lots {
+		cost += l.Cost
+	}
+	return cost
+}
+
+// Cost returns the remaining cost under method "fifo" or "avg".
+func (p *Position) Cost(method string) float64 {
+	if method == MethodAvg {
+		return p.AvgCost
+	}
+	return p.FIFOCost()
+}
+
+// Realized returns the realized P&L under method "fifo" or "avg".
+func (p *Position) Realized(method string) float64 {
+	if method == MethodAvg {
+		return p.RealizedAvg
+	}
+	return p.RealizedFIFO
+}
+
+// 成本计算方法
+const (
+	MethodFIFO = "fifo"
+	MethodAvg  = "avg"
+)
+
+// dividendEvents converts dividend records to bonus, transfer and cash
+// events of code. Records without a valid ex-dividend date or after now
+// are skipped.
+func dividendEvents(code string, dids []sina.Dividend, now time.Time) []Transaction {
+	var res []Transaction
+	for _, d := range dids {
+		date, err := time.Parse(utils.LayoutYYMMDD, strings.TrimSpace(d.DividendedDate))
+		if err != nil || date.After(now) {
+			continue
+		}
+		ds := utils.TimeYYMMDDString(date)
+		if d.Shares > 0 {
+			res = append(res, Transaction{Date: ds, Type: TxBonus, Code: code, Ratio: d.Shares})
+		}
+		if d.AddShares > 0 {
+			res = append(res, Transaction{Date: ds, Type: TxTransfer, Code: code, Ratio: d.AddShares})
+		}
+		if d.Bonus > 0 {
+			res = append(res, Transaction{Date: ds, Type: TxCash, Code: code, Ratio: d.Bonus})
+		}
+	}
+	return res
+}
+
+// Replay applies transactions and dividend events (keyed by code) in date
+// order and returns the positions and the full timeline. Events on an
+// ex-dividend date are applied before trades of the same day since shares
+// bought on that date are not entitled. Events of a code before its first
+// trade have no effect.
+func Replay(txs []Transaction, events map[string][]Transaction) (map[string]*Position, []Transaction, error) {
+	timeline := make([]Transaction, 0, len(txs))
+	timeline = append(timeline, txs...)
+	for _, evs := range events {
+		timeline = append(timeline, evs...)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		a, b := timeline[i], timeline[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return isEvent(a) && !isEvent(b)
+	})
+
+	positions := make(map[string]*Position)
+	res := make([]Transaction, 0, len(timeline))
+	for _, tx := range timeline {
+		p, ok := positions[tx.Code]
+		if !ok {
+			if isEvent(tx) {
+				continue
+			}
+			p = &Position{Code: tx.Code, ExCode: tx.ExCode, Name: tx.Name}
+			positions[tx.Code] = p
+		}
+		if isEvent(tx) && p.Shares == 0 {
+			continue
+		}
+		if err := p.apply(&tx); err != nil {
+			return nil, nil, err
+		}
+		if isEvent(tx) {
+			tx.ExCode, tx.Name = p.ExCode, p.Name
+		}
+		res = append(res, tx)
+	}
+	return positions, res, nil
+}
+
+func isEvent(tx Transaction) bool {
+	return tx.Type == TxBonus || tx.Type == TxTransfer || tx.Type == TxCash
+}
+
+// apply updates p with tx and fills computed fields of tx.
+func (p *Position) apply(tx *Transaction) error {
+	switch tx.Type {
+	case TxBuy:
+		cost := float64(tx.Shares)*tx.Price + tx.Fee
+		p.Lots = append(p.Lots, Lot{Date: tx.Date, Shares: tx.Shares, Cost: cost})
+		p.Shares += tx.Shares
+		p.AvgCost += cost
+		p.Fees += tx.Fee
+
+	case TxSell:
+		if tx.Shares > p.Shares {
+			return fmt.Errorf("%s %s 卖出 %d 股超过持仓 %d 股", tx.Date, tx.ExCode, tx.Shares, p.Shares)
+		}
+		proceeds := float64(tx.Shares)*tx.Price - tx.Fee
+
+		// FIFO
+		var fifoCost float64
+		left := tx.Shares
+		for left > 0 && len(p.Lots) > 0 {
+			l := &p.Lots[0]
+			if l.Shares <= left {
+				fifoCost += l.Cost
+				left -= l.Shares
+				p.Lots = p.Lots[1:]
+				continue
+			}
+			part := l.Cost * float64(left) / float64(l.Shares)
+			fifoCost += part
+			l.Cost -= part
+			l.Shares -= left
+			left = 0
+		}
+
+		// 移动加权平均
+		avgCost := p.AvgCost * float64(tx.Shares) / float64(p.Shares)
+		p.AvgCost -= avgCost
+
+		p.Shares -= tx.Shares
+		p.RealizedFIFO += proceeds - fifoCost
+		p.RealizedAvg += proceeds - avgCost
+		p.Fees += tx.Fee
+		tx.Realized = proceeds - fifoCost
+		if p.Shares == 0 {
+			p.AvgCost = 0
+		}
+
+	case TxBonus, TxTransfer:
+		// 每 10 股送/转 Ratio 股，成本不变，不足 1 股的部分舍去
+		added := 0
+		for i := range p.Lots {
+			n := int(math.Floor(float64(p.Lots[i].Shares) * tx.Ratio / 10))
+			p.Lots[i].Shares += n
+			added += n
+		}
+		p.Shares += added
+		tx.Shares = added
+
+	case TxCash:
+		// 每 10 股派 Ratio 元（税前）
+		amount := float64(p.Shares) * tx.Ratio / 10
+		p.Dividends += amount
+		tx.Shares = p.Shares
+		tx.Price = tx.Ratio / 10
+		tx.Realized = amount
+
+	default:
+		return fmt.Errorf("unknown transaction type %q", tx.Type)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package portfolio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
+)
+
+func trade(id int, date, typ string, shares int, price, fee float64) Transaction {
+	return Transaction{ID: id, Date: date, Type: typ, Code: "600036", ExCode: "SH600036", Name: "招商银行", Shares: shares, Price: price, Fee: fee}
+}
+
+func TestReplayFIFOAndAvg(t *testing.T) {
+	txs := []Transaction{
+		trade(1, "2025-01-02", TxBuy, 100, 10, 5),
+		trade(2, "2025-02-03", TxBuy, 100, 20, 5),
+		trade(3, "2025-03-03", TxSell, 150, 30, 10),
+	}
+	positions, timeline, err := Replay(txs, nil)
+	require.NoError(t, err)
+	require.Len(t, timeline, 3)
+
+	p := positions["600036"]
+	require.Equal(t, 50, p.Shares)
+	require.Equal(t, 20.0, p.Fees)
+
+	// FIFO: sold lot1 (1005) + half of lot2 (1002.5)
+	require.InDelta(t, 4490-1005-1002.5, p.RealizedFIFO, 1e-9)
+	require.InDelta(t, 1002.5, p.FIFOCost(), 1e-9)
+	require.InDelta(t, p.RealizedFIFO, timeline[2].Realized, 1e-9)
+
+	// avg: cost 3010 for 200 shares, 150 shares cost 2257.5
+	require.InDelta(t, 4490-2257.5, p.RealizedAvg, 1e-9)
+	require.InDelta(t, 752.5, p.Cost(MethodAvg), 1e-9)
+}
+
+func TestReplayOversell(t *testing.T) {
+	txs := []Transaction{
+		trade(1, "2025-01-02", TxBuy, 100, 10, 0),
+		trade(2, "2025-01-03", TxSell, 200, 10, 0),
+	}
+	_, _, err := Replay(txs, nil)
+	require.Error(t, err)
+
+	// sell dated before the buy is also rejected
+	txs = []Transaction{
+		trade(1, "2025-01-03", TxBuy, 100, 10, 0),
+		trade(2, "2025-01-02", TxSell, 100, 10, 0),
+	}
+	_, _, err = Replay(txs, nil)
+	require.Error(t, err)
+}
+
+func TestDividendEvents(t *testing.T) {
+	now := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	dids := []sina.Dividend{
+		{DividendedDate: "2025-07-10", Shares: 2, AddShares: 3, Bonus: 5},
+		{DividendedDate: "2025-12-10", Bonus: 5}, // future
+		{DividendedDate: "--", Bonus: 5},
+	}
+	evs := dividendEvents("600036", dids, now)
+	require.Len(t, evs, 3)
+	require.Equal(t, TxBonus, evs[0].Type)
+	require.Equal(t, 2.0, evs[0].Ratio)
+	require.Equal(t, TxTransfer, evs[1].Type)
+	require.Equal(t, TxCash, evs[2].Type)
+	require.Equal(t, "2025-07-10", evs[2].Date)
+}
+
+func TestReplayDividends(t *testing.T) {
+	txs := []Transaction{
+		trade(1, "2025-01-02", TxBuy, 1000, 10, 0),
+		trade(2, "2025-07-10", TxBuy, 100, 8, 0), // on ex-date, not entitled
+		trade(3, "2025-08-01", TxSell, 1400, 9, 0),
+	}
+	events := map[string][]Transaction{
+		"600036": dividendEvents("600036", []sina.Dividend{
+			{DividendedDate: "2024-07-10", Bonus: 10}, // before the first buy
+			{DividendedDate: "2025-07-10", Shares: 1, AddShares: 2, Bonus: 5},
+		}, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	positions, timeline, err := Replay(txs, events)
+	require.NoError(t, err)
+	require.Len(t, timeline, 6)
+	require.Equal(t, TxBonus, timeline[1].Type)
+	require.Equal(t, "SH600036", timeline[1].ExCode)
+
+	p := positions["600036"]
+	// 1000 -> +100 bonus -> +220 transfer, cash on 1320 shares, then +100 bought
+	require.Equal(t, 1420-1400, p.Shares)
+	require.Equal(t, 100, timeline[1].Shares)
+	require.Equal(t, 220, timeline[2].Shares)
+	require.InDelta(t, 1320*0.5, p.Dividends, 1e-9)
+	require.InDelta(t, 1320*0.5, timeline[3].Realized, 1e-9)
+
+	// cost of 10000 spread over 1320 shares, sold FIFO
+	require.InDelta(t, 1320*9-10000+80*(9-8), p.RealizedFIFO, 1e-6)
+	require.InDelta(t, 20*8, p.FIFOCost(), 1e-6)
+}
+
+func TestSummarize(t *testing.T) {
+	positions := map[string]*Position{
+		"600036": {Code: "600036", ExCode: "SH600036", Shares: 100, Lots: []Lot{{Shares: 100, Cost: 1000}}, AvgCost: 1000, RealizedFIFO: 50, Dividends: 20, Fees: 5},
+		"601398": {Code: "601398", ExCode: "SH601398", Shares: 1000, Lots: []Lot{{Shares: 1000, Cost: 6000}}, AvgCost: 6000},
+		"000001": {Code: "000001", ExCode: "SZ000001", RealizedFIFO: -30, Fees: 10},
+	}
+	prices := map[string]float64{"SH600036": 12, "SH601398": 0}
+
+	rows, sum := summarize(positions, prices, MethodFIFO)
+	require.Len(t, rows, 2)
+	require.Equal(t, "601398", rows[0].pos.Code) // valued at cost without quote
+	require.InDelta(t, 6000, rows[0].value, 1e-9)
+	require.InDelta(t, 200, rows[1].unrealized, 1e-9)
+	require.InDelta(t, 1200.0/7200*100, rows[1].weight, 1e-9)
+
+	require.InDelta(t, 7200, sum.value, 1e-9)
+	require.InDelta(t, 7000, sum.cost, 1e-9)
+	require.InDelta(t, 20, sum.realized, 1e-9)
+	require.InDelta(t, 20, sum.dividends, 1e-9)
+	require.InDelta(t, 15, sum.fees, 1e-9)
+
+	var buf bytes.Buffer
+	printHoldings(&buf, rows, sum, MethodFIFO)
+	require.Contains(t, buf.String(), "SH600036")
+	require.Contains(t, buf.String(), "总盈亏: +240.00")
+
+	recs := holdingRecords(rows, MethodFIFO)
+	require.Nil(t, recs[0].Price)
+	require.InDelta(t, 12, *recs[1].Price, 1e-9)
+	require.InDelta(t, 20, *recs[1].UnrealizedRate, 1e-9)
+	require.InDelta(t, 50, recs[1].Realized, 1e-9)
+}
+
+func TestTxRecords(t *testing.T) {
+	sell := trade(2, "2025-02-01", TxSell, 100, 12, 5)
+	sell.Realized = 195
+	cash := trade(0, "2025-03-01", TxCash, 100, 0, 0)
+	cash.Realized = 80
+	recs := txRecords([]Transaction{trade(1, "2025-01-02", TxBuy, 100, 10, 5), sell, cash})
+	require.Len(t, recs, 3)
+	require.Equal(t, 1, *recs[0].ID)
+	require.Nil(t, recs[0].Realized)
+	require.InDelta(t, 195, *recs[1].Realized, 1e-9)
+	require.Nil(t, recs[2].ID)
+	require.InDelta(t, 80, recs[2].Amount, 1e-9)
+}
+
+func TestParseDate(t *testing.T) {
+	d, err := parseDate("20250102")
+	require.NoError(t, err)
+	require.Equal(t, "2025-01-02", d)
+	d, err = parseDate("2025-01-02")
+	require.NoError(t, err)
+	require.Equal(t, "2025-01-02", d)
+	_, err = parseDate("2025/01/02")
+	require.Error(t, err)
+}
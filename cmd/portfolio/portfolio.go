@@ -0,0 +1,607 @@
+// Package portfolio implements position tracking at ~/.sec/portfolio.json.
+//
+// Subcommands:
+//
+//	sec portfolio                                   show positions with P&L
+//	sec portfolio buy <code> <shares> <price>       record a buy
+//	sec portfolio sell <code> <shares> <price>      record a sell
+//	sec portfolio history [code]                    list transactions
+//
+// Bonus shares, transferred shares and cash dividends are derived from
+// sina.QueryDividends when positions are computed, so they are never stored.
+package portfolio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// portfolioPath returns the path to the portfolio JSON file.
+func portfolioPath() (string, error) {
+	dir, err := utils.SecDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "portfolio.json"), nil
+}
+
+func loadTransactions() ([]Transaction, error) {
+	path, err := portfolioPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var txs []Transaction
+	if err := json.Unmarshal(data, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+func saveTransactions(txs []Transaction) error {
+	path, err := portfolioPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(txs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// NewPortfolioCLI returns the portfolio command with subcommands.
+func NewPortfolioCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "portfolio",
+		Aliases:       []string{"pf"},
+		Short:         "Track portfolio positions and P&L",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		RunE: runShow,
+	}
+	cmd.PersistentFlags().Bool("no-dividends", false, "Do not adjust positions for dividends, bonus and transferred shares")
+	cmd.Flags().StringP("method", "m", MethodFIFO, "Cost basis method: fifo, avg")
+
+	show := &cobra.Command{
+		Use:   "show",
+		Short: "Show positions with P&L",
+		Args:  cobra.NoArgs,
+		RunE:  runShow,
+	}
+	show.Flags().StringP("method", "m", MethodFIFO, "Cost basis method: fifo, avg")
+
+	buy := &cobra.Command{
+		Use:   "buy <code> <shares> <price>",
+		Short: "Record a buy transaction",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrade(cmd, args, TxBuy)
+		},
+	}
+	sell := &cobra.Command{
+		Use:   "sell <code> <shares> <price>",
+		Short: "Record a sell transaction",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrade(cmd, args, TxSell)
+		},
+	}
+	for _, c := range []*cobra.Command{buy, sell} {
+		c.Flags().Float64("fee", 0, "Commission, stamp duty and other fees")
+		c.Flags().StringP("date", "d", "", "Trade date 20250101, default today")
+	}
+
+	history := &cobra.Command{
+		Use:   "history [code]",
+		Short: "List transactions and dividend events",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runHistory,
+	}
+
+	output.Enable(cmd, show, history)
+	cmd.AddCommand(show, buy, sell, history)
+	return cmd
+}
+
+// parseDate parses 20060102 or 2006-01-02, an empty string means today.
+func parseDate(s string) (string, error) {
+	if s == "" {
+		return utils.TimeYYMMDDString(time.Now()), nil
+	}
+	for _, layout := range []string{utils.ParseMetalCmdArgTimeLayout, utils.LayoutYYMMDD} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return utils.TimeYYMMDDString(t), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date %q, use 20250101", s)
+}
+
+func runTrade(cmd *cobra.Command, args []string, typ string) error {
+	shares, err := strconv.Atoi(args[1])
+	if err != nil || shares <= 0 {
+		return fmt.Errorf("invalid shares %q", args[1])
+	}
+	price, err := strconv.ParseFloat(args[2], 64)
+	if err != nil || price <= 0 {
+		return fmt.Errorf("invalid price %q", args[2])
+	}
+	fee, _ := cmd.Flags().GetFloat64("fee")
+	if fee < 0 {
+		return fmt.Errorf("invalid fee %v", fee)
+	}
+	dateStr, _ := cmd.Flags().GetString("date")
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return err
+	}
+
+	txs, err := loadTransactions()
+	if err != nil {
+		return fmt.Errorf("读取持仓记录失败: %w", err)
+	}
+
+	sec, err := lookup(cmd, txs, args[0])
+	if err != nil {
+		return err
+	}
+
+	tx := Transaction{
+		ID:     nextID(txs),
+		Date:   date,
+		Type:   typ,
+		Code:   sec.Code,
+		ExCode: sec.ExCode,
+		Name:   sec.Name,
+		Shares: shares,
+		Price:  price,
+		Fee:    fee,
+	}
+	txs = append(txs, tx)
+
+	// 校验卖出数量不超过持仓
+	if _, _, err := Replay(txs, loadEvents(cmd, txs)); err != nil {
+		return err
+	}
+	if err := saveTransactions(txs); err != nil {
+		return fmt.Errorf("保存失败: %w", err)
+	}
+
+	action := "买入"
+	if typ == TxSell {
+		action = "卖出"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "  ✓ #%d %s %s %s %s %d 股 @ %.3f 费用 %.2f\n",
+		tx.ID, tx.Date, action, tx.ExCode, tx.Name, tx.Shares, tx.Price, tx.Fee)
+	return nil
+}
+
+// lookup finds the security of key from existing transactions first and
+// then from the search source.
+func lookup(cmd *cobra.Command, txs []Transaction, key string) (*sina.BasicSecurity, error) {
+	key = strings.TrimSpace(key)
+	for _, tx := range txs {
+		if strings.EqualFold(tx.Code, key) || strings.EqualFold(tx.ExCode, key) {
+			return &sina.BasicSecurity{Code: tx.Code, ExCode: tx.ExCode, Name: tx.Name}, nil
+		}
+	}
+
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	secs := src.Searcher.Search(cmd.Context(), key)
+	if len(secs) == 0 {
+		return nil, fmt.Errorf("未找到证券: %s", key)
+	}
+	return secs[0], nil
+}
+
+func nextID(txs []Transaction) int {
+	id := 0
+	for _, tx := range txs {
+		if tx.ID > id {
+			id = tx.ID
+		}
+	}
+	return id + 1
+}
+
+// loadEvents queries dividends of every traded code concurrently. Failures
+// are logged and the code is treated as having no events.
+func loadEvents(cmd *cobra.Command, txs []Transaction) map[string][]Transaction {
+	if noDiv, _ := cmd.Flags().GetBool("no-dividends"); noDiv {
+		return nil
+	}
+
+	codes := make(map[string]bool)
+	for _, tx := range txs {
+		codes[tx.Code] = true
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		events = make(map[string][]Transaction, len(codes))
+		now    = time.Now()
+	)
+	for code := range codes {
+		wg.Add(1)
+		go func(code string) {
+			defer wg.Done()
+			dids, err := sina.QueryDividends(cmd.Context(), code)
+			if err != nil {
+				slog.WarnContext(cmd.Context(), "failed query dividends", "code", code, "error", err)
+				return
+			}
+			mu.Lock()
+			events[code] = dividendEvents(code, dids, now)
+			mu.Unlock()
+		}(code)
+	}
+	wg.Wait()
+	return events
+}
+
+// holding 持仓展示行
+type holding struct {
+	pos        *Position
+	price      float64
+	cost       float64
+	value      float64
+	unrealized float64
+	weight     float64
+}
+
+// summary 组合汇总
+type summary struct {
+	value      float64
+	cost       float64
+	unrealized float64
+	realized   float64
+	dividends  float64
+	fees       float64
+}
+
+// summarize computes holdings sorted by market value and portfolio totals.
+// Closed positions only contribute realized P&L, dividends and fees. A
+// position without a quote is valued at cost.
+func summarize(positions map[string]*Position, prices map[string]float64, method string) ([]holding, summary) {
+	var (
+		rows []holding
+		sum  summary
+	)
+	for _, p := range positions {
+		sum.realized += p.Realized(method)
+		sum.dividends += p.Dividends
+		sum.fees += p.Fees
+		if p.Shares == 0 {
+			continue
+		}
+
+		h := holding{pos: p, price: prices[p.ExCode], cost: p.Cost(method)}
+		if h.price > 0 {
+			h.value = h.price * float64(p.Shares)
+		} else {
+			h.value = h.cost
+		}
+		h.unrealized = h.value - h.cost
+		sum.value += h.value
+		sum.cost += h.cost
+		sum.unrealized += h.unrealized
+		rows = append(rows, h)
+	}
+
+	for i := range rows {
+		if sum.value > 0 {
+			rows[i].weight = rows[i].value / sum.value * 100
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].value != rows[j].value {
+			return rows[i].value > rows[j].value
+		}
+		return rows[i].pos.Code < rows[j].pos.Code
+	})
+	return rows, sum
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	method, _ := cmd.Flags().GetString("method")
+	method = strings.ToLower(method)
+	if method != MethodFIFO && method != MethodAvg {
+		return fmt.Errorf("invalid method %q, use fifo or avg", method)
+	}
+
+	txs, err := loadTransactions()
+	if err != nil {
+		return fmt.Errorf("读取持仓记录失败: %w", err)
+	}
+	f := output.FormatOf(cmd)
+	if len(txs) == 0 {
+		if f.Structured() {
+			return output.Write(cmd.OutOrStdout(), f, []holdingRecord{})
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "持仓为空。使用 sec portfolio buy <代码> <股数> <价格> 添加交易\n")
+		return nil
+	}
+
+	positions, _, err := Replay(txs, loadEvents(cmd, txs))
+	if err != nil {
+		return err
+	}
+
+	exCodes := make([]string, 0, len(positions))
+	for _, p := range positions {
+		if p.Shares > 0 {
+			exCodes = append(exCodes, p.ExCode)
+		}
+	}
+	prices := make(map[string]float64, len(exCodes))
+	if len(exCodes) > 0 {
+		src, err := provider.FromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		qlist, err := src.Quote.QueryQuoteList(cmd.Context(), exCodes)
+		if err != nil {
+			slog.Warn("获取行情失败", "error", err)
+		}
+		for _, q := range qlist {
+			prices[q.ExCode] = q.Current
+		}
+	}
+
+	rows, sum := summarize(positions, prices, method)
+	if f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, holdingRecords(rows, method))
+	}
+	printHoldings(cmd.OutOrStdout(), rows, sum, method)
+	return nil
+}
+
+func printHoldings(out io.Writer, rows []holding, sum summary, method string) {
+	methodName := "先进先出"
+	if method == MethodAvg {
+		methodName = "移动加权平均"
+	}
+	fmt.Fprintf(out, "\n持仓组合 (%d 只，成本法: %s)\n\n", len(rows), methodName)
+
+	if len(rows) > 0 {
+		headers := []string{"代码", "名称", "持仓", "成本价", "现价", "市值", "浮动盈亏", "盈亏比例", "已实现", "分红", "权重"}
+		table := utils.NewTable(out, headers)
+		for _, h := range rows {
+			var costPrice, pnlPct float64
+			if h.pos.Shares > 0 {
+				costPrice = h.cost / float64(h.pos.Shares)
+			}
+			if h.cost > 0 {
+				pnlPct = h.unrealized / h.cost * 100
+			}
+			row := []string{
+				h.pos.ExCode,
+				h.pos.Name,
+				strconv.Itoa(h.pos.Shares),
+				fmt.Sprintf("%.3f", costPrice),
+				fmt.Sprintf("%.2f", h.price),
+				fmt.Sprintf("%.2f", h.value),
+				fmt.Sprintf("%+.2f", h.unrealized),
+				fmt.Sprintf("%+.2f%%", pnlPct),
+				fmt.Sprintf("%+.2f", h.pos.Realized(method)),
+				fmt.Sprintf("%.2f", h.pos.Dividends),
+				fmt.Sprintf("%.1f%%", h.weight),
+			}
+			colors := make([]tablewriter.Colors, len(headers))
+			colors[6] = pnlColor(h.unrealized)
+			colors[7] = pnlColor(h.unrealized)
+			table.Rich(row, colors)
+		}
+		table.Render()
+	}
+
+	total := sum.unrealized + sum.realized + sum.dividends
+	fmt.Fprintf(out, "\n总市值: %.2f  总成本: %.2f  浮动盈亏: %+.2f  已实现盈亏: %+.2f  分红: %.2f  总盈亏: %+.2f  累计费用: %.2f\n\n",
+		sum.value, sum.cost, sum.unrealized, sum.realized, sum.dividends, total, sum.fees)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	txs, err := loadTransactions()
+	if err != nil {
+		return fmt.Errorf("读取持仓记录失败: %w", err)
+	}
+
+	_, timeline, err := Replay(txs, loadEvents(cmd, txs))
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		key := strings.TrimSpace(args[0])
+		filtered := timeline[:0]
+		for _, tx := range timeline {
+			if strings.EqualFold(tx.Code, key) || strings.EqualFold(tx.ExCode, key) {
+				filtered = append(filtered, tx)
+			}
+		}
+		timeline = filtered
+	}
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, txRecords(timeline))
+	}
+	if len(timeline) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "无交易记录\n")
+		return nil
+	}
+
+	printHistory(cmd.OutOrStdout(), timeline)
+	return nil
+}
+
+var txTypeNames = map[string]string{
+	TxBuy:      "买入",
+	TxSell:     "卖出",
+	TxBonus:    "送股",
+	TxTransfer: "转增",
+	TxCash:     "分红",
+}
+
+func printHistory(out io.Writer, timeline []Transaction) {
+	headers := []string{"编号", "日期", "类型", "代码", "名称", "股数", "价格", "费用", "金额", "盈亏"}
+	table := utils.NewTable(out, headers)
+	for _, tx := range timeline {
+		id, pnl := "-", "-"
+		if tx.ID > 0 {
+			id = strconv.Itoa(tx.ID)
+		}
+		amount := float64(tx.Shares) * tx.Price
+		colors := make([]tablewriter.Colors, len(headers))
+		switch tx.Type {
+		case TxSell:
+			pnl = fmt.Sprintf("%+.2f", tx.Realized)
+			colors[9] = pnlColor(tx.Realized)
+		case TxCash:
+			amount = tx.Realized
+		case TxBonus, TxTransfer:
+			amount = 0
+		}
+		table.Rich([]string{
+			id,
+			tx.Date,
+			txTypeNames[tx.Type],
+			tx.ExCode,
+			tx.Name,
+			strconv.Itoa(tx.Shares),
+			fmt.Sprintf("%.3f", tx.Price),
+			fmt.Sprintf("%.2f", tx.Fee),
+			fmt.Sprintf("%.2f", amount),
+			pnl,
+		}, colors)
+	}
+	table.Render()
+	fmt.Fprintln(out)
+}
+
+// holdingRecord 持仓的结构化输出
+type holdingRecord struct {
+	Code           string   `json:"code"`
+	ExCode         string   `json:"excode"`
+	Name           string   `json:"name"`
+	Shares         int      `json:"shares"`
+	Cost           float64  `json:"cost"`            // 持仓成本，含费用
+	CostPrice      float64  `json:"cost_price"`      // 每股成本
+	Price          *float64 `json:"price"`           // 现价，没有行情时为空
+	MarketValue    float64  `json:"market_value"`    // 市值，没有行情时按成本计
+	Unrealized     float64  `json:"unrealized"`      // 浮动盈亏
+	UnrealizedRate *float64 `json:"unrealized_rate"` // 浮动盈亏比例 %
+	Realized       float64  `json:"realized"`        // 已实现盈亏
+	Dividends      float64  `json:"dividends"`       // 现金分红
+	Weight         float64  `json:"weight"`          // 市值权重 %
+}
+
+func holdingRecords(rows []holding, method string) []holdingRecord {
+	res := make([]holdingRecord, 0, len(rows))
+	for _, h := range rows {
+		rec := holdingRecord{
+			Code:        h.pos.Code,
+			ExCode:      h.pos.ExCode,
+			Name:        h.pos.Name,
+			Shares:      h.pos.Shares,
+			Cost:        h.cost,
+			Price:       output.NonZero(h.price),
+			MarketValue: h.value,
+			Unrealized:  h.unrealized,
+			Realized:    h.pos.Realized(method),
+			Dividends:   h.pos.Dividends,
+			Weight:      h.weight,
+		}
+		if h.pos.Shares > 0 {
+			rec.CostPrice = h.cost / float64(h.pos.Shares)
+		}
+		if h.cost > 0 {
+			rec.UnrealizedRate = output.Num(h.unrealized / h.cost * 100)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// txRecord 交易和权益事件的结构化输出
+type txRecord struct {
+	ID       *int     `json:"id"` // 权益事件没有编号
+	Date     string   `json:"date"`
+	Type     string   `json:"type"` // buy、sell、bonus、transfer、cash
+	Code     string   `json:"code"`
+	ExCode   string   `json:"excode"`
+	Name     string   `json:"name"`
+	Shares   int      `json:"shares"`
+	Price    float64  `json:"price"`
+	Fee      float64  `json:"fee"`
+	Amount   float64  `json:"amount"`   // 成交金额或分红金额
+	Realized *float64 `json:"realized"` // 卖出实现盈亏
+}
+
+func txRecords(timeline []Transaction) []txRecord {
+	res := make([]txRecord, 0, len(timeline))
+	for _, tx := range timeline {
+		rec := txRecord{
+			Date:   tx.Date,
+			Type:   tx.Type,
+			Code:   tx.Code,
+			ExCode: tx.ExCode,
+			Name:   tx.Name,
+			Shares: tx.Shares,
+			Price:  tx.Price,
+			Fee:    tx.Fee,
+			Amount: float64(tx.Shares) * tx.Price,
+		}
+		if tx.ID > 0 {
+			id := tx.ID
+			rec.ID = &id
+		}
+		switch tx.Type {
+		case TxSell:
+			realized := tx.Realized
+			rec.Realized = &realized
+		case TxCash:
+			rec.Amount = tx.Realized
+		case TxBonus, TxTransfer:
+			rec.Amount = 0
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+func pnlColor(v float64) tablewriter.Colors {
+	if v > 0 {
+		return tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold}
+	} else if v < 0 {
+		return tablewriter.Colors{tablewriter.FgGreenColor, tablewriter.Bold}
+	}
+	return tablewriter.Colors{}
+}
@@ -5,14 +5,14 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/exec"
 	"os/signal"
-	"runtime"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
 	"github.com/alwqx/sec/provider/sina"
 	"github.com/alwqx/sec/utils"
 	"github.com/olekukonko/tablewriter"
@@ -34,11 +34,16 @@ func NewQuoteCLI() *cobra.Command {
 	}
 	rootCmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
 	rootCmd.Flags().BoolP("realtime", "r", false, "Realtime update quote info")
+	output.Enable(rootCmd)
 
 	return rootCmd
 }
 
 func QuoteHandler(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
 
 	// 查询参数由逗号分隔
 	keys := strings.Split(args[0], ",")
@@ -53,8 +58,20 @@ func QuoteHandler(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	f := output.FormatOf(cmd)
 	if !realTime {
-		return quoteMultiSec(cmd.Context(), dedupKeys)
+		quotes, err := quoteMultiSec(cmd.Context(), src, dedupKeys)
+		if err != nil {
+			return err
+		}
+		if f.Structured() {
+			return output.Write(cmd.OutOrStdout(), f, quoteRecords(quotes))
+		}
+		printQuote(quotes)
+		return nil
+	}
+	if f.Structured() {
+		return fmt.Errorf("--realtime only supports table output, got --output %s", f)
 	}
 
 	ctx, cancel := context.WithCancel(cmd.Context())
@@ -76,21 +93,23 @@ func QuoteHandler(cmd *cobra.Command, args []string) error {
 	}()
 
 	slog.DebugContext(ctx, "QuoteHandler", "realTime", realTime)
-	err = quoteMultiSecRealtime(ctx, dedupKeys)
+	err = quoteMultiSecRealtime(ctx, src, dedupKeys)
 	return err
 }
 
-func quoteMultiSec(ctx context.Context, keys []string) error {
+// quoteMultiSec searches keys and returns their quotes with security codes
+// filled in.
+func quoteMultiSec(ctx context.Context, src *provider.Source, keys []string) ([]*sina.SecurityQuote, error) {
 	// keys 长度不能超过5
 	if len(keys) > 5 {
 		slog.WarnContext(ctx, "quoteMultiSec support 5 secs at most, will choose top 5 keys")
 		keys = keys[:5]
 	}
 	// 1. search security
-	secs := sina.MultiSearch(ctx, keys)
+	secs := provider.MultiSearch(ctx, src.Searcher, keys)
 	if len(secs) == 0 {
 		slog.WarnContext(ctx, "no result", "keys", keys)
-		return nil
+		return nil, nil
 	}
 
 	slog.DebugContext(ctx, "quoteMultiSec", "secs", secs)
@@ -103,9 +122,9 @@ func quoteMultiSec(ctx context.Context, keys []string) error {
 	}
 
 	// res, err := sina.QuoteWs(codes)
-	res, err := sina.QueryQuoteList(ctx, codes)
+	res, err := src.Quote.QueryQuoteList(ctx, codes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 填充证券代码
@@ -116,19 +135,17 @@ func quoteMultiSec(ctx context.Context, keys []string) error {
 		}
 	}
 
-	printQuote(res)
-
-	return nil
+	return res, nil
 }
 
-func quoteMultiSecRealtime(ctx context.Context, keys []string) error {
+func quoteMultiSecRealtime(ctx context.Context, src *provider.Source, keys []string) error {
 	// keys 长度不能超过5
 	if len(keys) > 5 {
 		slog.WarnContext(ctx, "quoteMultiSecRealtime support 5 secs at most, will choose top 5 keys")
 		keys = keys[:5]
 	}
 	// 1. search security
-	secs := sina.MultiSearch(ctx, keys)
+	secs := provider.MultiSearch(ctx, src.Searcher, keys)
 	if len(secs) == 0 {
 		slog.WarnContext(ctx, "no result", "keys", keys)
 		return nil
@@ -143,13 +160,17 @@ func quoteMultiSecRealtime(ctx context.Context, keys []string) error {
 		secMap[sec.Name] = secs[i]
 	}
 
+	if src.Stream != nil {
+		return quoteStream(ctx, src, codes, secMap)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		default:
 			// res, err := sina.QuoteWs(codes)
-			res, err := sina.QueryQuoteList(ctx, codes)
+			res, err := src.Quote.QueryQuoteList(ctx, codes)
 			if err != nil {
 				return err
 			}
@@ -161,7 +182,7 @@ func quoteMultiSecRealtime(ctx context.Context, keys []string) error {
 					quote.Code = sec.Code
 				}
 			}
-			clearTerm()
+			utils.ClearTerm()
 			printQuote(res)
 
 			time.Sleep(3 * time.Second)
@@ -169,6 +190,59 @@ func quoteMultiSecRealtime(ctx context.Context, keys []string) error {
 	}
 }
 
+// quoteStream 通过行情推送刷新，先用 HTTP 查询一次以便立即显示，之后最多
+// 每秒重绘一次
+func quoteStream(ctx context.Context, src *provider.Source, codes []string, secMap map[string]*sina.BasicSecurity) error {
+	latest := make(map[string]*sina.SecurityQuote, len(codes))
+	res, err := src.Quote.QueryQuoteList(ctx, codes)
+	if err != nil {
+		return err
+	}
+	for _, quote := range res {
+		latest[quote.ExCode] = quote
+	}
+
+	stream := src.Stream.QuoteStream(ctx, codes)
+	defer stream.Close()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	dirty := true
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case quote, ok := <-stream.Quotes():
+			if !ok {
+				return nil
+			}
+			latest[quote.ExCode] = quote
+			dirty = true
+		case <-ticker.C:
+			if !dirty {
+				continue
+			}
+			dirty = false
+
+			res := make([]*sina.SecurityQuote, 0, len(codes))
+			for _, code := range codes {
+				if quote, ok := latest[code]; ok {
+					res = append(res, quote)
+				}
+			}
+			// 填充证券代码
+			for _, quote := range res {
+				if sec, ok := secMap[quote.Name]; ok {
+					quote.ExCode = sec.ExCode
+					quote.Code = sec.Code
+				}
+			}
+			utils.ClearTerm()
+			printQuote(res)
+		}
+	}
+}
+
 // printQuote 打印 quote 信息
 func printQuote(quotes []*sina.SecurityQuote) {
 	if len(quotes) == 0 {
@@ -237,6 +311,50 @@ func printQuote(quotes []*sina.SecurityQuote) {
 	table.Render()
 }
 
+// quoteRecord 实时行情的结构化输出
+type quoteRecord struct {
+	Date       string  `json:"date"`
+	Time       string  `json:"time"`
+	Code       string  `json:"code"`
+	ExCode     string  `json:"excode"`
+	Name       string  `json:"name"`
+	Price      float64 `json:"price"`
+	Change     float64 `json:"change"`
+	ChangeRate float64 `json:"change_rate"` // 涨跌幅 %
+	PrevClose  float64 `json:"prev_close"`
+	Open       float64 `json:"open"`
+	High       float64 `json:"high"`
+	Low        float64 `json:"low"`
+	Volume     int64   `json:"volume"` // 成交量，单位：股
+	Amount     float64 `json:"amount"` // 成交额，单位：元
+}
+
+func quoteRecords(quotes []*sina.SecurityQuote) []quoteRecord {
+	res := make([]quoteRecord, 0, len(quotes))
+	for _, q := range quotes {
+		rec := quoteRecord{
+			Date:      q.TradeDate,
+			Time:      q.Time,
+			Code:      q.Code,
+			ExCode:    q.ExCode,
+			Name:      q.Name,
+			Price:     q.Current,
+			PrevClose: q.YClose,
+			Open:      q.Open,
+			High:      q.High,
+			Low:       q.Low,
+			Volume:    q.TurnOver,
+			Amount:    q.Volume,
+		}
+		if q.YClose > 0 {
+			rec.Change = q.Current - q.YClose
+			rec.ChangeRate = rec.Change / q.YClose * 100
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
 // stringSliceDedup 字符串数组去重
 func stringSliceDedup(strs []string) []string {
 	num := len(strs)
@@ -255,20 +373,3 @@ func stringSliceDedup(strs []string) []string {
 
 	return res
 }
-
-// clearTerm 终端清屏
-func clearTerm() {
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("cmd", "/c", "cls")
-	default:
-		cmd = exec.Command("clear")
-	}
-
-	cmd.Stdout = os.Stdout
-	err := cmd.Run()
-	if err != nil {
-		slog.Error("clearTerm", "cmd error", err)
-	}
-}
@@ -6,9 +6,14 @@ import (
 	"log/slog"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/cache"
 	"github.com/alwqx/sec/provider/eastmoney"
-	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/provider/index"
 	"github.com/alwqx/sec/types"
 	"github.com/alwqx/sec/utils"
 	"github.com/olekukonko/tablewriter"
@@ -33,17 +38,27 @@ func NewQuoteHistoryCLI() *cobra.Command {
 	rootCmd.Flags().BoolP("desc", "d", false, "Order by date in descending order")
 	rootCmd.Flags().StringP("begin", "b", "", "Begin date 20250101")
 	rootCmd.Flags().StringP("end", "e", "", "End date 20250131")
+	rootCmd.Flags().StringP("period", "p", "day", "K-line period: "+strings.Join(eastmoney.PeriodNames(), ", "))
 	rootCmd.Flags().StringP("fq", "f", "", "FuQuan type choice: bfq none, qfq front, hfq post")
+	rootCmd.Flags().Bool("no-cache", false, "Bypass local K-line history cache")
+	output.Enable(rootCmd)
 
 	return rootCmd
 }
 
 // QuoteHistoryHandler 查询行情历史
 func QuoteHistoryHandler(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+		src.History = cache.NewHistory(src.History)
+	}
+	src.Searcher = index.NewSearcher(src.Searcher)
 
-	// 查询参数由逗号分隔
 	key := args[0]
-	secs := sina.Search(cmd.Context(), key)
+	secs := src.Searcher.Search(cmd.Context(), key)
 	num := len(secs)
 	if num == 0 {
 		slog.Info("search no sec", "code", key)
@@ -53,6 +68,9 @@ func QuoteHistoryHandler(cmd *cobra.Command, args []string) error {
 	// 默认选择第一个查询结果
 	sec := secs[0]
 	slog.Debug("QuoteHistoryHandler", "num", num, "excode", sec.ExCode, "code", sec.Code, "exchange", sec.ExChange)
+	if sec.SecurityType == types.SecurityTypeFund && sec.ExChange == "" {
+		return fmt.Errorf("%s %s 是场外基金，没有场内行情，请使用 sec fund 查看净值", sec.Code, sec.Name)
+	}
 	req := &eastmoney.GetQuoteHistoryReq{
 		Code: sec.Code,
 	}
@@ -65,6 +83,8 @@ func QuoteHistoryHandler(cmd *cobra.Command, args []string) error {
 		req.MarketCode = 116
 	case types.ExChangeNasdaq:
 		req.MarketCode = 105
+	case types.ExChangeCSI:
+		req.MarketCode = int(eastmoney.MarketTypeCSI)
 	default:
 		return fmt.Errorf("unsupported exchange: %s", sec.ExChange)
 	}
@@ -86,14 +106,20 @@ func QuoteHistoryHandler(cmd *cobra.Command, args []string) error {
 		req.FQT = eastmoney.QuoteFQTDefault
 	}
 
+	periodStr, _ := cmd.Flags().GetString("period")
+	req.Period, err = eastmoney.ParsePeriod(periodStr)
+	if err != nil {
+		return err
+	}
+
 	beginStr, _ := cmd.Flags().GetString("begin")
 	endStr, _ := cmd.Flags().GetString("end")
-	req.Begin, req.End, err = utils.ParseBeginEnd(beginStr, endStr, 30, eastmoney.TimeYYMMDD, eastmoney.TimeYYMMDD)
+	req.Begin, req.End, err = utils.ParseBeginEnd(beginStr, endStr, req.Period.Lookback(30), eastmoney.TimeYYMMDD, eastmoney.TimeYYMMDD)
 	if err != nil {
 		return err
 	}
 
-	quotes, err := eastmoney.GetQuoteHistory(cmd.Context(), req)
+	quotes, err := src.History.GetQuoteHistory(cmd.Context(), req)
 	if err != nil {
 		slog.Error("failed QuoteHistoryHandler", "code", req.Code, "error", err)
 		return err
@@ -110,11 +136,55 @@ func QuoteHistoryHandler(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, historyRecords(quotes))
+	}
 	printQuoteHistory(cmd.OutOrStdout(), quotes)
 
 	return nil
 }
 
+// historyRecord 历史行情的结构化输出
+type historyRecord struct {
+	Date         string  `json:"date"`
+	Code         string  `json:"code"`
+	ExCode       string  `json:"excode"`
+	Name         string  `json:"name"`
+	Open         float64 `json:"open"`
+	Close        float64 `json:"close"`
+	High         float64 `json:"high"`
+	Low          float64 `json:"low"`
+	Change       float64 `json:"change"`
+	ChangeRate   float64 `json:"change_rate"`   // 涨跌幅 %
+	Volume       int64   `json:"volume"`        // 成交量
+	Amount       float64 `json:"amount"`        // 成交额
+	Amplitude    float64 `json:"amplitude"`     // 振幅 %
+	TurnoverRate float64 `json:"turnover_rate"` // 换手率 %
+}
+
+func historyRecords(quotes []*eastmoney.Quote) []historyRecord {
+	res := make([]historyRecord, 0, len(quotes))
+	for _, q := range quotes {
+		res = append(res, historyRecord{
+			Date:         quoteDateString(q.Date),
+			Code:         q.Code,
+			ExCode:       q.Market.String() + q.Code,
+			Name:         q.Name,
+			Open:         q.Open,
+			Close:        q.Close,
+			High:         q.High,
+			Low:          q.Low,
+			Change:       q.Change,
+			ChangeRate:   q.ChangeRate,
+			Volume:       q.Volume,
+			Amount:       q.TurnOver,
+			Amplitude:    q.Amplitude,
+			TurnoverRate: q.Velocity,
+		})
+	}
+	return res
+}
+
 // printQuote 打印 quote 信息
 func printQuoteHistory(out io.Writer, quotes []*eastmoney.Quote) {
 	if len(quotes) == 0 {
@@ -128,7 +198,7 @@ func printQuoteHistory(out io.Writer, quotes []*eastmoney.Quote) {
 	for _, quote := range quotes {
 		combineClose := fmt.Sprintf("%-.5g %-.5g %-.2g%s", quote.Close, quote.Change, quote.ChangeRate, "%")
 		row := []string{
-			utils.TimeYYMMDDString(quote.Date),
+			quoteDateString(quote.Date),
 			quote.Name,
 			combineClose,
 			strconv.FormatFloat(quote.Open, 'g', -1, 64),
@@ -181,3 +251,12 @@ func printQuoteHistory(out io.Writer, quotes []*eastmoney.Quote) {
 	table.SetTablePadding("\t")
 	table.Render()
 }
+
+// quoteDateString formats the date of a bar, with the time of day for minute
+// bars.
+func quoteDateString(t time.Time) string {
+	if t.Hour() != 0 || t.Minute() != 0 {
+		return t.Format(eastmoney.TimeMinute)
+	}
+	return utils.TimeYYMMDDString(t)
+}
@@ -1,11 +1,18 @@
 package quote
 
 import (
+	"bytes"
+	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
 	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPrintQuoteHistory(t *testing.T) {
@@ -48,3 +55,67 @@ func TestPrintQuoteHistory(t *testing.T) {
 
 	printQuoteHistory(os.Stdout, []*eastmoney.Quote{quote1, quote2})
 }
+
+type fakeSource struct{}
+
+func (fakeSource) Search(ctx context.Context, key string) []*sina.BasicSecurity {
+	return []*sina.BasicSecurity{{Code: "600036", ExCode: "SH600036", Name: "招商银行", ExChange: "sh"}}
+}
+
+func (fakeSource) GetQuoteHistory(ctx context.Context, req *eastmoney.GetQuoteHistoryReq) ([]*eastmoney.Quote, error) {
+	if req.Period == eastmoney.Period5Min {
+		return []*eastmoney.Quote{
+			{Date: time.Date(2025, 6, 3, 9, 35, 0, 0, time.UTC), Code: req.Code, Name: "招商银行", Market: eastmoney.MarketType(req.MarketCode), Close: 30.32},
+		}, nil
+	}
+	return []*eastmoney.Quote{
+		{Date: time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), Code: req.Code, Name: "招商银行", Market: eastmoney.MarketType(req.MarketCode), Close: 30.32},
+		{Date: time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), Code: req.Code, Name: "招商银行", Market: eastmoney.MarketType(req.MarketCode), Close: 30.8},
+	}, nil
+}
+
+func TestQuoteHistoryHandlerWithSource(t *testing.T) {
+	require.Nil(t, provider.Register("fake", fakeSource{}))
+	t.Cleanup(func() { provider.Unregister("fake") })
+
+	var buf bytes.Buffer
+	cmd := NewQuoteHistoryCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"600036", "--source", "fake", "--desc", "--no-cache"})
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, buf.String(), "SH600036")
+	require.Less(t, strings.Index(buf.String(), "2025-06-04"), strings.Index(buf.String(), "2025-06-03"))
+
+	buf.Reset()
+	cmd = NewQuoteHistoryCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"600036", "--source", "fake", "--period", "5m"})
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, buf.String(), "2025-06-03 09:35")
+
+	// 结构化输出使用英文键
+	buf.Reset()
+	cmd = NewQuoteHistoryCLI()
+	provider.AddFlag(cmd)
+	output.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"600036", "--source", "fake", "--no-cache", "--output", "csv"})
+	require.Nil(t, cmd.Execute())
+	require.Equal(t, "date,code,excode,name,open,close,high,low,change,change_rate,volume,amount,amplitude,turnover_rate\n"+
+		"2025-06-03,600036,SH600036,招商银行,0,30.32,0,0,0,0,0,0,0,0\n"+
+		"2025-06-04,600036,SH600036,招商银行,0,30.8,0,0,0,0,0,0,0,0\n", buf.String())
+
+	cmd = NewQuoteHistoryCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"600036", "--source", "fake", "--period", "2h"})
+	require.NotNil(t, cmd.Execute())
+
+	cmd = NewQuoteHistoryCLI()
+	provider.AddFlag(cmd)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"600036", "--source", "unknown"})
+	require.NotNil(t, cmd.Execute())
+}
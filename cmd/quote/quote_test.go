@@ -100,3 +100,28 @@ func TestPrintQuote(t *testing.T) {
 	require.Nil(t, err)
 	printQuote(quotes)
 }
+
+func TestQuoteRecords(t *testing.T) {
+	recs := quoteRecords([]*sina.SecurityQuote{{
+		Name:      "龙芯中科",
+		Code:      "688047",
+		ExCode:    "SH688047",
+		TradeDate: "2024-09-30",
+		Time:      "15:00:01",
+		Current:   110,
+		YClose:    100,
+		Volume:    938310086,
+		TurnOver:  8256723,
+	}, {Name: "停牌", Current: 10}})
+	require.Len(t, recs, 2)
+	require.Equal(t, 10.0, recs[0].Change)
+	require.InDelta(t, 10.0, recs[0].ChangeRate, 1e-9)
+	require.Equal(t, int64(8256723), recs[0].Volume)
+	require.Equal(t, 938310086.0, recs[0].Amount)
+	// 没有昨收时不计算涨跌
+	require.Zero(t, recs[1].ChangeRate)
+
+	data, err := json.Marshal(recs[0])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"excode":"SH688047","name":"龙芯中科","price":110,"change":10`)
+}
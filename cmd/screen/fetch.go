@@ -0,0 +1,186 @@
+package screen
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alwqx/sec/cmd/valuation"
+	"github.com/alwqx/sec/provider/cninfo"
+	"github.com/alwqx/sec/provider/sina"
+)
+
+// Stock 待筛选的股票
+type Stock struct {
+	Code   string
+	ExCode string
+	Name   string
+}
+
+// universe converts the CNINFO stock list to Shanghai and Shenzhen A-shares.
+// B-shares, Beijing stocks and non-stock entries are skipped.
+func universe(list []*cninfo.StockInfo) []Stock {
+	res := make([]Stock, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, s := range list {
+		if s == nil || len(s.Code) != 6 || seen[s.Code] {
+			continue
+		}
+		if s.Category != "" && s.Category != "A股" {
+			continue
+		}
+		var exCode string
+		switch s.Code[:2] {
+		case "60", "68":
+			exCode = "SH" + s.Code
+		case "00", "30":
+			exCode = "SZ" + s.Code
+		default:
+			continue
+		}
+		seen[s.Code] = true
+		res = append(res, Stock{Code: s.Code, ExCode: exCode, Name: s.Name})
+	}
+	return res
+}
+
+// limiter 简单的令牌桶限流，每 interval 放行一个请求
+type limiter struct {
+	ticker *time.Ticker
+}
+
+// newLimiter returns a limiter allowing rate requests per second, or nil when
+// rate <= 0 which means unlimited.
+func newLimiter(rate float64) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &limiter{ticker: time.NewTicker(time.Duration(float64(time.Second) / rate))}
+}
+
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.ticker.C:
+		return nil
+	}
+}
+
+func (l *limiter) stop() {
+	if l != nil {
+		l.ticker.Stop()
+	}
+}
+
+// fetchFunc 获取单只股票的筛选指标
+type fetchFunc func(ctx context.Context, s Stock) (*Row, error)
+
+// scan fetches rows of stocks with at most concurrency workers and at most
+// rate stocks started per second. Stocks that fail are skipped and counted.
+// progress, if not nil, is called after each stock is done.
+func scan(ctx context.Context, stocks []Stock, concurrency int, rate float64, fetch fetchFunc, progress func(done, total int)) ([]*Row, int) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	lim := newLimiter(rate)
+	defer lim.stop()
+
+	jobs := make(chan Stock)
+	var (
+		mu     sync.Mutex
+		rows   = make([]*Row, 0, len(stocks))
+		failed int
+		done   atomic.Int64
+		wg     sync.WaitGroup
+	)
+
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range jobs {
+				row, err := fetch(ctx, s)
+				mu.Lock()
+				if err != nil {
+					failed++
+					slog.DebugContext(ctx, "screen fetch failed", "code", s.ExCode, "error", err)
+				} else if row != nil {
+					rows = append(rows, row)
+				}
+				mu.Unlock()
+				if progress != nil {
+					progress(int(done.Add(1)), len(stocks))
+				}
+			}
+		}()
+	}
+
+	for _, s := range stocks {
+		if err := lim.wait(ctx); err != nil {
+			break
+		}
+		jobs <- s
+	}
+	close(jobs)
+	wg.Wait()
+	return rows, failed
+}
+
+// newFetcher returns a fetchFunc built on valuation.FetchMetrics. Dividends
+// are only queried when withDividends is set since they cost one more request.
+func newFetcher(withDividends bool) fetchFunc {
+	return func(ctx context.Context, s Stock) (*Row, error) {
+		m, err := valuation.FetchMetrics(ctx, s.Code, s.ExCode, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		row := rowFromMetrics(s, m)
+		if withDividends {
+			dids, err := sina.QueryDividends(ctx, s.Code)
+			if err != nil {
+				return nil, err
+			}
+			applyDividends(row, dids, time.Now())
+		}
+		return row, nil
+	}
+}
+
+func rowFromMetrics(s Stock, m *valuation.Metrics) *Row {
+	return &Row{
+		Code:   s.Code,
+		ExCode: s.ExCode,
+		Name:   s.Name,
+		Price:  m.Price,
+		MktCap: m.MktCap / 1e8,
+		PE:     m.PE,
+		PB:     m.PB,
+		PS:     m.PS,
+		PEG:    m.PEG,
+		ROE:    m.ROE,
+		Growth: m.GrowthRate,
+		EPS:    m.EPS,
+		BVPS:   m.BVPS,
+		Graham: m.Graham,
+	}
+}
+
+// applyDividends sets the trailing 12 months dividend yield and payout ratio.
+func applyDividends(row *Row, dids []sina.Dividend, now time.Time) {
+	dps := valuation.TrailingDPS(dids, now)
+	if dps <= 0 {
+		return
+	}
+	if row.Price > 0 {
+		row.DY = dps / row.Price * 100
+	}
+	if row.EPS > 0 {
+		row.Payout = dps / row.EPS * 100
+	}
+}
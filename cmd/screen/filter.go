@@ -0,0 +1,238 @@
+package screen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Row 单只股票的筛选指标
+type Row struct {
+	Code   string  `json:"code"`
+	ExCode string  `json:"excode"`
+	Name   string  `json:"name"`
+	Price  float64 `json:"price"`
+	MktCap float64 `json:"mktcap"` // 总市值，单位：亿元
+	PE     float64 `json:"pe"`
+	PB     float64 `json:"pb"`
+	PS     float64 `json:"ps"`
+	PEG    float64 `json:"peg"`
+	ROE    float64 `json:"roe"`    // %
+	Growth float64 `json:"growth"` // 净利润年复合增速 %
+	DY     float64 `json:"dy"`     // 近 12 个月股息率 %
+	Payout float64 `json:"payout"` // 派息率 %
+	EPS    float64 `json:"eps"`
+	BVPS   float64 `json:"bvps"`
+	Graham float64 `json:"graham"` // 格雷厄姆数
+}
+
+// fields 可用于筛选和排序的指标
+var fields = map[string]func(r *Row) float64{
+	"price":  func(r *Row) float64 { return r.Price },
+	"mktcap": func(r *Row) float64 { return r.MktCap },
+	"pe":     func(r *Row) float64 { return r.PE },
+	"pb":     func(r *Row) float64 { return r.PB },
+	"ps":     func(r *Row) float64 { return r.PS },
+	"peg":    func(r *Row) float64 { return r.PEG },
+	"roe":    func(r *Row) float64 { return r.ROE },
+	"growth": func(r *Row) float64 { return r.Growth },
+	"dy":     func(r *Row) float64 { return r.DY },
+	"payout": func(r *Row) float64 { return r.Payout },
+	"eps":    func(r *Row) float64 { return r.EPS },
+	"bvps":   func(r *Row) float64 { return r.BVPS },
+	"graham": func(r *Row) float64 { return r.Graham },
+}
+
+// signed 负值有效的指标（如亏损公司的 ROE、利润负增长），其余指标只有正值有效
+var signed = map[string]bool{
+	"roe":    true,
+	"growth": true,
+	"eps":    true,
+	"bvps":   true,
+}
+
+// value returns the value of field in r and whether it is valid. Zero is
+// always missing, negative values are missing unless the field is signed,
+// e.g. PE of a loss-making company.
+func value(r *Row, field string) (float64, bool) {
+	v := fields[field](r)
+	return v, v != 0 && (v > 0 || signed[field])
+}
+
+// presets 预设筛选模板
+var presets = map[string]string{
+	"graham":   "pe<15, pb<1.5, graham>price",
+	"dividend": "dy>3, pe<15, payout<80",
+	"growth":   "peg<1, growth>20, roe>15",
+}
+
+// fieldNames returns the sorted names of all fields.
+func fieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// presetNames returns the sorted names of all presets.
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cond 单个比较条件，如 pe<15 或 graham>price
+type Cond struct {
+	Field string
+	Op    string
+	Value float64
+	Ref   string // 右侧为指标时的指标名
+}
+
+func (c Cond) String() string {
+	if c.Ref != "" {
+		return c.Field + c.Op + c.Ref
+	}
+	return c.Field + c.Op + strconv.FormatFloat(c.Value, 'f', -1, 64)
+}
+
+// Match reports whether r satisfies c. Any comparison with a missing metric
+// fails, see value.
+func (c Cond) Match(r *Row) bool {
+	lhs, ok := value(r, c.Field)
+	if !ok {
+		return false
+	}
+	rhs := c.Value
+	if c.Ref != "" {
+		if rhs, ok = value(r, c.Ref); !ok {
+			return false
+		}
+	}
+
+	switch c.Op {
+	case "<":
+		return lhs < rhs
+	case "<=":
+		return lhs <= rhs
+	case ">":
+		return lhs > rhs
+	case ">=":
+		return lhs >= rhs
+	case "=", "==":
+		return lhs == rhs
+	case "!=":
+		return lhs != rhs
+	}
+	return false
+}
+
+// ops 按长度降序排列，保证 <= 优先于 < 匹配
+var ops = []string{"<=", ">=", "==", "!=", "<", ">", "="}
+
+// ParseFilter parses a filter expression of comparisons joined by ",", "&&"
+// or "and", e.g. "pe<15, pb<1.5 && roe>=15". The right side is a number or
+// another field name.
+func ParseFilter(expr string) ([]Cond, error) {
+	expr = strings.ToLower(expr)
+	expr = strings.ReplaceAll(expr, "&&", ",")
+	expr = strings.ReplaceAll(expr, " and ", ",")
+
+	var conds []Cond
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		cond, err := parseCond(part)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+func parseCond(s string) (Cond, error) {
+	for _, op := range ops {
+		i := strings.Index(s, op)
+		if i < 0 {
+			continue
+		}
+		cond := Cond{
+			Field: strings.TrimSpace(s[:i]),
+			Op:    op,
+		}
+		if _, ok := fields[cond.Field]; !ok {
+			return Cond{}, fmt.Errorf("unknown field %q in %q, available: %s", cond.Field, s, strings.Join(fieldNames(), ", "))
+		}
+		rhs := strings.TrimSpace(s[i+len(op):])
+		if v, err := strconv.ParseFloat(rhs, 64); err == nil {
+			cond.Value = v
+		} else if _, ok := fields[rhs]; ok {
+			cond.Ref = rhs
+		} else {
+			return Cond{}, fmt.Errorf("invalid value %q in %q", rhs, s)
+		}
+		return cond, nil
+	}
+	return Cond{}, fmt.Errorf("missing operator in %q", s)
+}
+
+// Filter returns the rows matching all conds.
+func Filter(rows []*Row, conds []Cond) []*Row {
+	res := make([]*Row, 0, len(rows))
+	for _, r := range rows {
+		ok := true
+		for _, c := range conds {
+			if !c.Match(r) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// Sort sorts rows by field, rows missing the field are always last.
+func Sort(rows []*Row, field string, asc bool) error {
+	if _, ok := fields[field]; !ok {
+		return fmt.Errorf("unknown sort field %q, available: %s", field, strings.Join(fieldNames(), ", "))
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, okA := value(rows[i], field)
+		b, okB := value(rows[j], field)
+		if !okA || !okB {
+			return okA && !okB
+		}
+		if asc {
+			return a < b
+		}
+		return a > b
+	})
+	return nil
+}
+
+// uses reports whether any cond or the sort field references one of names.
+func uses(conds []Cond, sortField string, names ...string) bool {
+	for _, name := range names {
+		if sortField == name {
+			return true
+		}
+		for _, c := range conds {
+			if c.Field == name || c.Ref == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+// Package screen implements the sec screen command, a full-market A-share
+// screener over valuation metrics.
+//
+// Examples:
+//
+//	sec screen --preset graham
+//	sec screen --pe-max 15 --roe-min 15 --sort roe
+//	sec screen --filter "pb<1, dy>=4" --export result.csv
+//	sec screen --preset dividend --output json
+package screen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/cninfo"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewScreenCLI returns the screen command.
+func NewScreenCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "screen",
+		Short:         "Screen A-share stocks by valuation metrics",
+		Long:          "Screen A-share stocks by valuation metrics.\n\nFields: " + strings.Join(fieldNames(), ", ") + "\nPresets: " + presetDesc(),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		RunE: runScreen,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("preset", "p", "", "Preset filter: "+strings.Join(presetNames(), ", "))
+	cmd.Flags().StringArrayP("filter", "f", nil, `Filter expression, e.g. "pe<15, roe>=15, graham>price"`)
+	cmd.Flags().Float64("pe-min", 0, "Minimum PE")
+	cmd.Flags().Float64("pe-max", 0, "Maximum PE")
+	cmd.Flags().Float64("pb-min", 0, "Minimum PB")
+	cmd.Flags().Float64("pb-max", 0, "Maximum PB")
+	cmd.Flags().Float64("roe-min", 0, "Minimum ROE %")
+	cmd.Flags().Float64("cap-min", 0, "Minimum market cap in 100 million yuan")
+	cmd.Flags().Float64("dy-min", 0, "Minimum dividend yield %")
+	cmd.Flags().Float64("growth-min", 0, "Minimum net profit CAGR %")
+	cmd.Flags().StringP("sort", "s", "mktcap", "Sort field")
+	cmd.Flags().Bool("asc", false, "Sort ascending")
+	cmd.Flags().IntP("top", "n", 50, "Show top N results, 0 for all")
+	cmd.Flags().Int("max-scan", 0, "Scan at most N stocks, 0 for the full market")
+	cmd.Flags().IntP("concurrency", "c", 8, "Number of concurrent requests")
+	cmd.Flags().Float64("rate", 10, "Maximum stocks fetched per second, 0 for unlimited")
+	cmd.Flags().String("export", "", "Export all results to file (.csv, .tsv, .json or .md)")
+	output.Enable(cmd)
+	return cmd
+}
+
+func presetDesc() string {
+	descs := make([]string, 0, len(presets))
+	for _, name := range presetNames() {
+		descs = append(descs, fmt.Sprintf("%s(%s)", name, presets[name]))
+	}
+	return strings.Join(descs, "; ")
+}
+
+// buildConds collects conditions from --preset, --filter and shortcut flags.
+func buildConds(cmd *cobra.Command) ([]Cond, error) {
+	var exprs []string
+	if preset, _ := cmd.Flags().GetString("preset"); preset != "" {
+		expr, ok := presets[strings.ToLower(preset)]
+		if !ok {
+			return nil, fmt.Errorf("unknown preset %q, available: %s", preset, strings.Join(presetNames(), ", "))
+		}
+		exprs = append(exprs, expr)
+	}
+	filters, _ := cmd.Flags().GetStringArray("filter")
+	exprs = append(exprs, filters...)
+
+	shortcuts := []struct{ flag, cond string }{
+		{"pe-min", "pe>="}, {"pe-max", "pe<="},
+		{"pb-min", "pb>="}, {"pb-max", "pb<="},
+		{"roe-min", "roe>="}, {"cap-min", "mktcap>="},
+		{"dy-min", "dy>="}, {"growth-min", "growth>="},
+	}
+	for _, s := range shortcuts {
+		if cmd.Flags().Changed(s.flag) {
+			v, _ := cmd.Flags().GetFloat64(s.flag)
+			exprs = append(exprs, fmt.Sprintf("%s%g", s.cond, v))
+		}
+	}
+
+	conds, err := ParseFilter(strings.Join(exprs, ","))
+	if err != nil {
+		return nil, err
+	}
+	if len(conds) == 0 {
+		return nil, fmt.Errorf("no filter given, use --preset, --filter or shortcut flags such as --pe-max")
+	}
+	return conds, nil
+}
+
+func runScreen(cmd *cobra.Command, args []string) error {
+	conds, err := buildConds(cmd)
+	if err != nil {
+		return err
+	}
+	sortField, _ := cmd.Flags().GetString("sort")
+	sortField = strings.ToLower(sortField)
+	if _, ok := fields[sortField]; !ok {
+		return fmt.Errorf("unknown sort field %q, available: %s", sortField, strings.Join(fieldNames(), ", "))
+	}
+
+	list, err := cninfo.GetStockList(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("获取股票列表失败: %w", err)
+	}
+	stocks := universe(list)
+	if maxScan, _ := cmd.Flags().GetInt("max-scan"); maxScan > 0 && maxScan < len(stocks) {
+		stocks = stocks[:maxScan]
+	}
+
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	withDividends := uses(conds, sortField, "dy", "payout")
+
+	errOut := cmd.ErrOrStderr()
+	fmt.Fprintf(errOut, "筛选条件: %s\n", condsString(conds))
+	rows, failed := scan(cmd.Context(), stocks, concurrency, rate, newFetcher(withDividends), func(done, total int) {
+		if done%50 == 0 || done == total {
+			fmt.Fprintf(errOut, "\r扫描进度: %d/%d", done, total)
+		}
+	})
+	fmt.Fprintln(errOut)
+
+	rows = Filter(rows, conds)
+	asc, _ := cmd.Flags().GetBool("asc")
+	if err := Sort(rows, sortField, asc); err != nil {
+		return err
+	}
+
+	// 结构化输出时统计信息写到 stderr，stdout 只有结果
+	out, f := cmd.OutOrStdout(), output.FormatOf(cmd)
+	if f.Structured() {
+		out = errOut
+	}
+	fmt.Fprintf(out, "\n共扫描 %d 只，失败 %d 只，符合条件 %d 只\n\n", len(stocks), failed, len(rows))
+
+	if path, _ := cmd.Flags().GetString("export"); path != "" {
+		if err := output.WriteFile(path, screenRecords(rows)); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "已导出到 %s\n\n", path)
+	}
+
+	if top, _ := cmd.Flags().GetInt("top"); top > 0 && top < len(rows) {
+		rows = rows[:top]
+	}
+	if f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, screenRecords(rows))
+	}
+	if len(rows) > 0 {
+		printRows(out, rows, withDividends)
+	}
+	return nil
+}
+
+func condsString(conds []Cond) string {
+	ss := make([]string, len(conds))
+	for i, c := range conds {
+		ss[i] = c.String()
+	}
+	return strings.Join(ss, ", ")
+}
+
+func printRows(out io.Writer, rows []*Row, withDividends bool) {
+	headers := []string{"代码", "名称", "现价", "市值(亿)", "PE", "PB", "PEG", "ROE", "增速", "格雷厄姆数"}
+	if withDividends {
+		headers = append(headers, "股息率", "派息率")
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+
+	for _, r := range rows {
+		row := []string{
+			r.ExCode, r.Name, ff(r.Price), ff(r.MktCap), ff(r.PE), ff(r.PB), ff(r.PEG),
+			fpct(r.ROE), fpct(r.Growth), ff(r.Graham),
+		}
+		if withDividends {
+			row = append(row, fpct(r.DY), fpct(r.Payout))
+		}
+		table.Append(row)
+	}
+	table.Render()
+	fmt.Fprintln(out)
+}
+
+// screenRecord 筛选结果的结构化输出，缺失的指标为空
+type screenRecord struct {
+	Code      string   `json:"code"`
+	ExCode    string   `json:"excode"`
+	Name      string   `json:"name"`
+	Price     *float64 `json:"price"`
+	MarketCap *float64 `json:"market_cap"` // 总市值，单位元
+	PE        *float64 `json:"pe"`
+	PB        *float64 `json:"pb"`
+	PS        *float64 `json:"ps"`
+	PEG       *float64 `json:"peg"`
+	ROE       *float64 `json:"roe"`    // %
+	Growth    *float64 `json:"growth"` // 净利润年复合增速 %
+	DY        *float64 `json:"dy"`     // 近 12 个月股息率 %
+	Payout    *float64 `json:"payout"` // 派息率 %
+	EPS       *float64 `json:"eps"`
+	BVPS      *float64 `json:"bvps"`
+	Graham    *float64 `json:"graham"` // 格雷厄姆数
+}
+
+// screenRecords converts rows, leaving out the metrics that are missing as
+// defined by value.
+func screenRecords(rows []*Row) []screenRecord {
+	res := make([]screenRecord, 0, len(rows))
+	for _, r := range rows {
+		metric := func(name string) *float64 {
+			if v, ok := value(r, name); ok {
+				return &v
+			}
+			return nil
+		}
+		rec := screenRecord{
+			Code: r.Code, ExCode: r.ExCode, Name: r.Name,
+			Price: metric("price"), MarketCap: metric("mktcap"),
+			PE: metric("pe"), PB: metric("pb"), PS: metric("ps"), PEG: metric("peg"),
+			ROE: metric("roe"), Growth: metric("growth"), DY: metric("dy"), Payout: metric("payout"),
+			EPS: metric("eps"), BVPS: metric("bvps"), Graham: metric("graham"),
+		}
+		if rec.MarketCap != nil {
+			*rec.MarketCap *= 1e8
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+func ff(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+func fpct(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", v)
+}
@@ -0,0 +1,180 @@
+package screen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/cninfo"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	conds, err := ParseFilter("PE<15, pb <= 1.5 && roe>=15 and graham>price")
+	require.NoError(t, err)
+	require.Equal(t, []Cond{
+		{Field: "pe", Op: "<", Value: 15},
+		{Field: "pb", Op: "<=", Value: 1.5},
+		{Field: "roe", Op: ">=", Value: 15},
+		{Field: "graham", Op: ">", Ref: "price"},
+	}, conds)
+
+	for _, expr := range presets {
+		_, err := ParseFilter(expr)
+		require.NoError(t, err, expr)
+	}
+
+	for _, expr := range []string{"foo<1", "pe<abc", "pe 15"} {
+		_, err := ParseFilter(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	rows := []*Row{
+		{Code: "A", Price: 10, PE: 8, PB: 1.0, Graham: 15},
+		{Code: "B", Price: 10, PE: 20, PB: 1.0, Graham: 15},
+		{Code: "C", Price: 10, PE: 0, PB: 0.8, Graham: 15}, // loss-making
+		{Code: "D", Price: 20, PE: 10, PB: 1.2, Graham: 15},
+		{Code: "E", Price: 10, PE: -5, PB: 0.9, Graham: 15}, // negative PE from a loss
+	}
+	conds, err := ParseFilter(presets["graham"])
+	require.NoError(t, err)
+
+	got := Filter(rows, conds)
+	require.Len(t, got, 1)
+	require.Equal(t, "A", got[0].Code)
+
+	// ROE is signed, a negative value is valid
+	conds, err = ParseFilter("roe<0")
+	require.NoError(t, err)
+	got = Filter([]*Row{{Code: "A", ROE: 12}, {Code: "B", ROE: -3}, {Code: "C"}}, conds)
+	require.Equal(t, "B", codes(got))
+}
+
+func TestSort(t *testing.T) {
+	rows := []*Row{{Code: "A", ROE: 10}, {Code: "B"}, {Code: "C", ROE: 20}, {Code: "D", ROE: 5}}
+
+	require.NoError(t, Sort(rows, "roe", false))
+	require.Equal(t, "C,A,D,B", codes(rows))
+
+	require.NoError(t, Sort(rows, "roe", true))
+	require.Equal(t, "D,A,C,B", codes(rows))
+
+	rows = []*Row{{Code: "A", PE: 12}, {Code: "B", PE: -4}, {Code: "C", PE: 8}}
+	require.NoError(t, Sort(rows, "pe", true))
+	require.Equal(t, "C,A,B", codes(rows))
+
+	require.Error(t, Sort(rows, "foo", true))
+}
+
+func codes(rows []*Row) string {
+	ss := make([]string, len(rows))
+	for i, r := range rows {
+		ss[i] = r.Code
+	}
+	return strings.Join(ss, ",")
+}
+
+func TestUses(t *testing.T) {
+	conds, _ := ParseFilter("pe<15")
+	require.False(t, uses(conds, "mktcap", "dy", "payout"))
+	require.True(t, uses(conds, "dy", "dy", "payout"))
+	conds, _ = ParseFilter(presets["dividend"])
+	require.True(t, uses(conds, "mktcap", "dy", "payout"))
+}
+
+func TestUniverse(t *testing.T) {
+	list := []*cninfo.StockInfo{
+		{Code: "600036", Name: "招商银行", Category: "A股"},
+		{Code: "000001", Name: "平安银行", Category: "A股"},
+		{Code: "300750", Name: "宁德时代"},
+		{Code: "688981", Name: "中芯国际", Category: "A股"},
+		{Code: "600036", Name: "招商银行", Category: "A股"},
+		{Code: "830799", Name: "艾融软件", Category: "A股"},
+		{Code: "200002", Name: "万科B", Category: "B股"},
+		{Code: "900901", Name: "云赛B股", Category: "A股"},
+		nil,
+	}
+	got := universe(list)
+	require.Equal(t, []Stock{
+		{Code: "600036", ExCode: "SH600036", Name: "招商银行"},
+		{Code: "000001", ExCode: "SZ000001", Name: "平安银行"},
+		{Code: "300750", ExCode: "SZ300750", Name: "宁德时代"},
+		{Code: "688981", ExCode: "SH688981", Name: "中芯国际"},
+	}, got)
+}
+
+func TestApplyDividends(t *testing.T) {
+	now := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	dids := []sina.Dividend{
+		{DividendedDate: "2025-07-10", Bonus: 20},
+		{DividendedDate: "2025-01-10", Bonus: 10},
+		{DividendedDate: "2024-07-10", Bonus: 18}, // older than a year
+		{DividendedDate: "--", Bonus: 5},
+	}
+	row := &Row{Price: 30, EPS: 5}
+	applyDividends(row, dids, now)
+	require.InDelta(t, 10.0, row.DY, 1e-9)
+	require.InDelta(t, 60.0, row.Payout, 1e-9)
+
+	row = &Row{Price: 30, EPS: 5}
+	applyDividends(row, nil, now)
+	require.Zero(t, row.DY)
+}
+
+func TestScan(t *testing.T) {
+	stocks := make([]Stock, 20)
+	for i := range stocks {
+		stocks[i] = Stock{Code: string(rune('a' + i))}
+	}
+	fetch := func(ctx context.Context, s Stock) (*Row, error) {
+		if s.Code == "c" {
+			return nil, errors.New("boom")
+		}
+		return &Row{Code: s.Code}, nil
+	}
+
+	var last int
+	rows, failed := scan(context.Background(), stocks, 4, 0, fetch, func(done, total int) {
+		last = done
+		require.Equal(t, 20, total)
+	})
+	require.Len(t, rows, 19)
+	require.Equal(t, 1, failed)
+	require.Equal(t, 20, last)
+
+	// rate limited: 5 stocks at 100/s take at least 40ms
+	start := time.Now()
+	rows, _ = scan(context.Background(), stocks[3:8], 2, 100, fetch, nil)
+	require.Len(t, rows, 5)
+	require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestScreenRecords(t *testing.T) {
+	rows := []*Row{{Code: "600036", ExCode: "SH600036", Name: "招商银行", MktCap: 9800, PE: 6.5, PB: -1, ROE: -2.5}}
+	recs := screenRecords(rows)
+	require.Len(t, recs, 1)
+	require.Equal(t, 9.8e11, *recs[0].MarketCap)
+	require.Equal(t, 6.5, *recs[0].PE)
+	// 非正的 PB 视为缺失，ROE 可以为负
+	require.Nil(t, recs[0].PB)
+	require.Equal(t, -2.5, *recs[0].ROE)
+	require.Nil(t, recs[0].Price)
+
+	path := filepath.Join(t.TempDir(), "out.json")
+	require.NoError(t, output.WriteFile(path, recs))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, "SH600036", got[0]["excode"])
+	require.Nil(t, got[0]["pb"])
+}
@@ -0,0 +1,278 @@
+package sector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// NewSectorCLI returns the sector command.
+func NewSectorCLI() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "sector [name]",
+		Aliases: []string{"board"},
+		Short:   "Print industry and concept board rankings",
+		Long: `Print industry, concept or region boards ranked by change, turnover or fund
+flow. With a board name or code (BK0475), print its constituents with live
+quotes.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: runSector,
+	}
+	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().StringP("type", "t", "industry", "Board type: industry, concept, region")
+	cmd.Flags().StringP("sort", "s", "change", "Sort by: change, amount, turnover, inflow")
+	cmd.Flags().BoolP("asc", "a", false, "Sort in ascending order")
+	cmd.Flags().IntP("limit", "n", 20, "Number of rows to print, 0 for all")
+	output.Enable(cmd)
+
+	return cmd
+}
+
+func runSector(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	typeStr, _ := cmd.Flags().GetString("type")
+	boardType, err := eastmoney.ParseBoardType(typeStr)
+	if err != nil {
+		return err
+	}
+	sortBy, _ := cmd.Flags().GetString("sort")
+	asc, _ := cmd.Flags().GetBool("asc")
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return fmt.Errorf("invalid --limit %d: must be >= 0", limit)
+	}
+	f := output.FormatOf(cmd)
+
+	if len(args) == 0 {
+		boards, err := eastmoney.QueryBoards(ctx, boardType)
+		if err != nil {
+			return err
+		}
+		if err := sortBoards(boards, sortBy, asc); err != nil {
+			return err
+		}
+		if f.Structured() {
+			return output.Write(cmd.OutOrStdout(), f, boardRecords(head(boards, limit)))
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s板块 (%d 个)\n", boardType.Label(), len(boards))
+		printBoards(cmd.OutOrStdout(), head(boards, limit))
+		return nil
+	}
+
+	// 未指定类型时在行业、概念、地域板块中查找
+	types := []eastmoney.BoardType{eastmoney.BoardIndustry, eastmoney.BoardConcept, eastmoney.BoardRegion}
+	if cmd.Flags().Changed("type") {
+		types = []eastmoney.BoardType{boardType}
+	}
+	board, err := findBoard(ctx, types, args[0])
+	if err != nil {
+		return err
+	}
+	stocks, err := eastmoney.QueryBoardStocks(ctx, board.Code)
+	if err != nil {
+		return err
+	}
+	if asc {
+		for i, j := 0, len(stocks)-1; i < j; i, j = i+1, j-1 {
+			stocks[i], stocks[j] = stocks[j], stocks[i]
+		}
+	}
+	if f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, stockRecords(board, head(stocks, limit)))
+	}
+	printBoardDetail(cmd.OutOrStdout(), board, stocks, limit)
+	return nil
+}
+
+func head[T any](list []T, limit int) []T {
+	if limit > 0 && len(list) > limit {
+		return list[:limit]
+	}
+	return list
+}
+
+// sortBoards sorts boards by the given key, descending unless asc.
+func sortBoards(boards []*eastmoney.Board, by string, asc bool) error {
+	var key func(b *eastmoney.Board) float64
+	switch strings.ToLower(by) {
+	case "change", "chg", "":
+		key = func(b *eastmoney.Board) float64 { return b.ChangeRate }
+	case "amount", "amt":
+		key = func(b *eastmoney.Board) float64 { return b.Amount }
+	case "turnover", "to":
+		key = func(b *eastmoney.Board) float64 { return b.TurnoverRate }
+	case "inflow", "flow":
+		key = func(b *eastmoney.Board) float64 { return b.MainInflow }
+	default:
+		return fmt.Errorf("invalid --sort %q: expected change, amount, turnover or inflow", by)
+	}
+	sort.SliceStable(boards, func(i, j int) bool {
+		if asc {
+			return key(boards[i]) < key(boards[j])
+		}
+		return key(boards[i]) > key(boards[j])
+	})
+	return nil
+}
+
+// findBoard looks up a board by code or name in boards of types. An exact name
+// wins; otherwise the name must be a substring of exactly one board.
+func findBoard(ctx context.Context, types []eastmoney.BoardType, key string) (*eastmoney.Board, error) {
+	var all []*eastmoney.Board
+	for _, t := range types {
+		boards, err := eastmoney.QueryBoards(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, boards...)
+	}
+	return matchBoard(all, key)
+}
+
+func matchBoard(boards []*eastmoney.Board, key string) (*eastmoney.Board, error) {
+	key = strings.TrimSpace(key)
+	var partial []*eastmoney.Board
+	for _, b := range boards {
+		if strings.EqualFold(b.Code, key) || b.Name == key {
+			return b, nil
+		}
+		if strings.Contains(b.Name, key) {
+			partial = append(partial, b)
+		}
+	}
+	switch len(partial) {
+	case 0:
+		return nil, fmt.Errorf("未找到板块: %s", key)
+	case 1:
+		return partial[0], nil
+	}
+	names := make([]string, 0, len(partial))
+	for _, b := range head(partial, 10) {
+		names = append(names, b.Name)
+	}
+	return nil, fmt.Errorf("板块 %s 不唯一，可选: %s", key, strings.Join(names, ", "))
+}
+
+func printBoards(out io.Writer, boards []*eastmoney.Board) {
+	table := utils.NewTable(out, []string{"板块", "代码", "涨跌幅", "成交额", "换手率", "主力净流入", "涨/跌", "领涨股", "领涨股涨幅"})
+	for _, b := range boards {
+		leader := "-"
+		if b.LeaderName != "" {
+			leader = b.LeaderName
+		}
+		table.Rich([]string{
+			b.Name,
+			b.Code,
+			fmt.Sprintf("%+.2f%%", b.ChangeRate),
+			utils.HumanNum(b.Amount),
+			fmt.Sprintf("%.2f%%", b.TurnoverRate),
+			utils.SignedNum(b.MainInflow),
+			fmt.Sprintf("%d/%d", b.Up, b.Down),
+			leader,
+			fmt.Sprintf("%+.2f%%", b.LeaderChange),
+		}, []tablewriter.Colors{{}, {}, utils.ChangeColor(b.ChangeRate), {}, {}, utils.ChangeColor(b.MainInflow), {}, {}, utils.ChangeColor(b.LeaderChange)})
+	}
+	table.Render()
+}
+
+func printBoardDetail(out io.Writer, b *eastmoney.Board, stocks []*eastmoney.MarketStock, limit int) {
+	label := b.Type.Label()
+	fmt.Fprintf(out, "板块名称\t%s (%s)\n板块类型\t%s\n最新点位\t%.2f\n涨跌幅  \t%+.2f%%\n成交额  \t%s\n主力净流入\t%s\n涨/跌家数\t%d/%d\n成分股  \t%d 只\n\n",
+		b.Name, b.Code, label, b.Price, b.ChangeRate, utils.HumanNum(b.Amount), utils.SignedNum(b.MainInflow), b.Up, b.Down, len(stocks))
+
+	table := utils.NewTable(out, []string{"代码", "名称", "最新", "涨跌幅", "成交额"})
+	for _, s := range head(stocks, limit) {
+		price, chg := "-", "停牌"
+		if !s.Suspended() {
+			price = strconv.FormatFloat(s.Price, 'f', 2, 64)
+			chg = fmt.Sprintf("%+.2f%%", s.ChangeRate)
+		}
+		table.Rich([]string{s.ExCode(), s.Name, price, chg, utils.HumanNum(s.Amount)},
+			[]tablewriter.Colors{{}, {}, {}, utils.ChangeColor(s.ChangeRate), {}})
+	}
+	table.Render()
+}
+
+// boardRecord 板块排行的结构化输出
+type boardRecord struct {
+	Code             string  `json:"code"`
+	Name             string  `json:"name"`
+	Type             string  `json:"type"` // industry、concept、region
+	Price            float64 `json:"price"`
+	ChangeRate       float64 `json:"change_rate"` // 涨跌幅 %
+	Amount           float64 `json:"amount"`
+	TurnoverRate     float64 `json:"turnover_rate"` // 换手率 %
+	MainInflow       float64 `json:"main_inflow"`   // 主力净流入，单位：元
+	Up               int     `json:"up"`
+	Down             int     `json:"down"`
+	LeaderCode       string  `json:"leader_code"`
+	LeaderName       string  `json:"leader_name"`
+	LeaderChangeRate float64 `json:"leader_change_rate"`
+}
+
+func boardRecords(boards []*eastmoney.Board) []boardRecord {
+	res := make([]boardRecord, 0, len(boards))
+	for _, b := range boards {
+		res = append(res, boardRecord{
+			Code:             b.Code,
+			Name:             b.Name,
+			Type:             string(b.Type),
+			Price:            b.Price,
+			ChangeRate:       b.ChangeRate,
+			Amount:           b.Amount,
+			TurnoverRate:     b.TurnoverRate,
+			MainInflow:       b.MainInflow,
+			Up:               b.Up,
+			Down:             b.Down,
+			LeaderCode:       b.LeaderCode,
+			LeaderName:       b.LeaderName,
+			LeaderChangeRate: b.LeaderChange,
+		})
+	}
+	return res
+}
+
+// stockRecord 板块成分股的结构化输出，停牌时价格和涨跌幅为空
+type stockRecord struct {
+	BoardCode  string   `json:"board_code"`
+	BoardName  string   `json:"board_name"`
+	Code       string   `json:"code"`
+	ExCode     string   `json:"excode"`
+	Name       string   `json:"name"`
+	Price      *float64 `json:"price"`
+	ChangeRate *float64 `json:"change_rate"`
+	Amount     float64  `json:"amount"`
+}
+
+func stockRecords(b *eastmoney.Board, stocks []*eastmoney.MarketStock) []stockRecord {
+	res := make([]stockRecord, 0, len(stocks))
+	for _, s := range stocks {
+		rec := stockRecord{
+			BoardCode: b.Code,
+			BoardName: b.Name,
+			Code:      s.Code,
+			ExCode:    s.ExCode(),
+			Name:      s.Name,
+			Amount:    s.Amount,
+		}
+		if !s.Suspended() {
+			rec.Price, rec.ChangeRate = output.Num(s.Price), output.Num(s.ChangeRate)
+		}
+		res = append(res, rec)
+	}
+	return res
+}
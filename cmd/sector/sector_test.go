@@ -0,0 +1,88 @@
+package sector
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func testBoards() []*eastmoney.Board {
+	return []*eastmoney.Board{
+		{Code: "BK0475", Name: "银行", Type: eastmoney.BoardIndustry, ChangeRate: -1.2, Amount: 1.2e10, MainInflow: -3.5e8, LeaderName: "招商银行", LeaderChange: 1.5},
+		{Code: "BK1036", Name: "半导体", Type: eastmoney.BoardIndustry, ChangeRate: 3.4, Amount: 5e9, MainInflow: 1.2e9},
+		{Code: "BK0891", Name: "国产芯片", Type: eastmoney.BoardConcept, ChangeRate: 2.1, Amount: 8e9, MainInflow: 2e8},
+		{Code: "BK0917", Name: "半导体概念", Type: eastmoney.BoardConcept, ChangeRate: 2.0, Amount: 9e9},
+	}
+}
+
+func TestSortBoards(t *testing.T) {
+	boards := testBoards()
+	require.NoError(t, sortBoards(boards, "inflow", false))
+	require.Equal(t, "BK1036", boards[0].Code)
+	require.Equal(t, "BK0475", boards[3].Code)
+
+	require.NoError(t, sortBoards(boards, "amount", true))
+	require.Equal(t, "BK1036", boards[0].Code)
+
+	require.Error(t, sortBoards(boards, "name", false))
+}
+
+func TestMatchBoard(t *testing.T) {
+	b, err := matchBoard(testBoards(), "半导体")
+	require.NoError(t, err)
+	require.Equal(t, "BK1036", b.Code)
+
+	b, err = matchBoard(testBoards(), "bk0891")
+	require.NoError(t, err)
+	require.Equal(t, "国产芯片", b.Name)
+
+	b, err = matchBoard(testBoards(), "芯片")
+	require.NoError(t, err)
+	require.Equal(t, "BK0891", b.Code)
+
+	_, err = matchBoard(testBoards(), "银")
+	require.NoError(t, err)
+
+	_, err = matchBoard(testBoards(), "体")
+	require.ErrorContains(t, err, "不唯一")
+
+	_, err = matchBoard(testBoards(), "白酒")
+	require.ErrorContains(t, err, "未找到板块")
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	printBoards(&buf, testBoards())
+	require.Contains(t, buf.String(), "招商银行")
+	require.Contains(t, buf.String(), "-3.50亿")
+	require.Contains(t, buf.String(), "+12.00亿")
+
+	buf.Reset()
+	stocks := []*eastmoney.MarketStock{
+		{Code: "600036", Market: eastmoney.MarketTypeSse, Name: "招商银行", Price: 40, ChangeRate: 1, Amount: 1e9},
+		{Code: "000001", Market: eastmoney.MarketTypeSzSe, Name: "平安银行"},
+	}
+	printBoardDetail(&buf, testBoards()[0], stocks, 1)
+	require.Contains(t, buf.String(), "板块名称\t银行 (BK0475)")
+	require.Contains(t, buf.String(), "成分股  \t2 只")
+	require.Contains(t, buf.String(), "SH600036")
+	require.NotContains(t, buf.String(), "SZ000001")
+
+	recs := stockRecords(testBoards()[0], stocks)
+	require.Equal(t, "BK0475", recs[0].BoardCode)
+	require.Equal(t, "SH600036", recs[0].ExCode)
+	require.Equal(t, 40.0, *recs[0].Price)
+	require.Nil(t, recs[1].Price)
+	require.Nil(t, recs[1].ChangeRate)
+}
+
+func TestBoardRecords(t *testing.T) {
+	recs := boardRecords(testBoards())
+	require.Len(t, recs, 4)
+	require.Equal(t, "industry", recs[0].Type)
+	require.Equal(t, -3.5e8, recs[0].MainInflow)
+	require.Equal(t, "招商银行", recs[0].LeaderName)
+	require.Equal(t, "concept", recs[2].Type)
+}
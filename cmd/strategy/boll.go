@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/eastmoney"
 	"github.com/spf13/cobra"
 )
@@ -26,7 +27,7 @@ func ComputeBollinger(quotes []*eastmoney.Quote, period int, k float64) (headers
 	dates := make([]string, n)
 	for i, q := range quotes {
 		prices[i] = q.Close
-		dates[i] = q.Date.Format("2006-01-02")
+		dates[i] = DateString(q.Date)
 	}
 
 	middle := sma(prices, period)
@@ -75,13 +76,13 @@ func NewBollCLI() *cobra.Command {
 		Args:          cobra.ExactArgs(1),
 		RunE:          runBoll,
 	}
-	cmd.Flags().IntP("period", "p", 20, "MA period")
+	cmd.Flags().IntP("window", "p", 20, "MA window in bars")
 	cmd.Flags().Float64P("k", "k", 2.0, "Standard deviation multiplier")
 	return cmd
 }
 
 func runBoll(cmd *cobra.Command, args []string) error {
-	period, _ := cmd.Flags().GetInt("period")
+	period, _ := cmd.Flags().GetInt("window")
 	k, _ := cmd.Flags().GetFloat64("k")
 
 	exCode, name, quotes, err := fetchOHLCV(cmd, args[0], 250)
@@ -90,6 +91,9 @@ func runBoll(cmd *cobra.Command, args []string) error {
 	}
 
 	headers, data, signals := ComputeBollinger(quotes, period, k)
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, signalTable([]string{"lower", "middle", "upper"}, data, signals))
+	}
 	if headers == nil {
 		fmt.Fprintf(cmd.OutOrStdout(), "数据不足（需要至少 %d 个交易日）\n", period)
 		return nil
@@ -3,6 +3,7 @@ package strategy
 import (
 	"fmt"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/eastmoney"
 	"github.com/spf13/cobra"
 )
@@ -19,7 +20,7 @@ func ComputeMA(quotes []*eastmoney.Quote, fastPeriod, slowPeriod int) (headers [
 	dates := make([]string, len(quotes))
 	for i, q := range quotes {
 		prices[i] = q.Close
-		dates[i] = q.Date.Format("2006-01-02")
+		dates[i] = DateString(q.Date)
 	}
 
 	fastMA := sma(prices, fastPeriod)
@@ -97,6 +98,9 @@ func runMA(cmd *cobra.Command, args []string) error {
 	}
 
 	headers, data, signals := ComputeMA(quotes, fast, slow)
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, signalTable([]string{"ma_fast", "ma_slow"}, data, signals))
+	}
 	if headers == nil {
 		fmt.Fprintf(cmd.OutOrStdout(), "数据不足（需要至少 %d 个交易日）\n", slow)
 		return nil
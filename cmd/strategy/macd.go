@@ -3,6 +3,7 @@ package strategy
 import (
 	"fmt"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/eastmoney"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +24,7 @@ func ComputeMACD(quotes []*eastmoney.Quote, fast, slow, signal int) (headers []s
 	dates := make([]string, len(quotes))
 	for i, q := range quotes {
 		prices[i] = q.Close
-		dates[i] = q.Date.Format("2006-01-02")
+		dates[i] = DateString(q.Date)
 	}
 
 	emaFast := ema(prices, fast)
@@ -121,6 +122,9 @@ func runMACD(cmd *cobra.Command, args []string) error {
 	}
 
 	headers, data, signals := ComputeMACD(quotes, fast, slow, signal)
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, signalTable([]string{"macd", "signal_line", "histogram"}, data, signals))
+	}
 	if headers == nil {
 		fmt.Fprintf(cmd.OutOrStdout(), "数据不足（需要至少 %d 个交易日）\n", slow+signal)
 		return nil
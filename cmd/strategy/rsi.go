@@ -3,6 +3,7 @@ package strategy
 import (
 	"fmt"
 
+	"github.com/alwqx/sec/output"
 	"github.com/alwqx/sec/provider/eastmoney"
 	"github.com/spf13/cobra"
 )
@@ -19,7 +20,7 @@ func ComputeRSI(quotes []*eastmoney.Quote, period int, overbought, oversold floa
 	dates := make([]string, len(quotes))
 	for i, q := range quotes {
 		prices[i] = q.Close
-		dates[i] = q.Date.Format("2006-01-02")
+		dates[i] = DateString(q.Date)
 	}
 
 	// Calculate RSI
@@ -97,14 +98,14 @@ func NewRSICLI() *cobra.Command {
 		Args:          cobra.ExactArgs(1),
 		RunE:          runRSI,
 	}
-	cmd.Flags().IntP("period", "p", 14, "RSI period")
+	cmd.Flags().IntP("window", "p", 14, "RSI window in bars")
 	cmd.Flags().Float64("oversold", 30, "Oversold threshold")
 	cmd.Flags().Float64("overbought", 70, "Overbought threshold")
 	return cmd
 }
 
 func runRSI(cmd *cobra.Command, args []string) error {
-	period, _ := cmd.Flags().GetInt("period")
+	period, _ := cmd.Flags().GetInt("window")
 	overbought, _ := cmd.Flags().GetFloat64("overbought")
 	oversold, _ := cmd.Flags().GetFloat64("oversold")
 
@@ -114,6 +115,9 @@ func runRSI(cmd *cobra.Command, args []string) error {
 	}
 
 	headers, data, signals := ComputeRSI(quotes, period, overbought, oversold)
+	if f := output.FormatOf(cmd); f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, signalTable([]string{"rsi"}, data, signals))
+	}
 	if headers == nil {
 		fmt.Fprintf(cmd.OutOrStdout(), "数据不足（需要至少 %d 个交易日）\n", period+1)
 		return nil
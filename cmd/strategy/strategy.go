@@ -4,10 +4,16 @@ package strategy
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/cache"
 	"github.com/alwqx/sec/provider/eastmoney"
-	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/provider/index"
+	"github.com/alwqx/sec/types"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
@@ -50,7 +56,7 @@ func displayTable(cmd *cobra.Command, headers []string, data [][]string, signals
 		if sigIdx < len(signals) && start+i < len(data) {
 			idx := start + i
 			for _, s := range signals {
-				if s.Date.Format("2006-01-02") == data[idx][0] {
+				if DateString(s.Date) == data[idx][0] {
 					switch s.Type {
 					case "buy":
 						colors[len(row)-1] = tablewriter.Colors{tablewriter.FgRedColor, tablewriter.Bold}
@@ -79,36 +85,138 @@ func displayTable(cmd *cobra.Command, headers []string, data [][]string, signals
 	fmt.Fprintf(out, "\n信号统计: 买入 %d 次 / 卖出 %d 次\n\n", buyCount, sellCount)
 }
 
-// fetchOHLCV searches the stock and returns daily OHLCV data.
+// signalTable converts the rows of a strategy result into a table for the
+// structured output formats. keys name the indicator columns between the
+// close and signal columns; "-" cells become nil. Unlike the table view it
+// keeps every bar.
+func signalTable(keys []string, data [][]string, signals []Signal) *output.Table {
+	bySignal := make(map[string]Signal, len(signals))
+	for _, s := range signals {
+		bySignal[DateString(s.Date)] = s
+	}
+
+	columns := append([]string{"date", "close"}, keys...)
+	columns = append(columns, "signal", "reason")
+	t := &output.Table{Columns: columns, Rows: make([][]any, 0, len(data))}
+	for _, row := range data {
+		if len(row) < len(keys)+2 {
+			continue
+		}
+		values := make([]any, 0, len(columns))
+		values = append(values, row[0])
+		for _, v := range row[1 : len(keys)+2] {
+			values = append(values, parseCell(v))
+		}
+		if s, ok := bySignal[row[0]]; ok {
+			values = append(values, s.Type, s.Reason)
+		} else {
+			values = append(values, nil, nil)
+		}
+		t.Rows = append(t.Rows, values)
+	}
+	return t
+}
+
+// parseCell parses a formatted number, nil for "-".
+func parseCell(s string) any {
+	if s == "-" {
+		return nil
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v
+	}
+	return s
+}
+
+// fetchOHLCV searches the stock and returns OHLCV bars of the --period flag
+// for the last days calendar days. The window is scaled up for weekly and
+// longer periods so they get a comparable number of bars.
 func fetchOHLCV(cmd *cobra.Command, code string, days int) (string, string, []*eastmoney.Quote, error) {
-	secs := sina.Search(cmd.Context(), code)
+	periodStr, _ := cmd.Flags().GetString("period")
+	period, err := eastmoney.ParsePeriod(periodStr)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	end := time.Now()
+	begin := end.Add(-time.Duration(period.Lookback(days)) * 24 * time.Hour)
+	return fetchHistory(cmd, code, begin.Format(eastmoney.TimeYYMMDD), end.Format(eastmoney.TimeYYMMDD), period)
+}
+
+// FetchOHLCV searches the stock and returns OHLCV bars of period between
+// begin and end (20060102). It honors the --source, --no-cache and --fq flags
+// of cmd.
+func FetchOHLCV(cmd *cobra.Command, code, begin, end string, period eastmoney.Period) (string, string, []*eastmoney.Quote, error) {
+	return fetchHistory(cmd, code, begin, end, period)
+}
+
+func fetchHistory(cmd *cobra.Command, code, begin, end string, period eastmoney.Period) (string, string, []*eastmoney.Quote, error) {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+		src.History = cache.NewHistory(src.History)
+	}
+	src.Searcher = index.NewSearcher(src.Searcher)
+	fqt, err := fqtOf(cmd)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	secs := src.Searcher.Search(cmd.Context(), code)
 	if len(secs) == 0 {
 		return "", "", nil, fmt.Errorf("未找到证券: %s", code)
 	}
 	sec := secs[0]
 
-	req := &eastmoney.GetQuoteHistoryReq{Code: sec.Code}
+	req := &eastmoney.GetQuoteHistoryReq{Code: sec.Code, Begin: begin, End: end, Period: period, FQT: fqt}
 	switch sec.ExChange {
 	case "sh":
 		req.MarketCode = 1
 	case "sz":
 		req.MarketCode = 0
+	case types.ExChangeCSI:
+		req.MarketCode = int(eastmoney.MarketTypeCSI)
 	default:
 		return "", "", nil, fmt.Errorf("不支持的交易所: %s", sec.ExChange)
 	}
 
-	end := time.Now()
-	begin := end.Add(-time.Duration(days) * 24 * time.Hour)
-	req.Begin = begin.Format(eastmoney.TimeYYMMDD)
-	req.End = end.Format(eastmoney.TimeYYMMDD)
-
-	quotes, err := eastmoney.GetQuoteHistory(cmd.Context(), req)
+	quotes, err := src.History.GetQuoteHistory(cmd.Context(), req)
 	if err != nil {
 		return "", "", nil, err
 	}
 	return sec.ExCode, sec.Name, quotes, nil
 }
 
+// FQFlagUsage --fq 参数说明
+const FQFlagUsage = "FuQuan type: qfq front, hfq post, bfq none"
+
+// fqtOf returns the FuQuan type of the --fq flag, unadjusted (bfq) when the
+// flag is empty or missing. Strategy keeps unadjusted bars by default, while
+// backtest defaults to qfq so ex-dividend and split gaps are not mistaken for
+// price moves.
+func fqtOf(cmd *cobra.Command) (eastmoney.FuQuanType, error) {
+	fq, _ := cmd.Flags().GetString("fq")
+	switch fq {
+	case "", "bfq":
+		return eastmoney.QuoteFQTDefault, nil
+	case "qfq":
+		return eastmoney.QuoteFQTFront, nil
+	case "hfq":
+		return eastmoney.QuoteFQTPost, nil
+	}
+	return 0, fmt.Errorf("invalid fq %q: expected one of qfq, hfq, bfq", fq)
+}
+
+// DateString formats the date of a bar, with the time of day for minute bars.
+func DateString(t time.Time) string {
+	if t.Hour() != 0 || t.Minute() != 0 {
+		return t.Format(eastmoney.TimeMinute)
+	}
+	return t.Format("2006-01-02")
+}
+
 // NewStrategyCLI returns the parent strategy command with subcommands.
 func NewStrategyCLI() *cobra.Command {
 	cmd := &cobra.Command{
@@ -124,6 +232,11 @@ func NewStrategyCLI() *cobra.Command {
 			cmd.Print(cmd.UsageString())
 		},
 	}
-	cmd.AddCommand(NewMACLI(), NewMACDCLI(), NewRSICLI(), NewBollCLI())
+	cmd.PersistentFlags().Bool("no-cache", false, "Bypass local K-line history cache")
+	cmd.PersistentFlags().String("fq", "bfq", FQFlagUsage)
+	cmd.PersistentFlags().String("period", "day", "K-line period: "+strings.Join(eastmoney.PeriodNames(), ", "))
+	subs := []*cobra.Command{NewMACLI(), NewMACDCLI(), NewRSICLI(), NewBollCLI()}
+	output.Enable(subs...)
+	cmd.AddCommand(subs...)
 	return cmd
 }
@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"testing"
 	"time"
@@ -180,3 +181,52 @@ func parseOrZero(s string) float64 {
 	fmt.Sscanf(s, "%f", &v)
 	return v
 }
+
+func TestDateString(t *testing.T) {
+	require.Equal(t, "2026-01-05", DateString(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, "2026-01-05 14:30", DateString(time.Date(2026, 1, 5, 14, 30, 0, 0, time.UTC)))
+}
+
+func TestPeriodFlag(t *testing.T) {
+	cmd := NewStrategyCLI()
+	cmd.SetArgs([]string{"rsi", "600036", "--period", "2m"})
+	cmd.SetOut(io.Discard)
+	require.ErrorContains(t, cmd.Execute(), "invalid period")
+}
+
+func TestSignalTable(t *testing.T) {
+	quotes := makeQuotes([]float64{10, 11, 12})
+	data := [][]string{
+		{"2026-01-05", "10.00", "-", "-"},
+		{"2026-01-06", "11.00", "10.50", "☍ 金叉买入"},
+		{"2026-01-07", "12.00", "11.50", "-"},
+	}
+	signals := []Signal{{Date: quotes[1].Date, Type: "buy", Price: 11, Reason: "金叉"}}
+
+	table := signalTable([]string{"ma_fast"}, data, signals)
+	require.Equal(t, []string{"date", "close", "ma_fast", "signal", "reason"}, table.Columns)
+	require.Equal(t, [][]any{
+		{"2026-01-05", 10.0, nil, nil, nil},
+		{"2026-01-06", 11.0, 10.5, "buy", "金叉"},
+		{"2026-01-07", 12.0, 11.5, nil, nil},
+	}, table.Rows)
+
+	// 数据不足时只有列名
+	require.Empty(t, signalTable([]string{"rsi"}, nil, nil).Rows)
+}
+
+func TestFQTOf(t *testing.T) {
+	cmd := NewStrategyCLI()
+	fqt, err := fqtOf(cmd)
+	require.NoError(t, err)
+	require.Equal(t, eastmoney.QuoteFQTDefault, fqt)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--fq", "qfq"}))
+	fqt, err = fqtOf(cmd)
+	require.NoError(t, err)
+	require.Equal(t, eastmoney.QuoteFQTFront, fqt)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--fq", "xfq"}))
+	_, err = fqtOf(cmd)
+	require.ErrorContains(t, err, `invalid fq "xfq"`)
+}
@@ -1,11 +1,16 @@
 package valuation
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"math"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
 	"github.com/alwqx/sec/provider/eastmoney"
 	"github.com/alwqx/sec/provider/sina"
 	"github.com/alwqx/sec/types"
@@ -35,6 +40,7 @@ func NewValuationCLI() *cobra.Command {
 	cmd.Flags().Float64("terminal-growth", 3, "DCF: terminal growth rate %")
 	cmd.Flags().Float64("wacc", 0, "DCF: discount rate %, 0=auto 8%")
 	cmd.Flags().Float64("margin-of-safety", 20, "DCF: margin of safety %")
+	output.Enable(cmd)
 	return cmd
 }
 
@@ -58,6 +64,7 @@ type Metrics struct {
 	RevenueTTM float64
 	ProfitTTM  float64
 	GrowthRate float64
+	RevGrowth  float64 // 最近一年营收同比增速 %
 
 	HistPE     []float64
 	HistYears  []string
@@ -69,14 +76,66 @@ type Metrics struct {
 }
 
 func ValuationHandler(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	key := args[0]
-	secs := sina.Search(cmd.Context(), key)
+	secs := src.Searcher.Search(cmd.Context(), key)
 	if len(secs) == 0 {
 		fmt.Fprintf(cmd.OutOrStdout(), "未找到证券: %s\n", key)
 		return nil
 	}
 	sec := secs[0]
 
+	profile, incomeItems, balanceItems, err := fetchReports(cmd.Context(), sec.Code, sec.ExCode)
+	if err != nil {
+		return err
+	}
+	m := computeMetrics(sec.ExCode, sec.Name, profile, incomeItems, balanceItems)
+
+	method, _ := cmd.Flags().GetString("method")
+	if f := output.FormatOf(cmd); f.Structured() {
+		rec := newMetricsRecord(m)
+		rec.Method = method
+		if method == "dcf" {
+			rec.DCF = computeDCF(m, dcfParamsOf(cmd))
+		}
+		return output.Write(cmd.OutOrStdout(), f, rec)
+	}
+	switch method {
+	case "pe":
+		printPEMethod(cmd, m)
+	case "pb":
+		printPBMethod(cmd, m)
+	case "ps":
+		printPSMethod(cmd, m)
+	case "peg":
+		printPEGMethod(cmd, m, incomeItems)
+	case "graham":
+		printGrahamMethod(cmd, m)
+	case "dcf":
+		printDCFMethod(cmd, m, computeDCF(m, dcfParamsOf(cmd)))
+	default:
+		printOverview(cmd, m, incomeItems)
+	}
+	return nil
+}
+
+// FetchMetrics fetches the company profile and annual financial reports of a
+// security and computes its valuation metrics.
+func FetchMetrics(ctx context.Context, code, exCode, name string) (*Metrics, error) {
+	profile, incomeItems, balanceItems, err := fetchReports(ctx, code, exCode)
+	if err != nil {
+		return nil, err
+	}
+	return computeMetrics(exCode, name, profile, incomeItems, balanceItems), nil
+}
+
+// fetchReports concurrently fetches the profile, annual income statements and
+// annual balance sheets used by computeMetrics.
+func fetchReports(ctx context.Context, code, exCode string) (*sina.CorpProfile, []*eastmoney.FinancialReportItem, []*eastmoney.FinancialReportItem, error) {
 	var (
 		profile          *sina.CorpProfile
 		incomeItems      []*eastmoney.FinancialReportItem
@@ -86,59 +145,35 @@ func ValuationHandler(cmd *cobra.Command, args []string) error {
 	)
 	wg.Add(3)
 
-	opts := &types.InfoOptions{Code: sec.Code, ExCode: sec.ExCode}
+	opts := &types.InfoOptions{Code: code, ExCode: exCode}
 	go func() {
 		defer wg.Done()
-		profile, err1 = sina.Profile(cmd.Context(), opts)
+		profile, err1 = sina.Profile(ctx, opts)
 	}()
 	go func() {
 		defer wg.Done()
-		incomeItems, err2 = eastmoney.GetFinancialReport(cmd.Context(), &eastmoney.GetFinancialReportReq{
-			Code: sec.Code, ReportType: eastmoney.ReportIncome, Period: eastmoney.PeriodAnnual,
+		incomeItems, err2 = eastmoney.GetFinancialReport(ctx, &eastmoney.GetFinancialReportReq{
+			Code: code, ReportType: eastmoney.ReportIncome, Period: eastmoney.PeriodAnnual,
 		})
 	}()
 	go func() {
 		defer wg.Done()
-		balanceItems, err3 = eastmoney.GetFinancialReport(cmd.Context(), &eastmoney.GetFinancialReportReq{
-			Code: sec.Code, ReportType: eastmoney.ReportBalance, Period: eastmoney.PeriodAnnual,
+		balanceItems, err3 = eastmoney.GetFinancialReport(ctx, &eastmoney.GetFinancialReportReq{
+			Code: code, ReportType: eastmoney.ReportBalance, Period: eastmoney.PeriodAnnual,
 		})
 	}()
 	wg.Wait()
 
 	if err1 != nil {
-		return fmt.Errorf("获取公司信息失败: %w", err1)
+		return nil, nil, nil, fmt.Errorf("获取公司信息失败: %w", err1)
 	}
 	if err2 != nil {
-		return fmt.Errorf("获取利润表失败: %w", err2)
+		return nil, nil, nil, fmt.Errorf("获取利润表失败: %w", err2)
 	}
 	if err3 != nil {
-		return fmt.Errorf("获取资产负债表失败: %w", err3)
-	}
-
-	m := computeMetrics(sec.ExCode, sec.Name, profile, incomeItems, balanceItems)
-
-	method, _ := cmd.Flags().GetString("method")
-	switch method {
-	case "pe":
-		printPEMethod(cmd, m)
-	case "pb":
-		printPBMethod(cmd, m)
-	case "ps":
-		printPSMethod(cmd, m)
-	case "peg":
-		printPEGMethod(cmd, m, incomeItems)
-	case "graham":
-		printGrahamMethod(cmd, m)
-	case "dcf":
-		growthRate, _ := cmd.Flags().GetFloat64("growth-rate")
-		terminalGrowth, _ := cmd.Flags().GetFloat64("terminal-growth")
-		wacc, _ := cmd.Flags().GetFloat64("wacc")
-		marginOfSafety, _ := cmd.Flags().GetFloat64("margin-of-safety")
-		printDCFMethod(cmd, m, growthRate, terminalGrowth, wacc, marginOfSafety)
-	default:
-		printOverview(cmd, m, incomeItems)
+		return nil, nil, nil, fmt.Errorf("获取资产负债表失败: %w", err3)
 	}
-	return nil
+	return profile, incomeItems, balanceItems, nil
 }
 
 // computeMetrics builds valuation metrics from financial data.
@@ -166,6 +201,7 @@ func computeMetrics(code, name string, profile *sina.CorpProfile,
 			m.PS = m.MktCap / m.RevenueTTM
 		}
 		m.GrowthRate = computeGrowthRate(incomeItems)
+		m.RevGrowth = computeRevenueGrowth(incomeItems)
 		m.PEG = computePEG(m.PE, m.GrowthRate)
 	}
 
@@ -220,6 +256,35 @@ func computeGrowthRate(incomeItems []*eastmoney.FinancialReportItem) float64 {
 	return (math.Pow(latest/oldest, 1.0/years) - 1) * 100
 }
 
+// computeRevenueGrowth returns the year-over-year revenue growth (%) of the
+// latest annual income statement. Returns 0 if insufficient data.
+func computeRevenueGrowth(incomeItems []*eastmoney.FinancialReportItem) float64 {
+	if len(incomeItems) < 2 {
+		return 0
+	}
+	latest := fieldFloat(incomeItems[0], "TOTAL_OPERATE_INCOME")
+	prev := fieldFloat(incomeItems[1], "TOTAL_OPERATE_INCOME")
+	if latest <= 0 || prev <= 0 {
+		return 0
+	}
+	return (latest/prev - 1) * 100
+}
+
+// TrailingDPS returns the cash dividend per share whose ex-dividend date is
+// within one year before now. sina.Dividend.Bonus is quoted per 10 shares.
+func TrailingDPS(dids []sina.Dividend, now time.Time) float64 {
+	yearAgo := now.AddDate(-1, 0, 0)
+	var dps float64
+	for _, d := range dids {
+		date, err := time.Parse(utils.LayoutYYMMDD, strings.TrimSpace(d.DividendedDate))
+		if err != nil || date.Before(yearAgo) || date.After(now) {
+			continue
+		}
+		dps += d.Bonus / 10
+	}
+	return dps
+}
+
 // computePEG returns the PEG ratio: P/E divided by earnings growth rate (%).
 // PEG = 1 is considered fair value; < 1 suggests undervaluation; > 2 suggests overvaluation.
 // Returns 0 if either input is invalid.
@@ -514,63 +579,35 @@ func printGrahamMethod(cmd *cobra.Command, m *Metrics) {
 	fmt.Fprintln(out)
 }
 
-func printDCFMethod(cmd *cobra.Command, m *Metrics,
-	growthRate, terminalGrowth, wacc, marginOfSafety float64) {
+func printDCFMethod(cmd *cobra.Command, m *Metrics, d *DCF) {
 	out := cmd.OutOrStdout()
 	printHeader(cmd, m)
 	fmt.Fprintf(out, "方法: 自由现金流折现 (DCF)\n")
 	fmt.Fprintf(out, "公式: 企业价值 = Σ(FCF_t/(1+WACC)^t) + 终值/(1+WACC)^n\n\n")
 
-	if growthRate <= 0 {
-		growthRate = m.GrowthRate
-	}
-	if growthRate <= 0 {
-		growthRate = 5
-	}
-	if wacc <= 0 {
-		wacc = 8
-	}
-
-	fcf := m.ProfitTTM * 0.7
-	years := 5
-
 	fmt.Fprintf(out, "【DCF 参数】\n")
 	richTable(out, []string{"参数", "数值", "说明"}, [][]string{
-		{"当前 FCF (近似)", utils.HumanNum(fcf), "净利润 × 70%"},
-		{"增长阶段", fmt.Sprintf("%d 年", years), "高增长期"},
-		{"增长率", fpct(growthRate), "--growth-rate"},
-		{"永续增长率", fpct(terminalGrowth), "--terminal-growth"},
-		{"折现率 WACC", fpct(wacc), "--wacc"},
-		{"安全边际", fpct(marginOfSafety), "--margin-of-safety"},
+		{"当前 FCF (近似)", utils.HumanNum(d.FCF), "净利润 × 70%"},
+		{"增长阶段", fmt.Sprintf("%d 年", d.Years), "高增长期"},
+		{"增长率", fpct(d.GrowthRate), "--growth-rate"},
+		{"永续增长率", fpct(d.TerminalGrowth), "--terminal-growth"},
+		{"折现率 WACC", fpct(d.WACC), "--wacc"},
+		{"安全边际", fpct(d.MarginOfSafety), "--margin-of-safety"},
 	})
 
-	pv := 0.0
-	cf := fcf
-	for t := 1; t <= years; t++ {
-		cf = cf * (1 + growthRate/100)
-		pv += cf / math.Pow(1+wacc/100, float64(t))
-	}
-	terminalCF := cf * (1 + terminalGrowth/100)
-	terminalValue := terminalCF / ((wacc - terminalGrowth) / 100)
-	pvTerminal := terminalValue / math.Pow(1+wacc/100, float64(years))
-
-	enterpriseValue := pv + pvTerminal
-	equityValue := enterpriseValue / m.Shares
-	fairPrice := equityValue * (1 - marginOfSafety/100)
-
 	fmt.Fprintf(out, "\n【DCF 估值结果】\n")
 	richTable(out, []string{"项目", "数值"}, [][]string{
-		{"增长期现值", utils.HumanNum(pv)},
-		{"终值现值", utils.HumanNum(pvTerminal)},
-		{"企业价值", utils.HumanNum(enterpriseValue)},
-		{"每股内在价值", fmt.Sprintf("%.2f", equityValue)},
-		{"安全边际后合理价", fmt.Sprintf("%.2f", fairPrice)},
+		{"增长期现值", utils.HumanNum(d.PV)},
+		{"终值现值", utils.HumanNum(d.PVTerminal)},
+		{"企业价值", utils.HumanNum(d.EnterpriseValue)},
+		{"每股内在价值", fmt.Sprintf("%.2f", d.ValuePerShare)},
+		{"安全边际后合理价", fmt.Sprintf("%.2f", d.FairPrice)},
 		{"当前股价", fmt.Sprintf("%.2f", m.Price)},
-		{"上涨/下跌空间", fmt.Sprintf("%+.1f%%", (fairPrice/m.Price-1)*100)},
+		{"上涨/下跌空间", fmt.Sprintf("%+.1f%%", d.Upside)},
 	})
 
 	fmt.Fprintf(out, "\n【评估】")
-	if fairPrice > m.Price {
+	if d.FairPrice > m.Price {
 		fmt.Fprintf(out, "内在价值高于当前价，存在低估\n")
 	} else {
 		fmt.Fprintf(out, "内在价值低于当前价，当前价已反映或高估\n")
@@ -578,6 +615,121 @@ func printDCFMethod(cmd *cobra.Command, m *Metrics,
 	fmt.Fprintf(out, "注意: DCF 对参数高度敏感，可通过 --growth-rate/--wacc/--terminal-growth 调整\n\n")
 }
 
+// DCF holds the parameters and result of a discounted cash flow valuation.
+// Rates are percentages.
+type DCF struct {
+	FCF             float64 `json:"fcf"` // 当前自由现金流，近似为净利润 × 70%
+	Years           int     `json:"years"`
+	GrowthRate      float64 `json:"growth_rate"`
+	TerminalGrowth  float64 `json:"terminal_growth"`
+	WACC            float64 `json:"wacc"`
+	MarginOfSafety  float64 `json:"margin_of_safety"`
+	PV              float64 `json:"pv"`          // 增长期现值
+	PVTerminal      float64 `json:"pv_terminal"` // 终值现值
+	EnterpriseValue float64 `json:"enterprise_value"`
+	ValuePerShare   float64 `json:"value_per_share"` // 每股内在价值
+	FairPrice       float64 `json:"fair_price"`      // 安全边际后合理价
+	Upside          float64 `json:"upside"`          // 相对当前股价的空间 %
+}
+
+// dcfParamsOf returns the DCF parameters from the flags of cmd, leaving the
+// result fields empty.
+func dcfParamsOf(cmd *cobra.Command) DCF {
+	var d DCF
+	d.GrowthRate, _ = cmd.Flags().GetFloat64("growth-rate")
+	d.TerminalGrowth, _ = cmd.Flags().GetFloat64("terminal-growth")
+	d.WACC, _ = cmd.Flags().GetFloat64("wacc")
+	d.MarginOfSafety, _ = cmd.Flags().GetFloat64("margin-of-safety")
+	return d
+}
+
+// computeDCF values m with a 5-year growth stage followed by perpetual
+// growth. A zero growth rate defaults to the net profit CAGR, or 5% without
+// growth, and a zero WACC defaults to 8%.
+func computeDCF(m *Metrics, params DCF) *DCF {
+	d := params
+	if d.GrowthRate <= 0 {
+		d.GrowthRate = m.GrowthRate
+	}
+	if d.GrowthRate <= 0 {
+		d.GrowthRate = 5
+	}
+	if d.WACC <= 0 {
+		d.WACC = 8
+	}
+
+	d.FCF = m.ProfitTTM * 0.7
+	d.Years = 5
+
+	cf := d.FCF
+	for t := 1; t <= d.Years; t++ {
+		cf = cf * (1 + d.GrowthRate/100)
+		d.PV += cf / math.Pow(1+d.WACC/100, float64(t))
+	}
+	terminalCF := cf * (1 + d.TerminalGrowth/100)
+	terminalValue := terminalCF / ((d.WACC - d.TerminalGrowth) / 100)
+	d.PVTerminal = terminalValue / math.Pow(1+d.WACC/100, float64(d.Years))
+
+	d.EnterpriseValue = d.PV + d.PVTerminal
+	d.ValuePerShare = d.EnterpriseValue / m.Shares
+	d.FairPrice = d.ValuePerShare * (1 - d.MarginOfSafety/100)
+	d.Upside = (d.FairPrice/m.Price - 1) * 100
+	return &d
+}
+
+// metricsRecord 估值指标的结构化输出
+type metricsRecord struct {
+	ExCode        string  `json:"excode"`
+	Name          string  `json:"name"`
+	Method        string  `json:"method"`
+	Price         float64 `json:"price"`
+	MktCap        float64 `json:"mktcap"` // 总市值，单位：元
+	Shares        float64 `json:"shares"` // 总股本，由市值和股价推算
+	PE            float64 `json:"pe"`     // 市盈率 TTM
+	PB            float64 `json:"pb"`
+	PS            float64 `json:"ps"`
+	PEG           float64 `json:"peg"`
+	ROE           float64 `json:"roe"` // %
+	EPS           float64 `json:"eps"`
+	BVPS          float64 `json:"bvps"`
+	Graham        float64 `json:"graham"`         // 格雷厄姆数
+	Revenue       float64 `json:"revenue"`        // 最近年报营业收入
+	NetProfit     float64 `json:"net_profit"`     // 最近年报归母净利润
+	Growth        float64 `json:"growth"`         // 净利润年复合增速 %
+	RevenueGrowth float64 `json:"revenue_growth"` // 营收同比增速 %
+	HistPEMin     float64 `json:"hist_pe_min"`
+	HistPEMedian  float64 `json:"hist_pe_median"`
+	HistPEMax     float64 `json:"hist_pe_max"`
+	Assessment    string  `json:"assessment"`    // 偏低区间、合理区间或偏高区间
+	DCF           *DCF    `json:"dcf,omitempty"` // 仅 --method dcf 时输出
+}
+
+func newMetricsRecord(m *Metrics) *metricsRecord {
+	return &metricsRecord{
+		ExCode:        m.Code,
+		Name:          m.Name,
+		Price:         m.Price,
+		MktCap:        m.MktCap,
+		Shares:        m.Shares,
+		PE:            m.PE,
+		PB:            m.PB,
+		PS:            m.PS,
+		PEG:           m.PEG,
+		ROE:           m.ROE,
+		EPS:           m.EPS,
+		BVPS:          m.BVPS,
+		Graham:        m.Graham,
+		Revenue:       m.RevenueTTM,
+		NetProfit:     m.ProfitTTM,
+		Growth:        m.GrowthRate,
+		RevenueGrowth: m.RevGrowth,
+		HistPEMin:     m.HistMin,
+		HistPEMedian:  m.HistMedian,
+		HistPEMax:     m.HistMax,
+		Assessment:    m.Assessment,
+	}
+}
+
 // --- Helpers ---
 
 func richTable(out io.Writer, headers []string, data [][]string) {
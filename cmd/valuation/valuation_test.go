@@ -2,8 +2,10 @@ package valuation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
 	"github.com/stretchr/testify/require"
 )
 
@@ -204,3 +206,54 @@ func TestFpct(t *testing.T) {
 	require.Equal(t, "-", fpct(0))
 	require.Equal(t, "15.2%", fpct(15.2))
 }
+
+func TestComputeRevenueGrowth(t *testing.T) {
+	item := func(revenue float64) *eastmoney.FinancialReportItem {
+		return &eastmoney.FinancialReportItem{Fields: map[string]interface{}{"TOTAL_OPERATE_INCOME": revenue}}
+	}
+	require.Equal(t, 0.0, computeRevenueGrowth(nil))
+	require.Equal(t, 0.0, computeRevenueGrowth([]*eastmoney.FinancialReportItem{item(100)}))
+	require.Equal(t, 0.0, computeRevenueGrowth([]*eastmoney.FinancialReportItem{item(100), item(0)}))
+	require.InDelta(t, 25.0, computeRevenueGrowth([]*eastmoney.FinancialReportItem{item(125), item(100), item(50)}), 1e-9)
+	require.InDelta(t, -20.0, computeRevenueGrowth([]*eastmoney.FinancialReportItem{item(80), item(100)}), 1e-9)
+}
+
+func TestTrailingDPS(t *testing.T) {
+	now := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	dids := []sina.Dividend{
+		{DividendedDate: "2025-07-10", Bonus: 20},
+		{DividendedDate: "2025-01-10", Bonus: 10},
+		{DividendedDate: "2024-07-10", Bonus: 18}, // older than a year
+		{DividendedDate: "--", Bonus: 5},          // not yet paid
+	}
+	require.InDelta(t, 3.0, TrailingDPS(dids, now), 1e-9)
+	require.Zero(t, TrailingDPS(nil, now))
+}
+
+func TestComputeDCF(t *testing.T) {
+	m := &Metrics{Price: 10, Shares: 1e9, ProfitTTM: 1e9, GrowthRate: 0}
+	d := computeDCF(m, DCF{TerminalGrowth: 3, MarginOfSafety: 20})
+	// 默认增长率 5%，WACC 8%
+	require.Equal(t, 5.0, d.GrowthRate)
+	require.Equal(t, 8.0, d.WACC)
+	require.Equal(t, 5, d.Years)
+	require.Equal(t, 7e8, d.FCF)
+	require.InDelta(t, d.PV+d.PVTerminal, d.EnterpriseValue, 1e-6)
+	require.InDelta(t, d.EnterpriseValue/1e9, d.ValuePerShare, 1e-9)
+	require.InDelta(t, d.ValuePerShare*0.8, d.FairPrice, 1e-9)
+	require.InDelta(t, (d.FairPrice/10-1)*100, d.Upside, 1e-9)
+
+	// 指定增长率和折现率
+	d = computeDCF(&Metrics{Price: 10, Shares: 1e9, ProfitTTM: 1e9, GrowthRate: 12}, DCF{GrowthRate: 6, WACC: 10})
+	require.Equal(t, 6.0, d.GrowthRate)
+	require.Equal(t, 10.0, d.WACC)
+}
+
+func TestNewMetricsRecord(t *testing.T) {
+	m := &Metrics{Code: "SH600036", Name: "招商银行", Price: 40, PE: 6.5, RevenueTTM: 3e11, ProfitTTM: 1.4e11, Assessment: "偏低区间"}
+	rec := newMetricsRecord(m)
+	require.Equal(t, "SH600036", rec.ExCode)
+	require.Equal(t, 3e11, rec.Revenue)
+	require.Equal(t, 1.4e11, rec.NetProfit)
+	require.Nil(t, rec.DCF)
+}
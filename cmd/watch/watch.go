@@ -3,6 +3,7 @@
 // Subcommands:
 //
 //	sec watch              show list with real-time quotes
+//	sec watch -r           keep refreshing quotes until Ctrl+C
 //	sec watch add <code>   add stock(s) to watchlist
 //	sec watch remove <code>  remove stock(s) from watchlist
 package watch
@@ -13,11 +14,15 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/alwqx/sec/output"
+	"github.com/alwqx/sec/provider"
 	"github.com/alwqx/sec/provider/sina"
 	"github.com/alwqx/sec/utils"
 	"github.com/olekukonko/tablewriter"
@@ -60,6 +65,11 @@ func loadWatchlist() ([]WatchItem, error) {
 	return items, nil
 }
 
+// Load returns the saved watchlist, or nil if nothing has been added yet.
+func Load() ([]WatchItem, error) {
+	return loadWatchlist()
+}
+
 func saveWatchlist(items []WatchItem) error {
 	path, err := watchlistPath()
 	if err != nil {
@@ -86,6 +96,8 @@ func NewWatchCLI() *cobra.Command {
 		RunE: runWatchShow,
 	}
 	cmd.Flags().BoolP("debug", "D", false, "Enable debug mode")
+	cmd.Flags().BoolP("realtime", "r", false, "Keep refreshing quotes")
+	output.Enable(cmd)
 
 	cmd.AddCommand(
 		&cobra.Command{
@@ -116,26 +128,40 @@ type quoteRow struct {
 }
 
 func runWatchShow(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	realtime, _ := cmd.Flags().GetBool("realtime")
+	f := output.FormatOf(cmd)
+	if realtime && f.Structured() {
+		return fmt.Errorf("--realtime only supports table output, got --output %s", f)
+	}
+
 	items, err := loadWatchlist()
 	if err != nil {
 		return fmt.Errorf("读取自选列表失败: %w", err)
 	}
 	if len(items) == 0 {
+		if f.Structured() {
+			return output.Write(cmd.OutOrStdout(), f, []watchRecord{})
+		}
 		fmt.Fprintf(cmd.OutOrStdout(), "自选列表为空。使用 sec watch add <代码> 添加股票\n")
 		return nil
 	}
 
-	rows := make([]quoteRow, 0, len(items))
-	// Query in batches via multi-search quote pattern
-	exCodes := make([]string, len(items))
-	for i, item := range items {
-		exCodes[i] = item.ExCode
+	if realtime {
+		return watchRealtime(cmd, src, items)
 	}
+
+	// Query in batches via multi-search quote pattern
+	exCodes := itemCodes(items)
 	slog.DebugContext(cmd.Context(), "runWatchShow", "items", strings.Join(exCodes, ","))
 
 	quoteMap := make(map[string]*sina.SecurityQuote)
 	if len(exCodes) > 0 {
-		qlist, err := sina.QueryQuoteList(cmd.Context(), exCodes)
+		qlist, err := src.Quote.QueryQuoteList(cmd.Context(), exCodes)
 		if err != nil {
 			slog.Warn("获取行情失败", "error", err)
 		}
@@ -144,6 +170,58 @@ func runWatchShow(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Display
+	rows := buildRows(items, quoteMap)
+	if f.Structured() {
+		return output.Write(cmd.OutOrStdout(), f, watchRecords(rows))
+	}
+	printWatchQuotes(cmd.OutOrStdout(), rows)
+	return nil
+}
+
+// watchRecord 自选行情的结构化输出，没有行情时价格为 0
+type watchRecord struct {
+	Code       string  `json:"code"`
+	ExCode     string  `json:"excode"`
+	Name       string  `json:"name"`
+	Price      float64 `json:"price"`
+	Change     float64 `json:"change"`
+	ChangeRate float64 `json:"change_rate"` // 涨跌幅 %
+	High       float64 `json:"high"`
+	Low        float64 `json:"low"`
+	Amount     float64 `json:"amount"` // 成交额，单位：元
+	AddedAt    string  `json:"added_at"`
+}
+
+func watchRecords(rows []quoteRow) []watchRecord {
+	res := make([]watchRecord, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, watchRecord{
+			Code:       r.item.Code,
+			ExCode:     r.item.ExCode,
+			Name:       r.item.Name,
+			Price:      r.price,
+			Change:     r.chg,
+			ChangeRate: r.chgPct,
+			High:       r.high,
+			Low:        r.low,
+			Amount:     r.vol,
+			AddedAt:    r.item.AddedAt,
+		})
+	}
+	return res
+}
+
+func itemCodes(items []WatchItem) []string {
+	exCodes := make([]string, len(items))
+	for i, item := range items {
+		exCodes[i] = item.ExCode
+	}
+	return exCodes
+}
+
+func buildRows(items []WatchItem, quoteMap map[string]*sina.SecurityQuote) []quoteRow {
+	rows := make([]quoteRow, 0, len(items))
 	for _, item := range items {
 		r := quoteRow{item: item}
 		if q, ok := quoteMap[item.ExCode]; ok {
@@ -158,10 +236,75 @@ func runWatchShow(cmd *cobra.Command, args []string) error {
 		}
 		rows = append(rows, r)
 	}
+	return rows
+}
 
-	// Display
-	printWatchQuotes(cmd.OutOrStdout(), rows)
-	return nil
+// watchRealtime 持续刷新自选行情，优先使用行情推送，否则每 3 秒轮询。
+// 自选列表每 10 秒重新读取一次，其他终端的 add/remove 会自动生效。
+func watchRealtime(cmd *cobra.Command, src *provider.Source, items []WatchItem) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	quoteMap := make(map[string]*sina.SecurityQuote, len(items))
+	poll := func() {
+		qlist, err := src.Quote.QueryQuoteList(ctx, itemCodes(items))
+		if err != nil {
+			slog.WarnContext(ctx, "获取行情失败", "error", err)
+			return
+		}
+		for _, q := range qlist {
+			quoteMap[q.ExCode] = q
+		}
+	}
+	poll()
+
+	var quotes <-chan *sina.SecurityQuote
+	var stream *sina.Stream
+	if src.Stream != nil {
+		stream = src.Stream.QuoteStream(ctx, itemCodes(items))
+		defer stream.Close()
+		quotes = stream.Quotes()
+	}
+
+	redraw := time.NewTicker(time.Second)
+	defer redraw.Stop()
+	reload := time.NewTicker(10 * time.Second)
+	defer reload.Stop()
+	var lastPoll time.Time
+	dirty := true
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case q, ok := <-quotes:
+			if !ok {
+				return nil
+			}
+			quoteMap[q.ExCode] = q
+			dirty = true
+		case <-reload.C:
+			latest, err := loadWatchlist()
+			if err != nil {
+				slog.WarnContext(ctx, "读取自选列表失败", "error", err)
+				continue
+			}
+			if stream != nil {
+				stream.Set(itemCodes(latest)...)
+			}
+			items, dirty = latest, true
+		case now := <-redraw.C:
+			if stream == nil && now.Sub(lastPoll) >= 3*time.Second {
+				poll()
+				lastPoll, dirty = now, true
+			}
+			if !dirty {
+				continue
+			}
+			dirty = false
+			utils.ClearTerm()
+			printWatchQuotes(cmd.OutOrStdout(), buildRows(items, quoteMap))
+		}
+	}
 }
 
 func printWatchQuotes(out io.Writer, rows []quoteRow) {
@@ -214,6 +357,11 @@ func printWatchQuotes(out io.Writer, rows []quoteRow) {
 }
 
 func runWatchAdd(cmd *cobra.Command, args []string) error {
+	src, err := provider.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	items, err := loadWatchlist()
 	if err != nil {
 		return fmt.Errorf("读取自选列表失败: %w", err)
@@ -233,7 +381,7 @@ func runWatchAdd(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		secs := sina.Search(cmd.Context(), code)
+		secs := src.Searcher.Search(cmd.Context(), code)
 		if len(secs) == 0 {
 			fmt.Fprintf(cmd.OutOrStdout(), "  %s 未找到\n", code)
 			continue
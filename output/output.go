@@ -0,0 +1,118 @@
+// Package output writes command results in the format chosen by the global
+// --output flag: the default human-readable table, or JSON, CSV, TSV and
+// Markdown for scripts.
+//
+// Commands keep printing their own tablewriter tables for the table format.
+// For the structured formats they hand their records to Write, which uses the
+// json tags of the record struct as stable English keys and column names:
+//
+//	if f := output.FormatOf(cmd); f.Structured() {
+//		return output.Write(cmd.OutOrStdout(), f, records)
+//	}
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Format 输出格式
+type Format string
+
+const (
+	FormatTable    Format = "table"    // 中文表头表格，默认
+	FormatJSON     Format = "json"     // JSON 数组或对象
+	FormatCSV      Format = "csv"      // 逗号分隔，首行为列名
+	FormatTSV      Format = "tsv"      // 制表符分隔，首行为列名
+	FormatMarkdown Format = "markdown" // Markdown 表格
+)
+
+// Formats 支持的输出格式
+var Formats = []Format{FormatTable, FormatJSON, FormatCSV, FormatTSV, FormatMarkdown}
+
+// FlagName 全局输出格式 flag 名称
+const FlagName = "output"
+
+// annotation 标记命令支持结构化输出的 cobra annotation
+const annotation = "sec/output"
+
+// ParseFormat parses a format name, case-insensitively. "md" is accepted for
+// markdown.
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "md" {
+		return FormatMarkdown, nil
+	}
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid output format %q, want one of %s", s, formatNames())
+}
+
+// Structured reports whether f is a machine-readable format.
+func (f Format) Structured() bool {
+	return f != FormatTable && f != ""
+}
+
+func formatNames() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// AddFlag adds the persistent --output flag to the root command.
+func AddFlag(root *cobra.Command) {
+	root.PersistentFlags().String(FlagName, string(FormatTable), "Output format: "+formatNames())
+}
+
+// Enable marks cmds as supporting the structured output formats.
+func Enable(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = make(map[string]string)
+		}
+		cmd.Annotations[annotation] = "true"
+	}
+}
+
+// Enabled reports whether cmd supports the structured output formats.
+func Enabled(cmd *cobra.Command) bool {
+	return cmd.Annotations[annotation] == "true"
+}
+
+// Check validates the --output flag of cmd, rejecting a structured format on
+// a command that only prints tables. It is called before every command runs.
+func Check(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup(FlagName)
+	if flag == nil {
+		return nil
+	}
+	f, err := ParseFormat(flag.Value.String())
+	if err != nil {
+		return err
+	}
+	if f.Structured() && !Enabled(cmd) {
+		return fmt.Errorf("%s does not support --output %s", cmd.CommandPath(), f)
+	}
+	return nil
+}
+
+// FormatOf returns the output format chosen for cmd, FormatTable when the
+// flag is absent or invalid.
+func FormatOf(cmd *cobra.Command) Format {
+	flag := cmd.Flags().Lookup(FlagName)
+	if flag == nil {
+		return FormatTable
+	}
+	f, err := ParseFormat(flag.Value.String())
+	if err != nil {
+		return FormatTable
+	}
+	return f
+}
@@ -0,0 +1,221 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type testRecord struct {
+	Code   string   `json:"code"`
+	Name   string   `json:"name"`
+	Price  float64  `json:"price"`
+	Change *float64 `json:"change"`
+	Tags   []string `json:"tags,omitempty"`
+	Hidden string   `json:"-"`
+	secret string
+}
+
+func testRecords() []*testRecord {
+	change := -0.5
+	return []*testRecord{
+		{Code: "SH600036", Name: "招商银行", Price: 39.12, Change: &change, Tags: []string{"银行", "沪深300"}},
+		{Code: "HK00700", Name: "腾讯|控股", Price: 500, Hidden: "x", secret: "y"},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for s, want := range map[string]Format{
+		"table": FormatTable, "JSON": FormatJSON, " csv ": FormatCSV,
+		"tsv": FormatTSV, "markdown": FormatMarkdown, "md": FormatMarkdown,
+	} {
+		f, err := ParseFormat(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, f)
+	}
+	_, err := ParseFormat("xml")
+	require.ErrorContains(t, err, `invalid output format "xml"`)
+
+	require.False(t, FormatTable.Structured())
+	require.True(t, FormatJSON.Structured())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJSON, testRecords()))
+	require.Equal(t, `[
+  {
+    "code": "SH600036",
+    "name": "招商银行",
+    "price": 39.12,
+    "change": -0.5,
+    "tags": [
+      "银行",
+      "沪深300"
+    ]
+  },
+  {
+    "code": "HK00700",
+    "name": "腾讯|控股",
+    "price": 500,
+    "change": null
+  }
+]
+`, buf.String())
+
+	// 空结果输出 []
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatJSON, []*testRecord(nil)))
+	require.Equal(t, "[]\n", buf.String())
+
+	// 单个对象
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatJSON, testRecord{Code: "a&b"}))
+	require.Contains(t, buf.String(), `"code": "a&b"`)
+}
+
+func TestWriteDelimited(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, testRecords()))
+	require.Equal(t, "code,name,price,change,tags\n"+
+		`SH600036,招商银行,39.12,-0.5,"[""银行"",""沪深300""]"`+"\n"+
+		"HK00700,腾讯|控股,500,,null\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatTSV, testRecords()[1:]))
+	require.Equal(t, "code\tname\tprice\tchange\ttags\nHK00700\t腾讯|控股\t500\t\tnull\n", buf.String())
+
+	// 空结果只有列名
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatCSV, []testRecord{}))
+	require.Equal(t, "code,name,price,change,tags\n", buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatMarkdown, testRecords()[1:]))
+	require.Equal(t, "| code | name | price | change | tags |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		`| HK00700 | 腾讯\|控股 | 500 |  | null |`+"\n", buf.String())
+}
+
+func TestWriteTable(t *testing.T) {
+	table := &Table{
+		Columns: []string{"date", "rsi", "signal"},
+		Rows: [][]any{
+			{"2026-01-05", nil, ""},
+			{"2026-01-06", 71.5, "sell"},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJSON, table))
+	require.Equal(t, `[
+  {
+    "date": "2026-01-05",
+    "rsi": null,
+    "signal": ""
+  },
+  {
+    "date": "2026-01-06",
+    "rsi": 71.5,
+    "signal": "sell"
+  }
+]
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatCSV, table))
+	require.Equal(t, "date,rsi,signal\n2026-01-05,,\n2026-01-06,71.5,sell\n", buf.String())
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "out.json")
+	require.NoError(t, WriteFile(path, testRecords()))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), "[\n  {\n    \"code\": \"SH600036\""))
+
+	path = filepath.Join(dir, "out.CSV")
+	require.NoError(t, WriteFile(path, testRecords()))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), "\uFEFFcode,name,price,change,tags\n"))
+
+	require.ErrorContains(t, WriteFile(filepath.Join(dir, "out.xlsx"), testRecords()), "unsupported export file")
+}
+
+func TestNum(t *testing.T) {
+	require.Nil(t, Num(math.NaN()))
+	require.Nil(t, Num(math.Inf(1)))
+	require.Equal(t, 0.0, *Num(0))
+	require.Nil(t, NonZero(0))
+	require.Equal(t, -1.5, *NonZero(-1.5))
+}
+
+func TestCell(t *testing.T) {
+	require.Equal(t, "0.1", cell(0.1))
+	require.Equal(t, "12345678.9", cell(12345678.9))
+	require.Equal(t, "", cell(nil))
+	require.Equal(t, "true", cell(true))
+	require.Equal(t, "2026-01-05T00:00:00Z", cell(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)))
+}
+
+func newTestCLI() (*cobra.Command, *cobra.Command) {
+	root := &cobra.Command{
+		Use:               "sec",
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return Check(cmd) },
+	}
+	AddFlag(root)
+	sub := &cobra.Command{Use: "quote", RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Annotations["got"] = string(FormatOf(cmd))
+		return nil
+	}}
+	Enable(sub)
+	plain := &cobra.Command{Use: "plain", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	root.AddCommand(sub, plain)
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	return root, sub
+}
+
+func TestCheck(t *testing.T) {
+	for args, want := range map[string]string{
+		"quote":                   "table",
+		"quote --output json":     "json",
+		"quote --output=md":       "markdown",
+		"--output csv quote":      "csv",
+		"plain --output table":    "",
+		"plain":                   "",
+		"quote --output xml":      `invalid output format "xml"`,
+		"plain --output json":     "sec plain does not support --output json",
+		"plain --output=MARKDOWN": "sec plain does not support --output markdown",
+	} {
+		root, sub := newTestCLI()
+		root.SetArgs(strings.Fields(args))
+		err := root.Execute()
+		switch want {
+		case "", "table", "json", "markdown", "csv":
+			require.NoError(t, err, args)
+			if want != "" {
+				require.Equal(t, want, sub.Annotations["got"], args)
+			}
+		default:
+			require.ErrorContains(t, err, want, args)
+		}
+	}
+
+	// 没有全局 flag 的独立命令默认表格
+	require.Equal(t, FormatTable, FormatOf(&cobra.Command{}))
+}
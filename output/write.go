@@ -0,0 +1,333 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Table 列在运行时确定的结果，如策略指标列随策略变化
+type Table struct {
+	Columns []string // 英文列名，也是 JSON 的键
+	Rows    [][]any  // 每行与 Columns 一一对应，nil 表示缺失
+}
+
+// Write writes v in format f. v is a slice of structs or struct pointers, a
+// single struct written as one JSON object or one row, or a *Table. Columns
+// are the json tag names of the exported fields, in declaration order.
+//
+// In CSV, TSV and Markdown, numbers are written in full precision, nil
+// pointers as empty cells, and slices, maps and nested structs as JSON.
+func Write(w io.Writer, f Format, v any) error {
+	if f == FormatJSON {
+		return writeJSON(w, v)
+	}
+	t, err := toTable(v)
+	if err != nil {
+		return err
+	}
+	switch f {
+	case FormatCSV:
+		return writeDelimited(w, t, ',')
+	case FormatTSV:
+		return writeDelimited(w, t, '\t')
+	case FormatMarkdown:
+		return writeMarkdown(w, t)
+	}
+	return fmt.Errorf("output format %q is not structured", f)
+}
+
+// fileFormats 导出文件扩展名对应的格式
+var fileFormats = map[string]Format{
+	".json": FormatJSON,
+	".csv":  FormatCSV,
+	".tsv":  FormatTSV,
+	".md":   FormatMarkdown,
+}
+
+// WriteFile writes v to the file path in the format given by its extension:
+// .json, .csv, .tsv or .md. CSV files start with a UTF-8 BOM so that Excel
+// detects the encoding.
+func WriteFile(path string, v any) error {
+	f, ok := fileFormats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return fmt.Errorf("unsupported export file %s, want .json, .csv, .tsv or .md", path)
+	}
+	var buf bytes.Buffer
+	if f == FormatCSV {
+		buf.WriteString("\uFEFF")
+	}
+	if err := Write(&buf, f, v); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Num returns a pointer to v for an optional number of a record, nil when v
+// is NaN or infinite.
+func Num(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+// NonZero is like Num but also treats 0 as missing, for metrics where 0 means
+// the value is unknown.
+func NonZero(v float64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	return Num(v)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	if t, ok := v.(*Table); ok {
+		rows := make([]orderedRow, len(t.Rows))
+		for i, row := range t.Rows {
+			rows[i] = orderedRow{columns: t.Columns, values: row}
+		}
+		v = rows
+	}
+	// nil 切片输出 [] 而不是 null，方便脚本直接遍历
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.IsNil() {
+		v = []any{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// orderedRow 按列顺序输出键的 JSON 对象
+type orderedRow struct {
+	columns []string
+	values  []any
+}
+
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, col := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(col)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		var v any
+		if i < len(r.values) {
+			v = r.values[i]
+		}
+		if fv, ok := v.(float64); ok && (math.IsNaN(fv) || math.IsInf(fv, 0)) {
+			v = nil
+		}
+		val, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toTable flattens a struct, a slice of structs or a *Table into a Table.
+func toTable(v any) (*Table, error) {
+	if t, ok := v.(*Table); ok {
+		return t, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("output: nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		fields := structFields(rv.Type())
+		return &Table{Columns: fieldColumns(fields), Rows: [][]any{structRow(rv, fields)}}, nil
+	case reflect.Slice, reflect.Array:
+		elem := rv.Type().Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("output: unsupported element type %s", elem)
+		}
+		fields := structFields(elem)
+		t := &Table{Columns: fieldColumns(fields), Rows: make([][]any, 0, rv.Len())}
+		for i := 0; i < rv.Len(); i++ {
+			item := rv.Index(i)
+			for item.Kind() == reflect.Pointer {
+				if item.IsNil() {
+					break
+				}
+				item = item.Elem()
+			}
+			if item.Kind() != reflect.Struct {
+				continue
+			}
+			t.Rows = append(t.Rows, structRow(item, fields))
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("output: unsupported type %T", v)
+}
+
+// field 结构体导出字段及其列名
+type field struct {
+	index int
+	name  string
+}
+
+func structFields(t reflect.Type) []field {
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fields = append(fields, field{index: i, name: name})
+	}
+	return fields
+}
+
+func fieldColumns(fields []field) []string {
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		cols[i] = f.name
+	}
+	return cols
+}
+
+func structRow(rv reflect.Value, fields []field) []any {
+	row := make([]any, len(fields))
+	for i, f := range fields {
+		row[i] = rv.Field(f.index).Interface()
+	}
+	return row
+}
+
+// cell formats a value for a CSV, TSV or Markdown cell.
+func cell(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		fv := rv.Float()
+		if math.IsNaN(fv) || math.IsInf(fv, 0) {
+			return ""
+		}
+		return strconv.FormatFloat(fv, 'f', -1, 64)
+	}
+
+	data, err := json.Marshal(rv.Interface())
+	if err != nil {
+		return fmt.Sprint(rv.Interface())
+	}
+	// time.Time 等编码为 JSON 字符串的值去掉引号
+	if len(data) > 0 && data[0] == '"' {
+		if s, err := strconv.Unquote(string(data)); err == nil {
+			return s
+		}
+	}
+	return string(data)
+}
+
+func writeDelimited(w io.Writer, t *Table, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		record := make([]string, len(t.Columns))
+		for i := range record {
+			if i < len(row) {
+				record[i] = cell(row[i])
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func writeMarkdown(w io.Writer, t *Table) error {
+	var sb strings.Builder
+	writeLine := func(cells []string) {
+		sb.WriteString("|")
+		for _, c := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(markdownEscaper.Replace(c))
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeLine(t.Columns)
+	sep := make([]string, len(t.Columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeLine(sep)
+	for _, row := range t.Rows {
+		cells := make([]string, len(t.Columns))
+		for i := range cells {
+			if i < len(row) {
+				cells[i] = cell(row[i])
+			}
+		}
+		writeLine(cells)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
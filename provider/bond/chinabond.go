@@ -0,0 +1,155 @@
+package bond
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/alwqx/sec/utils"
+)
+
+// 中国国债收益率曲线：中债估值中心 yield.chinabond.com.cn 的历史查询页面，返回 HTML 表格，
+// 包含 3 月至 30 年的关键期限。单次查询跨度不超过一年。参考 AKShare bond_china_yield。
+
+var (
+	chinaBondURL = "https://yield.chinabond.com.cn/cbweb-pbc-web/pbc/historyQuery"
+
+	// chinaBondCurveName 表格中国债收益率曲线的名称，同一页面还包含企业债等曲线
+	chinaBondCurveName = "中债国债收益率曲线"
+)
+
+// queryChinaBond fetches the ChinaBond government yield curves between start
+// and end (2006-01-02), one request per year, in ascending order of date.
+func queryChinaBond(ctx context.Context, startStr, endStr string) ([]*YieldCurve, error) {
+	start, err := time.Parse(utils.LayoutYYMMDD, startStr)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(utils.LayoutYYMMDD, endStr)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[time.Time]*YieldCurve)
+	for from := start; !from.After(end); {
+		to := from.AddDate(1, 0, -1)
+		if to.After(end) {
+			to = end
+		}
+		curves, err := fetchChinaBond(ctx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("chinabond %s~%s: %w", from.Format(utils.LayoutYYMMDD), to.Format(utils.LayoutYYMMDD), err)
+		}
+		for _, c := range curves {
+			byDate[c.Date] = c
+		}
+		from = to.AddDate(0, 0, 1)
+	}
+	if len(byDate) == 0 {
+		return nil, fmt.Errorf("no chinabond yield data between %s and %s", startStr, endStr)
+	}
+
+	res := make([]*YieldCurve, 0, len(byDate))
+	for _, c := range byDate {
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Date.Before(res[j].Date) })
+	return res, nil
+}
+
+func fetchChinaBond(ctx context.Context, from, to time.Time) ([]*YieldCurve, error) {
+	v := url.Values{}
+	v.Set("startDate", from.Format(utils.LayoutYYMMDD))
+	v.Set("endDate", to.Format(utils.LayoutYYMMDD))
+	v.Set("gjqx", "0")
+	v.Set("qxId", "ycqx")
+	v.Set("locale", "cn_ZH")
+	reqURL := chinaBondURL + "?" + v.Encode()
+	slog.DebugContext(ctx, "fetchChinaBond", "reqURL", reqURL)
+
+	headers := http.Header{}
+	headers.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36")
+	resp, err := utils.MakeRequest(ctx, http.MethodGet, reqURL, headers, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseChinaBond(body)
+}
+
+// parseChinaBond parses the history table, whose header row is like
+// 曲线名称 | 日期 | 3月 | 6月 | 1年 | 3年 | 5年 | 7年 | 10年 | 30年, keeping the rows
+// of the government bond curve.
+func parseChinaBond(data []byte) ([]*YieldCurve, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		dateCol = -1
+		tenors  map[int]Tenor // 列号 → 期限
+		res     []*YieldCurve
+	)
+	doc.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		var cells []string
+		tr.Find("th,td").Each(func(_ int, td *goquery.Selection) {
+			cells = append(cells, strings.TrimSpace(strings.ReplaceAll(td.Text(), " ", "")))
+		})
+		if len(cells) == 0 {
+			return
+		}
+		if tenors == nil {
+			for i, cell := range cells {
+				if cell == "日期" {
+					dateCol = i
+				}
+			}
+			if dateCol < 0 {
+				return
+			}
+			tenors = make(map[int]Tenor)
+			for i, cell := range cells {
+				if t, err := ParseTenor(cell); err == nil {
+					tenors[i] = t
+				}
+			}
+			return
+		}
+		if cells[0] != chinaBondCurveName || dateCol >= len(cells) {
+			return
+		}
+		date, err := time.Parse(utils.LayoutYYMMDD, cells[dateCol])
+		if err != nil {
+			slog.Warn("failed parse chinabond date", "date", cells[dateCol], "error", err)
+			return
+		}
+		c := &YieldCurve{Date: date, Yields: make(map[Tenor]float64, len(tenors))}
+		for i, t := range tenors {
+			if i >= len(cells) {
+				continue
+			}
+			if v, err := strconv.ParseFloat(cells[i], 64); err == nil {
+				c.Yields[t] = v
+			}
+		}
+		res = append(res, c)
+	})
+	return res, nil
+}
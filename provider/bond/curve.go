@@ -0,0 +1,189 @@
+package bond
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Country 国债收益率曲线所属国家
+type Country string
+
+const (
+	CountryUS Country = "us" // 美国国债，美国财政部
+	CountryCN Country = "cn" // 中国国债，中债估值中心
+)
+
+// ParseCountry parses "us" or "cn".
+func ParseCountry(s string) (Country, error) {
+	switch c := Country(strings.ToLower(strings.TrimSpace(s))); c {
+	case CountryUS, CountryCN:
+		return c, nil
+	}
+	return "", fmt.Errorf("invalid country %q: expected cn or us", s)
+}
+
+// Label returns the Chinese name of the country's government bond.
+func (c Country) Label() string {
+	switch c {
+	case CountryCN:
+		return "中国国债"
+	case CountryUS:
+		return "美国国债"
+	}
+	return string(c)
+}
+
+// Tenor 期限，单位月
+type Tenor int
+
+// 常用期限
+const (
+	Tenor1M  Tenor = 1
+	Tenor3M  Tenor = 3
+	Tenor6M  Tenor = 6
+	Tenor1Y  Tenor = 12
+	Tenor2Y  Tenor = 24
+	Tenor3Y  Tenor = 36
+	Tenor5Y  Tenor = 60
+	Tenor7Y  Tenor = 84
+	Tenor10Y Tenor = 120
+	Tenor20Y Tenor = 240
+	Tenor30Y Tenor = 360
+)
+
+// String returns the tenor as 3M or 10Y.
+func (t Tenor) String() string {
+	if t%12 == 0 {
+		return fmt.Sprintf("%dY", t/12)
+	}
+	return fmt.Sprintf("%dM", t)
+}
+
+// Label returns the Chinese label of the tenor, e.g. 3个月 or 10年.
+func (t Tenor) Label() string {
+	if t%12 == 0 {
+		return fmt.Sprintf("%d年", t/12)
+	}
+	return fmt.Sprintf("%d个月", t)
+}
+
+// ParseTenor parses a tenor such as 3M, 10Y, 3个月 or 10年.
+func ParseTenor(s string) (Tenor, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	for _, u := range []struct {
+		suffix string
+		months int
+	}{{"个月", 1}, {"月", 1}, {"M", 1}, {"年", 12}, {"Y", 12}} {
+		if !strings.HasSuffix(v, u.suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(v, u.suffix))
+		if err != nil || n <= 0 {
+			break
+		}
+		return Tenor(n * u.months), nil
+	}
+	return 0, fmt.Errorf("invalid tenor %q: expected like 3M or 10Y", s)
+}
+
+// ParseTenors parses a comma-separated list of tenors.
+func ParseTenors(s string) ([]Tenor, error) {
+	var res []Tenor
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		t, err := ParseTenor(item)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// YieldCurve 单日国债收益率曲线
+type YieldCurve struct {
+	Date   time.Time
+	Yields map[Tenor]float64 // 收益率，百分比
+}
+
+// Tenors returns the tenors of c in ascending order.
+func (c *YieldCurve) Tenors() []Tenor {
+	res := make([]Tenor, 0, len(c.Yields))
+	for t := range c.Yields {
+		res = append(res, t)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// At returns the yield of tenor t. A tenor missing from c but within its
+// range is linearly interpolated between the nearest tenors, e.g. 2Y from 1Y
+// and 3Y.
+func (c *YieldCurve) At(t Tenor) (float64, bool) {
+	if v, ok := c.Yields[t]; ok {
+		return v, true
+	}
+	tenors := c.Tenors()
+	for i := 1; i < len(tenors); i++ {
+		lo, hi := tenors[i-1], tenors[i]
+		if lo < t && t < hi {
+			w := float64(t-lo) / float64(hi-lo)
+			return c.Yields[lo]*(1-w) + c.Yields[hi]*w, true
+		}
+	}
+	return math.NaN(), false
+}
+
+// Curve converts a US Treasury item to a yield curve. Tenors reported as 0
+// are treated as missing.
+func (item *BondYieldItem) Curve() *YieldCurve {
+	c := &YieldCurve{Date: item.DateTime, Yields: make(map[Tenor]float64, 11)}
+	for t, v := range map[Tenor]float64{
+		Tenor1M: item.BC1Month, Tenor3M: item.BC3Month, Tenor6M: item.BC6Month,
+		Tenor1Y: item.BC1Year, Tenor2Y: item.BC2Year, Tenor3Y: item.BC3Year,
+		Tenor5Y: item.BC5Year, Tenor7Y: item.BC7Year, Tenor10Y: item.BC10Year,
+		Tenor20Y: item.BC20Year, Tenor30Y: item.BC30Year,
+	} {
+		if v != 0 {
+			c.Yields[t] = v
+		}
+	}
+	return c
+}
+
+// QueryCurveReq 请求结构体，日期格式 2006-01-02
+type QueryCurveReq struct {
+	Country    Country
+	Start, End string
+}
+
+// QueryCurves returns the daily government bond yield curves of the country
+// between Start and End, in ascending order of date.
+func QueryCurves(ctx context.Context, req *QueryCurveReq) ([]*YieldCurve, error) {
+	if req == nil {
+		return nil, errors.New("req is nil")
+	}
+	switch req.Country {
+	case CountryUS:
+		resp, err := QueryBond(ctx, &QueryBondReq{Start: req.Start, End: req.End})
+		if err != nil {
+			return nil, err
+		}
+		res := make([]*YieldCurve, 0, len(resp.Data))
+		for _, item := range resp.Data {
+			res = append(res, item.Curve())
+		}
+		return res, nil
+	case CountryCN:
+		return queryChinaBond(ctx, req.Start, req.End)
+	}
+	return nil, fmt.Errorf("unsupported country %q", req.Country)
+}
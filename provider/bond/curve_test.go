@@ -0,0 +1,175 @@
+package bond
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTenor(t *testing.T) {
+	for s, want := range map[string]Tenor{"3M": Tenor3M, "10y": Tenor10Y, "3月": Tenor3M, "6个月": Tenor6M, "30年": Tenor30Y, " 2Y ": Tenor2Y} {
+		got, err := ParseTenor(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, got, s)
+	}
+	for _, s := range []string{"", "Y", "0Y", "10", "3D", "曲线名称"} {
+		_, err := ParseTenor(s)
+		require.Error(t, err, s)
+	}
+	require.Equal(t, "3M", Tenor3M.String())
+	require.Equal(t, "10Y", Tenor10Y.String())
+	require.Equal(t, "6个月", Tenor6M.Label())
+
+	tenors, err := ParseTenors("3M, 2Y,10Y,")
+	require.NoError(t, err)
+	require.Equal(t, []Tenor{Tenor3M, Tenor2Y, Tenor10Y}, tenors)
+}
+
+func TestYieldCurveAt(t *testing.T) {
+	c := &YieldCurve{Yields: map[Tenor]float64{Tenor1Y: 1.4, Tenor3Y: 1.6, Tenor10Y: 1.9}}
+	require.Equal(t, []Tenor{Tenor1Y, Tenor3Y, Tenor10Y}, c.Tenors())
+
+	v, ok := c.At(Tenor3Y)
+	require.True(t, ok)
+	require.Equal(t, 1.6, v)
+
+	// 2Y 由 1Y、3Y 线性插值
+	v, ok = c.At(Tenor2Y)
+	require.True(t, ok)
+	require.InDelta(t, 1.5, v, 1e-9)
+
+	_, ok = c.At(Tenor3M)
+	require.False(t, ok)
+	_, ok = c.At(Tenor30Y)
+	require.False(t, ok)
+
+	item := &BondYieldItem{BC3Month: 3.7, BC10Year: 4.4}
+	require.Equal(t, []Tenor{Tenor3M, Tenor10Y}, item.Curve().Tenors())
+}
+
+const chinaBondPage = `<html><body>
+<table><tr><td>起始日期：</td><td>2026-05-01</td></tr></table>
+<table>
+<tr><td>曲线名称</td><td>日期</td><td>3月</td><td>6月</td><td>1年</td><td>3年</td><td>5年</td><td>7年</td><td>10年</td><td>30年</td></tr>
+<tr><td>中债国债收益率曲线</td><td>%s</td><td>1.38</td><td>1.42</td><td>1.44&nbsp</td><td>1.52</td><td>1.60</td><td>1.70</td><td>1.75</td><td>2.01</td></tr>
+<tr><td>中债中短期票据收益率曲线(AAA)</td><td>%s</td><td>1.60</td><td>1.65</td><td>1.70</td><td>1.85</td><td>1.95</td><td>2.05</td><td>2.15</td><td>-</td></tr>
+</table></body></html>`
+
+func TestQueryChinaBond(t *testing.T) {
+	var windows []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		windows = append(windows, q.Get("startDate")+"~"+q.Get("endDate"))
+		fmt.Fprintf(w, chinaBondPage, q.Get("endDate"), q.Get("endDate"))
+	}))
+	defer srv.Close()
+	origURL := chinaBondURL
+	chinaBondURL = srv.URL
+	defer func() { chinaBondURL = origURL }()
+
+	curves, err := QueryCurves(context.Background(), &QueryCurveReq{Country: CountryCN, Start: "2024-06-01", End: "2026-05-08"})
+	require.NoError(t, err)
+	// 单次查询不超过一年
+	require.Equal(t, []string{"2024-06-01~2025-05-31", "2025-06-01~2026-05-08"}, windows)
+	require.Len(t, curves, 2)
+	require.Equal(t, "2025-05-31", curves[0].Date.Format(utils.LayoutYYMMDD))
+	c := curves[1]
+	require.Equal(t, []Tenor{Tenor3M, Tenor6M, Tenor1Y, Tenor3Y, Tenor5Y, Tenor7Y, Tenor10Y, Tenor30Y}, c.Tenors())
+	require.Equal(t, 1.44, c.Yields[Tenor1Y])
+	require.Equal(t, 1.75, c.Yields[Tenor10Y])
+}
+
+func TestSpreads(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 5, d, 0, 0, 0, 0, time.UTC) }
+	cn := []*YieldCurve{
+		{Date: day(6), Yields: map[Tenor]float64{Tenor3M: 1.4, Tenor1Y: 1.4, Tenor3Y: 1.6, Tenor10Y: 1.8}},
+		{Date: day(7), Yields: map[Tenor]float64{Tenor3M: 1.4, Tenor1Y: 1.4, Tenor3Y: 1.6, Tenor10Y: 1.7}},
+	}
+	us := []*YieldCurve{
+		{Date: day(7), Yields: map[Tenor]float64{Tenor3M: 4.5, Tenor2Y: 4.2, Tenor10Y: 4.4}},
+		{Date: day(8), Yields: map[Tenor]float64{Tenor3M: 4.5, Tenor2Y: 4.2, Tenor10Y: 4.3}},
+	}
+
+	points := CountrySpread(cn, us, Tenor10Y)
+	require.Len(t, points, 1)
+	require.Equal(t, day(7), points[0].Date)
+	require.InDelta(t, -270, points[0].Value, 1e-9)
+
+	points = TermSpread(cn, Tenor10Y, Tenor2Y)
+	require.Len(t, points, 2)
+	require.InDelta(t, 30, points[0].Value, 1e-9)
+
+	s := Stats(TermSpread(us, Tenor10Y, Tenor3M))
+	require.InDelta(t, -20, s.Latest, 1e-9)
+	require.InDelta(t, -10, s.Change, 1e-9)
+	require.InDelta(t, -15, s.Mean, 1e-9)
+	require.InDelta(t, 50, s.Percentile, 1e-9)
+	require.True(t, s.Inverted())
+
+	require.Nil(t, Stats(nil))
+}
+
+func TestCurveFactors(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	c := &YieldCurve{Date: day, Yields: map[Tenor]float64{Tenor3M: 1.0, Tenor1Y: 1.2, Tenor3Y: 1.6, Tenor10Y: 2.0}}
+	f, ok := CurveFactors(c)
+	require.True(t, ok)
+	require.Equal(t, day, f.Date)
+	// 2Y 由 1Y 和 3Y 插值为 1.4
+	require.InDelta(t, (1.0+1.4+2.0)/3, f.Level, 1e-9)
+	require.InDelta(t, 100, f.Slope, 1e-9)
+	require.InDelta(t, -20, f.Curvature, 1e-9)
+
+	_, ok = CurveFactors(&YieldCurve{Yields: map[Tenor]float64{Tenor1Y: 1.2, Tenor10Y: 2.0}})
+	require.False(t, ok)
+
+	curves := []*YieldCurve{{Date: day.AddDate(0, 0, -7)}, {Date: day.AddDate(0, 0, -3)}, c}
+	require.Equal(t, curves[1], CurveOn(curves, day.AddDate(0, 0, -1)))
+	require.Equal(t, c, CurveOn(curves, day))
+	require.Nil(t, CurveOn(curves, day.AddDate(0, 0, -8)))
+}
+
+func TestParseLookback(t *testing.T) {
+	lbs, err := ParseLookbacks("1w, 1M,1y,10d,")
+	require.NoError(t, err)
+	require.Equal(t, []Lookback{{1, 'w'}, {1, 'm'}, {1, 'y'}, {10, 'd'}}, lbs)
+
+	day := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, "2026-03-24", lbs[0].Before(day).Format(utils.LayoutYYMMDD))
+	require.Equal(t, "2026-03-03", lbs[1].Before(day).Format(utils.LayoutYYMMDD))
+	require.Equal(t, "2025-03-31", lbs[2].Before(day).Format(utils.LayoutYYMMDD))
+	require.Equal(t, "1周前", lbs[0].Label())
+	require.Equal(t, "10日前", lbs[3].Label())
+
+	for _, s := range []string{"", "w", "0m", "-1y", "1q", "1.5y"} {
+		_, err := ParseLookback(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestShapeChange(t *testing.T) {
+	for _, tc := range []struct {
+		level, slope float64
+		want         Shape
+	}{
+		{-10, 20, ShapeBullSteepening},
+		{10, 20, ShapeBearSteepening},
+		{-10, -20, ShapeBullFlattening},
+		{10, -20, ShapeBearFlattening},
+		{10, 3, ShapeStable},
+	} {
+		from := &Factors{Level: 2, Slope: 50, Curvature: 10}
+		to := &Factors{Level: 2 + tc.level/100, Slope: 50 + tc.slope, Curvature: 5}
+		s := CompareFactors(from, to)
+		require.InDelta(t, tc.level, s.Level, 1e-9)
+		require.InDelta(t, tc.slope, s.Slope, 1e-9)
+		require.InDelta(t, -5, s.Curvature, 1e-9)
+		require.Equal(t, tc.want, s.Shape(), "%+v", tc)
+	}
+}
@@ -0,0 +1,167 @@
+package bond
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 收益率曲线形态因子：水平、斜率、曲率，使用 3 月、2 年、10 年三个期限，
+// 缺失的期限按相邻期限线性插值（如中债 2 年）。
+//
+//	水平 level     = (3M + 2Y + 10Y) / 3
+//	斜率 slope     = 10Y - 3M
+//	曲率 curvature = 2*2Y - 3M - 10Y
+
+// Factors 单日曲线形态因子
+type Factors struct {
+	Date      time.Time
+	Level     float64 // 水平，百分比
+	Slope     float64 // 斜率，bp
+	Curvature float64 // 曲率，bp，正值为中段隆起
+}
+
+// CurveFactors computes the level, slope and curvature of c. It reports false
+// when c does not cover 3M to 10Y.
+func CurveFactors(c *YieldCurve) (*Factors, bool) {
+	short, ok1 := c.At(Tenor3M)
+	mid, ok2 := c.At(Tenor2Y)
+	long, ok3 := c.At(Tenor10Y)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, false
+	}
+	return &Factors{
+		Date:      c.Date,
+		Level:     (short + mid + long) / 3,
+		Slope:     (long - short) * 100,
+		Curvature: (2*mid - short - long) * 100,
+	}, true
+}
+
+// CurveOn returns the latest curve dated on or before date, or nil. curves
+// must be in ascending order of date.
+func CurveOn(curves []*YieldCurve, date time.Time) *YieldCurve {
+	for i := len(curves) - 1; i >= 0; i-- {
+		if !curves[i].Date.After(date) {
+			return curves[i]
+		}
+	}
+	return nil
+}
+
+// Lookback 回看区间，如 1w、1m、1y、10d
+type Lookback struct {
+	N    int
+	Unit byte // d/w/m/y
+}
+
+// ParseLookback parses a lookback like 10d, 1w, 3m or 1y.
+func ParseLookback(s string) (Lookback, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return Lookback{}, fmt.Errorf("invalid lookback %q, want e.g. 1w, 1m or 1y", s)
+	}
+	unit := s[len(s)-1]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 || !strings.ContainsRune("dwmy", rune(unit)) {
+		return Lookback{}, fmt.Errorf("invalid lookback %q, want e.g. 1w, 1m or 1y", s)
+	}
+	return Lookback{N: n, Unit: unit}, nil
+}
+
+// ParseLookbacks parses comma-separated lookbacks, skipping empty items.
+func ParseLookbacks(s string) ([]Lookback, error) {
+	var res []Lookback
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		lb, err := ParseLookback(item)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, lb)
+	}
+	return res, nil
+}
+
+// Before returns the date lb before t.
+func (lb Lookback) Before(t time.Time) time.Time {
+	switch lb.Unit {
+	case 'd':
+		return t.AddDate(0, 0, -lb.N)
+	case 'w':
+		return t.AddDate(0, 0, -7*lb.N)
+	case 'm':
+		return t.AddDate(0, -lb.N, 0)
+	}
+	return t.AddDate(-lb.N, 0, 0)
+}
+
+// Label returns the Chinese label like 1周前.
+func (lb Lookback) Label() string {
+	unit := map[byte]string{'d': "日", 'w': "周", 'm': "月", 'y': "年"}[lb.Unit]
+	return fmt.Sprintf("%d%s前", lb.N, unit)
+}
+
+// Shape 曲线形态变化
+type Shape string
+
+const (
+	ShapeBullSteepening Shape = "牛陡" // 利率下行，曲线变陡
+	ShapeBearSteepening Shape = "熊陡" // 利率上行，曲线变陡
+	ShapeBullFlattening Shape = "牛平" // 利率下行，曲线变平
+	ShapeBearFlattening Shape = "熊平" // 利率上行，曲线变平
+	ShapeStable         Shape = "形态稳定"
+)
+
+// SlopeThreshold 斜率变动超过该值（bp）才认为曲线变陡或变平
+const SlopeThreshold = 5.0
+
+// ShapeChange 区间内曲线形态因子的变化，单位 bp
+type ShapeChange struct {
+	From, To  *Factors
+	Level     float64
+	Slope     float64
+	Curvature float64
+}
+
+// CompareFactors returns the change of the factors from one date to another.
+func CompareFactors(from, to *Factors) *ShapeChange {
+	return &ShapeChange{
+		From:      from,
+		To:        to,
+		Level:     (to.Level - from.Level) * 100,
+		Slope:     to.Slope - from.Slope,
+		Curvature: to.Curvature - from.Curvature,
+	}
+}
+
+// Steepening reports whether the slope rose by more than SlopeThreshold.
+func (s *ShapeChange) Steepening() bool {
+	return s.Slope > SlopeThreshold
+}
+
+// Flattening reports whether the slope fell by more than SlopeThreshold.
+func (s *ShapeChange) Flattening() bool {
+	return s.Slope < -SlopeThreshold
+}
+
+// Shape classifies the change as bull or bear steepening or flattening by
+// the direction of the slope and level, or stable when the slope moved no
+// more than SlopeThreshold.
+func (s *ShapeChange) Shape() Shape {
+	bull := s.Level < 0
+	switch {
+	case s.Steepening() && bull:
+		return ShapeBullSteepening
+	case s.Steepening():
+		return ShapeBearSteepening
+	case s.Flattening() && bull:
+		return ShapeBullFlattening
+	case s.Flattening():
+		return ShapeBearFlattening
+	}
+	return ShapeStable
+}
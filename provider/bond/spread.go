@@ -0,0 +1,90 @@
+package bond
+
+import (
+	"math"
+	"time"
+)
+
+// SpreadPoint 单日利差，单位 bp
+type SpreadPoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// SpreadStats 利差序列的最新值与历史统计，单位 bp
+type SpreadStats struct {
+	Date       time.Time
+	Latest     float64
+	Change     float64 // 较前一日变动
+	Mean       float64
+	Min        float64
+	Max        float64
+	Percentile float64 // 最新值在历史中的分位，0~100
+}
+
+// Inverted reports whether the latest spread is negative, i.e. the long rate
+// is below the short rate.
+func (s *SpreadStats) Inverted() bool {
+	return s.Latest < 0
+}
+
+// TermSpread returns the spread between the long and short tenor of each
+// curve in bp. Missing tenors are interpolated; curves where either tenor is
+// out of range are skipped.
+func TermSpread(curves []*YieldCurve, long, short Tenor) []SpreadPoint {
+	res := make([]SpreadPoint, 0, len(curves))
+	for _, c := range curves {
+		l, ok1 := c.At(long)
+		s, ok2 := c.At(short)
+		if ok1 && ok2 {
+			res = append(res, SpreadPoint{Date: c.Date, Value: (l - s) * 100})
+		}
+	}
+	return res
+}
+
+// CountrySpread returns the spread of tenor t between curves a and b on the
+// dates both have, in bp.
+func CountrySpread(a, b []*YieldCurve, t Tenor) []SpreadPoint {
+	byDate := make(map[time.Time]float64, len(b))
+	for _, c := range b {
+		if v, ok := c.At(t); ok {
+			byDate[c.Date] = v
+		}
+	}
+	res := make([]SpreadPoint, 0, len(a))
+	for _, c := range a {
+		va, ok := c.At(t)
+		vb, found := byDate[c.Date]
+		if ok && found {
+			res = append(res, SpreadPoint{Date: c.Date, Value: (va - vb) * 100})
+		}
+	}
+	return res
+}
+
+// Stats returns the latest value and history statistics of points, which are
+// in ascending order of date, or nil when points is empty.
+func Stats(points []SpreadPoint) *SpreadStats {
+	if len(points) == 0 {
+		return nil
+	}
+	last := points[len(points)-1]
+	s := &SpreadStats{Date: last.Date, Latest: last.Value, Min: math.Inf(1), Max: math.Inf(-1)}
+	if len(points) > 1 {
+		s.Change = last.Value - points[len(points)-2].Value
+	}
+	var sum float64
+	below := 0
+	for _, p := range points {
+		sum += p.Value
+		s.Min = math.Min(s.Min, p.Value)
+		s.Max = math.Max(s.Max, p.Value)
+		if p.Value <= last.Value {
+			below++
+		}
+	}
+	s.Mean = sum / float64(len(points))
+	s.Percentile = float64(below) / float64(len(points)) * 100
+	return s
+}
@@ -0,0 +1,350 @@
+// Package cache provides a persistent on-disk cache for daily K-line history
+// under ~/.sec/cache/kline.
+//
+// Entries are keyed by market, code and FQT. A request only downloads the
+// dates missing from the cached range: the leading gap when begin is earlier
+// than what is cached, and the trailing dates after the last complete day the
+// entry covers. The
+// trailing download always starts from the second to last cached bar; if the
+// adjusted (qfq/hfq) price of that bar changed, an ex-dividend event rebased
+// the series and the whole entry is downloaded again.
+//
+// Only daily bars are cached; requests for other periods go straight to the
+// wrapped source.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/provider"
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/utils"
+)
+
+// priceEpsilon 判断复权价格是否变化的精度
+const priceEpsilon = 1e-6
+
+// Entry 单个证券的缓存内容
+type Entry struct {
+	Code      string               `json:"code"`
+	Market    int                  `json:"market"`
+	FQT       eastmoney.FuQuanType `json:"fqt"`
+	Begin     string               `json:"begin"`      // 已缓存的起始日期 20060102
+	End       string               `json:"end"`        // 已缓存的截止日期 20060102，即最后一次下载请求的截止日期
+	UpdatedAt time.Time            `json:"updated_at"` // 最后一次从数据源刷新的时间
+	Quotes    []*eastmoney.Quote   `json:"quotes"`
+}
+
+// EntryInfo 缓存条目统计信息
+type EntryInfo struct {
+	Path      string
+	Size      int64
+	Code      string
+	Market    eastmoney.MarketType
+	FQT       eastmoney.FuQuanType
+	Bars      int
+	First     string
+	Last      string
+	UpdatedAt time.Time
+}
+
+// History 带本地磁盘缓存的历史行情数据源，实现 provider.HistorySource
+type History struct {
+	Dir    string
+	Source provider.HistorySource
+
+	now func() time.Time
+}
+
+// Dir returns the kline cache directory, creating it if needed.
+func Dir() (string, error) {
+	return utils.SecDir("cache", "kline")
+}
+
+// NewHistory wraps src with the default cache directory. If the directory
+// cannot be created the returned source passes requests through to src.
+func NewHistory(src provider.HistorySource) *History {
+	dir, err := Dir()
+	if err != nil {
+		slog.Warn("kline cache disabled", "error", err)
+	}
+	return &History{Dir: dir, Source: src, now: time.Now}
+}
+
+// GetQuoteHistory 优先读取缓存，只下载缺失的日期
+func (h *History) GetQuoteHistory(ctx context.Context, req *eastmoney.GetQuoteHistoryReq) ([]*eastmoney.Quote, error) {
+	if req == nil {
+		return nil, errors.New("req is nil")
+	}
+	if h.Dir == "" || req.Period != eastmoney.PeriodDay {
+		return h.Source.GetQuoteHistory(ctx, req)
+	}
+
+	begin, err := time.Parse(eastmoney.TimeYYMMDD, req.Begin)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(eastmoney.TimeYYMMDD, req.End)
+	if err != nil {
+		return nil, err
+	}
+
+	path := h.path(req)
+	entry, err := load(path)
+	if err != nil {
+		slog.WarnContext(ctx, "failed load kline cache, refetch", "path", path, "error", err)
+	}
+
+	if entry == nil || len(entry.Quotes) < 2 {
+		entry, err = h.fetchAll(ctx, req, req.Begin)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		entry, err = h.refresh(ctx, req, entry, begin, end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := save(path, entry); err != nil {
+		slog.WarnContext(ctx, "failed save kline cache", "path", path, "error", err)
+	}
+
+	return filterQuotes(entry.Quotes, begin, end), nil
+}
+
+// refresh 补齐缓存中缺失的头部和尾部数据
+func (h *History) refresh(ctx context.Context, req *eastmoney.GetQuoteHistoryReq, entry *Entry, begin, end time.Time) (*Entry, error) {
+	cachedBegin, err := time.Parse(eastmoney.TimeYYMMDD, entry.Begin)
+	if err != nil {
+		return h.fetchAll(ctx, req, req.Begin)
+	}
+
+	// 1. 头部缺失
+	if begin.Before(cachedBegin) {
+		head, err := h.fetch(ctx, req, req.Begin, cachedBegin.AddDate(0, 0, -1).Format(eastmoney.TimeYYMMDD))
+		if err != nil {
+			return nil, err
+		}
+		entry.Quotes = mergeQuotes(head, entry.Quotes)
+		entry.Begin = req.Begin
+	}
+
+	// 2. 尾部缺失
+	if !end.After(coveredEnd(entry)) {
+		return entry, nil
+	}
+
+	n := len(entry.Quotes)
+	anchor := entry.Quotes[n-2]
+	if anchor.Date.After(end) {
+		return entry, nil
+	}
+	tail, err := h.fetch(ctx, req, anchor.Date.Format(eastmoney.TimeYYMMDD), req.End)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FQT != eastmoney.QuoteFQTDefault && rebased(anchor, tail) {
+		slog.DebugContext(ctx, "kline cache invalidated by adjusted price change", "code", req.Code, "date", anchor.Date)
+		return h.fetchAll(ctx, req, entry.Begin)
+	}
+
+	entry.Quotes = mergeQuotes(entry.Quotes, tail)
+	entry.End = req.End
+	entry.UpdatedAt = h.now()
+	return entry, nil
+}
+
+// coveredEnd 返回缓存中数据完整的最后一天：不晚于下载请求的截止日期，
+// 也不晚于最后一次刷新的前一天，刷新当天的行情可能尚未收盘。
+// 旧版本缓存没有记录截止日期，以最后一根 K 线的日期为准。
+func coveredEnd(entry *Entry) time.Time {
+	last := day(entry.UpdatedAt).AddDate(0, 0, -1)
+	end, err := time.Parse(eastmoney.TimeYYMMDD, entry.End)
+	if err != nil && len(entry.Quotes) > 0 {
+		end, err = day(entry.Quotes[len(entry.Quotes)-1].Date), nil
+	}
+	if err == nil && end.Before(last) {
+		return end
+	}
+	return last
+}
+
+// fetchAll 下载从 begin 到 req.End 的全部数据并生成新的缓存条目
+func (h *History) fetchAll(ctx context.Context, req *eastmoney.GetQuoteHistoryReq, begin string) (*Entry, error) {
+	quotes, err := h.fetch(ctx, req, begin, req.End)
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{
+		Code:      req.Code,
+		Market:    req.MarketCode,
+		FQT:       req.FQT,
+		Begin:     begin,
+		End:       req.End,
+		UpdatedAt: h.now(),
+		Quotes:    quotes,
+	}, nil
+}
+
+func (h *History) fetch(ctx context.Context, req *eastmoney.GetQuoteHistoryReq, begin, end string) ([]*eastmoney.Quote, error) {
+	sub := *req
+	sub.Begin = begin
+	sub.End = end
+	slog.DebugContext(ctx, "kline cache fetch", "code", req.Code, "begin", begin, "end", end)
+	return h.Source.GetQuoteHistory(ctx, &sub)
+}
+
+func (h *History) path(req *eastmoney.GetQuoteHistoryReq) string {
+	return filepath.Join(h.Dir, fmt.Sprintf("%d_%s_%d.json", req.MarketCode, req.Code, req.FQT))
+}
+
+// rebased 比较重叠日期的复权收盘价，判断复权序列是否因除权除息而变化
+func rebased(anchor *eastmoney.Quote, tail []*eastmoney.Quote) bool {
+	for _, q := range tail {
+		if q.Date.Equal(anchor.Date) {
+			return math.Abs(q.Close-anchor.Close) > priceEpsilon
+		}
+	}
+	return false
+}
+
+// mergeQuotes 合并两段按日期升序的行情，日期重叠时以 newer 为准
+func mergeQuotes(older, newer []*eastmoney.Quote) []*eastmoney.Quote {
+	if len(newer) == 0 {
+		return older
+	}
+	first := newer[0].Date
+	res := make([]*eastmoney.Quote, 0, len(older)+len(newer))
+	for _, q := range older {
+		if q.Date.Before(first) {
+			res = append(res, q)
+		}
+	}
+	res = append(res, newer...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Date.Before(res[j].Date)
+	})
+	return res
+}
+
+// filterQuotes 返回 [begin, end] 区间内的行情
+func filterQuotes(quotes []*eastmoney.Quote, begin, end time.Time) []*eastmoney.Quote {
+	res := make([]*eastmoney.Quote, 0, len(quotes))
+	for _, q := range quotes {
+		d := day(q.Date)
+		if d.Before(begin) || d.After(end) {
+			continue
+		}
+		res = append(res, q)
+	}
+	return res
+}
+
+// day 返回 t 所在的自然日，与 time.Parse 解析的日期可直接比较
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func load(path string) (*Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func save(path string, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Stats 返回 dir 下全部缓存条目的统计信息
+func Stats(dir string) ([]*EntryInfo, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*EntryInfo, 0, len(paths))
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		entry, err := load(path)
+		if err != nil || entry == nil {
+			slog.Warn("invalid kline cache file", "path", path, "error", err)
+			continue
+		}
+		info := &EntryInfo{
+			Path:      path,
+			Size:      fi.Size(),
+			Code:      entry.Code,
+			Market:    eastmoney.MarketType(entry.Market),
+			FQT:       entry.FQT,
+			Bars:      len(entry.Quotes),
+			UpdatedAt: entry.UpdatedAt,
+		}
+		if n := len(entry.Quotes); n > 0 {
+			info.First = utils.TimeYYMMDDString(entry.Quotes[0].Date)
+			info.Last = utils.TimeYYMMDDString(entry.Quotes[n-1].Date)
+		}
+		res = append(res, info)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+	return res, nil
+}
+
+// Clear 删除 dir 下的缓存文件，code 非空时只删除该证券的缓存，返回删除数量
+func Clear(dir, code string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, path := range paths {
+		if code != "" {
+			// 文件名格式 {market}_{code}_{fqt}.json
+			toks := strings.Split(strings.TrimSuffix(filepath.Base(path), ".json"), "_")
+			if len(toks) != 3 || toks[1] != code {
+				continue
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
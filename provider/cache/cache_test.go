@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHistory serves quotes from a fixed series and records every request.
+type fakeHistory struct {
+	quotes []*eastmoney.Quote
+	reqs   []eastmoney.GetQuoteHistoryReq
+}
+
+func (f *fakeHistory) GetQuoteHistory(ctx context.Context, req *eastmoney.GetQuoteHistoryReq) ([]*eastmoney.Quote, error) {
+	f.reqs = append(f.reqs, *req)
+	begin, _ := time.Parse(eastmoney.TimeYYMMDD, req.Begin)
+	end, _ := time.Parse(eastmoney.TimeYYMMDD, req.End)
+	var res []*eastmoney.Quote
+	for _, q := range f.quotes {
+		if q.Date.Before(begin) || q.Date.After(end) {
+			continue
+		}
+		c := *q
+		res = append(res, &c)
+	}
+	return res, nil
+}
+
+func date(s string) time.Time {
+	t, _ := time.Parse(eastmoney.TimeYYMMDD, s)
+	return t
+}
+
+func series(begin string, n int, close float64) []*eastmoney.Quote {
+	d := date(begin)
+	res := make([]*eastmoney.Quote, n)
+	for i := range res {
+		res[i] = &eastmoney.Quote{Date: d.AddDate(0, 0, i), Code: "600036", Close: close + float64(i)}
+	}
+	return res
+}
+
+func newTestHistory(t *testing.T, f *fakeHistory, now string) *History {
+	return &History{
+		Dir:    t.TempDir(),
+		Source: f,
+		now:    func() time.Time { return date(now).Add(16 * time.Hour) },
+	}
+}
+
+func req(fqt eastmoney.FuQuanType, begin, end string) *eastmoney.GetQuoteHistoryReq {
+	return &eastmoney.GetQuoteHistoryReq{Code: "600036", MarketCode: 1, FQT: fqt, Begin: begin, End: end}
+}
+
+func TestGetQuoteHistory(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("incremental tail", func(t *testing.T) {
+		f := &fakeHistory{quotes: series("20240101", 10, 10)}
+		h := newTestHistory(t, f, "20240105")
+
+		quotes, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240105"))
+		require.NoError(t, err)
+		require.Len(t, quotes, 5)
+		require.Len(t, f.reqs, 1)
+
+		h.now = func() time.Time { return date("20240110").Add(16 * time.Hour) }
+		quotes, err = h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240110"))
+		require.NoError(t, err)
+		require.Len(t, quotes, 10)
+		require.Len(t, f.reqs, 2)
+		require.Equal(t, "20240104", f.reqs[1].Begin)
+		require.Equal(t, "20240110", f.reqs[1].End)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		f := &fakeHistory{quotes: series("20240101", 10, 10)}
+		h := newTestHistory(t, f, "20240111")
+
+		_, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240110"))
+		require.NoError(t, err)
+		quotes, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240103", "20240108"))
+		require.NoError(t, err)
+		require.Len(t, quotes, 6)
+		require.Len(t, f.reqs, 1)
+	})
+
+	t.Run("old range then newer range", func(t *testing.T) {
+		f := &fakeHistory{quotes: series("20240101", 10, 10)}
+		h := newTestHistory(t, f, "20240111")
+
+		quotes, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240104"))
+		require.NoError(t, err)
+		require.Len(t, quotes, 4)
+
+		quotes, err = h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240110"))
+		require.NoError(t, err)
+		require.Len(t, quotes, 10)
+		require.Len(t, f.reqs, 2)
+		require.Equal(t, "20240103", f.reqs[1].Begin)
+		require.Equal(t, "20240110", f.reqs[1].End)
+
+		// 再次请求已完整覆盖的区间不再下载
+		_, err = h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240110"))
+		require.NoError(t, err)
+		require.Len(t, f.reqs, 2)
+	})
+
+	t.Run("legacy entry without end", func(t *testing.T) {
+		f := &fakeHistory{quotes: series("20240101", 10, 10)}
+		h := newTestHistory(t, f, "20240111")
+
+		r := req(eastmoney.QuoteFQTDefault, "20240101", "20240104")
+		entry, err := h.fetchAll(ctx, r, r.Begin)
+		require.NoError(t, err)
+		entry.End = ""
+		require.NoError(t, save(h.path(r), entry))
+
+		quotes, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240110"))
+		require.NoError(t, err)
+		require.Len(t, quotes, 10)
+		require.Len(t, f.reqs, 2)
+	})
+
+	t.Run("head gap", func(t *testing.T) {
+		f := &fakeHistory{quotes: series("20240101", 10, 10)}
+		h := newTestHistory(t, f, "20240111")
+
+		_, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240105", "20240110"))
+		require.NoError(t, err)
+		quotes, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240110"))
+		require.NoError(t, err)
+		require.Len(t, quotes, 10)
+		require.Len(t, f.reqs, 2)
+		require.Equal(t, "20240101", f.reqs[1].Begin)
+		require.Equal(t, "20240104", f.reqs[1].End)
+	})
+
+	t.Run("adjusted price rebased", func(t *testing.T) {
+		f := &fakeHistory{quotes: series("20240101", 10, 10)}
+		h := newTestHistory(t, f, "20240105")
+
+		_, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTFront, "20240101", "20240105"))
+		require.NoError(t, err)
+
+		// ex-dividend: all qfq prices before the event shift down
+		f.quotes = series("20240101", 10, 9)
+		h.now = func() time.Time { return date("20240110").Add(16 * time.Hour) }
+		quotes, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTFront, "20240101", "20240110"))
+		require.NoError(t, err)
+		require.Len(t, f.reqs, 3)
+		require.Equal(t, "20240101", f.reqs[2].Begin)
+		require.Len(t, quotes, 10)
+		require.Equal(t, 9.0, quotes[0].Close)
+	})
+
+	t.Run("non-daily passthrough", func(t *testing.T) {
+		f := &fakeHistory{quotes: series("20240101", 10, 10)}
+		h := newTestHistory(t, f, "20240111")
+
+		r := req(eastmoney.QuoteFQTDefault, "20240101", "20240110")
+		r.Period = eastmoney.Period5Min
+		for range 2 {
+			_, err := h.GetQuoteHistory(ctx, r)
+			require.NoError(t, err)
+		}
+		require.Len(t, f.reqs, 2)
+		entries, err := os.ReadDir(h.Dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
+func TestStatsAndClear(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeHistory{quotes: series("20240101", 10, 10)}
+	h := newTestHistory(t, f, "20240111")
+
+	_, err := h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTDefault, "20240101", "20240110"))
+	require.NoError(t, err)
+	_, err = h.GetQuoteHistory(ctx, req(eastmoney.QuoteFQTFront, "20240101", "20240110"))
+	require.NoError(t, err)
+
+	infos, err := Stats(h.Dir)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, "600036", infos[0].Code)
+	require.Equal(t, 10, infos[0].Bars)
+	require.Equal(t, "2024-01-01", infos[0].First)
+	require.Equal(t, "2024-01-10", infos[0].Last)
+
+	removed, err := Clear(h.Dir, "000001")
+	require.NoError(t, err)
+	require.Equal(t, 0, removed)
+
+	removed, err = Clear(h.Dir, "600036")
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+
+	infos, err = Stats(h.Dir)
+	require.NoError(t, err)
+	require.Empty(t, infos)
+}
@@ -384,6 +384,15 @@ func QueryAnnouncements(ctx context.Context, req *QueryRequest) (*QueryResponse,
 	return &qr, nil
 }
 
+// Link returns the PDF download link of a. QueryAnnouncements does not fill
+// PDFURL, so it is derived from AdjunctURL when empty.
+func (a *Announcement) Link() string {
+	if a.PDFURL != "" {
+		return a.PDFURL
+	}
+	return resolvePDFURL(a)
+}
+
 // resolvePDFURL
 func resolvePDFURL(a *Announcement) string {
 	if a.AdjunctURL != "" && !strings.HasPrefix(a.AdjunctURL, "http") {
@@ -160,3 +160,11 @@ func TestResolvePDFURL(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnouncementLink(t *testing.T) {
+	a := &Announcement{AdjunctURL: "finalpage/2024-01-01/123.PDF"}
+	require.Equal(t, "http://static.cninfo.com.cn/finalpage/2024-01-01/123.PDF", a.Link())
+
+	a.PDFURL = "https://example.com/123.PDF"
+	require.Equal(t, "https://example.com/123.PDF", a.Link())
+}
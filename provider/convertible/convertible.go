@@ -0,0 +1,332 @@
+// Package convertible provides the list of outstanding Shanghai and Shenzhen
+// convertible bonds (可转债) with derived analytics: yield to maturity,
+// remaining years and the double-low (双低) score, plus filtering and sorting
+// used by screens.
+//
+// Quotes and conversion figures come from East Money push2, issue terms such
+// as rating, size and coupons from the East Money datacenter, and the
+// outstanding balance from jisilu when its login cookie is set.
+package convertible
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/jisilu"
+)
+
+// Bond 可转债行情、发行要素与衍生指标
+type Bond struct {
+	*eastmoney.ConvertibleQuote
+	Rating     string    // 信用评级
+	IssueScale float64   // 发行规模，单位元
+	Balance    float64   // 剩余规模，单位元；未知时为 NaN
+	ValueDate  time.Time // 起息日
+	ExpireDate time.Time // 到期日
+	Coupons    []float64 // 各年票面利率，百分比
+
+	YearsLeft float64 // 剩余年限
+	YTM       float64 // 税前到期收益率，百分比；无法计算时为 NaN
+	DoubleLow float64 // 双低值 = 价格 + 转股溢价率
+}
+
+// ExCode returns the exchange-prefixed code of the bond, e.g. SH113011.
+func (b *Bond) ExCode() string {
+	return b.Market.String() + b.Code
+}
+
+// StockExCode returns the exchange-prefixed code of the underlying stock.
+func (b *Bond) StockExCode() string {
+	return b.Market.String() + b.StockCode
+}
+
+// Scale returns the outstanding balance of the bond, or the issue size when
+// the balance is unknown, and whether it is the balance. It is NaN when both
+// are unknown.
+func (b *Bond) Scale() (float64, bool) {
+	if !math.IsNaN(b.Balance) {
+		return b.Balance, true
+	}
+	if b.IssueScale > 0 {
+		return b.IssueScale, false
+	}
+	return math.NaN(), false
+}
+
+// List returns all listed convertible bonds that are trading, i.e. have a
+// price, with their analytics computed at now.
+func List(ctx context.Context, now time.Time) ([]*Bond, error) {
+	quotes, err := eastmoney.QueryConvertibleQuotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := eastmoney.QueryConvertibleInfos(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*eastmoney.ConvertibleInfo, len(infos))
+	for _, info := range infos {
+		if _, ok := byCode[info.Code]; !ok {
+			byCode[info.Code] = info
+		}
+	}
+	balances := queryBalances(ctx)
+	res := make([]*Bond, 0, len(quotes))
+	for _, q := range quotes {
+		if q.Price <= 0 {
+			continue
+		}
+		b := newBond(q, byCode[q.Code], now)
+		if v, ok := balances[q.Code]; ok {
+			b.Balance = v
+		}
+		res = append(res, b)
+	}
+	return res, nil
+}
+
+// queryBalances returns the outstanding balances by bond code. Without a login
+// cookie jisilu leaves out most bonds, so it is only queried when the cookie is
+// set. The list is still usable without balances, so a failure is only logged.
+func queryBalances(ctx context.Context) map[string]float64 {
+	if strings.TrimSpace(os.Getenv(jisilu.EnvCookie)) == "" {
+		return nil
+	}
+	balances, err := jisilu.QueryConvertibleBalances(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed query convertible balances", "error", err)
+	}
+	return balances
+}
+
+// Get returns the listed convertible bond with code or name key.
+func Get(ctx context.Context, key string, now time.Time) (*Bond, error) {
+	quotes, err := eastmoney.QueryConvertibleQuotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key = strings.TrimSpace(key)
+	for _, q := range quotes {
+		if q.Code != key && q.Name != key && !strings.EqualFold(q.Market.String()+q.Code, key) {
+			continue
+		}
+		infos, err := eastmoney.QueryConvertibleInfos(ctx, q.Code)
+		if err != nil {
+			return nil, err
+		}
+		var info *eastmoney.ConvertibleInfo
+		if len(infos) > 0 {
+			info = infos[0]
+		}
+		b := newBond(q, info, now)
+		if v, ok := queryBalances(ctx)[q.Code]; ok {
+			b.Balance = v
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("未找到可转债: %s", key)
+}
+
+// newBond merges a quote with its issue terms, which may be nil, and computes
+// the analytics at now.
+func newBond(q *eastmoney.ConvertibleQuote, info *eastmoney.ConvertibleInfo, now time.Time) *Bond {
+	b := &Bond{ConvertibleQuote: q, Balance: math.NaN(), YTM: math.NaN()}
+	if q.Price > 0 {
+		b.DoubleLow = q.Price + q.Premium
+	}
+	if info == nil {
+		return b
+	}
+	b.Rating = info.Rating
+	b.IssueScale = info.IssueScale
+	b.ValueDate = info.ValueDate
+	b.ExpireDate = info.ExpireDate
+	b.Coupons = ParseCoupons(info.CouponExplain)
+	if !b.ExpireDate.IsZero() {
+		b.YearsLeft = math.Max(b.ExpireDate.Sub(now).Hours()/24/365, 0)
+	}
+	if q.Price > 0 {
+		b.YTM = YTM(q.Price, CashFlows(b.ValueDate, b.ExpireDate, b.Coupons, q.RedeemPrice, now), now)
+	}
+	return b
+}
+
+var couponRe = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+
+// ParseCoupons extracts the yearly coupon rates in percent from a coupon
+// explanation such as "第一年0.30%、第二年0.50%、第三年1.00%".
+func ParseCoupons(explain string) []float64 {
+	matches := couponRe.FindAllStringSubmatch(explain, -1)
+	res := make([]float64, 0, len(matches))
+	for _, m := range matches {
+		if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// CashFlow 一笔未来现金流，按每张面值 100 元计
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// CashFlows returns the cash flows of a bond after now: yearly coupons paid on
+// the anniversaries of valueDate and the redemption at expireDate. The
+// redemption price includes the last coupon; when it is unknown the face
+// value plus the last coupon is used.
+func CashFlows(valueDate, expireDate time.Time, coupons []float64, redeem float64, now time.Time) []CashFlow {
+	if expireDate.IsZero() || !expireDate.After(now) {
+		return nil
+	}
+	if redeem <= 0 {
+		redeem = 100
+		if len(coupons) > 0 {
+			redeem += coupons[len(coupons)-1]
+		}
+	}
+	var flows []CashFlow
+	if !valueDate.IsZero() {
+		// 最后一年的利息已包含在到期赎回价中
+		for i := 0; i+1 < len(coupons); i++ {
+			date := valueDate.AddDate(i+1, 0, 0)
+			if date.After(now) && date.Before(expireDate) {
+				flows = append(flows, CashFlow{Date: date, Amount: coupons[i]})
+			}
+		}
+	}
+	return append(flows, CashFlow{Date: expireDate, Amount: redeem})
+}
+
+// YTM returns the annual yield in percent that discounts flows to price at
+// now, or NaN when there is no cash flow.
+func YTM(price float64, flows []CashFlow, now time.Time) float64 {
+	if price <= 0 || len(flows) == 0 {
+		return math.NaN()
+	}
+	pv := func(y float64) float64 {
+		var sum float64
+		for _, f := range flows {
+			t := f.Date.Sub(now).Hours() / 24 / 365
+			sum += f.Amount / math.Pow(1+y, t)
+		}
+		return sum
+	}
+	// 现值随收益率单调递减，二分求解
+	lo, hi := -0.99, 10.0
+	if pv(lo) < price || pv(hi) > price {
+		return math.NaN()
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if pv(mid) > price {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2 * 100
+}
+
+// ratings 信用评级从高到低
+var ratings = []string{"AAA", "AA+", "AA", "AA-", "A+", "A", "A-", "BBB+", "BBB", "BBB-", "BB+", "BB", "BB-", "B+", "B", "B-", "CCC", "CC", "C"}
+
+// RatingRank returns the rank of a credit rating, 0 for AAA and larger for
+// lower ratings, or -1 for an unknown rating.
+func RatingRank(r string) int {
+	r = strings.ToUpper(strings.TrimSpace(r))
+	for i, v := range ratings {
+		if v == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Filter 筛选条件，nil 表示不限
+type Filter struct {
+	PriceMax   *float64 // 价格上限
+	PremiumMax *float64 // 转股溢价率上限，百分比
+	YTMMin     *float64 // 到期收益率下限，百分比
+	Rating     string   // 最低信用评级，如 "AA-"，空表示不限
+}
+
+// Match reports whether b satisfies f.
+func (f *Filter) Match(b *Bond) bool {
+	if f.PriceMax != nil && b.Price > *f.PriceMax {
+		return false
+	}
+	if f.PremiumMax != nil && b.Premium > *f.PremiumMax {
+		return false
+	}
+	if f.YTMMin != nil && (math.IsNaN(b.YTM) || b.YTM < *f.YTMMin) {
+		return false
+	}
+	if f.Rating != "" {
+		rank := RatingRank(b.Rating)
+		if rank < 0 || rank > RatingRank(f.Rating) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the rating of f.
+func (f *Filter) Validate() error {
+	if f.Rating != "" && RatingRank(f.Rating) < 0 {
+		return fmt.Errorf("invalid rating %q: expected one of %s", f.Rating, strings.Join(ratings, ", "))
+	}
+	return nil
+}
+
+// sortKeys 排序字段
+var sortKeys = map[string]func(b *Bond) float64{
+	"dblow":   func(b *Bond) float64 { return b.DoubleLow },
+	"price":   func(b *Bond) float64 { return b.Price },
+	"premium": func(b *Bond) float64 { return b.Premium },
+	"ytm":     func(b *Bond) float64 { return b.YTM },
+	"change":  func(b *Bond) float64 { return b.ChangeRate },
+	"scale":   func(b *Bond) float64 { v, _ := b.Scale(); return v },
+	"years":   func(b *Bond) float64 { return b.YearsLeft },
+	"value":   func(b *Bond) float64 { return b.ConvertValue },
+}
+
+// SortKeys returns the names accepted by Sort.
+func SortKeys() []string {
+	keys := make([]string, 0, len(sortKeys))
+	for k := range sortKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Sort sorts list by key in ascending order, or descending when desc is set.
+// Bonds without a value for key, such as an unknown YTM, are placed last.
+func Sort(list []*Bond, key string, desc bool) error {
+	value, ok := sortKeys[key]
+	if !ok {
+		return fmt.Errorf("invalid sort key %q: expected one of %s", key, strings.Join(SortKeys(), ", "))
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		vi, vj := value(list[i]), value(list[j])
+		if math.IsNaN(vi) || math.IsNaN(vj) {
+			return !math.IsNaN(vi) && math.IsNaN(vj)
+		}
+		if desc {
+			return vi > vj
+		}
+		return vi < vj
+	})
+	return nil
+}
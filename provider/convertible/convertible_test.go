@@ -0,0 +1,108 @@
+package convertible
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoupons(t *testing.T) {
+	got := ParseCoupons("第一年0.30%、第二年0.50%、第三年1.0%、第四年 1.50 %、第五年1.80%、第六年2.00%。")
+	require.Equal(t, []float64{0.3, 0.5, 1.0, 1.5, 1.8, 2.0}, got)
+	require.Empty(t, ParseCoupons(""))
+}
+
+func TestCashFlowsAndYTM(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+	value, expire := day(2021, 3, 1), day(2027, 3, 1)
+	coupons := []float64{0.3, 0.5, 1.0, 1.5, 1.8, 2.0}
+	now := day(2025, 9, 1)
+
+	flows := CashFlows(value, expire, coupons, 110, now)
+	// 2026-03-01 第五年利息，2027-03-01 到期赎回（含最后一年利息）
+	require.Len(t, flows, 2)
+	require.Equal(t, 1.8, flows[0].Amount)
+	require.Equal(t, 110.0, flows[1].Amount)
+
+	require.True(t, math.IsNaN(YTM(100, nil, now)))
+	require.Empty(t, CashFlows(value, expire, coupons, 110, expire))
+
+	// 价格等于未来现金流之和时收益率为 0
+	require.InDelta(t, 0, YTM(111.8, flows, now), 1e-6)
+	// 价格越低收益率越高
+	require.Greater(t, YTM(100, flows, now), YTM(105, flows, now))
+	require.Less(t, YTM(130, flows, now), 0.0)
+
+	// 单笔现金流：1 年后 110，价格 100 时收益率 10%
+	one := []CashFlow{{Date: now.AddDate(0, 0, 365), Amount: 110}}
+	require.InDelta(t, 10, YTM(100, one, now), 1e-6)
+
+	// 赎回价未知时按面值加最后一年利息
+	flows = CashFlows(value, expire, coupons, 0, now)
+	require.Equal(t, 102.0, flows[len(flows)-1].Amount)
+}
+
+func TestFilterAndSort(t *testing.T) {
+	bond := func(code string, price, premium, ytm float64, rating string, issue, balance float64) *Bond {
+		return &Bond{
+			ConvertibleQuote: &eastmoney.ConvertibleQuote{Code: code, Price: price, Premium: premium},
+			Rating:           rating,
+			IssueScale:       issue,
+			Balance:          balance,
+			YTM:              ytm,
+			DoubleLow:        price + premium,
+		}
+	}
+	list := []*Bond{
+		bond("A", 120, 30, 1.0, "AA", 50e8, 2e8),
+		bond("B", 105, 10, 3.0, "AA-", 10e8, 9e8),
+		bond("C", 150, 5, math.NaN(), "AAA", 30e8, math.NaN()),
+		bond("D", 98, 80, 6.0, "A+", 5e8, 5e8),
+	}
+
+	f := &Filter{Rating: "AA-"}
+	require.NoError(t, f.Validate())
+	require.True(t, f.Match(list[1]))
+	require.False(t, f.Match(list[3]))
+	require.Error(t, (&Filter{Rating: "ZZ"}).Validate())
+
+	ytm := 2.0
+	f = &Filter{YTMMin: &ytm}
+	require.False(t, f.Match(list[2]))
+	require.True(t, f.Match(list[3]))
+
+	price, premium := 130.0, 50.0
+	f = &Filter{PriceMax: &price, PremiumMax: &premium}
+	require.True(t, f.Match(list[0]))
+	require.False(t, f.Match(list[2]))
+	require.False(t, f.Match(list[3]))
+
+	// 溢价率上限为 0 时只保留折价转债
+	premium = 0
+	require.False(t, f.Match(list[0]))
+
+	require.NoError(t, Sort(list, "dblow", false))
+	require.Equal(t, "B", list[0].Code)
+
+	require.NoError(t, Sort(list, "ytm", true))
+	require.Equal(t, "D", list[0].Code)
+	// 无法计算收益率的排在最后
+	require.Equal(t, "C", list[3].Code)
+
+	// 按剩余规模排序，剩余规模未知时使用发行规模
+	require.NoError(t, Sort(list, "scale", true))
+	require.Equal(t, []string{"C", "B", "D", "A"}, []string{list[0].Code, list[1].Code, list[2].Code, list[3].Code})
+
+	v, outstanding := list[0].Scale()
+	require.Equal(t, 30e8, v)
+	require.False(t, outstanding)
+	_, outstanding = list[1].Scale()
+	require.True(t, outstanding)
+	v, _ = (&Bond{Balance: math.NaN()}).Scale()
+	require.True(t, math.IsNaN(v))
+
+	require.Error(t, Sort(list, "foo", false))
+}
@@ -0,0 +1,184 @@
+package eastmoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// 行业、概念、地域板块：板块列表与成分股来自 push2 clist，个股所属板块来自 push2 slist。
+// 字段语义参考 AKShare stock_board_industry_name_em / stock_board_concept_name_em。
+
+// BoardType 板块类型
+type BoardType string
+
+const (
+	BoardIndustry BoardType = "industry" // 行业板块
+	BoardConcept  BoardType = "concept"  // 概念板块
+	BoardRegion   BoardType = "region"   // 地域板块
+)
+
+// Label returns the Chinese name of t.
+func (t BoardType) Label() string {
+	switch t {
+	case BoardIndustry:
+		return "行业"
+	case BoardConcept:
+		return "概念"
+	case BoardRegion:
+		return "地域"
+	}
+	return string(t)
+}
+
+// fs returns the clist filter listing boards of t.
+func (t BoardType) fs() string {
+	switch t {
+	case BoardConcept:
+		return "m:90+t:3"
+	case BoardRegion:
+		return "m:90+t:1"
+	}
+	return "m:90+t:2"
+}
+
+// ParseBoardType parses "industry", "concept" or "region" and their Chinese
+// names.
+func ParseBoardType(s string) (BoardType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "industry", "ind", "行业":
+		return BoardIndustry, nil
+	case "concept", "con", "概念":
+		return BoardConcept, nil
+	case "region", "area", "地域":
+		return BoardRegion, nil
+	}
+	return "", fmt.Errorf("invalid board type %q: expected industry, concept or region", s)
+}
+
+var (
+	// f2: 最新点位 f3: 涨跌幅 f6: 成交额 f8: 换手率 f12: 板块代码 f14: 板块名称
+	// f62: 主力净流入 f104: 上涨家数 f105: 下跌家数
+	// f128: 领涨股名称 f136: 领涨股涨跌幅 f140: 领涨股代码
+	boardFields = "f2,f3,f6,f8,f12,f14,f62,f104,f105,f128,f136,f140"
+
+	// slistURL 个股所属板块接口
+	slistURL = EastMoneyPush2ApiBase + "/api/qt/slist/get"
+)
+
+// Board 板块行情
+type Board struct {
+	Code         string // 板块代码 BK0475
+	Name         string
+	Type         BoardType // 个股所属板块中为空
+	Price        float64   // 板块指数点位
+	ChangeRate   float64   // 涨跌幅，百分比
+	Amount       float64   // 成交额，单位元
+	TurnoverRate float64   // 换手率，百分比
+	MainInflow   float64   // 主力净流入，单位元
+	Up           int       // 上涨家数
+	Down         int       // 下跌家数
+	LeaderCode   string    // 领涨股代码
+	LeaderName   string    // 领涨股名称
+	LeaderChange float64   // 领涨股涨跌幅，百分比
+}
+
+// QueryBoards returns all boards of t sorted by change rate, best first.
+func QueryBoards(ctx context.Context, t BoardType) ([]*Board, error) {
+	rows, err := fetchClist(ctx, t.fs(), boardFields)
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney %s boards: %w", t, err)
+	}
+	boards := make([]*Board, 0, len(rows))
+	for _, m := range rows {
+		if b := parseBoardItem(m); b != nil {
+			b.Type = t
+			boards = append(boards, b)
+		}
+	}
+	sort.SliceStable(boards, func(i, j int) bool { return boards[i].ChangeRate > boards[j].ChangeRate })
+	return boards, nil
+}
+
+func parseBoardItem(m map[string]interface{}) *Board {
+	code := getStrField(m, "f12")
+	if code == "" || code == "-" {
+		return nil
+	}
+	num := func(key string) float64 {
+		// 停牌或无数据时为 "-"
+		if v, ok := m[key].(float64); ok {
+			return v
+		}
+		return 0
+	}
+	b := &Board{
+		Code:         code,
+		Name:         getStrField(m, "f14"),
+		Price:        num("f2"),
+		ChangeRate:   num("f3"),
+		Amount:       num("f6"),
+		TurnoverRate: num("f8"),
+		MainInflow:   num("f62"),
+		Up:           int(num("f104")),
+		Down:         int(num("f105")),
+		LeaderChange: num("f136"),
+	}
+	if name := getStrField(m, "f128"); name != "-" {
+		b.LeaderName = name
+	}
+	if code := getStrField(m, "f140"); code != "-" {
+		b.LeaderCode = code
+	}
+	return b
+}
+
+// QueryBoardStocks returns the constituents of the board code, e.g. BK0475,
+// with their latest quotes sorted by change rate, best first.
+func QueryBoardStocks(ctx context.Context, code string) ([]*MarketStock, error) {
+	rows, err := fetchClist(ctx, "b:"+code, marketFields)
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney board %s stocks: %w", code, err)
+	}
+	stocks := parseMarketItems(rows)
+	sort.SliceStable(stocks, func(i, j int) bool { return stocks[i].ChangeRate > stocks[j].ChangeRate })
+	return stocks, nil
+}
+
+// QueryStockBoards returns the industry, concept and region boards the stock
+// belongs to.
+func QueryStockBoards(ctx context.Context, market MarketType, code string) ([]*Board, error) {
+	v := url.Values{}
+	v.Set("spt", "3")
+	v.Set("pn", "1")
+	v.Set("pz", "200")
+	v.Set("po", "1")
+	v.Set("np", "1")
+	v.Set("fltt", "2")
+	v.Set("invt", "2")
+	v.Set("fid", "f3")
+	v.Set("secid", fmt.Sprintf("%d.%s", market, code))
+	v.Set("fields", "f2,f3,f12,f14")
+
+	body, err := getPush2(ctx, slistURL+"?"+v.Encode(), "http://quote.eastmoney.com/")
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney stock boards: %w", err)
+	}
+	var raw marketResp
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("eastMoney stock boards parse: %w", err)
+	}
+	if raw.Data == nil {
+		return nil, nil
+	}
+	boards := make([]*Board, 0, len(raw.Data.Diff))
+	for _, m := range raw.Data.Diff {
+		if b := parseBoardItem(m); b != nil && strings.HasPrefix(b.Code, "BK") {
+			boards = append(boards, b)
+		}
+	}
+	return boards, nil
+}
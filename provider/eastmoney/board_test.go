@@ -0,0 +1,84 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBoardType(t *testing.T) {
+	for in, want := range map[string]BoardType{"industry": BoardIndustry, "概念": BoardConcept, "Region": BoardRegion} {
+		got, err := ParseBoardType(in)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := ParseBoardType("sector")
+	require.Error(t, err)
+	require.Equal(t, "行业", BoardIndustry.Label())
+}
+
+func TestQueryBoards(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("fs") {
+		case "m:90+t:2":
+			fmt.Fprint(w, `{"rc":0,"data":{"total":2,"diff":[
+				{"f2":1000.5,"f3":-1.2,"f6":1.2e10,"f8":0.8,"f12":"BK0475","f14":"银行","f62":-3.5e8,"f104":10,"f105":32,"f128":"招商银行","f136":1.5,"f140":"600036"},
+				{"f2":2000,"f3":3.4,"f6":5e9,"f8":2.1,"f12":"BK1036","f14":"半导体","f62":1.2e9,"f104":100,"f105":5,"f128":"-","f136":"-","f140":"-"}]}}`)
+		case "b:BK0475":
+			fmt.Fprint(w, `{"rc":0,"data":{"total":2,"diff":[
+				{"f2":40.0,"f3":-1.0,"f6":1e9,"f12":"600036","f13":1,"f14":"招商银行"},
+				{"f2":12.0,"f3":2.0,"f6":5e8,"f12":"000001","f13":0,"f14":"平安银行"}]}}`)
+		default:
+			fmt.Fprint(w, `{"rc":0,"data":null}`)
+		}
+	}))
+	defer srv.Close()
+	origURL := marketURL
+	marketURL = srv.URL
+	defer func() { marketURL = origURL }()
+
+	boards, err := QueryBoards(context.Background(), BoardIndustry)
+	require.NoError(t, err)
+	require.Len(t, boards, 2)
+	require.Equal(t, "半导体", boards[0].Name)
+	require.Equal(t, BoardIndustry, boards[0].Type)
+	require.Empty(t, boards[0].LeaderName)
+	require.Equal(t, "招商银行", boards[1].LeaderName)
+	require.Equal(t, -3.5e8, boards[1].MainInflow)
+	require.Equal(t, 32, boards[1].Down)
+
+	boards, err = QueryBoards(context.Background(), BoardRegion)
+	require.NoError(t, err)
+	require.Empty(t, boards)
+
+	stocks, err := QueryBoardStocks(context.Background(), "BK0475")
+	require.NoError(t, err)
+	require.Len(t, stocks, 2)
+	require.Equal(t, "SZ000001", stocks[0].ExCode())
+	require.Equal(t, "SH600036", stocks[1].ExCode())
+	require.Equal(t, "BJ830799", (&MarketStock{Code: "830799"}).ExCode())
+}
+
+func TestQueryStockBoards(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "1.600036", r.URL.Query().Get("secid"))
+		fmt.Fprint(w, `{"rc":0,"data":{"total":3,"diff":[
+			{"f2":40.0,"f3":-1.0,"f12":"600036","f14":"招商银行"},
+			{"f2":1000.5,"f3":-1.2,"f12":"BK0475","f14":"银行"},
+			{"f2":900,"f3":0.5,"f12":"BK0596","f14":"融资融券"}]}}`)
+	}))
+	defer srv.Close()
+	origURL := slistURL
+	slistURL = srv.URL
+	defer func() { slistURL = origURL }()
+
+	boards, err := QueryStockBoards(context.Background(), MarketTypeSse, "600036")
+	require.NoError(t, err)
+	require.Len(t, boards, 2)
+	require.Equal(t, "银行", boards[0].Name)
+	require.Equal(t, "融资融券", boards[1].Name)
+}
@@ -0,0 +1,141 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 可转债：行情与转股指标来自 push2 clist（b:MK0354），发行要素来自数据中心
+// RPT_BOND_CB_LIST。字段语义参考 AKShare bond_cov_comparison / bond_zh_cov。
+
+var (
+	cbFS = "b:MK0354"
+
+	// f2: 转债最新价 f3: 转债涨跌幅 f12: 转债代码 f13: 市场 f14: 转债名称
+	// f229: 正股最新价 f230: 正股涨跌幅 f232: 正股代码 f234: 正股名称
+	// f235: 转股价 f236: 转股价值 f237: 转股溢价率 f238: 纯债溢价率
+	// f239: 回售触发价 f240: 强赎触发价 f241: 到期赎回价 f242: 纯债价值
+	cbFields = "f2,f3,f12,f13,f14,f229,f230,f232,f234,f235,f236,f237,f238,f239,f240,f241,f242"
+
+	cbInfoColumns = "SECURITY_CODE,SECURITY_NAME_ABBR,CONVERT_STOCK_CODE,SECURITY_SHORT_NAME,RATING," +
+		"ACTUAL_ISSUE_SCALE,VALUE_DATE,EXPIRE_DATE,LISTING_DATE,DELIST_DATE,INTEREST_RATE_EXPLAIN"
+)
+
+// ConvertibleQuote 可转债实时行情与转股指标
+type ConvertibleQuote struct {
+	Code            string
+	Name            string
+	Market          MarketType
+	Price           float64 // 转债最新价，未上市或停牌为 0
+	ChangeRate      float64 // 涨跌幅，百分比
+	StockCode       string  // 正股代码
+	StockName       string
+	StockPrice      float64
+	StockChangeRate float64
+	ConvertPrice    float64 // 转股价
+	ConvertValue    float64 // 转股价值
+	Premium         float64 // 转股溢价率，百分比
+	BondPremium     float64 // 纯债溢价率，百分比
+	BondValue       float64 // 纯债价值
+	PutTrigger      float64 // 回售触发价
+	CallTrigger     float64 // 强赎触发价
+	RedeemPrice     float64 // 到期赎回价，含最后一期利息
+}
+
+// ConvertibleInfo 可转债发行要素
+type ConvertibleInfo struct {
+	Code          string
+	Name          string
+	StockCode     string
+	StockName     string
+	Rating        string    // 信用评级
+	IssueScale    float64   // 发行规模，单位元
+	ValueDate     time.Time // 起息日
+	ExpireDate    time.Time // 到期日
+	ListingDate   time.Time // 上市日，未上市为零值
+	DelistDate    time.Time // 摘牌日，未摘牌为零值
+	CouponExplain string    // 票面利率说明，如"第一年0.30%、第二年0.50%……"
+}
+
+// QueryConvertibleQuotes returns the latest quotes of all listed convertible
+// bonds in Shanghai and Shenzhen.
+func QueryConvertibleQuotes(ctx context.Context) ([]*ConvertibleQuote, error) {
+	rows, err := fetchClist(ctx, cbFS, cbFields)
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney convertible quotes: %w", err)
+	}
+	res := make([]*ConvertibleQuote, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, m := range rows {
+		code := getStrField(m, "f12")
+		if code == "" || code == "-" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		num := func(key string) float64 {
+			// 未上市或无数据时为 "-"
+			if v, ok := m[key].(float64); ok {
+				return v
+			}
+			return 0
+		}
+		res = append(res, &ConvertibleQuote{
+			Code:            code,
+			Name:            getStrField(m, "f14"),
+			Market:          MarketType(num("f13")),
+			Price:           num("f2"),
+			ChangeRate:      num("f3"),
+			StockCode:       getStrField(m, "f232"),
+			StockName:       getStrField(m, "f234"),
+			StockPrice:      num("f229"),
+			StockChangeRate: num("f230"),
+			ConvertPrice:    num("f235"),
+			ConvertValue:    num("f236"),
+			Premium:         num("f237"),
+			BondPremium:     num("f238"),
+			PutTrigger:      num("f239"),
+			CallTrigger:     num("f240"),
+			RedeemPrice:     num("f241"),
+			BondValue:       num("f242"),
+		})
+	}
+	return res, nil
+}
+
+// QueryConvertibleInfos returns the issue terms of convertible bonds, or of
+// the bond code only when code is not empty, latest issued first. Delisted
+// bonds are included.
+func QueryConvertibleInfos(ctx context.Context, code string) ([]*ConvertibleInfo, error) {
+	req := &reportReq{
+		ReportName:  "RPT_BOND_CB_LIST",
+		Columns:     cbInfoColumns,
+		SortColumns: "PUBLIC_START_DATE",
+		SortTypes:   "-1",
+		PageSize:    500,
+	}
+	if code != "" {
+		req.Filter = fmt.Sprintf(`(SECURITY_CODE="%s")`, code)
+	}
+	rows, err := queryReportAll(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*ConvertibleInfo, 0, len(rows))
+	for _, m := range rows {
+		res = append(res, &ConvertibleInfo{
+			Code:          reportString(m, "SECURITY_CODE"),
+			Name:          reportString(m, "SECURITY_NAME_ABBR"),
+			StockCode:     reportString(m, "CONVERT_STOCK_CODE"),
+			StockName:     reportString(m, "SECURITY_SHORT_NAME"),
+			Rating:        reportString(m, "RATING"),
+			IssueScale:    reportFloat(m, "ACTUAL_ISSUE_SCALE") * 1e8, // 单位亿元
+			ValueDate:     reportDate(m, "VALUE_DATE"),
+			ExpireDate:    reportDate(m, "EXPIRE_DATE"),
+			ListingDate:   reportDate(m, "LISTING_DATE"),
+			DelistDate:    reportDate(m, "DELIST_DATE"),
+			CouponExplain: reportString(m, "INTEREST_RATE_EXPLAIN"),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryConvertibleQuotes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "b:MK0354", r.URL.Query().Get("fs"))
+		fmt.Fprint(w, `{"rc":0,"data":{"total":2,"diff":[
+			{"f2":128.5,"f3":0.62,"f12":"113011","f13":1,"f14":"光大转债","f229":3.2,"f230":1.1,"f232":"601818","f234":"光大银行",
+			 "f235":3.3,"f236":96.97,"f237":32.51,"f238":18.2,"f239":2.31,"f240":4.29,"f241":106,"f242":108.7},
+			{"f2":"-","f3":"-","f12":"127999","f13":0,"f14":"未上市转债","f229":10,"f230":0,"f232":"000001","f234":"平安银行",
+			 "f235":"-","f236":"-","f237":"-","f238":"-","f239":"-","f240":"-","f241":"-","f242":"-"}]}}`)
+	}))
+	defer srv.Close()
+	origURL := marketURL
+	marketURL = srv.URL
+	defer func() { marketURL = origURL }()
+
+	list, err := QueryConvertibleQuotes(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, MarketTypeSse, list[0].Market)
+	require.Equal(t, "601818", list[0].StockCode)
+	require.Equal(t, 32.51, list[0].Premium)
+	require.Equal(t, 106.0, list[0].RedeemPrice)
+	require.Zero(t, list[1].Price)
+	require.Equal(t, MarketTypeSzSe, list[1].Market)
+}
+
+func TestQueryConvertibleInfos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		require.Equal(t, "RPT_BOND_CB_LIST", q.Get("reportName"))
+		require.Equal(t, `(SECURITY_CODE="113011")`, q.Get("filter"))
+		fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":1,"data":[
+			{"SECURITY_CODE":"113011","SECURITY_NAME_ABBR":"光大转债","CONVERT_STOCK_CODE":"601818","SECURITY_SHORT_NAME":"光大银行",
+			 "RATING":"AAA","ACTUAL_ISSUE_SCALE":300,"VALUE_DATE":"2017-03-17 00:00:00","EXPIRE_DATE":"2023-03-16 00:00:00",
+			 "LISTING_DATE":"2017-04-05 00:00:00","DELIST_DATE":null,"INTEREST_RATE_EXPLAIN":"第一年0.2%、第二年0.5%"}]}}`)
+	}))
+	defer srv.Close()
+	origURL := datacenterURL
+	datacenterURL = srv.URL
+	defer func() { datacenterURL = origURL }()
+
+	list, err := QueryConvertibleInfos(context.Background(), "113011")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "AAA", list[0].Rating)
+	require.Equal(t, 3e10, list[0].IssueScale)
+	require.Equal(t, "2023-03-16", list[0].ExpireDate.Format(time.DateOnly))
+	require.True(t, list[0].DelistDate.IsZero())
+	require.Equal(t, "光大银行", list[0].StockName)
+}
@@ -0,0 +1,238 @@
+package eastmoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 沪深港通：每日成交（数据中心 RPT_MUTUAL_DEAL_HISTORY）、当日分时净流入（push2 kamt.rtmin）
+// 以及北向个股持股（数据中心 RPT_MUTUAL_HOLDSTOCKNORTH_STA）。
+// 字段语义参考 AKShare stock_hsgt_hist_em / stock_hsgt_fund_min_em / stock_hsgt_individual_em。
+
+// ConnectChannel 沪深港通通道，取值为数据中心的 MUTUAL_TYPE
+type ConnectChannel string
+
+const (
+	ChannelShanghai   ConnectChannel = "001" // 沪股通
+	ChannelHKShanghai ConnectChannel = "002" // 港股通(沪)
+	ChannelShenzhen   ConnectChannel = "003" // 深股通
+	ChannelHKShenzhen ConnectChannel = "004" // 港股通(深)
+	ChannelNorth      ConnectChannel = "005" // 北向合计
+	ChannelSouth      ConnectChannel = "006" // 南向合计
+)
+
+// String returns the Chinese name of c.
+func (c ConnectChannel) String() string {
+	switch c {
+	case ChannelShanghai:
+		return "沪股通"
+	case ChannelHKShanghai:
+		return "港股通(沪)"
+	case ChannelShenzhen:
+		return "深股通"
+	case ChannelHKShenzhen:
+		return "港股通(深)"
+	case ChannelNorth:
+		return "北向合计"
+	case ChannelSouth:
+		return "南向合计"
+	}
+	return string(c)
+}
+
+// NorthChannels returns the northbound channels followed by their total.
+func NorthChannels() []ConnectChannel {
+	return []ConnectChannel{ChannelShanghai, ChannelShenzhen, ChannelNorth}
+}
+
+// SouthChannels returns the southbound channels followed by their total.
+func SouthChannels() []ConnectChannel {
+	return []ConnectChannel{ChannelHKShanghai, ChannelHKShenzhen, ChannelSouth}
+}
+
+// ConnectFlow 沪深港通单日成交
+type ConnectFlow struct {
+	Date     time.Time
+	Channel  ConnectChannel
+	NetBuy   float64 // 成交净买额，单位元
+	Buy      float64 // 买入成交额，单位元
+	Sell     float64 // 卖出成交额，单位元
+	Accum    float64 // 历史累计净买额，单位元
+	LeadName string  // 领涨股
+}
+
+// QueryConnectHistory returns the daily flows of ch since begin in ascending
+// date order.
+func QueryConnectHistory(ctx context.Context, ch ConnectChannel, begin time.Time) ([]*ConnectFlow, error) {
+	rows, err := queryReportAll(ctx, &reportReq{
+		ReportName:  "RPT_MUTUAL_DEAL_HISTORY",
+		Filter:      fmt.Sprintf(`(MUTUAL_TYPE="%s")(TRADE_DATE>='%s')`, string(ch), begin.Format(time.DateOnly)),
+		SortColumns: "TRADE_DATE",
+		SortTypes:   "-1",
+		PageSize:    500,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*ConnectFlow, 0, len(rows))
+	for _, m := range rows {
+		// 数值单位为百万元
+		res = append(res, &ConnectFlow{
+			Date:     reportDate(m, "TRADE_DATE"),
+			Channel:  ch,
+			NetBuy:   reportFloat(m, "NET_DEAL_AMT") * 1e6,
+			Buy:      reportFloat(m, "BUY_AMT") * 1e6,
+			Sell:     reportFloat(m, "SELL_AMT") * 1e6,
+			Accum:    reportFloat(m, "ACCUM_DEAL_AMT") * 1e6,
+			LeadName: reportString(m, "LEAD_STOCKS_NAME"),
+		})
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res, nil
+}
+
+// ConnectMinute 沪深港通分时累计净流入，单位元
+type ConnectMinute struct {
+	Time     time.Time
+	Shanghai float64 // 沪股通或港股通(沪)
+	Shenzhen float64 // 深股通或港股通(深)
+	Total    float64
+}
+
+// ConnectIntraday 当日沪深港通分时净流入
+type ConnectIntraday struct {
+	North []*ConnectMinute
+	South []*ConnectMinute
+}
+
+// connectMinURL 沪深港通分时资金接口
+var connectMinURL = EastMoneyPush2ApiBase + "/api/qt/kamt.rtmin/get"
+
+// connectMinResp kamt.rtmin 返回结构
+type connectMinResp struct {
+	Rc   int `json:"rc"`
+	Data *struct {
+		S2NDate string   `json:"s2nDate"` // 北向数据日期 MM-DD
+		N2SDate string   `json:"n2sDate"` // 南向数据日期 MM-DD
+		S2N     []string `json:"s2n"`
+		N2S     []string `json:"n2s"`
+	} `json:"data"`
+}
+
+// QueryConnectIntraday returns the cumulative minute net inflows of the latest
+// trading day in both directions.
+func QueryConnectIntraday(ctx context.Context) (*ConnectIntraday, error) {
+	v := url.Values{}
+	v.Set("fields1", "f1,f2,f3,f4")
+	v.Set("fields2", "f51,f52,f53,f54,f55,f56")
+	v.Set("ut", "b2884a393a59ad64002292a3e90d46a5")
+
+	body, err := getPush2(ctx, connectMinURL+"?"+v.Encode(), "http://data.eastmoney.com/hsgt/index.html")
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney connect intraday: %w", err)
+	}
+	var raw connectMinResp
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("eastMoney connect intraday parse: %w", err)
+	}
+	if raw.Data == nil {
+		return nil, fmt.Errorf("eastMoney connect intraday: nil data")
+	}
+	now := time.Now()
+	return &ConnectIntraday{
+		North: parseConnectMinutes(raw.Data.S2NDate, raw.Data.S2N, now),
+		South: parseConnectMinutes(raw.Data.N2SDate, raw.Data.N2S, now),
+	}, nil
+}
+
+// parseConnectMinutes parses lines of "9:30,沪净流入,沪余额,深净流入,深余额,合计"
+// in 10 thousand yuan. Minutes not traded yet are "-" and skipped.
+func parseConnectMinutes(date string, lines []string, now time.Time) []*ConnectMinute {
+	day, err := time.ParseInLocation("01-02", date, time.Local)
+	if err != nil {
+		day = now
+	} else {
+		day = time.Date(now.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+		// 跨年时 1 月初看到的是上一年 12 月的数据
+		if day.After(now.AddDate(0, 0, 1)) {
+			day = day.AddDate(-1, 0, 0)
+		}
+	}
+
+	res := make([]*ConnectMinute, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Split(line, ",")
+		if len(fields) < 6 {
+			continue
+		}
+		hm, err := time.Parse("15:04", fields[0])
+		if err != nil {
+			continue
+		}
+		sh, err1 := strconv.ParseFloat(fields[1], 64)
+		sz, err2 := strconv.ParseFloat(fields[3], 64)
+		total, err3 := strconv.ParseFloat(fields[5], 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		res = append(res, &ConnectMinute{
+			Time:     time.Date(day.Year(), day.Month(), day.Day(), hm.Hour(), hm.Minute(), 0, 0, time.Local),
+			Shanghai: sh * 1e4,
+			Shenzhen: sz * 1e4,
+			Total:    total * 1e4,
+		})
+	}
+	return res
+}
+
+// NorthHolding 北向资金个股持股
+type NorthHolding struct {
+	Date       time.Time
+	Code       string
+	Name       string
+	Close      float64 // 当日收盘价
+	ChangeRate float64 // 当日涨跌幅，百分比
+	Shares     float64 // 持股数量，单位股
+	MarketCap  float64 // 持股市值，单位元
+	Ratio      float64 // 持股占 A 股百分比
+}
+
+// QueryNorthHoldings returns the latest limit northbound holding records of
+// the A-share code, newest first.
+func QueryNorthHoldings(ctx context.Context, code string, limit int) ([]*NorthHolding, error) {
+	rows, _, err := queryReport(ctx, &reportReq{
+		ReportName:  "RPT_MUTUAL_HOLDSTOCKNORTH_STA",
+		Filter:      fmt.Sprintf(`(SECURITY_CODE="%s")`, code),
+		SortColumns: "TRADE_DATE",
+		SortTypes:   "-1",
+		PageSize:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*NorthHolding, 0, len(rows))
+	for _, m := range rows {
+		name := reportString(m, "SECURITY_NAME")
+		if name == "" {
+			name = reportString(m, "SECURITY_NAME_ABBR")
+		}
+		res = append(res, &NorthHolding{
+			Date:       reportDate(m, "TRADE_DATE"),
+			Code:       reportString(m, "SECURITY_CODE"),
+			Name:       name,
+			Close:      reportFloat(m, "CLOSE_PRICE"),
+			ChangeRate: reportFloat(m, "CHANGE_RATE"),
+			Shares:     reportFloat(m, "HOLD_SHARES"),
+			MarketCap:  reportFloat(m, "HOLD_MARKET_CAP"),
+			Ratio:      reportFloat(m, "A_SHARES_RATIO"),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,103 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConnectMinutes(t *testing.T) {
+	now := time.Date(2026, 10, 16, 14, 0, 0, 0, time.Local)
+	mins := parseConnectMinutes("10-16", []string{
+		"9:30,1000.5,5200000,-200,5200000,800.5",
+		"9:31,2000,5200000,300,5200000,2300",
+		"9:32,-,-,-,-,-",
+		"bad",
+	}, now)
+	require.Len(t, mins, 2)
+	require.Equal(t, time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local), mins[0].Time)
+	require.InDelta(t, 1000.5e4, mins[0].Shanghai, 1e-6)
+	require.InDelta(t, -200e4, mins[0].Shenzhen, 1e-6)
+	require.InDelta(t, 2300e4, mins[1].Total, 1e-6)
+
+	// 1 月初看到的是上一年 12 月 31 日的数据
+	mins = parseConnectMinutes("12-31", []string{"15:00,1,0,1,0,2"}, time.Date(2027, 1, 1, 9, 0, 0, 0, time.Local))
+	require.Equal(t, 2026, mins[0].Time.Year())
+}
+
+func TestQueryConnectIntraday(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"rc":0,"data":{"s2nDate":"10-16","n2sDate":"10-16","s2n":["9:30,1,0,2,0,3"],"n2s":["9:30,4,0,5,0,9","9:31,-,-,-,-,-"]}}`)
+	}))
+	defer srv.Close()
+	origURL := connectMinURL
+	connectMinURL = srv.URL
+	defer func() { connectMinURL = origURL }()
+
+	data, err := QueryConnectIntraday(context.Background())
+	require.NoError(t, err)
+	require.Len(t, data.North, 1)
+	require.Len(t, data.South, 1)
+	require.InDelta(t, 9e4, data.South[0].Total, 1e-6)
+}
+
+func TestQueryConnectHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		require.Equal(t, "RPT_MUTUAL_DEAL_HISTORY", q.Get("reportName"))
+		require.True(t, strings.HasPrefix(q.Get("filter"), `(MUTUAL_TYPE="005")`))
+		if q.Get("pageNumber") == "1" {
+			fmt.Fprint(w, `{"success":true,"result":{"pages":2,"count":3,"data":[
+				{"TRADE_DATE":"2026-10-16 00:00:00","NET_DEAL_AMT":1234.5,"BUY_AMT":60000,"SELL_AMT":58765.5,"ACCUM_DEAL_AMT":2000000,"LEAD_STOCKS_NAME":"招商银行"},
+				{"TRADE_DATE":"2026-10-15 00:00:00","NET_DEAL_AMT":-100}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"result":{"pages":2,"count":3,"data":[{"TRADE_DATE":"2026-10-14 00:00:00","NET_DEAL_AMT":50}]}}`)
+	}))
+	defer srv.Close()
+	origURL := datacenterURL
+	datacenterURL = srv.URL
+	defer func() { datacenterURL = origURL }()
+
+	flows, err := QueryConnectHistory(context.Background(), ChannelNorth, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Len(t, flows, 3)
+	require.Equal(t, "2026-10-14", flows[0].Date.Format(time.DateOnly))
+	require.Equal(t, "2026-10-16", flows[2].Date.Format(time.DateOnly))
+	require.InDelta(t, 1234.5e6, flows[2].NetBuy, 1e-3)
+	require.Equal(t, "招商银行", flows[2].LeadName)
+	require.Equal(t, ChannelNorth, flows[0].Channel)
+}
+
+func TestQueryNorthHoldings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("filter"), "000000") {
+			// 无数据
+			fmt.Fprint(w, `{"success":false,"message":"返回数据为空","result":null}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"result":{"pages":1,"data":[{"TRADE_DATE":"2026-09-30 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME":"招商银行","CLOSE_PRICE":40.1,"CHANGE_RATE":-1.2,"HOLD_SHARES":1.5e9,"HOLD_MARKET_CAP":6.0e10,"A_SHARES_RATIO":7.3}]}}`)
+	}))
+	defer srv.Close()
+	origURL := datacenterURL
+	datacenterURL = srv.URL
+	defer func() { datacenterURL = origURL }()
+
+	holdings, err := QueryNorthHoldings(context.Background(), "600036", 20)
+	require.NoError(t, err)
+	require.Len(t, holdings, 1)
+	require.Equal(t, "招商银行", holdings[0].Name)
+	require.Equal(t, 7.3, holdings[0].Ratio)
+
+	holdings, err = QueryNorthHoldings(context.Background(), "000000", 20)
+	require.NoError(t, err)
+	require.Empty(t, holdings)
+
+	require.Equal(t, "深股通", ChannelShenzhen.String())
+}
@@ -0,0 +1,149 @@
+package eastmoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/utils"
+)
+
+// datacenterURL 数据中心报表接口
+var datacenterURL = datacenterAPIBase
+
+// reportReq 东方财富数据中心报表查询参数
+type reportReq struct {
+	ReportName  string
+	Columns     string // 为空时查询全部字段
+	Filter      string // 形如 (SECURITY_CODE="600036")(TRADE_DATE>='2026-01-01')
+	SortColumns string
+	SortTypes   string // -1 倒序，1 正序；多列以逗号分隔
+	PageSize    int
+	PageNumber  int
+}
+
+// queryReport fetches one page of a datacenter report. It returns the rows and
+// the total number of pages; a report with no matching rows is not an error.
+func queryReport(ctx context.Context, req *reportReq) ([]map[string]interface{}, int, error) {
+	columns := req.Columns
+	if columns == "" {
+		columns = "ALL"
+	}
+	pageSize, pageNumber := req.PageSize, req.PageNumber
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageNumber <= 0 {
+		pageNumber = 1
+	}
+
+	params := url.Values{}
+	params.Set("reportName", req.ReportName)
+	params.Set("columns", columns)
+	if req.Filter != "" {
+		params.Set("filter", req.Filter)
+	}
+	if req.SortColumns != "" {
+		params.Set("sortColumns", req.SortColumns)
+		params.Set("sortTypes", req.SortTypes)
+	}
+	params.Set("pageSize", strconv.Itoa(pageSize))
+	params.Set("pageNumber", strconv.Itoa(pageNumber))
+	params.Set("source", "WEB")
+	params.Set("client", "WEB")
+
+	reqURL := fmt.Sprintf("%s?%s", datacenterURL, params.Encode())
+	slog.DebugContext(ctx, "queryReport", "reqURL", reqURL)
+	resp, err := utils.MakeRequest(ctx, http.MethodGet, reqURL, nil, nil, 0)
+	if err != nil {
+		return nil, 0, fmt.Errorf("eastMoney report %s: %w", req.ReportName, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	jsonData, err := stripJSONP(body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("strip JSONP: %w", err)
+	}
+	var apiResp finReportResponse
+	if err := json.Unmarshal(jsonData, &apiResp); err != nil {
+		return nil, 0, fmt.Errorf("eastMoney report %s parse: %w", req.ReportName, err)
+	}
+	if apiResp.Result == nil {
+		// 无数据时 success 为 false，result 为 null
+		return nil, 0, nil
+	}
+	if !apiResp.Success {
+		return nil, 0, fmt.Errorf("eastMoney report %s: %s", req.ReportName, apiResp.Message)
+	}
+	return apiResp.Result.Data, apiResp.Result.Pages, nil
+}
+
+// maxReportPages 限制 queryReportAll 的翻页次数，防止过宽的筛选条件拉取过多数据
+const maxReportPages = 50
+
+// queryReportAll fetches all pages of a datacenter report starting at
+// req.PageNumber. It fails rather than returning part of the rows when the
+// report has more than maxReportPages pages left.
+func queryReportAll(ctx context.Context, req *reportReq) ([]map[string]interface{}, error) {
+	r := *req
+	if r.PageNumber <= 0 {
+		r.PageNumber = 1
+	}
+	var res []map[string]interface{}
+	for i := 1; ; i++ {
+		rows, pages, err := queryReport(ctx, &r)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rows...)
+		if r.PageNumber >= pages || len(rows) == 0 {
+			return res, nil
+		}
+		if i >= maxReportPages {
+			return nil, fmt.Errorf("eastMoney report %s: more than %d pages, narrow the query", req.ReportName, maxReportPages)
+		}
+		r.PageNumber++
+	}
+}
+
+// reportDate parses a datacenter date such as "2026-10-16 00:00:00".
+func reportDate(m map[string]interface{}, key string) time.Time {
+	s, _ := m[key].(string)
+	if len(s) >= len(time.DateOnly) {
+		s = s[:len(time.DateOnly)]
+	}
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// reportFloat returns a numeric field of a datacenter row, 0 when missing.
+func reportFloat(m map[string]interface{}, key string) float64 {
+	switch v := m[key].(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f
+	}
+	return 0
+}
+
+// reportString returns a string field of a datacenter row.
+func reportString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return strings.TrimSpace(s)
+}
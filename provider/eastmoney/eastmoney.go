@@ -19,10 +19,14 @@ import (
 // 东方财富接口封装
 
 const (
-	MarketTypeSzSe   MarketType = 0   // 深圳证券交易所
-	MarketTypeSse    MarketType = 1   // 上海证券交易所
+	MarketTypeSzSe   MarketType = 0   // 深圳证券交易所，深证系列指数也在此市场
+	MarketTypeSse    MarketType = 1   // 上海证券交易所，上证、中证系列指数也在此市场
+	MarketTypeCSI    MarketType = 2   // 中证指数公司独有的指数，如中证 A50
 	MarketTypeNasdaq MarketType = 105 // 纳斯达克交易所
 	MarketTypeHK     MarketType = 116 // 香港证券交易所
+	MarketTypeFxSpot MarketType = 119 // 在岸人民币即期汇率，如美元兑人民币 USDCNY
+	MarketTypeMetal  MarketType = 122 // 国际贵金属现货，如伦敦金 XAU
+	MarketTypeForex  MarketType = 133 // 外汇，如美元兑离岸人民币 USDCNH
 
 	EastMoney80Push2ApiBase             = "http://80.push2.eastmoney.com"
 	EastMoneyPush2ApiBase               = "http://push2.eastmoney.com"
@@ -54,7 +58,7 @@ func getOriginQuoteHistory(ctx context.Context, req *GetQuoteHistoryReq) (res *Q
 	values.Add("fields1", "f1,f2,f3,f4,f5,f6,f7,f8,f9,f10,f11,f12,f13")
 	values.Add("fields2", "f51,f52,f53,f54,f55,f56,f57,f58,f59,f60,f61")
 	values.Add("rtntype", "6")
-	values.Add("klt", "101")
+	values.Add("klt", strconv.Itoa(req.Period.KLT()))
 	switch req.FQT {
 	case QuoteFQTDefault:
 		values.Add("fqt", "0")
@@ -91,6 +95,10 @@ func GetQuoteHistory(ctx context.Context, req *GetQuoteHistoryReq) (res []*Quote
 		return
 	}
 
+	if req.Period == PeriodTimeShare {
+		return getTimeShare(ctx, req)
+	}
+
 	tmpRes, err := getOriginQuoteHistory(ctx, req)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed getOriginQuoteHistory", "error", err)
@@ -140,8 +148,9 @@ func parseQuoteHistoryResp(resp *QuoteHistoryResp) ([]*Quote, error) {
 	return res, nil
 }
 
-// parseKlineItem 解析单条 k 线数据
+// parseKlineItem 解析单条 k 线数据，分钟 K 线的日期带有时间
 // "2024-12-26,39.40,39.48,39.54,39.01,539252,2125139425.00,1.35,0.20,0.08,0.26"
+// "2024-12-26 14:30,39.40,39.48,39.54,39.01,539252,2125139425.00,1.35,0.20,0.08,0.26"
 func parseKlineItem(line string) (kline KLineQuote, err error) {
 	toks := strings.Split(line, ",")
 	if len(toks) != 11 {
@@ -150,7 +159,7 @@ func parseKlineItem(line string) (kline KLineQuote, err error) {
 		return
 	}
 
-	kline.Date, err = time.Parse(utils.LayoutYYMMDD, toks[0])
+	kline.Date, err = parseKlineTime(toks[0])
 	if err != nil {
 		return
 	}
@@ -197,3 +206,107 @@ func parseKlineItem(line string) (kline KLineQuote, err error) {
 	kline.Velocity, err = strconv.ParseFloat(toks[10], 64)
 	return
 }
+
+func parseKlineTime(s string) (time.Time, error) {
+	if len(s) > len(utils.LayoutYYMMDD) {
+		return time.Parse(TimeMinute, s)
+	}
+	return time.Parse(utils.LayoutYYMMDD, s)
+}
+
+// getTimeShare 获取当日分时数据，每分钟一条，Begin 和 End 不生效
+func getTimeShare(ctx context.Context, req *GetQuoteHistoryReq) ([]*Quote, error) {
+	values := url.Values{}
+	values.Add("secid", fmt.Sprintf("%d.%s", req.MarketCode, req.Code))
+	values.Add("fields1", "f1,f2,f3,f4,f5,f6,f7,f8,f9,f10,f11,f12,f13")
+	values.Add("fields2", "f51,f52,f53,f54,f55,f56,f57,f58")
+	values.Add("iscr", "0")
+	values.Add("ndays", "1")
+
+	reqURL := fmt.Sprintf("%s/api/qt/stock/trends2/get?%s", EastMoneyPush2HisApiBase, values.Encode())
+	resp, err := utils.MakeRequest(ctx, http.MethodGet, reqURL, nil, nil, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed request", "url", reqURL, "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed read body", "error", err)
+		return nil, err
+	}
+
+	var res *TimeShareResp
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+	return parseTimeShareResp(res)
+}
+
+// parseTimeShareResp 解析分时数据，涨跌幅相对昨收计算
+func parseTimeShareResp(resp *TimeShareResp) ([]*Quote, error) {
+	if resp == nil || resp.Data == nil {
+		return nil, errors.New("nil data")
+	}
+
+	data := resp.Data
+	res := make([]*Quote, 0, len(data.Trends))
+	for _, line := range data.Trends {
+		item, err := parseTrendItem(line)
+		if err != nil {
+			slog.Error("parseTimeShareResp failed parse trend", "trend", line, "error", err)
+			continue
+		}
+		item.Code = data.Code
+		item.Name = data.Name
+		item.Market = data.Market
+		if data.PreClose > 0 {
+			item.Change = item.Close - data.PreClose
+			item.ChangeRate = item.Change / data.PreClose * 100
+		}
+		if data.Market == MarketTypeSse || data.Market == MarketTypeSzSe {
+			item.Volume *= 100
+		}
+		res = append(res, item)
+	}
+
+	return res, nil
+}
+
+// parseTrendItem 解析单条分时数据：时间,开盘,最新价,最高,最低,成交量,成交额,均价
+// "2024-12-26 09:31,39.40,39.45,39.48,39.38,12345,48662310.00,39.420"
+func parseTrendItem(line string) (*Quote, error) {
+	toks := strings.Split(line, ",")
+	if len(toks) < 7 {
+		return nil, ErrInvalidKLine
+	}
+
+	var (
+		q   = new(Quote)
+		err error
+	)
+	if q.Date, err = time.Parse(TimeMinute, toks[0]); err != nil {
+		return nil, err
+	}
+	prices := []*float64{&q.Open, &q.Close, &q.High, &q.Low}
+	for i, p := range prices {
+		if *p, err = strconv.ParseFloat(toks[i+1], 64); err != nil {
+			return nil, err
+		}
+	}
+	if q.Volume, err = strconv.ParseInt(toks[5], 10, 64); err != nil {
+		return nil, err
+	}
+	if q.TurnOver, err = strconv.ParseFloat(toks[6], 64); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Provider 东方财富数据源，实现 provider.HistorySource
+type Provider struct{}
+
+// GetQuoteHistory 获取标准证券历史行情信息
+func (Provider) GetQuoteHistory(ctx context.Context, req *GetQuoteHistoryReq) ([]*Quote, error) {
+	return GetQuoteHistory(ctx, req)
+}
@@ -18,6 +18,18 @@ func TestMarketType_String(t *testing.T) {
 	require.Equal(t, "SH", m2.String())
 	require.Equal(t, "unknown 3", m3.String())
 	require.Equal(t, "$", m4.String())
+	require.Equal(t, "CSI", MarketTypeCSI.String())
+}
+
+func TestMarketOf(t *testing.T) {
+	m, ok := MarketOf("SH600036")
+	require.True(t, ok)
+	require.Equal(t, MarketTypeSse, m)
+	m, ok = MarketOf("SZ000001")
+	require.True(t, ok)
+	require.Equal(t, MarketTypeSzSe, m)
+	_, ok = MarketOf("HK00700")
+	require.False(t, ok)
 }
 
 func TestGetOriginQuoteHistory(t *testing.T) {
@@ -55,6 +67,11 @@ func TestParseKlineItem(t *testing.T) {
 	require.EqualValues(t, 0.08, res.Change)
 	require.EqualValues(t, 0.20, res.ChangeRate)
 	require.EqualValues(t, 0.26, res.Velocity)
+
+	// 3. minute bar
+	res, err = parseKlineItem("2024-12-26 14:30,39.40,39.48,39.54,39.01,5392,21251394.00,1.35,0.20,0.08,0.26")
+	require.Nil(t, err)
+	require.Equal(t, "2024-12-26 14:30", res.Date.Format(TimeMinute))
 }
 
 func TestParseQuoteHistoryResp(t *testing.T) {
@@ -126,3 +143,38 @@ func TestParseQuoteHistoryResp(t *testing.T) {
 	require.NotNil(t, res)
 	require.EqualValues(t, 0, len(res))
 }
+
+func TestParseTimeShareResp(t *testing.T) {
+	_, err := parseTimeShareResp(nil)
+	require.NotNil(t, err)
+
+	rawJson := `{
+    "rc": 0,
+    "rt": 10,
+    "svr": 181669437,
+    "data": {
+        "code": "600036",
+        "market": 1,
+        "name": "招商银行",
+        "decimal": 2,
+        "preClose": 39.40,
+        "trendsTotal": 241,
+        "trends": [
+            "2024-12-26 09:30,39.40,39.40,39.40,39.40,4444,17509360.00,39.400",
+            "2024-12-26 09:31,39.40,39.79,39.80,39.38,12345,48662310.00,39.420",
+            "bad"
+        ]
+    }
+}`
+	var resp TimeShareResp
+	require.Nil(t, json.Unmarshal([]byte(rawJson), &resp))
+	res, err := parseTimeShareResp(&resp)
+	require.Nil(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, "2024-12-26 09:31", res[1].Date.Format(TimeMinute))
+	require.EqualValues(t, 39.79, res[1].Close)
+	require.EqualValues(t, 1234500, res[1].Volume)
+	require.InDelta(t, 0.39, res[1].Change, 1e-9)
+	require.InDelta(t, 0.9898, res[1].ChangeRate, 1e-4)
+	require.Equal(t, "招商银行", res[0].Name)
+}
@@ -0,0 +1,133 @@
+package eastmoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 个股资金流向：当日分时累计净流入（push2 fflow/kline）与历史每日净流入（push2his fflow/daykline），
+// 按成交单大小拆分为超大单、大单、中单、小单，主力 = 超大单 + 大单。
+// 字段语义参考 AKShare stock_individual_fund_flow。
+
+var (
+	flowMinURL = EastMoneyPush2ApiBase + "/api/qt/stock/fflow/kline/get"
+	flowDayURL = EastMoneyPush2HisApiBase + "/api/qt/stock/fflow/daykline/get"
+)
+
+// FundFlow 资金净流入，单位元，负数为净流出
+type FundFlow struct {
+	Time   time.Time // 分时为分钟，历史为交易日
+	Main   float64   // 主力净流入
+	Super  float64   // 超大单净流入
+	Large  float64   // 大单净流入
+	Medium float64   // 中单净流入
+	Small  float64   // 小单净流入
+
+	// 以下仅历史数据有
+	MainRatio  float64 // 主力净占比，百分比
+	Close      float64 // 收盘价
+	ChangeRate float64 // 涨跌幅，百分比
+}
+
+// flowResp fflow 接口返回结构
+type flowResp struct {
+	Rc   int `json:"rc"`
+	Data *struct {
+		Code   string   `json:"code"`
+		Market int      `json:"market"`
+		Name   string   `json:"name"`
+		Klines []string `json:"klines"`
+	} `json:"data"`
+}
+
+// QueryFundFlowIntraday returns the cumulative minute net inflows of the
+// latest trading day of the security.
+func QueryFundFlowIntraday(ctx context.Context, market MarketType, code string) ([]*FundFlow, error) {
+	v := url.Values{}
+	v.Set("lmt", "0")
+	v.Set("klt", "1")
+	v.Set("secid", fmt.Sprintf("%d.%s", market, code))
+	v.Set("fields1", "f1,f2,f3,f7")
+	v.Set("fields2", "f51,f52,f53,f54,f55,f56,f57")
+	v.Set("ut", "b2884a393a59ad64002292a3e90d46a5")
+
+	lines, err := queryFundFlow(ctx, flowMinURL+"?"+v.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney fund flow intraday %s: %w", code, err)
+	}
+	return parseFundFlows(lines, "2006-01-02 15:04"), nil
+}
+
+// QueryFundFlowHistory returns the daily net inflows of the security in
+// ascending date order. East Money keeps about the last 120 trading days.
+func QueryFundFlowHistory(ctx context.Context, market MarketType, code string) ([]*FundFlow, error) {
+	v := url.Values{}
+	v.Set("lmt", "0")
+	v.Set("klt", "101")
+	v.Set("secid", fmt.Sprintf("%d.%s", market, code))
+	v.Set("fields1", "f1,f2,f3,f7")
+	v.Set("fields2", "f51,f52,f53,f54,f55,f56,f57,f58,f59,f60,f61,f62,f63,f64,f65")
+	v.Set("ut", "b2884a393a59ad64002292a3e90d46a5")
+
+	lines, err := queryFundFlow(ctx, flowDayURL+"?"+v.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney fund flow history %s: %w", code, err)
+	}
+	return parseFundFlows(lines, time.DateOnly), nil
+}
+
+func queryFundFlow(ctx context.Context, reqURL string) ([]string, error) {
+	body, err := getPush2(ctx, reqURL, "http://data.eastmoney.com/zjlx/")
+	if err != nil {
+		return nil, err
+	}
+	var raw flowResp
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	if raw.Data == nil {
+		// 代码不存在或不支持资金流向
+		return nil, nil
+	}
+	return raw.Data.Klines, nil
+}
+
+// parseFundFlows parses lines of "时间,主力,小单,中单,大单,超大单[,主力占比,小单占比,
+// 中单占比,大单占比,超大单占比,收盘价,涨跌幅,...]". Malformed lines are skipped.
+func parseFundFlows(lines []string, layout string) []*FundFlow {
+	res := make([]*FundFlow, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Split(line, ",")
+		if len(fields) < 6 {
+			continue
+		}
+		t, err := time.ParseInLocation(layout, fields[0], time.Local)
+		if err != nil {
+			continue
+		}
+		num := func(i int) float64 {
+			if i >= len(fields) {
+				return 0
+			}
+			f, _ := strconv.ParseFloat(fields[i], 64)
+			return f
+		}
+		res = append(res, &FundFlow{
+			Time:       t,
+			Main:       num(1),
+			Small:      num(2),
+			Medium:     num(3),
+			Large:      num(4),
+			Super:      num(5),
+			MainRatio:  num(6),
+			Close:      num(11),
+			ChangeRate: num(12),
+		})
+	}
+	return res
+}
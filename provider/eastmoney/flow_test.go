@@ -0,0 +1,69 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFundFlow(t *testing.T) {
+	var secid string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secid = r.URL.Query().Get("secid")
+		switch r.URL.Query().Get("klt") {
+		case "1":
+			fmt.Fprint(w, `{"rc":0,"data":{"code":"600036","market":1,"name":"招商银行","klines":[
+				"2026-10-16 09:31,-1200000.0,500000.0,700000.0,-200000.0,-1000000.0",
+				"2026-10-16 09:32,3000000.0,-1000000.0,-2000000.0,1000000.0,2000000.0",
+				"bad"]}}`)
+		case "101":
+			fmt.Fprint(w, `{"rc":0,"data":{"code":"600036","market":1,"name":"招商银行","klines":[
+				"2026-10-15,-350000000.0,200000000.0,150000000.0,-50000000.0,-300000000.0,-8.5,4.8,3.7,-1.2,-7.3,41.20,-1.03,0.00,0.00",
+				"2026-10-16,120000000.0,-80000000.0,-40000000.0,20000000.0,100000000.0,3.1,-2.0,-1.1,0.5,2.6,41.80,1.46,0.00,0.00"]}}`)
+		}
+	}))
+	defer srv.Close()
+	origMin, origDay := flowMinURL, flowDayURL
+	flowMinURL, flowDayURL = srv.URL, srv.URL
+	defer func() { flowMinURL, flowDayURL = origMin, origDay }()
+
+	minutes, err := QueryFundFlowIntraday(context.Background(), MarketTypeSse, "600036")
+	require.NoError(t, err)
+	require.Equal(t, "1.600036", secid)
+	require.Len(t, minutes, 2)
+	require.Equal(t, time.Date(2026, 10, 16, 9, 32, 0, 0, time.Local), minutes[1].Time)
+	require.Equal(t, 3e6, minutes[1].Main)
+	require.Equal(t, 2e6, minutes[1].Super)
+	require.Equal(t, 1e6, minutes[1].Large)
+	require.Equal(t, -2e6, minutes[1].Medium)
+	require.Equal(t, -1e6, minutes[1].Small)
+
+	days, err := QueryFundFlowHistory(context.Background(), MarketTypeSzSe, "000001")
+	require.NoError(t, err)
+	require.Equal(t, "0.000001", secid)
+	require.Len(t, days, 2)
+	require.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.Local), days[0].Time)
+	require.Equal(t, -3.5e8, days[0].Main)
+	require.Equal(t, -8.5, days[0].MainRatio)
+	require.Equal(t, 41.8, days[1].Close)
+	require.Equal(t, 1.46, days[1].ChangeRate)
+}
+
+func TestQueryFundFlowNoData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"rc":0,"data":null}`)
+	}))
+	defer srv.Close()
+	origDay := flowDayURL
+	flowDayURL = srv.URL
+	defer func() { flowDayURL = origDay }()
+
+	days, err := QueryFundFlowHistory(context.Background(), MarketTypeSse, "999999")
+	require.NoError(t, err)
+	require.Empty(t, days)
+}
@@ -0,0 +1,292 @@
+package eastmoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 公募基金：历史净值来自天天基金 f10/lsjz，基本资料、持仓和资产配置来自天天基金移动端接口
+// fundmobapi（FundMNDetailInformation / FundMNInverstPosition / FundMNAssetAllocationNew）。
+
+var (
+	fundNAVURL    = "https://api.fund.eastmoney.com/f10/lsjz"
+	fundMobileURL = "https://fundmobapi.eastmoney.com/FundMNewApi"
+)
+
+// FundNAV 基金单日净值
+type FundNAV struct {
+	Date       time.Time
+	NAV        float64 // 单位净值
+	AccNAV     float64 // 累计净值
+	ChangeRate float64 // 日增长率，百分比
+}
+
+// FundInfo 基金基本资料，费率为带百分号的原始字符串
+type FundInfo struct {
+	Code        string
+	Name        string
+	Type        string  // 基金类型，如"指数型-股票"
+	Company     string  // 基金公司
+	Manager     string  // 基金经理，多人以空格分隔
+	Established string  // 成立日期
+	Scale       float64 // 资产规模，单位元
+	Benchmark   string  // 业绩比较基准
+	ManageFee   string  // 管理费率
+	CustodyFee  string  // 托管费率
+	SaleFee     string  // 销售服务费率
+	PurchaseFee string  // 当前申购费率
+	RiskLevel   string  // 风险等级
+}
+
+// FundHolding 基金季报重仓股
+type FundHolding struct {
+	Code   string
+	Name   string
+	Ratio  float64 // 占净值比例，百分比
+	Change string  // 较上期变动，如"新增"、"增持"、"减持"
+}
+
+// FundAllocation 基金资产配置，比例均为占净值百分比
+type FundAllocation struct {
+	Date     time.Time
+	Stock    float64
+	Bond     float64
+	Cash     float64
+	Other    float64
+	NetAsset float64 // 净资产，单位元
+}
+
+// fundNAVResp lsjz 返回结构，数值均为字符串
+type fundNAVResp struct {
+	Data *struct {
+		LSJZList []struct {
+			FSRQ  string `json:"FSRQ"`  // 净值日期
+			DWJZ  string `json:"DWJZ"`  // 单位净值
+			LJJZ  string `json:"LJJZ"`  // 累计净值
+			JZZZL string `json:"JZZZL"` // 日增长率
+		} `json:"LSJZList"`
+	} `json:"Data"`
+	ErrCode    int    `json:"ErrCode"`
+	ErrMsg     string `json:"ErrMsg"`
+	TotalCount int    `json:"TotalCount"`
+}
+
+// fundMobileResp fundmobapi 通用返回结构
+type fundMobileResp struct {
+	Datas     json.RawMessage `json:"Datas"`
+	ErrCode   int             `json:"ErrCode"`
+	ErrMsg    string          `json:"ErrMsg"`
+	Expansion json.RawMessage `json:"Expansion"`
+}
+
+// fundNum parses a numeric string of the fund APIs, 0 for "" or "--".
+func fundNum(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	return f
+}
+
+// QueryFundNAV returns the latest limit daily NAVs of the fund, newest first.
+func QueryFundNAV(ctx context.Context, code string, limit int) ([]*FundNAV, error) {
+	const pageSize = 20 // 接口单页最多 20 条
+	res := make([]*FundNAV, 0, limit)
+	for page := 1; len(res) < limit; page++ {
+		v := url.Values{}
+		v.Set("fundCode", code)
+		v.Set("pageIndex", strconv.Itoa(page))
+		v.Set("pageSize", strconv.Itoa(pageSize))
+		body, err := getPush2(ctx, fundNAVURL+"?"+v.Encode(), "http://fundf10.eastmoney.com/")
+		if err != nil {
+			return nil, fmt.Errorf("eastMoney fund %s nav: %w", code, err)
+		}
+		var raw fundNAVResp
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, fmt.Errorf("eastMoney fund %s nav parse: %w", code, err)
+		}
+		if raw.ErrCode != 0 {
+			return nil, fmt.Errorf("eastMoney fund %s nav: %s", code, raw.ErrMsg)
+		}
+		if raw.Data == nil || len(raw.Data.LSJZList) == 0 {
+			break
+		}
+		for _, item := range raw.Data.LSJZList {
+			date, err := time.ParseInLocation(time.DateOnly, item.FSRQ, time.Local)
+			if err != nil {
+				continue
+			}
+			res = append(res, &FundNAV{
+				Date:       date,
+				NAV:        fundNum(item.DWJZ),
+				AccNAV:     fundNum(item.LJJZ),
+				ChangeRate: fundNum(item.JZZZL),
+			})
+		}
+		if page*pageSize >= raw.TotalCount {
+			break
+		}
+	}
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// queryFundMobile calls a fundmobapi endpoint and returns its Datas and
+// Expansion.
+func queryFundMobile(ctx context.Context, api, code string, extra url.Values) (json.RawMessage, json.RawMessage, error) {
+	v := url.Values{}
+	v.Set("FCODE", code)
+	v.Set("deviceid", "Wap")
+	v.Set("plat", "Wap")
+	v.Set("product", "EFund")
+	v.Set("version", "2.0.0")
+	for k := range extra {
+		v.Set(k, extra.Get(k))
+	}
+	body, err := getPush2(ctx, fundMobileURL+"/"+api+"?"+v.Encode(), "http://fund.eastmoney.com/")
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw fundMobileResp
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, nil, fmt.Errorf("parse: %w", err)
+	}
+	if raw.ErrCode != 0 {
+		return nil, nil, fmt.Errorf("%s", raw.ErrMsg)
+	}
+	return raw.Datas, raw.Expansion, nil
+}
+
+// QueryFundInfo returns the profile, manager and fees of the fund.
+func QueryFundInfo(ctx context.Context, code string) (*FundInfo, error) {
+	datas, _, err := queryFundMobile(ctx, "FundMNDetailInformation", code, nil)
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney fund %s info: %w", code, err)
+	}
+	var d struct {
+		FCODE      string `json:"FCODE"`
+		SHORTNAME  string `json:"SHORTNAME"`
+		FTYPE      string `json:"FTYPE"`
+		JJGS       string `json:"JJGS"`
+		JJJL       string `json:"JJJL"`
+		ESTABDATE  string `json:"ESTABDATE"`
+		ENDNAV     string `json:"ENDNAV"`
+		BENCH      string `json:"BENCH"`
+		MGREXP     string `json:"MGREXP"`
+		TRUSTEXP   string `json:"TRUSTEXP"`
+		SALESEXP   string `json:"SALESEXP"`
+		RATE       string `json:"RATE"`
+		RISKLEVEL  string `json:"RISKLEVEL"`
+		SOURCERATE string `json:"SOURCERATE"`
+	}
+	if len(datas) == 0 || string(datas) == "null" {
+		return nil, fmt.Errorf("eastMoney fund %s info: not found", code)
+	}
+	if err := json.Unmarshal(datas, &d); err != nil {
+		return nil, fmt.Errorf("eastMoney fund %s info parse: %w", code, err)
+	}
+	purchase := d.RATE
+	if purchase == "" {
+		purchase = d.SOURCERATE
+	}
+	return &FundInfo{
+		Code:        d.FCODE,
+		Name:        d.SHORTNAME,
+		Type:        d.FTYPE,
+		Company:     d.JJGS,
+		Manager:     d.JJJL,
+		Established: d.ESTABDATE,
+		Scale:       fundNum(d.ENDNAV),
+		Benchmark:   d.BENCH,
+		ManageFee:   d.MGREXP,
+		CustodyFee:  d.TRUSTEXP,
+		SaleFee:     d.SALESEXP,
+		PurchaseFee: purchase,
+		RiskLevel:   d.RISKLEVEL,
+	}, nil
+}
+
+// QueryFundHoldings returns the top stock holdings of the fund disclosed for
+// the report date, or for the latest report when date is zero, together with
+// the report date.
+func QueryFundHoldings(ctx context.Context, code string, date time.Time) (time.Time, []*FundHolding, error) {
+	extra := url.Values{}
+	if !date.IsZero() {
+		extra.Set("DATE", date.Format(time.DateOnly))
+	}
+	datas, expansion, err := queryFundMobile(ctx, "FundMNInverstPosition", code, extra)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("eastMoney fund %s holdings: %w", code, err)
+	}
+	var d struct {
+		FundStocks []struct {
+			GPDM         string `json:"GPDM"`
+			GPJC         string `json:"GPJC"`
+			JZBL         string `json:"JZBL"`
+			PCTNVCHGTYPE string `json:"PCTNVCHGTYPE"`
+		} `json:"fundStocks"`
+	}
+	if len(datas) > 0 && string(datas) != "null" {
+		if err := json.Unmarshal(datas, &d); err != nil {
+			return time.Time{}, nil, fmt.Errorf("eastMoney fund %s holdings parse: %w", code, err)
+		}
+	}
+	var reportDate string
+	_ = json.Unmarshal(expansion, &reportDate)
+	if t, err := time.ParseInLocation(time.DateOnly, reportDate, time.Local); err == nil {
+		date = t
+	}
+	res := make([]*FundHolding, 0, len(d.FundStocks))
+	for _, s := range d.FundStocks {
+		res = append(res, &FundHolding{
+			Code:   s.GPDM,
+			Name:   s.GPJC,
+			Ratio:  fundNum(s.JZBL),
+			Change: s.PCTNVCHGTYPE,
+		})
+	}
+	return date, res, nil
+}
+
+// QueryFundAllocation returns the asset allocation of the fund per report
+// period, newest first.
+func QueryFundAllocation(ctx context.Context, code string) ([]*FundAllocation, error) {
+	datas, _, err := queryFundMobile(ctx, "FundMNAssetAllocationNew", code, nil)
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney fund %s allocation: %w", code, err)
+	}
+	var list []struct {
+		FSRQ string `json:"FSRQ"`
+		GP   string `json:"GP"`
+		ZQ   string `json:"ZQ"`
+		HB   string `json:"HB"`
+		QT   string `json:"QT"`
+		JZC  string `json:"JZC"`
+	}
+	if len(datas) > 0 && string(datas) != "null" {
+		if err := json.Unmarshal(datas, &list); err != nil {
+			return nil, fmt.Errorf("eastMoney fund %s allocation parse: %w", code, err)
+		}
+	}
+	res := make([]*FundAllocation, 0, len(list))
+	for _, a := range list {
+		date, err := time.ParseInLocation(time.DateOnly, a.FSRQ, time.Local)
+		if err != nil {
+			continue
+		}
+		res = append(res, &FundAllocation{
+			Date:  date,
+			Stock: fundNum(a.GP),
+			Bond:  fundNum(a.ZQ),
+			Cash:  fundNum(a.HB),
+			Other: fundNum(a.QT),
+			// 净资产单位为亿元
+			NetAsset: fundNum(a.JZC) * 1e8,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,103 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFundNAV(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		require.Equal(t, "510300", q.Get("fundCode"))
+		switch q.Get("pageIndex") {
+		case "1":
+			fmt.Fprint(w, `{"Data":{"LSJZList":[
+				{"FSRQ":"2026-10-16","DWJZ":"4.1234","LJJZ":"1.8765","JZZZL":"1.02"},
+				{"FSRQ":"2026-10-15","DWJZ":"4.0818","LJJZ":"1.8580","JZZZL":"-0.35"}]},
+				"ErrCode":0,"ErrMsg":null,"TotalCount":3}`)
+		default:
+			fmt.Fprint(w, `{"Data":{"LSJZList":[
+				{"FSRQ":"2026-10-14","DWJZ":"4.0961","LJJZ":"1.8645","JZZZL":""}]},
+				"ErrCode":0,"ErrMsg":null,"TotalCount":3}`)
+		}
+	}))
+	defer srv.Close()
+	origURL := fundNAVURL
+	fundNAVURL = srv.URL
+	defer func() { fundNAVURL = origURL }()
+
+	// 单页 20 条，limit 超过 TotalCount 时在最后一页停止
+	list, err := QueryFundNAV(context.Background(), "510300", 30)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "2026-10-16", list[0].Date.Format(time.DateOnly))
+	require.Equal(t, 4.1234, list[0].NAV)
+	require.Equal(t, 1.8765, list[0].AccNAV)
+	require.Equal(t, -0.35, list[1].ChangeRate)
+
+	list, err = QueryFundNAV(context.Background(), "510300", 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+}
+
+func TestQueryFundMobile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		require.Equal(t, "014826", q.Get("FCODE"))
+		switch r.URL.Path {
+		case "/FundMNDetailInformation":
+			fmt.Fprint(w, `{"Datas":{"FCODE":"014826","SHORTNAME":"示例混合C","FTYPE":"混合型-偏股",
+				"JJGS":"示例基金","JJJL":"张三 李四","ESTABDATE":"2022-01-18","ENDNAV":"1234567890.12",
+				"BENCH":"沪深300指数收益率*80%+中债综合指数收益率*20%","MGREXP":"1.20%","TRUSTEXP":"0.20%",
+				"SALESEXP":"0.40%","RATE":"","SOURCERATE":"0.00%","RISKLEVEL":"4"},"ErrCode":0,"ErrMsg":null}`)
+		case "/FundMNInverstPosition":
+			require.Equal(t, "2026-06-30", q.Get("DATE"))
+			fmt.Fprint(w, `{"Datas":{"fundStocks":[
+				{"GPDM":"600519","GPJC":"贵州茅台","JZBL":"9.87","PCTNVCHGTYPE":"增持"},
+				{"GPDM":"300750","GPJC":"宁德时代","JZBL":"8.12","PCTNVCHGTYPE":"新增"}]},
+				"ErrCode":0,"ErrMsg":null,"Expansion":"2026-06-30"}`)
+		case "/FundMNAssetAllocationNew":
+			fmt.Fprint(w, `{"Datas":[
+				{"FSRQ":"2026-06-30","GP":"88.12","ZQ":"2.30","HB":"8.10","QT":"1.48","JZC":"12.3456"},
+				{"FSRQ":"2026-03-31","GP":"85.00","ZQ":"--","HB":"13.00","QT":"2.00","JZC":"11.0"}],
+				"ErrCode":0,"ErrMsg":null}`)
+		default:
+			fmt.Fprint(w, `{"Datas":null,"ErrCode":61101,"ErrMsg":"未知接口"}`)
+		}
+	}))
+	defer srv.Close()
+	origURL := fundMobileURL
+	fundMobileURL = srv.URL
+	defer func() { fundMobileURL = origURL }()
+
+	ctx := context.Background()
+	info, err := QueryFundInfo(ctx, "014826")
+	require.NoError(t, err)
+	require.Equal(t, "示例混合C", info.Name)
+	require.Equal(t, "张三 李四", info.Manager)
+	require.Equal(t, 1234567890.12, info.Scale)
+	require.Equal(t, "1.20%", info.ManageFee)
+	// RATE 为空时退回原始申购费率
+	require.Equal(t, "0.00%", info.PurchaseFee)
+
+	date, holdings, err := QueryFundHoldings(ctx, "014826", time.Date(2026, 6, 30, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Equal(t, "2026-06-30", date.Format(time.DateOnly))
+	require.Len(t, holdings, 2)
+	require.Equal(t, "600519", holdings[0].Code)
+	require.Equal(t, 9.87, holdings[0].Ratio)
+	require.Equal(t, "新增", holdings[1].Change)
+
+	allocs, err := QueryFundAllocation(ctx, "014826")
+	require.NoError(t, err)
+	require.Len(t, allocs, 2)
+	require.Equal(t, 88.12, allocs[0].Stock)
+	require.InDelta(t, 1234560000, allocs[0].NetAsset, 1)
+	require.Zero(t, allocs[1].Bond)
+}
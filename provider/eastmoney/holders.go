@@ -0,0 +1,105 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 股东研究：十大股东（数据中心 RPT_F10_EH_HOLDERS）、十大流通股东（RPT_F10_EH_FREEHOLDERS）
+// 以及股东户数（RPT_HOLDERNUM_DET）。字段语义参考东方财富 F10 股东研究页面和
+// AKShare stock_gdfx_top_10_em / stock_zh_a_gdhs_detail_em。
+
+// Shareholder 报告期十大股东或十大流通股东中的一名股东
+type Shareholder struct {
+	Date       time.Time // 报告期
+	Rank       int
+	Name       string
+	Type       string  // 股东性质，如"其它"、"证券投资基金"
+	SharesType string  // 股份类型，如"流通A股"
+	Shares     float64 // 持股数，单位股
+	Ratio      float64 // 占总股本或流通股本百分比
+}
+
+// HolderCount 报告期股东户数
+type HolderCount struct {
+	Date         time.Time // 截止日期
+	Count        float64   // 股东户数
+	ChangeRatio  float64   // 较上期变化，百分比
+	AvgShares    float64   // 户均持股数，单位股
+	AvgMarketCap float64   // 户均持股市值，单位元
+}
+
+// secuCode returns the datacenter SECUCODE of an A-share, e.g. 600036.SH.
+func secuCode(market MarketType, code string) (string, error) {
+	switch market {
+	case MarketTypeSse:
+		return code + ".SH", nil
+	case MarketTypeSzSe:
+		return code + ".SZ", nil
+	}
+	return "", fmt.Errorf("unsupported market %s for %s", market, code)
+}
+
+// QueryTopHolders returns the top-10 shareholders, or top-10 float
+// shareholders when float is set, of the latest periods report periods,
+// newest period first and by rank within a period.
+func QueryTopHolders(ctx context.Context, market MarketType, code string, float bool, periods int) ([]*Shareholder, error) {
+	secu, err := secuCode(market, code)
+	if err != nil {
+		return nil, err
+	}
+	report, ratioKey := "RPT_F10_EH_HOLDERS", "HOLD_NUM_RATIO"
+	if float {
+		report, ratioKey = "RPT_F10_EH_FREEHOLDERS", "FREE_HOLDNUM_RATIO"
+	}
+	rows, _, err := queryReport(ctx, &reportReq{
+		ReportName:  report,
+		Filter:      fmt.Sprintf(`(SECUCODE="%s")`, secu),
+		SortColumns: "END_DATE,HOLDER_RANK",
+		SortTypes:   "-1,1",
+		PageSize:    10 * periods,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Shareholder, 0, len(rows))
+	for _, m := range rows {
+		res = append(res, &Shareholder{
+			Date:       reportDate(m, "END_DATE"),
+			Rank:       int(reportFloat(m, "HOLDER_RANK")),
+			Name:       reportString(m, "HOLDER_NAME"),
+			Type:       reportString(m, "HOLDER_TYPE"),
+			SharesType: reportString(m, "SHARES_TYPE"),
+			Shares:     reportFloat(m, "HOLD_NUM"),
+			Ratio:      reportFloat(m, ratioKey),
+		})
+	}
+	return res, nil
+}
+
+// QueryHolderCounts returns the latest limit shareholder counts of the
+// A-share code, newest first.
+func QueryHolderCounts(ctx context.Context, code string, limit int) ([]*HolderCount, error) {
+	rows, _, err := queryReport(ctx, &reportReq{
+		ReportName:  "RPT_HOLDERNUM_DET",
+		Filter:      fmt.Sprintf(`(SECURITY_CODE="%s")`, code),
+		SortColumns: "END_DATE",
+		SortTypes:   "-1",
+		PageSize:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*HolderCount, 0, len(rows))
+	for _, m := range rows {
+		res = append(res, &HolderCount{
+			Date:         reportDate(m, "END_DATE"),
+			Count:        reportFloat(m, "HOLDER_NUM"),
+			ChangeRatio:  reportFloat(m, "HOLDER_NUM_RATIO"),
+			AvgShares:    reportFloat(m, "AVG_HOLD_NUM"),
+			AvgMarketCap: reportFloat(m, "AVG_MARKET_CAP"),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryHolders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("reportName") {
+		case "RPT_F10_EH_FREEHOLDERS":
+			require.Equal(t, `(SECUCODE="600036.SH")`, q.Get("filter"))
+			require.Equal(t, "20", q.Get("pageSize"))
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":2,"data":[
+				{"END_DATE":"2026-06-30 00:00:00","HOLDER_RANK":1,"HOLDER_NAME":"香港中央结算(代理人)有限公司","HOLDER_TYPE":"其它","SHARES_TYPE":"流通H股","HOLD_NUM":4.55e9,"FREE_HOLDNUM_RATIO":18.05},
+				{"END_DATE":"2026-03-31 00:00:00","HOLDER_RANK":1,"HOLDER_NAME":"香港中央结算(代理人)有限公司","HOLD_NUM":4.5e9,"FREE_HOLDNUM_RATIO":17.86}]}}`)
+		case "RPT_F10_EH_HOLDERS":
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":1,"data":[
+				{"END_DATE":"2026-06-30 00:00:00","HOLDER_RANK":2,"HOLDER_NAME":"招商局轮船有限公司","HOLD_NUM":3.28e9,"HOLD_NUM_RATIO":13.04}]}}`)
+		case "RPT_HOLDERNUM_DET":
+			require.Equal(t, `(SECURITY_CODE="600036")`, q.Get("filter"))
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":1,"data":[
+				{"END_DATE":"2026-06-30 00:00:00","HOLDER_NUM":512345,"HOLDER_NUM_RATIO":-3.21,"AVG_HOLD_NUM":49100.5,"AVG_MARKET_CAP":2012345.6}]}}`)
+		}
+	}))
+	defer srv.Close()
+	origURL := datacenterURL
+	datacenterURL = srv.URL
+	defer func() { datacenterURL = origURL }()
+
+	ctx := context.Background()
+	holders, err := QueryTopHolders(ctx, MarketTypeSse, "600036", true, 2)
+	require.NoError(t, err)
+	require.Len(t, holders, 2)
+	require.Equal(t, 1, holders[0].Rank)
+	require.Equal(t, 18.05, holders[0].Ratio)
+	require.Equal(t, "2026-03-31", holders[1].Date.Format("2006-01-02"))
+
+	holders, err = QueryTopHolders(ctx, MarketTypeSse, "600036", false, 1)
+	require.NoError(t, err)
+	require.Equal(t, 13.04, holders[0].Ratio)
+
+	_, err = QueryTopHolders(ctx, MarketTypeHK, "00700", false, 1)
+	require.Error(t, err)
+
+	counts, err := QueryHolderCounts(ctx, "600036", 10)
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	require.Equal(t, 512345.0, counts[0].Count)
+	require.Equal(t, -3.21, counts[0].ChangeRatio)
+}
@@ -0,0 +1,222 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 龙虎榜：上榜个股（数据中心 RPT_DAILYBILLBOARD_DETAILSNEW）、买卖营业部席位
+// （RPT_BILLBOARD_DAILYDETAILSBUY/SELL）；大宗交易每日明细（RPT_DATA_BLOCKTRADE）。
+// 字段语义参考 AKShare stock_lhb_detail_em / stock_lhb_stock_detail_em / stock_dzjy_mrmx。
+
+// LHBStock 龙虎榜上榜记录，同一只股票同一天可能因不同原因多次上榜
+type LHBStock struct {
+	Date       time.Time
+	Code       string
+	Name       string
+	Reason     string  // 上榜原因
+	Explain    string  // 解读，如"2家机构买入，成功率41.37%"
+	Close      float64 // 收盘价
+	ChangeRate float64 // 涨跌幅，百分比
+	NetBuy     float64 // 龙虎榜净买额，单位元
+	Buy        float64 // 龙虎榜买入额，单位元
+	Sell       float64 // 龙虎榜卖出额，单位元
+	Amount     float64 // 市场总成交额，单位元
+	Turnover   float64 // 换手率，百分比
+}
+
+// LHBSeat 龙虎榜营业部席位买卖
+type LHBSeat struct {
+	Date time.Time
+	Code string
+	Name string // 股票简称
+	Seat string // 营业部名称，机构席位为"机构专用"
+	Buy  float64
+	Sell float64
+	Net  float64 // 净买额，单位元
+}
+
+// BlockTrade 大宗交易成交明细
+type BlockTrade struct {
+	Date       time.Time
+	Code       string
+	Name       string
+	Close      float64 // 当日收盘价
+	ChangeRate float64 // 当日涨跌幅，百分比
+	Price      float64 // 成交价
+	Premium    float64 // 成交价相对收盘价的溢价率，百分比，负数为折价
+	Volume     float64 // 成交量，单位股
+	Amount     float64 // 成交额，单位元
+	Buyer      string  // 买方营业部
+	Seller     string  // 卖方营业部
+}
+
+// tradeDateFilter returns a datacenter filter of TRADE_DATE between begin and
+// end, and SECURITY_CODE when code is not empty.
+func tradeDateFilter(begin, end time.Time, code string) string {
+	filter := fmt.Sprintf("(TRADE_DATE>='%s')(TRADE_DATE<='%s')", begin.Format(time.DateOnly), end.Format(time.DateOnly))
+	if code != "" {
+		filter += fmt.Sprintf(`(SECURITY_CODE="%s")`, code)
+	}
+	return filter
+}
+
+// QueryLHB returns the dragon-tiger list between begin and end, optionally of
+// one stock, newest first and by net buying within a day.
+func QueryLHB(ctx context.Context, begin, end time.Time, code string) ([]*LHBStock, error) {
+	rows, err := queryReportAll(ctx, &reportReq{
+		ReportName:  "RPT_DAILYBILLBOARD_DETAILSNEW",
+		Filter:      tradeDateFilter(begin, end, code),
+		SortColumns: "TRADE_DATE,BILLBOARD_NET_AMT",
+		SortTypes:   "-1,-1",
+		PageSize:    500,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*LHBStock, 0, len(rows))
+	for _, m := range rows {
+		res = append(res, &LHBStock{
+			Date:       reportDate(m, "TRADE_DATE"),
+			Code:       reportString(m, "SECURITY_CODE"),
+			Name:       reportString(m, "SECURITY_NAME_ABBR"),
+			Reason:     reportString(m, "EXPLANATION"),
+			Explain:    reportString(m, "EXPLAIN"),
+			Close:      reportFloat(m, "CLOSE_PRICE"),
+			ChangeRate: reportFloat(m, "CHANGE_RATE"),
+			NetBuy:     reportFloat(m, "BILLBOARD_NET_AMT"),
+			Buy:        reportFloat(m, "BILLBOARD_BUY_AMT"),
+			Sell:       reportFloat(m, "BILLBOARD_SELL_AMT"),
+			Amount:     reportFloat(m, "ACCUM_AMOUNT"),
+			Turnover:   reportFloat(m, "TURNOVERRATE"),
+		})
+	}
+	return res, nil
+}
+
+// QueryLHBSeats returns the top buying and selling seats of code on date,
+// ordered by buy and sell amount respectively.
+func QueryLHBSeats(ctx context.Context, date time.Time, code string) (buys, sells []*LHBSeat, err error) {
+	filter := tradeDateFilter(date, date, code)
+	buys, err = querySeats(ctx, "RPT_BILLBOARD_DAILYDETAILSBUY", filter, "BUY")
+	if err != nil {
+		return nil, nil, err
+	}
+	sells, err = querySeats(ctx, "RPT_BILLBOARD_DAILYDETAILSSELL", filter, "SELL")
+	if err != nil {
+		return nil, nil, err
+	}
+	return buys, sells, nil
+}
+
+// seatFilter returns the datacenter filter of the trades between begin and
+// end of the seats whose name contains seat.
+func seatFilter(begin, end time.Time, seat string) string {
+	seat = strings.NewReplacer(`"`, "", "%", "").Replace(seat)
+	return tradeDateFilter(begin, end, "") + fmt.Sprintf(`(OPERATEDEPT_NAME like "%%%s%%")`, seat)
+}
+
+// QuerySeatTrades returns the dragon-tiger trades between begin and end of the
+// seats whose name contains seat, newest first.
+func QuerySeatTrades(ctx context.Context, begin, end time.Time, seat string) ([]*LHBSeat, error) {
+	filter := seatFilter(begin, end, seat)
+	buys, err := querySeats(ctx, "RPT_BILLBOARD_DAILYDETAILSBUY", filter, "BUY")
+	if err != nil {
+		return nil, err
+	}
+	sells, err := querySeats(ctx, "RPT_BILLBOARD_DAILYDETAILSSELL", filter, "SELL")
+	if err != nil {
+		return nil, err
+	}
+
+	// 同时出现在买卖前五的席位只保留一条
+	seen := make(map[string]bool)
+	var res []*LHBSeat
+	for _, s := range append(buys, sells...) {
+		if !strings.Contains(s.Seat, seat) {
+			continue
+		}
+		key := s.Date.Format(time.DateOnly) + s.Code + s.Seat
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, s)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if !res[i].Date.Equal(res[j].Date) {
+			return res[i].Date.After(res[j].Date)
+		}
+		return res[i].Net > res[j].Net
+	})
+	return res, nil
+}
+
+func querySeats(ctx context.Context, report, filter, sortColumn string) ([]*LHBSeat, error) {
+	rows, err := queryReportAll(ctx, &reportReq{
+		ReportName:  report,
+		Filter:      filter,
+		SortColumns: sortColumn,
+		SortTypes:   "-1",
+		PageSize:    500,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 一只股票因多个原因上榜时同一席位会重复出现
+	seen := make(map[string]bool)
+	res := make([]*LHBSeat, 0, len(rows))
+	for _, m := range rows {
+		s := &LHBSeat{
+			Date: reportDate(m, "TRADE_DATE"),
+			Code: reportString(m, "SECURITY_CODE"),
+			Name: reportString(m, "SECURITY_NAME_ABBR"),
+			Seat: reportString(m, "OPERATEDEPT_NAME"),
+			Buy:  reportFloat(m, "BUY"),
+			Sell: reportFloat(m, "SELL"),
+			Net:  reportFloat(m, "NET"),
+		}
+		key := s.Date.Format(time.DateOnly) + s.Code + s.Seat
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+// QueryBlockTrades returns the A-share block trades between begin and end,
+// optionally of one stock, newest first and by amount within a day.
+func QueryBlockTrades(ctx context.Context, begin, end time.Time, code string) ([]*BlockTrade, error) {
+	rows, err := queryReportAll(ctx, &reportReq{
+		ReportName:  "RPT_DATA_BLOCKTRADE",
+		Filter:      "(SECURITY_TYPE_WEB=1)" + tradeDateFilter(begin, end, code),
+		SortColumns: "TRADE_DATE,DEAL_AMT",
+		SortTypes:   "-1,-1",
+		PageSize:    500,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*BlockTrade, 0, len(rows))
+	for _, m := range rows {
+		res = append(res, &BlockTrade{
+			Date:       reportDate(m, "TRADE_DATE"),
+			Code:       reportString(m, "SECURITY_CODE"),
+			Name:       reportString(m, "SECURITY_NAME_ABBR"),
+			Close:      reportFloat(m, "CLOSE_PRICE"),
+			ChangeRate: reportFloat(m, "CHANGE_RATE"),
+			Price:      reportFloat(m, "DEAL_PRICE"),
+			Premium:    reportFloat(m, "PREMIUM_RATIO"),
+			Volume:     reportFloat(m, "DEAL_VOLUME"),
+			Amount:     reportFloat(m, "DEAL_AMT"),
+			Buyer:      reportString(m, "BUYER_NAME"),
+			Seller:     reportString(m, "SELLER_NAME"),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradeDateFilter(t *testing.T) {
+	begin := time.Date(2026, 10, 10, 0, 0, 0, 0, time.Local)
+	end := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	require.Equal(t, "(TRADE_DATE>='2026-10-10')(TRADE_DATE<='2026-10-16')", tradeDateFilter(begin, end, ""))
+	require.Equal(t, `(TRADE_DATE>='2026-10-10')(TRADE_DATE<='2026-10-16')(SECURITY_CODE="600036")`, tradeDateFilter(begin, end, "600036"))
+	require.Equal(t, `(TRADE_DATE>='2026-10-10')(TRADE_DATE<='2026-10-16')(OPERATEDEPT_NAME like "%华泰%")`, seatFilter(begin, end, `华泰"`))
+}
+
+func TestQueryLHB(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("reportName") {
+		case "RPT_DAILYBILLBOARD_DETAILSNEW":
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":1,"data":[
+				{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","EXPLANATION":"日涨幅偏离值达到7%的前5只证券",
+				"EXPLAIN":"2家机构买入","CLOSE_PRICE":44.1,"CHANGE_RATE":10.0,"BILLBOARD_NET_AMT":1.2e8,"BILLBOARD_BUY_AMT":3e8,"BILLBOARD_SELL_AMT":1.8e8,"ACCUM_AMOUNT":5e9,"TURNOVERRATE":1.5}]}}`)
+		case "RPT_BILLBOARD_DAILYDETAILSBUY":
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":3,"data":[
+				{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","OPERATEDEPT_NAME":"机构专用","BUY":2e8,"SELL":1e7,"NET":1.9e8},
+				{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","OPERATEDEPT_NAME":"机构专用","BUY":2e8,"SELL":1e7,"NET":1.9e8},
+				{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","OPERATEDEPT_NAME":"华泰证券上海武定路","BUY":5e7,"SELL":0,"NET":5e7}]}}`)
+		case "RPT_BILLBOARD_DAILYDETAILSSELL":
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":2,"data":[
+				{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","OPERATEDEPT_NAME":"深股通专用","BUY":0,"SELL":1.5e8,"NET":-1.5e8},
+				{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","OPERATEDEPT_NAME":"华泰证券上海武定路","BUY":5e7,"SELL":0,"NET":5e7}]}}`)
+		case "RPT_DATA_BLOCKTRADE":
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":1,"data":[
+				{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","CLOSE_PRICE":44.1,"CHANGE_RATE":1.2,
+				"DEAL_PRICE":40.0,"PREMIUM_RATIO":-9.3,"DEAL_VOLUME":1e6,"DEAL_AMT":4e7,"BUYER_NAME":"机构专用","SELLER_NAME":"中信证券北京总部"}]}}`)
+		default:
+			fmt.Fprint(w, `{"success":false,"result":null}`)
+		}
+	}))
+	defer srv.Close()
+	origURL := datacenterURL
+	datacenterURL = srv.URL
+	defer func() { datacenterURL = origURL }()
+
+	ctx := context.Background()
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	list, err := QueryLHB(ctx, day, day, "")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "日涨幅偏离值达到7%的前5只证券", list[0].Reason)
+	require.Equal(t, 1.2e8, list[0].NetBuy)
+
+	buys, sells, err := QueryLHBSeats(ctx, day, "600036")
+	require.NoError(t, err)
+	require.Len(t, buys, 2)
+	require.Equal(t, "机构专用", buys[0].Seat)
+	require.Len(t, sells, 2)
+	require.Equal(t, -1.5e8, sells[0].Net)
+
+	seats, err := QuerySeatTrades(ctx, day, day, "华泰")
+	require.NoError(t, err)
+	require.Len(t, seats, 1)
+	require.Equal(t, "招商银行", seats[0].Name)
+
+	trades, err := QueryBlockTrades(ctx, day, day, "600036")
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	require.Equal(t, -9.3, trades[0].Premium)
+	require.Equal(t, "中信证券北京总部", trades[0].Seller)
+}
+
+func TestQuerySeatTradesTooManyPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Contains(t, r.URL.Query().Get("filter"), `(OPERATEDEPT_NAME like "%华泰%")`)
+		fmt.Fprint(w, `{"success":true,"result":{"pages":100,"count":100,"data":[
+			{"TRADE_DATE":"2026-10-16 00:00:00","SECURITY_CODE":"600036","SECURITY_NAME_ABBR":"招商银行","OPERATEDEPT_NAME":"华泰证券上海武定路","BUY":5e7,"SELL":0,"NET":5e7}]}}`)
+	}))
+	defer srv.Close()
+	origURL := datacenterURL
+	datacenterURL = srv.URL
+	defer func() { datacenterURL = origURL }()
+
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	_, err := QuerySeatTrades(context.Background(), day.AddDate(-5, 0, 0), day, "华泰")
+	require.ErrorContains(t, err, "more than 50 pages")
+}
@@ -0,0 +1,75 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 融资融券：个股明细（数据中心 RPTA_WEB_RZRQ_GGMX）与沪深两市合计（RPTA_RZRQ_LSHJ）。
+// 字段语义参考东方财富融资融券页面 data.eastmoney.com/rzrq。
+
+// Margin 某一交易日的融资融券数据，金额单位元
+type Margin struct {
+	Date         time.Time
+	FinBalance   float64 // 融资余额
+	FinBuy       float64 // 融资买入额
+	FinRepay     float64 // 融资偿还额
+	FinNetBuy    float64 // 融资净买入
+	ShortBalance float64 // 融券余额
+	ShortVolume  float64 // 融券余量，单位股
+	ShortSell    float64 // 融券卖出量，单位股
+	Total        float64 // 融资融券余额
+	Close        float64 // 收盘价，市场合计时为沪深 300 点位
+	ChangeRate   float64 // 涨跌幅，百分比
+}
+
+// QueryMarginHistory returns the latest limit daily margin records of the
+// A-share code, or of the whole market when code is empty, newest first.
+func QueryMarginHistory(ctx context.Context, code string, limit int) ([]*Margin, error) {
+	req := &reportReq{
+		ReportName:  "RPTA_RZRQ_LSHJ",
+		SortColumns: "DIM_DATE",
+		SortTypes:   "-1",
+		PageSize:    min(limit, 500),
+	}
+	dateKey, closeKey := "DIM_DATE", "NEW"
+	if code != "" {
+		req.ReportName = "RPTA_WEB_RZRQ_GGMX"
+		req.Filter = fmt.Sprintf(`(SCODE="%s")`, code)
+		req.SortColumns = "DATE"
+		dateKey, closeKey = "DATE", "SPJ"
+	}
+
+	// 单页最多 500 条，超出时翻页
+	var rows []map[string]interface{}
+	var err error
+	if limit > req.PageSize {
+		rows, err = queryReportAll(ctx, req)
+	} else {
+		rows, _, err = queryReport(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	res := make([]*Margin, 0, len(rows))
+	for _, m := range rows {
+		res = append(res, &Margin{
+			Date:         reportDate(m, dateKey),
+			FinBalance:   reportFloat(m, "RZYE"),
+			FinBuy:       reportFloat(m, "RZMRE"),
+			FinRepay:     reportFloat(m, "RZCHE"),
+			FinNetBuy:    reportFloat(m, "RZJME"),
+			ShortBalance: reportFloat(m, "RQYE"),
+			ShortVolume:  reportFloat(m, "RQYL"),
+			ShortSell:    reportFloat(m, "RQMCL"),
+			Total:        reportFloat(m, "RZRQYE"),
+			Close:        reportFloat(m, closeKey),
+			ChangeRate:   reportFloat(m, "ZDF"),
+		})
+	}
+	return res, nil
+}
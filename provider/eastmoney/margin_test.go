@@ -0,0 +1,48 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMarginHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("reportName") {
+		case "RPTA_WEB_RZRQ_GGMX":
+			require.Equal(t, `(SCODE="600036")`, q.Get("filter"))
+			require.Equal(t, "DATE", q.Get("sortColumns"))
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":2,"data":[
+				{"DATE":"2026-10-16 00:00:00","SCODE":"600036","RZYE":6.2e9,"RZMRE":3e8,"RZCHE":2.5e8,"RZJME":5e7,"RQYE":1.2e7,"RQYL":3e5,"RQMCL":1e4,"RZRQYE":6.212e9,"SPJ":41.8,"ZDF":1.46},
+				{"DATE":"2026-10-15 00:00:00","SCODE":"600036","RZYE":6.15e9,"RQYE":1.1e7,"RZRQYE":6.161e9,"SPJ":41.2,"ZDF":-1.03}]}}`)
+		case "RPTA_RZRQ_LSHJ":
+			require.Empty(t, q.Get("filter"))
+			fmt.Fprint(w, `{"success":true,"result":{"pages":1,"count":1,"data":[
+				{"DIM_DATE":"2026-10-16 00:00:00","RZYE":1.85e12,"RZMRE":1.2e11,"RQYE":1.1e10,"RZRQYE":1.861e12,"NEW":4650.2,"ZDF":0.8}]}}`)
+		}
+	}))
+	defer srv.Close()
+	origURL := datacenterURL
+	datacenterURL = srv.URL
+	defer func() { datacenterURL = origURL }()
+
+	list, err := QueryMarginHistory(context.Background(), "600036", 20)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "2026-10-16", list[0].Date.Format("2006-01-02"))
+	require.Equal(t, 6.2e9, list[0].FinBalance)
+	require.Equal(t, 5e7, list[0].FinNetBuy)
+	require.Equal(t, 3e5, list[0].ShortVolume)
+	require.Equal(t, 41.8, list[0].Close)
+
+	list, err = QueryMarginHistory(context.Background(), "", 20)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, 1.85e12, list[0].FinBalance)
+	require.Equal(t, 4650.2, list[0].Close)
+}
@@ -0,0 +1,312 @@
+package eastmoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 全市场快照：基于东方财富 push2 clist 的沪深京 A 股行情列表，以及 push2ex 涨停股池。
+// 字段语义参考 AKShare stock_zh_a_spot_em / stock_zt_pool_em 源码。
+
+var (
+	// marketURL、ztPoolURL 用 var 而非 const 以方便测试用 httptest server 覆盖
+	marketURL = EastMoneyPush2ApiBase + "/api/qt/clist/get"
+	ztPoolURL = "http://push2ex.eastmoney.com/getTopicZTPool"
+
+	// 沪深京 A 股：深主板、创业板、沪主板、科创板、北交所
+	marketFS = "m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23,m:0+t:81+s:2048"
+
+	// f2: 最新价 f3: 涨跌幅(%) f6: 成交额(元) f12: 代码 f13: 市场 f14: 名称
+	// f124: 行情时间(unix 秒) f350: 涨停价 f351: 跌停价
+	marketFields = "f2,f3,f6,f12,f13,f14,f124,f350,f351"
+
+	// push2 clist 单页最多返回 100 条
+	marketPageSize = 100
+)
+
+// MarketStock 全市场快照中的单只股票
+type MarketStock struct {
+	Code       string
+	Name       string
+	Market     MarketType
+	Price      float64   // 最新价，停牌为 0
+	ChangeRate float64   // 涨跌幅，百分比
+	Amount     float64   // 成交额，单位元
+	LimitUp    float64   // 涨停价
+	LimitDown  float64   // 跌停价
+	Time       time.Time // 行情时间
+}
+
+// ExCode returns the exchange-prefixed code of s, e.g. SH600036 or BJ830799.
+func (s *MarketStock) ExCode() string {
+	// 北交所股票在 push2 中与深市同为市场 0
+	if s.Market == MarketTypeSzSe && (strings.HasPrefix(s.Code, "8") || strings.HasPrefix(s.Code, "4") || strings.HasPrefix(s.Code, "92")) {
+		return "BJ" + s.Code
+	}
+	return s.Market.String() + s.Code
+}
+
+// Suspended reports whether s has no trade today.
+func (s *MarketStock) Suspended() bool {
+	return s.Price <= 0
+}
+
+// AtLimitUp reports whether s closes at its limit-up price.
+func (s *MarketStock) AtLimitUp() bool {
+	return s.Price > 0 && s.LimitUp > 0 && s.Price >= s.LimitUp-0.001
+}
+
+// AtLimitDown reports whether s closes at its limit-down price.
+func (s *MarketStock) AtLimitDown() bool {
+	return s.Price > 0 && s.LimitDown > 0 && s.Price <= s.LimitDown+0.001
+}
+
+// MarketSnapshot 全市场 A 股快照
+type MarketSnapshot struct {
+	Time   time.Time // 最新行情时间
+	Stocks []*MarketStock
+}
+
+// marketResp push2 clist 返回结构
+type marketResp struct {
+	Rc   int `json:"rc"`
+	Data *struct {
+		Total int                      `json:"total"`
+		Diff  []map[string]interface{} `json:"diff"`
+	} `json:"data"`
+}
+
+// QueryMarketSnapshot returns the latest quotes of all Shanghai, Shenzhen and
+// Beijing A-shares.
+func QueryMarketSnapshot(ctx context.Context) (*MarketSnapshot, error) {
+	rows, err := fetchClist(ctx, marketFS, marketFields)
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney market snapshot: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("eastMoney market snapshot: no data")
+	}
+	snap := &MarketSnapshot{Stocks: parseMarketItems(rows)}
+	for _, s := range snap.Stocks {
+		if s.Time.After(snap.Time) {
+			snap.Time = s.Time
+		}
+	}
+	return snap, nil
+}
+
+// parseMarketItems parses clist rows, dropping invalid and duplicated stocks.
+func parseMarketItems(rows []map[string]interface{}) []*MarketStock {
+	stocks := make([]*MarketStock, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, m := range rows {
+		s := parseMarketItem(m)
+		if s == nil {
+			continue
+		}
+		// 翻页期间排序变化可能导致重复
+		key := s.Market.String() + s.Code
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		stocks = append(stocks, s)
+	}
+	return stocks
+}
+
+// fetchClist fetches all pages of a push2 clist query.
+func fetchClist(ctx context.Context, fs, fields string) ([]map[string]interface{}, error) {
+	first, total, err := fetchClistPage(ctx, fs, fields, 1)
+	if err != nil {
+		return nil, err
+	}
+	pages := (total + marketPageSize - 1) / marketPageSize
+	results := make([][]map[string]interface{}, max(pages, 1)+1)
+	results[1] = first
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	// 限制并发，避免被限流
+	sem := make(chan struct{}, 4)
+	for pn := 2; pn <= pages; pn++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			rows, _, err := fetchClistPage(ctx, fs, fields, pn)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			results[pn] = rows
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	rows := make([]map[string]interface{}, 0, total)
+	for _, page := range results {
+		rows = append(rows, page...)
+	}
+	return rows, nil
+}
+
+func fetchClistPage(ctx context.Context, fs, fields string, pn int) ([]map[string]interface{}, int, error) {
+	v := url.Values{}
+	v.Set("pn", strconv.Itoa(pn))
+	v.Set("pz", strconv.Itoa(marketPageSize))
+	v.Set("po", "1")
+	v.Set("np", "1")
+	v.Set("fltt", "2")
+	v.Set("invt", "2")
+	v.Set("fid", "f12")
+	v.Set("fs", fs)
+	v.Set("fields", fields)
+
+	body, err := getPush2(ctx, marketURL+"?"+v.Encode(), "http://quote.eastmoney.com/center/gridlist.html")
+	if err != nil {
+		return nil, 0, err
+	}
+	var raw marketResp
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, 0, fmt.Errorf("parse clist: %w", err)
+	}
+	if raw.Data == nil {
+		// 没有符合条件的数据时 data 为 null
+		return nil, 0, nil
+	}
+	return raw.Data.Diff, raw.Data.Total, nil
+}
+
+// parseMarketItem 解析 clist 单条记录，停牌股票的数值字段为 "-"
+func parseMarketItem(m map[string]interface{}) *MarketStock {
+	code := getStrField(m, "f12")
+	if code == "" || code == "-" {
+		return nil
+	}
+	nonNeg := func(key string) float64 {
+		return max(getFloat64Field(m, key), 0)
+	}
+	s := &MarketStock{
+		Code:      code,
+		Name:      getStrField(m, "f14"),
+		Market:    MarketType(nonNeg("f13")),
+		Price:     nonNeg("f2"),
+		Amount:    nonNeg("f6"),
+		LimitUp:   nonNeg("f350"),
+		LimitDown: nonNeg("f351"),
+	}
+	if !s.Suspended() {
+		s.ChangeRate = getFloat64Field(m, "f3")
+	}
+	if ts := nonNeg("f124"); ts > 0 {
+		s.Time = time.Unix(int64(ts), 0)
+	}
+	return s
+}
+
+// LimitUpStock 涨停股池中的单只股票
+type LimitUpStock struct {
+	Code       string  `json:"c"`
+	Name       string  `json:"n"`
+	Market     int     `json:"m"`
+	Price      float64 `json:"p"`   // 最新价，单位厘
+	ChangeRate float64 `json:"zdp"` // 涨跌幅，百分比
+	Amount     float64 `json:"amount"`
+	Streak     int     `json:"lbc"` // 连板数
+	FirstTime  int     `json:"fbt"` // 首次封板时间 HHMMSS
+	LastTime   int     `json:"lbt"` // 最后封板时间 HHMMSS
+	Breaks     int     `json:"zbc"` // 炸板次数
+	Industry   string  `json:"hybk"`
+}
+
+// ztPoolResp push2ex 涨停股池返回结构，非交易日 data 为 null
+type ztPoolResp struct {
+	Rc   int `json:"rc"`
+	Data *struct {
+		Tc   int             `json:"tc"`
+		Pool []*LimitUpStock `json:"pool"`
+	} `json:"data"`
+}
+
+// QueryLimitUpPool returns the stocks closing at limit-up on date. It returns
+// an empty list on non-trading days.
+func QueryLimitUpPool(ctx context.Context, date time.Time) ([]*LimitUpStock, error) {
+	v := url.Values{}
+	v.Set("ut", "7eea3edcaed734bea9cbfc24409ed989")
+	v.Set("dpt", "wz.ztzt")
+	v.Set("Pageindex", "0")
+	v.Set("pagesize", "10000")
+	v.Set("sort", "fbt:asc")
+	v.Set("date", date.Format("20060102"))
+
+	body, err := getPush2(ctx, ztPoolURL+"?"+v.Encode(), "http://quote.eastmoney.com/ztb/detail")
+	if err != nil {
+		return nil, fmt.Errorf("eastMoney limit-up pool: %w", err)
+	}
+	var raw ztPoolResp
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("eastMoney limit-up pool parse: %w", err)
+	}
+	if raw.Data == nil {
+		return []*LimitUpStock{}, nil
+	}
+	return raw.Data.Pool, nil
+}
+
+// getPush2 请求东方财富 push2/push2ex 接口，偶发 EOF 时重试
+func getPush2(ctx context.Context, reqURL, referer string) ([]byte, error) {
+	headers := http.Header{}
+	headers.Set("User-Agent", browserUA)
+	headers.Set("Referer", referer)
+	headers.Set("Accept", "*/*")
+	client := newHTTPClient(defaultTimeout)
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	for attempt := 1; attempt <= 3; attempt++ {
+		resp, err = doRequest(ctx, client, http.MethodGet, reqURL, headers, nil)
+		if err == nil {
+			break
+		}
+		slog.DebugContext(ctx, "failed getPush2", "attempt", attempt, "url", reqURL, "error", err)
+		if attempt == 3 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
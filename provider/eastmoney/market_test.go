@@ -0,0 +1,91 @@
+package eastmoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarketItem(t *testing.T) {
+	s := parseMarketItem(map[string]interface{}{
+		"f2": 11.0, "f3": 10.0, "f6": 1.2e9, "f12": "600036", "f13": 1.0, "f14": "招商银行",
+		"f124": 1760598000.0, "f350": 11.0, "f351": 9.0,
+	})
+	require.NotNil(t, s)
+	require.Equal(t, MarketTypeSse, s.Market)
+	require.True(t, s.AtLimitUp())
+	require.False(t, s.AtLimitDown())
+	require.False(t, s.Suspended())
+	require.Equal(t, time.Unix(1760598000, 0), s.Time)
+
+	// 停牌
+	s = parseMarketItem(map[string]interface{}{
+		"f2": "-", "f3": "-", "f6": "-", "f12": "000001", "f13": 0.0, "f14": "平安银行", "f350": 12.1, "f351": 9.9,
+	})
+	require.True(t, s.Suspended())
+	require.False(t, s.AtLimitUp())
+	require.False(t, s.AtLimitDown())
+	require.Zero(t, s.ChangeRate)
+	require.Equal(t, MarketTypeSzSe, s.Market)
+
+	require.Nil(t, parseMarketItem(map[string]interface{}{"f12": "-"}))
+}
+
+func TestQueryMarketSnapshot(t *testing.T) {
+	// 250 只股票，分 3 页
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pn, _ := strconv.Atoi(r.URL.Query().Get("pn"))
+		require.Equal(t, "100", r.URL.Query().Get("pz"))
+		diff := ""
+		for i := (pn - 1) * 100; i < min(pn*100, 250); i++ {
+			if diff != "" {
+				diff += ","
+			}
+			diff += fmt.Sprintf(`{"f2":10.0,"f3":1.0,"f6":100.0,"f12":"%06d","f13":0,"f14":"s%d","f124":%d,"f350":11.0,"f351":9.0}`, i, i, 1760598000+i)
+		}
+		fmt.Fprintf(w, `{"rc":0,"data":{"total":250,"diff":[%s]}}`, diff)
+	}))
+	defer srv.Close()
+	origURL := marketURL
+	marketURL = srv.URL
+	defer func() { marketURL = origURL }()
+
+	snap, err := QueryMarketSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Len(t, snap.Stocks, 250)
+	require.Equal(t, "000000", snap.Stocks[0].Code)
+	require.Equal(t, "000249", snap.Stocks[249].Code)
+	require.Equal(t, time.Unix(1760598249, 0), snap.Time)
+}
+
+func TestQueryLimitUpPool(t *testing.T) {
+	body := `{"rc":0,"data":{"tc":2,"pool":[{"c":"600001","n":"甲","m":1,"p":11000,"zdp":10.0,"lbc":3,"fbt":93000,"zbc":0,"hybk":"银行"},{"c":"000002","n":"乙","m":0,"p":5500,"zdp":10.0,"lbc":1}]}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date") == "20261017" {
+			fmt.Fprint(w, `{"rc":0,"data":null}`)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+	origURL := ztPoolURL
+	ztPoolURL = srv.URL
+	defer func() { ztPoolURL = origURL }()
+
+	pool, err := QueryLimitUpPool(context.Background(), time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Len(t, pool, 2)
+	require.Equal(t, 3, pool[0].Streak)
+	require.Equal(t, "甲", pool[0].Name)
+
+	// 非交易日
+	pool, err = QueryLimitUpPool(context.Background(), time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Empty(t, pool)
+}
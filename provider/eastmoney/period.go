@@ -0,0 +1,125 @@
+package eastmoney
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Period K 线周期，零值为日 K
+type Period int
+
+const (
+	PeriodDay       Period = iota // 日 K
+	Period1Min                    // 1 分钟
+	Period5Min                    // 5 分钟
+	Period15Min                   // 15 分钟
+	Period30Min                   // 30 分钟
+	Period60Min                   // 60 分钟
+	PeriodWeek                    // 周 K
+	PeriodMonth                   // 月 K
+	PeriodQuarter                 // 季 K
+	PeriodYear                    // 年 K
+	PeriodTimeShare               // 当日分时
+)
+
+// periodInfo 周期的命令行名称、中文名称和东方财富 klt 参数
+var periodInfo = []struct {
+	name    string
+	label   string
+	klt     int
+	aliases []string
+}{
+	PeriodDay:       {"day", "日K", 101, []string{"d", "daily", "1d"}},
+	Period1Min:      {"1m", "1分钟", 1, []string{"1min"}},
+	Period5Min:      {"5m", "5分钟", 5, []string{"5min"}},
+	Period15Min:     {"15m", "15分钟", 15, []string{"15min"}},
+	Period30Min:     {"30m", "30分钟", 30, []string{"30min"}},
+	Period60Min:     {"60m", "60分钟", 60, []string{"60min", "1h"}},
+	PeriodWeek:      {"week", "周K", 102, []string{"w", "weekly"}},
+	PeriodMonth:     {"month", "月K", 103, []string{"mon", "monthly"}},
+	PeriodQuarter:   {"quarter", "季K", 104, []string{"q", "quarterly"}},
+	PeriodYear:      {"year", "年K", 106, []string{"y", "yearly"}},
+	PeriodTimeShare: {"intraday", "分时", 0, []string{"ts", "fs"}},
+}
+
+// ParsePeriod parses a period name such as "5m", "day" or "intraday". An
+// empty string is the daily period.
+func ParsePeriod(s string) (Period, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return PeriodDay, nil
+	}
+	for p, info := range periodInfo {
+		if s == info.name {
+			return Period(p), nil
+		}
+		for _, a := range info.aliases {
+			if s == a {
+				return Period(p), nil
+			}
+		}
+	}
+	return PeriodDay, fmt.Errorf("invalid period %q: expected one of %s", s, strings.Join(PeriodNames(), ", "))
+}
+
+// PeriodNames returns the names accepted by ParsePeriod, one per period.
+func PeriodNames() []string {
+	names := make([]string, len(periodInfo))
+	for i, info := range periodInfo {
+		names[i] = info.name
+	}
+	return names
+}
+
+// String returns the command line name of p.
+func (p Period) String() string {
+	if !p.valid() {
+		return fmt.Sprintf("unknown %d", int(p))
+	}
+	return periodInfo[p].name
+}
+
+// Label returns the Chinese name of p, e.g. "日K" or "5分钟".
+func (p Period) Label() string {
+	if !p.valid() {
+		return p.String()
+	}
+	return periodInfo[p].label
+}
+
+// KLT returns the klt parameter of the kline API, 0 for the time-share series.
+func (p Period) KLT() int {
+	if !p.valid() {
+		return periodInfo[PeriodDay].klt
+	}
+	return periodInfo[p].klt
+}
+
+// Intraday reports whether bars of p carry a time of day.
+func (p Period) Intraday() bool {
+	return p >= Period1Min && p <= Period60Min || p == PeriodTimeShare
+}
+
+// maxLookbackDays Lookback 的上限，A 股 1990 年开市，更早的数据不存在
+const maxLookbackDays = 40 * 365
+
+// Lookback scales a lookback window of days calendar days sized for daily
+// bars so that weekly and longer periods get a comparable number of bars,
+// capped at maxLookbackDays.
+func (p Period) Lookback(days int) int {
+	switch p {
+	case PeriodWeek:
+		days *= 7
+	case PeriodMonth:
+		days *= 30
+	case PeriodQuarter:
+		days *= 91
+	case PeriodYear:
+		days *= 365
+	}
+	return min(days, maxLookbackDays)
+}
+
+func (p Period) valid() bool {
+	return p >= 0 && int(p) < len(periodInfo)
+}
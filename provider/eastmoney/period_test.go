@@ -0,0 +1,45 @@
+package eastmoney
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriod(t *testing.T) {
+	cases := map[string]Period{
+		"":         PeriodDay,
+		"day":      PeriodDay,
+		"D":        PeriodDay,
+		"5m":       Period5Min,
+		"60min":    Period60Min,
+		"week":     PeriodWeek,
+		"quarter":  PeriodQuarter,
+		"y":        PeriodYear,
+		"intraday": PeriodTimeShare,
+	}
+	for s, want := range cases {
+		p, err := ParsePeriod(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, p, s)
+	}
+
+	_, err := ParsePeriod("2m")
+	require.ErrorContains(t, err, "1m, 5m")
+
+	require.Equal(t, 101, PeriodDay.KLT())
+	require.Equal(t, 15, Period15Min.KLT())
+	require.Equal(t, 106, PeriodYear.KLT())
+	require.Equal(t, "月K", PeriodMonth.Label())
+	require.True(t, Period1Min.Intraday())
+	require.True(t, PeriodTimeShare.Intraday())
+	require.False(t, PeriodWeek.Intraday())
+	require.Equal(t, 90, Period5Min.Lookback(90))
+	require.Equal(t, 630, PeriodWeek.Lookback(90))
+	require.Equal(t, maxLookbackDays, PeriodYear.Lookback(90))
+	for _, name := range PeriodNames() {
+		p, err := ParsePeriod(name)
+		require.NoError(t, err)
+		require.Equal(t, name, p.String())
+	}
+}
@@ -2,11 +2,13 @@ package eastmoney
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 const (
 	TimeYYMMDD = "20060102"
+	TimeMinute = "2006-01-02 15:04" // 分钟 K 线和分时数据的时间格式
 )
 
 type MarketType int
@@ -22,6 +24,12 @@ func (m MarketType) String() string {
 		res = "$"
 	case MarketTypeHK:
 		return "HK"
+	case MarketTypeCSI:
+		res = "CSI"
+	case MarketTypeMetal:
+		res = "METAL"
+	case MarketTypeForex, MarketTypeFxSpot:
+		res = "FX"
 	default:
 		res = fmt.Sprintf("unknown %d", m)
 	}
@@ -29,6 +37,18 @@ func (m MarketType) String() string {
 	return res
 }
 
+// MarketOf returns the market of an exchange-prefixed Shanghai or Shenzhen
+// code such as SH600036, false for other exchanges.
+func MarketOf(exCode string) (MarketType, bool) {
+	switch {
+	case strings.HasPrefix(exCode, "SH"):
+		return MarketTypeSse, true
+	case strings.HasPrefix(exCode, "SZ"):
+		return MarketTypeSzSe, true
+	}
+	return 0, false
+}
+
 type FuQuanType int
 
 // Quote 基本行情
@@ -56,6 +76,7 @@ type GetQuoteHistoryReq struct {
 	FQT        FuQuanType // 复权类型 0不复权 1前复权 2后复权，默认不复权
 	Begin      string     // 开始时间 19000101 格式
 	End        string     // 结束时间 20500101 格式
+	Period     Period     // K 线周期，默认日 K
 }
 
 // QuoteHistoryResp 东方财富 K 线历史接口返回数据结构
@@ -83,6 +104,25 @@ type QuoteHistoryData struct {
 	Klines     []string   `json:"klines"`
 }
 
+// TimeShareResp 东方财富分时接口返回数据结构
+type TimeShareResp struct {
+	Rc   int            `json:"rc"`
+	Rt   int            `json:"rt"`
+	Svr  int64          `json:"svr"`
+	Data *TimeShareData `json:"data"`
+}
+
+// TimeShareData 东方财富分时接口中 data 字段结构体
+type TimeShareData struct {
+	Code        string     `json:"code"`
+	Market      MarketType `json:"market"`
+	Name        string     `json:"name"`
+	Decimal     int        `json:"decimal"`
+	PreClose    float64    `json:"preClose"`    // 昨收
+	TrendsTotal int        `json:"trendsTotal"` // 当日总分钟数
+	Trends      []string   `json:"trends"`
+}
+
 // KLineQuote K 线结构体
 type KLineQuote struct {
 	Date       time.Time
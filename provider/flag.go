@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// FlagName 全局数据源 flag 名称
+const FlagName = "source"
+
+// AddFlag adds the persistent --source flag to the root command.
+func AddFlag(root *cobra.Command) {
+	root.PersistentFlags().String(FlagName, "", "Data source: "+strings.Join(Names(), ", ")+", falls back to $"+EnvSource)
+}
+
+// FromFlags resolves the source chosen by the --source flag of cmd. A command
+// without the flag, such as one run on its own in tests, gets the source of
+// SEC_SOURCE or the default one.
+func FromFlags(cmd *cobra.Command) (*Source, error) {
+	name, _ := cmd.Flags().GetString(FlagName)
+	return Resolve(name)
+}
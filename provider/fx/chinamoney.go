@@ -0,0 +1,141 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/utils"
+)
+
+// 人民币汇率中间价：中国外汇交易中心 chinamoney.com.cn 的历史查询接口，单次查询跨度
+// 不超过一年，结果分页返回。参考 AKShare currency_boc_safe / macro_china_rmb。
+
+var (
+	chinaMoneyURL = "https://www.chinamoney.com.cn/ags/ms/cm-u-bk-ccpr/CcprHisNew"
+
+	chinaMoneyPageSize = 100
+)
+
+// ccprResp 中间价历史接口返回数据结构
+type ccprResp struct {
+	Data struct {
+		Head      []string `json:"head"` // 货币对，如 USD/CNY，与 values 一一对应
+		PageTotal int      `json:"pageTotal"`
+	} `json:"data"`
+	Records []struct {
+		Date   string   `json:"date"`
+		Values []string `json:"values"`
+	} `json:"records"`
+}
+
+// queryParity fetches the central parity rates of all currencies between
+// start and end (2006-01-02), one request per year and page, in ascending
+// order of date.
+func queryParity(ctx context.Context, startStr, endStr string) ([]Rate, error) {
+	start, err := time.Parse(utils.LayoutYYMMDD, startStr)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(utils.LayoutYYMMDD, endStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Rate
+	for from := start; !from.After(end); {
+		to := from.AddDate(1, 0, -1)
+		if to.After(end) {
+			to = end
+		}
+		for page, pages := 1, 1; page <= pages; page++ {
+			resp, err := fetchParity(ctx, from, to, page)
+			if err != nil {
+				return nil, fmt.Errorf("chinamoney %s~%s: %w", from.Format(utils.LayoutYYMMDD), to.Format(utils.LayoutYYMMDD), err)
+			}
+			pages = resp.Data.PageTotal
+			res = append(res, parseParity(resp)...)
+		}
+		from = to.AddDate(0, 0, 1)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Date.Before(res[j].Date) })
+	return res, nil
+}
+
+func fetchParity(ctx context.Context, from, to time.Time, page int) (*ccprResp, error) {
+	pairs := make([]string, len(Currencies))
+	for i, c := range Currencies {
+		pairs[i] = KindParity.Pair(c)
+	}
+	v := url.Values{}
+	v.Set("startDate", from.Format(utils.LayoutYYMMDD))
+	v.Set("endDate", to.Format(utils.LayoutYYMMDD))
+	v.Set("currency", strings.Join(pairs, ","))
+	v.Set("pageNum", strconv.Itoa(page))
+	v.Set("pageSize", strconv.Itoa(chinaMoneyPageSize))
+	reqURL := chinaMoneyURL + "?" + v.Encode()
+	slog.DebugContext(ctx, "fetchParity", "reqURL", reqURL)
+
+	headers := http.Header{}
+	headers.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36")
+	headers.Set("Referer", "https://www.chinamoney.com.cn/chinese/bkccpr/")
+	resp, err := utils.MakeRequest(ctx, http.MethodPost, reqURL, headers, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var res ccprResp
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("parse ccpr: %w", err)
+	}
+	return &res, nil
+}
+
+// parseParity converts the records to rates, skipping pairs other than
+// Currencies and empty values.
+func parseParity(resp *ccprResp) []Rate {
+	cols := make(map[int]Currency, len(resp.Data.Head))
+	for i, pair := range resp.Data.Head {
+		for _, c := range Currencies {
+			if pair == KindParity.Pair(c) {
+				cols[i] = c
+			}
+		}
+	}
+
+	var res []Rate
+	for _, rec := range resp.Records {
+		date, err := time.Parse(utils.LayoutYYMMDD, rec.Date)
+		if err != nil {
+			slog.Warn("failed parse ccpr date", "date", rec.Date, "error", err)
+			continue
+		}
+		for i, s := range rec.Values {
+			c, ok := cols[i]
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err != nil || v == 0 {
+				continue
+			}
+			res = append(res, Rate{Date: date, Currency: c, Kind: KindParity, Value: v})
+		}
+	}
+	return res
+}
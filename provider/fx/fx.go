@@ -0,0 +1,248 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/types"
+	"github.com/alwqx/sec/utils"
+)
+
+// 人民币汇率：中间价来自中国外汇交易中心 chinamoney.com.cn，在岸、离岸即期汇率来自东方财富
+// 外汇行情（在岸 119.USDCNY，离岸 133.USDCNH）。汇率统一为 1 单位外币兑人民币。
+
+// Currency 币种
+type Currency string
+
+const (
+	CurrencyCNY Currency = "CNY"
+	CurrencyUSD Currency = "USD"
+	CurrencyHKD Currency = "HKD"
+	CurrencyEUR Currency = "EUR"
+)
+
+// Currencies 支持查询汇率的外币
+var Currencies = []Currency{CurrencyUSD, CurrencyHKD, CurrencyEUR}
+
+// ParseCurrency parses a currency code like usd, ignoring case.
+func ParseCurrency(s string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(s)))
+	if c == CurrencyCNY {
+		return c, nil
+	}
+	for _, v := range Currencies {
+		if c == v {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("invalid currency %q, want one of CNY, USD, HKD, EUR", s)
+}
+
+// Label returns the Chinese name of the currency.
+func (c Currency) Label() string {
+	switch c {
+	case CurrencyCNY:
+		return "人民币"
+	case CurrencyUSD:
+		return "美元"
+	case CurrencyHKD:
+		return "港币"
+	case CurrencyEUR:
+		return "欧元"
+	}
+	return string(c)
+}
+
+// CurrencyOf returns the trading currency of a security by its exchange code,
+// e.g. HKD for HK00700 and USD for $AAPL. A shares and unknown codes are CNY.
+func CurrencyOf(exCode string) Currency {
+	switch {
+	case types.IsHCode(exCode):
+		return CurrencyHKD
+	case types.IsMCode(exCode):
+		return CurrencyUSD
+	}
+	return CurrencyCNY
+}
+
+// Kind 汇率类型
+type Kind string
+
+const (
+	KindParity   Kind = "parity"   // 人民币汇率中间价
+	KindOnshore  Kind = "onshore"  // 在岸人民币即期汇率 CNY
+	KindOffshore Kind = "offshore" // 离岸人民币即期汇率 CNH
+)
+
+// Kinds 全部汇率类型
+var Kinds = []Kind{KindParity, KindOnshore, KindOffshore}
+
+// ParseKind parses parity, onshore or offshore.
+func ParseKind(s string) (Kind, error) {
+	k := Kind(strings.ToLower(strings.TrimSpace(s)))
+	for _, v := range Kinds {
+		if k == v {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("invalid rate kind %q, want parity, onshore or offshore", s)
+}
+
+// Label returns the Chinese name of the kind.
+func (k Kind) Label() string {
+	switch k {
+	case KindParity:
+		return "中间价"
+	case KindOnshore:
+		return "在岸"
+	case KindOffshore:
+		return "离岸"
+	}
+	return string(k)
+}
+
+// Pair returns the currency pair of c quoted in this kind, e.g. USD/CNH.
+func (k Kind) Pair(c Currency) string {
+	if k == KindOffshore {
+		return string(c) + "/CNH"
+	}
+	return string(c) + "/CNY"
+}
+
+// Rate 单日汇率
+type Rate struct {
+	Date     time.Time
+	Currency Currency
+	Kind     Kind
+	Value    float64 // 1 单位外币兑人民币
+}
+
+// History returns the daily rates of currency c between start and end
+// (2006-01-02) in ascending order of date.
+func History(ctx context.Context, kind Kind, c Currency, start, end string) ([]Rate, error) {
+	switch kind {
+	case KindParity:
+		all, err := queryParity(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]Rate, 0, len(all))
+		for _, r := range all {
+			if r.Currency == c {
+				res = append(res, r)
+			}
+		}
+		return res, nil
+	case KindOnshore, KindOffshore:
+		return querySpot(ctx, kind, c, start, end)
+	}
+	return nil, fmt.Errorf("invalid rate kind %q", kind)
+}
+
+// querySpot fetches the daily closes of the onshore or offshore spot rate.
+func querySpot(ctx context.Context, kind Kind, c Currency, start, end string) ([]Rate, error) {
+	market, code := eastmoney.MarketTypeFxSpot, string(c)+"CNY"
+	if kind == KindOffshore {
+		market, code = eastmoney.MarketTypeForex, string(c)+"CNH"
+	}
+	quotes, err := eastmoney.GetQuoteHistory(ctx, &eastmoney.GetQuoteHistoryReq{
+		Code:       code,
+		MarketCode: int(market),
+		Begin:      strings.ReplaceAll(start, "-", ""),
+		End:        strings.ReplaceAll(end, "-", ""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query %s %s: %w", kind.Label(), kind.Pair(c), err)
+	}
+	res := make([]Rate, 0, len(quotes))
+	for _, q := range quotes {
+		res = append(res, Rate{Date: q.Date, Currency: c, Kind: kind, Value: q.Close})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Date.Before(res[j].Date) })
+	return res, nil
+}
+
+// Rates 同一类型下各外币的最新汇率，用于金额换算
+type Rates struct {
+	Kind   Kind
+	Values map[Currency]float64 // 1 单位外币兑人民币
+	Dates  map[Currency]time.Time
+}
+
+// latestDays 查询最新汇率时回看的天数，覆盖长假
+const latestDays = 15
+
+// Latest returns the latest rates of all currencies of the kind.
+func Latest(ctx context.Context, kind Kind) (*Rates, error) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -latestDays).Format(utils.LayoutYYMMDD)
+	endStr := end.Format(utils.LayoutYYMMDD)
+
+	var all []Rate
+	if kind == KindParity {
+		var err error
+		all, err = queryParity(ctx, start, endStr)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, c := range Currencies {
+			list, err := History(ctx, kind, c, start, endStr)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, list...)
+		}
+	}
+	return latestRates(kind, all)
+}
+
+// latestRates keeps the latest rate of each currency.
+func latestRates(kind Kind, all []Rate) (*Rates, error) {
+	res := &Rates{Kind: kind, Values: make(map[Currency]float64), Dates: make(map[Currency]time.Time)}
+	for _, r := range all {
+		if d, ok := res.Dates[r.Currency]; !ok || r.Date.After(d) {
+			res.Values[r.Currency], res.Dates[r.Currency] = r.Value, r.Date
+		}
+	}
+	if len(res.Values) == 0 {
+		return nil, fmt.Errorf("no %s rates", kind.Label())
+	}
+	return res, nil
+}
+
+// ErrNoRate 没有该币种的汇率
+var ErrNoRate = errors.New("no exchange rate")
+
+// ToCNY converts amount in currency c to CNY.
+func (r *Rates) ToCNY(amount float64, c Currency) (float64, error) {
+	if c == CurrencyCNY {
+		return amount, nil
+	}
+	v, ok := r.Values[c]
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoRate, c)
+	}
+	return amount * v, nil
+}
+
+// Convert converts amount from one currency to another through CNY.
+func (r *Rates) Convert(amount float64, from, to Currency) (float64, error) {
+	cny, err := r.ToCNY(amount, from)
+	if err != nil {
+		return 0, err
+	}
+	if to == CurrencyCNY {
+		return cny, nil
+	}
+	v, ok := r.Values[to]
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoRate, to)
+	}
+	return cny / v, nil
+}
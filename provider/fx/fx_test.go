@@ -0,0 +1,103 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alwqx/sec/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurrencyKind(t *testing.T) {
+	c, err := ParseCurrency(" hkd ")
+	require.NoError(t, err)
+	require.Equal(t, CurrencyHKD, c)
+	c, err = ParseCurrency("cny")
+	require.NoError(t, err)
+	require.Equal(t, CurrencyCNY, c)
+	_, err = ParseCurrency("JPY")
+	require.Error(t, err)
+
+	k, err := ParseKind("Offshore")
+	require.NoError(t, err)
+	require.Equal(t, KindOffshore, k)
+	require.Equal(t, "USD/CNH", k.Pair(CurrencyUSD))
+	require.Equal(t, "EUR/CNY", KindParity.Pair(CurrencyEUR))
+	_, err = ParseKind("spot")
+	require.Error(t, err)
+
+	require.Equal(t, CurrencyHKD, CurrencyOf("HK00700"))
+	require.Equal(t, CurrencyUSD, CurrencyOf("$AAPL"))
+	require.Equal(t, CurrencyCNY, CurrencyOf("SH600036"))
+}
+
+func TestQueryParity(t *testing.T) {
+	var windows []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		require.Equal(t, "USD/CNY,HKD/CNY,EUR/CNY", q.Get("currency"))
+		windows = append(windows, q.Get("startDate")+"~"+q.Get("endDate")+"#"+q.Get("pageNum"))
+		// 第一个年度窗口分两页返回
+		if q.Get("startDate") == "2024-03-01" && q.Get("pageNum") == "1" {
+			fmt.Fprint(w, `{"data":{"head":["USD/CNY","EUR/CNY","100JPY/CNY","HKD/CNY"],"pageTotal":2},
+				"records":[{"date":"2024-03-04","values":["7.1002","7.7000","4.7500","0.9079"]}]}`)
+			return
+		}
+		if q.Get("startDate") == "2024-03-01" {
+			fmt.Fprint(w, `{"data":{"head":["USD/CNY","EUR/CNY","100JPY/CNY","HKD/CNY"],"pageTotal":2},
+				"records":[{"date":"2024-03-01","values":["7.1000","","4.7400","0.9078"]}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"head":["USD/CNY","HKD/CNY"],"pageTotal":1},
+			"records":[{"date":"2025-03-03","values":["7.1700","0.9200"]}]}`)
+	}))
+	defer srv.Close()
+	old := chinaMoneyURL
+	chinaMoneyURL = srv.URL
+	defer func() { chinaMoneyURL = old }()
+
+	rates, err := queryParity(context.Background(), "2024-03-01", "2025-03-05")
+	require.NoError(t, err)
+	require.Equal(t, []string{"2024-03-01~2025-02-28#1", "2024-03-01~2025-02-28#2", "2025-03-01~2025-03-05#1"}, windows)
+
+	// 按日期升序，跳过日元和空值
+	require.Len(t, rates, 7)
+	require.Equal(t, "2024-03-01", rates[0].Date.Format(utils.LayoutYYMMDD))
+	require.Equal(t, Rate{Date: rates[6].Date, Currency: CurrencyHKD, Kind: KindParity, Value: 0.92}, rates[6])
+
+	latest, err := latestRates(KindParity, rates)
+	require.NoError(t, err)
+	require.Equal(t, 7.17, latest.Values[CurrencyUSD])
+	require.Equal(t, 7.7, latest.Values[CurrencyEUR])
+	require.Equal(t, "2024-03-04", latest.Dates[CurrencyEUR].Format(utils.LayoutYYMMDD))
+
+	_, err = latestRates(KindParity, nil)
+	require.Error(t, err)
+}
+
+func TestRatesConvert(t *testing.T) {
+	r := &Rates{Kind: KindOnshore, Values: map[Currency]float64{CurrencyUSD: 7.2, CurrencyHKD: 0.92}, Dates: map[Currency]time.Time{}}
+
+	v, err := r.ToCNY(100, CurrencyHKD)
+	require.NoError(t, err)
+	require.InDelta(t, 92, v, 1e-9)
+	v, err = r.ToCNY(100, CurrencyCNY)
+	require.NoError(t, err)
+	require.Equal(t, 100.0, v)
+
+	v, err = r.Convert(720, CurrencyCNY, CurrencyUSD)
+	require.NoError(t, err)
+	require.InDelta(t, 100, v, 1e-9)
+	v, err = r.Convert(100, CurrencyUSD, CurrencyHKD)
+	require.NoError(t, err)
+	require.InDelta(t, 720/0.92, v, 1e-9)
+
+	_, err = r.ToCNY(1, CurrencyEUR)
+	require.ErrorIs(t, err, ErrNoRate)
+	_, err = r.Convert(1, CurrencyUSD, CurrencyEUR)
+	require.ErrorIs(t, err, ErrNoRate)
+}
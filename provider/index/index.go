@@ -0,0 +1,144 @@
+// Package index provides the table of major market indexes and a searcher
+// that resolves index names and exchange-prefixed index codes before falling
+// back to the stock search.
+//
+// Index codes collide with stock codes: 000001 is both the SSE Composite
+// (SH000001) and Ping An Bank (SZ000001). A bare code therefore still
+// resolves to the stock; indexes are matched by their prefixed code, their
+// name or an alias such as "hs300".
+package index
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/types"
+)
+
+// Index 指数基本信息
+type Index struct {
+	Name    string
+	Code    string               // 指数代码 000001
+	ExCode  string               // 带交易所前缀的代码 SH000001
+	Market  eastmoney.MarketType // 东方财富 secid 中的市场
+	Major   bool                 // 是否在 sec index 总览中显示
+	Aliases []string             // 小写别名
+}
+
+var indexes = []*Index{
+	{Name: "上证指数", Code: "000001", ExCode: "SH000001", Market: eastmoney.MarketTypeSse, Major: true, Aliases: []string{"sse", "上证综指"}},
+	{Name: "深证成指", Code: "399001", ExCode: "SZ399001", Market: eastmoney.MarketTypeSzSe, Major: true, Aliases: []string{"szse"}},
+	{Name: "创业板指", Code: "399006", ExCode: "SZ399006", Market: eastmoney.MarketTypeSzSe, Major: true, Aliases: []string{"cyb", "chinext"}},
+	{Name: "科创50", Code: "000688", ExCode: "SH000688", Market: eastmoney.MarketTypeSse, Major: true, Aliases: []string{"kc50", "star50"}},
+	{Name: "沪深300", Code: "000300", ExCode: "SH000300", Market: eastmoney.MarketTypeSse, Major: true, Aliases: []string{"hs300", "csi300"}},
+	{Name: "上证50", Code: "000016", ExCode: "SH000016", Market: eastmoney.MarketTypeSse, Aliases: []string{"sse50"}},
+	{Name: "中证500", Code: "000905", ExCode: "SH000905", Market: eastmoney.MarketTypeSse, Aliases: []string{"zz500", "csi500"}},
+	{Name: "中证1000", Code: "000852", ExCode: "SH000852", Market: eastmoney.MarketTypeSse, Aliases: []string{"zz1000", "csi1000"}},
+	{Name: "深证100", Code: "399330", ExCode: "SZ399330", Market: eastmoney.MarketTypeSzSe, Aliases: []string{"sz100"}},
+	{Name: "中证A50", Code: "930050", ExCode: "CSI930050", Market: eastmoney.MarketTypeCSI, Aliases: []string{"a50", "csia50"}},
+}
+
+// All returns every known index.
+func All() []*Index {
+	return indexes
+}
+
+// Major returns the indexes shown in the overview.
+func Major() []*Index {
+	res := make([]*Index, 0, len(indexes))
+	for _, ix := range indexes {
+		if ix.Major {
+			res = append(res, ix)
+		}
+	}
+	return res
+}
+
+// Lookup finds an index by exchange-prefixed code, name or alias. Bare codes
+// are not matched because they may be stock codes.
+func Lookup(key string) (*Index, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		return nil, false
+	}
+	for _, ix := range indexes {
+		if key == strings.ToLower(ix.ExCode) || key == strings.ToLower(ix.Name) {
+			return ix, true
+		}
+		for _, a := range ix.Aliases {
+			if key == a {
+				return ix, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// LookupCode is like Lookup but also matches bare index codes. It is meant for
+// commands that only deal with indexes, where 000001 means the SSE Composite.
+func LookupCode(key string) (*Index, bool) {
+	if ix, ok := Lookup(key); ok {
+		return ix, true
+	}
+	key = strings.TrimSpace(key)
+	for _, ix := range indexes {
+		if key == ix.Code {
+			return ix, true
+		}
+	}
+	return nil, false
+}
+
+// Security returns ix as a search result.
+func (ix *Index) Security() *sina.BasicSecurity {
+	var exChange string
+	switch ix.Market {
+	case eastmoney.MarketTypeSse:
+		exChange = "sh"
+	case eastmoney.MarketTypeSzSe:
+		exChange = "sz"
+	case eastmoney.MarketTypeCSI:
+		exChange = types.ExChangeCSI
+	}
+	return &sina.BasicSecurity{
+		Name:         ix.Name,
+		SecurityType: types.SecurityTypeIndex,
+		Code:         ix.Code,
+		ExCode:       ix.ExCode,
+		ExChange:     exChange,
+	}
+}
+
+// HasQuote reports whether realtime quotes of ix are available from sina.
+func (ix *Index) HasQuote() bool {
+	return ix.Market != eastmoney.MarketTypeCSI
+}
+
+// searcher 与 provider.Searcher 相同，避免循环引用
+type searcher interface {
+	Search(ctx context.Context, key string) []*sina.BasicSecurity
+}
+
+// Searcher 先匹配指数，未命中时交给 Next 查询
+type Searcher struct {
+	Next searcher
+}
+
+// NewSearcher wraps next so that index names and prefixed codes resolve to
+// indexes.
+func NewSearcher(next searcher) *Searcher {
+	return &Searcher{Next: next}
+}
+
+// Search returns the index matching key, or the results of the wrapped searcher.
+func (s *Searcher) Search(ctx context.Context, key string) []*sina.BasicSecurity {
+	if ix, ok := Lookup(key); ok {
+		return []*sina.BasicSecurity{ix.Security()}
+	}
+	if s.Next == nil {
+		return nil
+	}
+	return s.Next.Search(ctx, key)
+}
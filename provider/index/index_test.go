@@ -0,0 +1,81 @@
+package index
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/alwqx/sec/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	for _, key := range []string{"SH000001", "sh000001", "上证指数", "sse"} {
+		ix, ok := Lookup(key)
+		require.True(t, ok, key)
+		require.Equal(t, "SH000001", ix.ExCode)
+	}
+
+	// 裸代码可能是股票代码
+	_, ok := Lookup("000001")
+	require.False(t, ok)
+	_, ok = Lookup("")
+	require.False(t, ok)
+
+	ix, ok := LookupCode("000001")
+	require.True(t, ok)
+	require.Equal(t, "上证指数", ix.Name)
+	ix, ok = LookupCode("HS300")
+	require.True(t, ok)
+	require.Equal(t, "000300", ix.Code)
+	_, ok = LookupCode("600036")
+	require.False(t, ok)
+}
+
+func TestMajor(t *testing.T) {
+	major := Major()
+	require.Len(t, major, 5)
+	for _, ix := range major {
+		require.True(t, ix.Major)
+		require.True(t, ix.HasQuote())
+	}
+}
+
+func TestSecurity(t *testing.T) {
+	ix, _ := Lookup("cyb")
+	sec := ix.Security()
+	require.Equal(t, "sz", sec.ExChange)
+	require.Equal(t, types.SecurityTypeIndex, sec.SecurityType)
+
+	ix, _ = Lookup("a50")
+	require.Equal(t, eastmoney.MarketTypeCSI, ix.Market)
+	require.Equal(t, types.ExChangeCSI, ix.Security().ExChange)
+	require.False(t, ix.HasQuote())
+}
+
+type fakeSearcher struct {
+	keys []string
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, key string) []*sina.BasicSecurity {
+	f.keys = append(f.keys, key)
+	return []*sina.BasicSecurity{{Code: "000001", ExCode: "SZ000001", Name: "平安银行", ExChange: "sz"}}
+}
+
+func TestSearcher(t *testing.T) {
+	next := new(fakeSearcher)
+	s := NewSearcher(next)
+
+	res := s.Search(context.Background(), "沪深300")
+	require.Len(t, res, 1)
+	require.Equal(t, "SH000300", res[0].ExCode)
+	require.Empty(t, next.keys)
+
+	res = s.Search(context.Background(), "000001")
+	require.Len(t, res, 1)
+	require.Equal(t, "SZ000001", res[0].ExCode)
+	require.Equal(t, []string{"000001"}, next.keys)
+
+	require.Nil(t, NewSearcher(nil).Search(context.Background(), "000001"))
+}
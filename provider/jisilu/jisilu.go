@@ -0,0 +1,98 @@
+// Package jisilu queries convertible bond data from 集思录 jisilu.cn, which
+// tracks the outstanding balance (剩余规模) of each bond after conversions and
+// redemptions. East Money only provides the original issue size.
+//
+// Without a login cookie jisilu returns only part of the list; set the cookie
+// of a logged-in browser session in SEC_JISILU_COOKIE to get all bonds.
+package jisilu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/utils"
+)
+
+// 可转债列表：集思录 cb_list_new，字段语义参考 AKShare bond_cb_jsl。
+
+var cbListURL = "https://www.jisilu.cn/data/cbnew/cb_list_new/"
+
+// EnvCookie 集思录登录 Cookie 的环境变量
+const EnvCookie = "SEC_JISILU_COOKIE"
+
+// cbListResp 可转债列表接口返回数据结构
+type cbListResp struct {
+	Rows []struct {
+		ID   string `json:"id"`
+		Cell struct {
+			BondID     string `json:"bond_id"`
+			BondName   string `json:"bond_nm"`
+			CurrIssAmt any    `json:"curr_iss_amt"` // 剩余规模，单位亿元，数字或字符串
+		} `json:"cell"`
+	} `json:"rows"`
+}
+
+// QueryConvertibleBalances returns the outstanding balance in yuan of the
+// listed convertible bonds, keyed by bond code. Bonds without a balance are
+// left out.
+func QueryConvertibleBalances(ctx context.Context) (map[string]float64, error) {
+	reqURL := fmt.Sprintf("%s?___jsl=LST___t=%d", cbListURL, time.Now().UnixMilli())
+	slog.DebugContext(ctx, "QueryConvertibleBalances", "reqURL", reqURL)
+
+	headers := http.Header{}
+	headers.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36")
+	headers.Set("Referer", "https://www.jisilu.cn/data/cbnew/")
+	headers.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie := strings.TrimSpace(os.Getenv(EnvCookie)); cookie != "" {
+		headers.Set("Cookie", cookie)
+	}
+	resp, err := utils.MakeRequest(ctx, http.MethodPost, reqURL, headers, strings.NewReader("fprice=&tprice=&curr_iss_amt=&listed=Y&qflag=N"), 0)
+	if err != nil {
+		return nil, fmt.Errorf("jisilu convertible list: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jisilu convertible list: unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var res cbListResp
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("parse jisilu convertible list: %w", err)
+	}
+
+	balances := make(map[string]float64, len(res.Rows))
+	for _, row := range res.Rows {
+		code := row.Cell.BondID
+		if code == "" {
+			code = row.ID
+		}
+		if v, ok := amount(row.Cell.CurrIssAmt); ok && code != "" {
+			balances[code] = v * 1e8
+		}
+	}
+	return balances, nil
+}
+
+// amount parses an amount returned either as a number or as a string, such
+// as 3.216 or "3.216". Missing values are "-" or empty.
+func amount(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, x > 0
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil && f > 0
+	}
+	return 0, false
+}
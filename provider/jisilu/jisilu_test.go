@@ -0,0 +1,33 @@
+package jisilu
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryConvertibleBalances(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "session=abc", r.Header.Get("Cookie"))
+		fmt.Fprint(w, `{"page":1,"rows":[
+			{"id":"113011","cell":{"bond_id":"113011","bond_nm":"光大转债","curr_iss_amt":"62.343"}},
+			{"id":"127001","cell":{"bond_id":"127001","bond_nm":"数字转债","curr_iss_amt":3.216}},
+			{"id":"128999","cell":{"bond_id":"128999","bond_nm":"缺失转债","curr_iss_amt":"-"}}],"total":3}`)
+	}))
+	defer srv.Close()
+	origURL := cbListURL
+	cbListURL = srv.URL
+	defer func() { cbListURL = origURL }()
+	t.Setenv(EnvCookie, "session=abc")
+
+	balances, err := QueryConvertibleBalances(context.Background())
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	require.InDelta(t, 6.2343e9, balances["113011"], 1)
+	require.InDelta(t, 3.216e8, balances["127001"], 1)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"sort"
 	"time"
 
@@ -14,7 +15,7 @@ import (
 )
 
 var (
-	sgeAu999DailyQuoteUrl = "https://www.sge.com.cn/graph/Dailyhq?instid=Au99.99"
+	sgeDailyQuoteUrl = "https://www.sge.com.cn/graph/Dailyhq"
 )
 
 // DailyHQItem 日行情
@@ -42,11 +43,12 @@ type innerDailyHQResp struct {
 	Time [][]interface{} `json:"time"`
 }
 
-// getAllDailyQuote 获取 https://www.sge.com.cn/sjzx/mrhq 中的全部日行情数据
-func getAllDailyQuote(ctx context.Context) (*dailyHQResp, error) {
-	resp, err := utils.MakeRequest(ctx, http.MethodGet, sgeAu999DailyQuoteUrl, nil, nil, 0)
+// getAllDailyQuote 获取 https://www.sge.com.cn/sjzx/mrhq 中合约 inst 的全部日行情数据
+func getAllDailyQuote(ctx context.Context, inst string) (*dailyHQResp, error) {
+	reqUrl := sgeDailyQuoteUrl + "?" + url.Values{"instid": []string{inst}}.Encode()
+	resp, err := utils.MakeRequest(ctx, http.MethodGet, reqUrl, nil, nil, 0)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed request", "url", sgeAu999DailyQuoteUrl)
+		slog.ErrorContext(ctx, "failed request", "url", reqUrl)
 		return nil, err
 	}
 
@@ -156,20 +158,38 @@ type QueryAu999Req struct {
 }
 
 // QueryAu999Resp 返回数据结构体
-type QueryAu999Resp struct {
-	Data []*DailyHQItem
-}
+type QueryAu999Resp = QueryDailyHQResp
 
-// QueryAu999 根据时间范围查询数据
+// QueryAu999 根据时间范围查询 Au99.99 数据
 func QueryAu999(ctx context.Context, req *QueryAu999Req) (*QueryAu999Resp, error) {
 	if req == nil {
 		return nil, errors.New("req is new")
 	}
+	return QueryDailyHQ(ctx, &QueryDailyHQReq{Inst: InstAu9999, Start: req.Start, End: req.End})
+}
+
+// QueryDailyHQReq 请求结构体，Inst 为上海黄金交易所合约代码，如 Au(T+D)
+type QueryDailyHQReq struct {
+	Inst       string
+	Start, End string
+}
+
+// QueryDailyHQResp 返回数据结构体
+type QueryDailyHQResp struct {
+	Data []*DailyHQItem
+}
+
+// QueryDailyHQ 根据合约和时间范围查询日行情数据
+func QueryDailyHQ(ctx context.Context, req *QueryDailyHQReq) (*QueryDailyHQResp, error) {
+	if req == nil {
+		return nil, errors.New("req is nil")
+	}
+	inst, err := ParseInstrument(req.Inst)
+	if err != nil {
+		return nil, err
+	}
 	// 校验时间
-	var (
-		start, end time.Time
-		err        error
-	)
+	var start, end time.Time
 	start, err = time.Parse(utils.LayoutYYMMDD, req.Start)
 	if err != nil {
 		return nil, err
@@ -179,7 +199,7 @@ func QueryAu999(ctx context.Context, req *QueryAu999Req) (*QueryAu999Resp, error
 		return nil, err
 	}
 
-	res, err := getAllDailyQuote(ctx)
+	res, err := getAllDailyQuote(ctx, inst.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -192,7 +212,7 @@ func QueryAu999(ctx context.Context, req *QueryAu999Req) (*QueryAu999Resp, error
 		data = append(data, item)
 	}
 
-	resp := &QueryAu999Resp{
+	resp := &QueryDailyHQResp{
 		Data: data,
 	}
 
@@ -115,7 +115,7 @@ func TestGetAllDailyQuote(t *testing.T) {
 		MatchParam("instid", "Au99.99").
 		Reply(200).BodyString(defaultAu999DailyHQBody)
 	ctx := context.TODO()
-	resp, err := getAllDailyQuote(ctx)
+	resp, err := getAllDailyQuote(ctx, InstAu9999)
 	require.Nil(t, err)
 	require.NotNil(t, resp)
 	require.EqualValues(t, 6, len(resp.Time))
@@ -152,3 +152,31 @@ func TestQueryAu999(t *testing.T) {
 	require.NotNil(t, resp)
 	require.EqualValues(t, 1, len(resp.Data))
 }
+
+func TestQueryDailyHQInst(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://www.sge.com.cn").Get("/graph/Dailyhq").
+		MatchParam("instid", `^Ag\(T\+D\)$`).
+		Reply(200).BodyString(defaultAu999DailyHQBody)
+
+	resp, err := QueryDailyHQ(context.TODO(), &QueryDailyHQReq{Inst: "ag(t+d)", Start: "2017-01-01", End: "2017-01-04"})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+
+	_, err = QueryDailyHQ(context.TODO(), &QueryDailyHQReq{Inst: "Cu99", Start: "2017-01-01", End: "2017-01-04"})
+	require.ErrorContains(t, err, "unknown SGE instrument")
+}
+
+func TestParseInstrument(t *testing.T) {
+	for s, want := range map[string]string{"Au99.99": InstAu9999, "au(t+d)": InstAuTD, " mAu(T+D) ": InstMAuTD, "Ag (T+D)": InstAgTD, "pt99.95": InstPt9995} {
+		inst, err := ParseInstrument(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, inst.ID, s)
+	}
+	inst, _ := ParseInstrument(InstAgTD)
+	require.False(t, inst.IsGold())
+	require.Equal(t, "元/千克", inst.Unit)
+
+	_, err := ParseInstrument("Au")
+	require.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package metal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 上海黄金交易所合约，代码与 https://www.sge.com.cn/sjzx/mrhq 中的合约代码一致
+
+const (
+	InstAu9999  = "Au99.99"
+	InstAu9995  = "Au99.95"
+	InstAu995   = "Au99.5"
+	InstAu100g  = "Au100g"
+	InstAu50g   = "Au50g"
+	InstIAu9999 = "iAu99.99"
+	InstAuTD    = "Au(T+D)"
+	InstMAuTD   = "mAu(T+D)"
+	InstAuTN1   = "Au(T+N1)"
+	InstAuTN2   = "Au(T+N2)"
+	InstPGC30g  = "PGC30g"
+	InstAgTD    = "Ag(T+D)"
+	InstAg9999  = "Ag99.99"
+	InstPt9995  = "Pt99.95"
+)
+
+// Instrument 上海黄金交易所合约
+type Instrument struct {
+	ID    string // 合约代码，如 Au(T+D)
+	Name  string // 中文名称
+	Metal string // 品种：Au 黄金、Ag 白银、Pt 铂金
+	Unit  string // 报价单位
+}
+
+// Instruments 支持查询的合约
+var Instruments = []Instrument{
+	{ID: InstAu9999, Name: "黄金99.99", Metal: "Au", Unit: "元/克"},
+	{ID: InstAu9995, Name: "黄金99.95", Metal: "Au", Unit: "元/克"},
+	{ID: InstAu995, Name: "黄金99.5", Metal: "Au", Unit: "元/克"},
+	{ID: InstAu100g, Name: "100克金条", Metal: "Au", Unit: "元/克"},
+	{ID: InstAu50g, Name: "50克金条", Metal: "Au", Unit: "元/克"},
+	{ID: InstIAu9999, Name: "国际板黄金99.99", Metal: "Au", Unit: "元/克"},
+	{ID: InstAuTD, Name: "黄金延期", Metal: "Au", Unit: "元/克"},
+	{ID: InstMAuTD, Name: "迷你黄金延期", Metal: "Au", Unit: "元/克"},
+	{ID: InstAuTN1, Name: "黄金延期N1", Metal: "Au", Unit: "元/克"},
+	{ID: InstAuTN2, Name: "黄金延期N2", Metal: "Au", Unit: "元/克"},
+	{ID: InstPGC30g, Name: "熊猫金币30克", Metal: "Au", Unit: "元/克"},
+	{ID: InstAgTD, Name: "白银延期", Metal: "Ag", Unit: "元/千克"},
+	{ID: InstAg9999, Name: "白银99.99", Metal: "Ag", Unit: "元/千克"},
+	{ID: InstPt9995, Name: "铂金99.95", Metal: "Pt", Unit: "元/克"},
+}
+
+// ParseInstrument looks up an instrument by code, ignoring case and spaces,
+// e.g. "au(t+d)" for Au(T+D).
+func ParseInstrument(s string) (Instrument, error) {
+	key := strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	for _, inst := range Instruments {
+		if strings.EqualFold(inst.ID, key) {
+			return inst, nil
+		}
+	}
+	ids := make([]string, len(Instruments))
+	for i, inst := range Instruments {
+		ids[i] = inst.ID
+	}
+	return Instrument{}, fmt.Errorf("unknown SGE instrument %q, supported: %s", s, strings.Join(ids, ", "))
+}
+
+// IsGold reports whether the instrument is gold quoted in CNY per gram.
+func (inst Instrument) IsGold() bool {
+	return inst.Metal == "Au"
+}
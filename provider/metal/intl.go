@@ -0,0 +1,110 @@
+package metal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/fx"
+	"github.com/alwqx/sec/utils"
+)
+
+// 国际金价折算：伦敦金现货（美元/盎司）× 美元兑人民币汇率 ÷ 每盎司克数，得到与上金所
+// 可比的人民币/克价格，上金所价格与折算价之差即为国内溢价。
+
+// GramsPerOunce 每金衡盎司克数
+const GramsPerOunce = 31.1034768
+
+// DailyPrice 日收盘价
+type DailyPrice struct {
+	Date  time.Time
+	Close float64
+}
+
+// QueryIntlGold 查询伦敦金现货日收盘价，美元/盎司，日期格式 2006-01-02
+func QueryIntlGold(ctx context.Context, start, end string) ([]DailyPrice, error) {
+	return queryEastMoneyDaily(ctx, eastmoney.MarketTypeMetal, "XAU", start, end)
+}
+
+// QueryUSDCNY 查询美元兑人民币汇率，kind 为中间价、在岸或离岸，日期格式 2006-01-02
+func QueryUSDCNY(ctx context.Context, kind fx.Kind, start, end string) ([]DailyPrice, error) {
+	rates, err := fx.History(ctx, kind, fx.CurrencyUSD, start, end)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]DailyPrice, 0, len(rates))
+	for _, r := range rates {
+		res = append(res, DailyPrice{Date: r.Date, Close: r.Value})
+	}
+	return res, nil
+}
+
+func queryEastMoneyDaily(ctx context.Context, market eastmoney.MarketType, code, start, end string) ([]DailyPrice, error) {
+	quotes, err := eastmoney.GetQuoteHistory(ctx, &eastmoney.GetQuoteHistoryReq{
+		Code:       code,
+		MarketCode: int(market),
+		Begin:      strings.ReplaceAll(start, "-", ""),
+		End:        strings.ReplaceAll(end, "-", ""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query %d.%s: %w", market, code, err)
+	}
+	res := make([]DailyPrice, 0, len(quotes))
+	for _, q := range quotes {
+		res = append(res, DailyPrice{Date: q.Date, Close: q.Close})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Date.Before(res[j].Date) })
+	return res, nil
+}
+
+// Conversion 上金所价格与国际金价折算价对比
+type Conversion struct {
+	Date        string
+	Price       float64 // 上金所收盘价，元/克
+	IntlPrice   float64 // 国际金价，美元/盎司
+	Rate        float64 // 美元兑人民币汇率
+	Parity      float64 // 国际金价折算，元/克
+	Premium     float64 // 国内溢价，元/克
+	PremiumRate float64 // 溢价率，Premium/Parity
+}
+
+// ConvertGold compares SGE gold prices in CNY per gram with international gold
+// in USD per ounce converted at rates. For each SGE day the latest
+// international price and rate on or before that day are used, since the
+// markets close at different times; days without both are skipped. intl and
+// rates must be in ascending order of date.
+func ConvertGold(items []*DailyHQItem, intl, rates []DailyPrice) []*Conversion {
+	res := make([]*Conversion, 0, len(items))
+	for _, item := range items {
+		p, ok1 := priceOn(intl, item.DateTime)
+		r, ok2 := priceOn(rates, item.DateTime)
+		if !ok1 || !ok2 {
+			continue
+		}
+		parity := p * r / GramsPerOunce
+		res = append(res, &Conversion{
+			Date:        item.Date,
+			Price:       item.Close,
+			IntlPrice:   p,
+			Rate:        r,
+			Parity:      parity,
+			Premium:     item.Close - parity,
+			PremiumRate: (item.Close - parity) / parity,
+		})
+	}
+	return res
+}
+
+// priceOn returns the latest close dated on or before the day of date.
+func priceOn(prices []DailyPrice, date time.Time) (float64, bool) {
+	day := date.Format(utils.LayoutYYMMDD)
+	for i := len(prices) - 1; i >= 0; i-- {
+		if prices[i].Date.Format(utils.LayoutYYMMDD) <= day {
+			return prices[i].Close, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,41 @@
+package metal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertGold(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	items := []*DailyHQItem{
+		{Date: "2026-05-04", DateTime: day("2026-05-04"), Close: 770},
+		{Date: "2026-05-06", DateTime: day("2026-05-06"), Close: 780},
+		{Date: "2026-05-07", DateTime: day("2026-05-07"), Close: 760},
+	}
+	intl := []DailyPrice{{Date: day("2026-05-05"), Close: 3300}, {Date: day("2026-05-07"), Close: 3400}}
+	rates := []DailyPrice{{Date: day("2026-05-01"), Close: 7.2}, {Date: day("2026-05-06"), Close: 7.0}}
+
+	res := ConvertGold(items, intl, rates)
+	// 05-04 之前没有国际金价，跳过
+	require.Len(t, res, 2)
+
+	// 05-06 使用 05-05 的国际金价和 05-06 的汇率
+	c := res[0]
+	require.Equal(t, "2026-05-06", c.Date)
+	require.Equal(t, 3300.0, c.IntlPrice)
+	require.Equal(t, 7.0, c.Rate)
+	require.InDelta(t, 3300*7.0/GramsPerOunce, c.Parity, 1e-9)
+	require.InDelta(t, 780-c.Parity, c.Premium, 1e-9)
+	require.InDelta(t, c.Premium/c.Parity, c.PremiumRate, 1e-9)
+
+	// 05-07 溢价为负
+	require.Equal(t, 3400.0, res[1].IntlPrice)
+	require.Less(t, res[1].Premium, 0.0)
+
+	require.Empty(t, ConvertGold(items, nil, rates))
+}
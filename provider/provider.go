@@ -0,0 +1,169 @@
+// Package provider defines the data source interfaces used by commands and a
+// registry to pick an implementation by name.
+//
+// The built-in sources are sina (search + realtime quote) and eastmoney
+// (quote history). The "default" source combines them; any capability a named
+// source does not implement falls back to the default one, so a source only
+// needs to implement what it actually provides.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+)
+
+const (
+	SourceDefault   = "default"   // sina 搜索/行情 + 东方财富历史行情
+	SourceSina      = "sina"      // 新浪
+	SourceEastMoney = "eastmoney" // 东方财富
+
+	// EnvSource 未指定 --source 时读取的环境变量
+	EnvSource = "SEC_SOURCE"
+)
+
+// Searcher 根据关键字查询证券
+type Searcher interface {
+	Search(ctx context.Context, key string) []*sina.BasicSecurity
+}
+
+// QuoteSource 查询多个证券实时行情
+type QuoteSource interface {
+	QueryQuoteList(ctx context.Context, exCodes []string) ([]*sina.SecurityQuote, error)
+}
+
+// StreamSource 通过长连接推送实时行情，订阅列表可随时调整
+type StreamSource interface {
+	QuoteStream(ctx context.Context, exCodes []string) *sina.Stream
+}
+
+// HistorySource 查询证券历史行情
+type HistorySource interface {
+	GetQuoteHistory(ctx context.Context, req *eastmoney.GetQuoteHistoryReq) ([]*eastmoney.Quote, error)
+}
+
+// Source is the set of capabilities resolved for a source name.
+type Source struct {
+	Name     string
+	Searcher Searcher
+	Quote    QuoteSource
+	Stream   StreamSource // 为 nil 时只能轮询 Quote
+	History  HistorySource
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]any)
+)
+
+func init() {
+	Register(SourceSina, sina.Provider{})
+	Register(SourceEastMoney, eastmoney.Provider{})
+}
+
+// Register adds a named source. p must implement at least one of Searcher,
+// QuoteSource, StreamSource or HistorySource. Registering an existing name replaces it.
+func Register(name string, p any) error {
+	if name == "" || name == SourceDefault {
+		return fmt.Errorf("invalid source name %q", name)
+	}
+	_, isSearcher := p.(Searcher)
+	_, isQuote := p.(QuoteSource)
+	_, isStream := p.(StreamSource)
+	_, isHistory := p.(HistorySource)
+	if !isSearcher && !isQuote && !isStream && !isHistory {
+		return fmt.Errorf("source %q implements no provider interface", name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	registry[name] = p
+	return nil
+}
+
+// Unregister removes a named source, mainly used by tests.
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(registry, name)
+}
+
+// Names returns all available source names in alphabetical order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry)+1)
+	names = append(names, SourceDefault)
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the source registered under name. An empty name falls back
+// to the SEC_SOURCE environment variable and then to the default source.
+func Resolve(name string) (*Source, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = strings.ToLower(strings.TrimSpace(os.Getenv(EnvSource)))
+	}
+	if name == "" {
+		name = SourceDefault
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	src := &Source{Name: name}
+	if sp, ok := registry[SourceSina]; ok {
+		src.Searcher, _ = sp.(Searcher)
+		src.Quote, _ = sp.(QuoteSource)
+		src.Stream, _ = sp.(StreamSource)
+	}
+	if ep, ok := registry[SourceEastMoney]; ok {
+		src.History, _ = ep.(HistorySource)
+	}
+	if name == SourceDefault {
+		return src, nil
+	}
+
+	p, ok := registry[name]
+	if !ok {
+		names := make([]string, 0, len(registry))
+		for n := range registry {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown source %q, available: %s, %s", name, SourceDefault, strings.Join(names, ", "))
+	}
+	if s, ok := p.(Searcher); ok {
+		src.Searcher = s
+	}
+	if q, ok := p.(QuoteSource); ok {
+		src.Quote = q
+		// 推送行情须与轮询行情来自同一数据源
+		src.Stream, _ = p.(StreamSource)
+	} else if st, ok := p.(StreamSource); ok {
+		src.Stream = st
+	}
+	if h, ok := p.(HistorySource); ok {
+		src.History = h
+	}
+	slog.Debug("provider.Resolve", "name", name)
+
+	return src, nil
+}
+
+// MultiSearch 并发查询多个关键字，每个关键字取第一条结果，结果顺序与 keys 一致
+// 最多支持 sina.MAX_KEY_NUM 条证券信息查询
+func MultiSearch(ctx context.Context, s Searcher, keys []string) []*sina.BasicSecurity {
+	return sina.SearchEach(ctx, s.Search, keys)
+}
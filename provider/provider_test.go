@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alwqx/sec/provider/eastmoney"
+	"github.com/alwqx/sec/provider/sina"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSearcher struct{}
+
+func (fakeSearcher) Search(ctx context.Context, key string) []*sina.BasicSecurity {
+	if key == "none" {
+		return nil
+	}
+	return []*sina.BasicSecurity{{Code: key, ExCode: "SH" + key, Name: "fake-" + key}}
+}
+
+type fakeQuote struct{}
+
+func (fakeQuote) QueryQuoteList(ctx context.Context, exCodes []string) ([]*sina.SecurityQuote, error) {
+	return nil, nil
+}
+
+func TestRegister(t *testing.T) {
+	// 1. invalid name or implementation
+	require.NotNil(t, Register("", fakeSearcher{}))
+	require.NotNil(t, Register(SourceDefault, fakeSearcher{}))
+	require.NotNil(t, Register("nothing", struct{}{}))
+
+	// 2. common
+	require.Nil(t, Register("fake", fakeSearcher{}))
+	t.Cleanup(func() { Unregister("fake") })
+	require.Contains(t, Names(), "fake")
+	require.Contains(t, Names(), SourceDefault)
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv(EnvSource, "")
+
+	// 1. default
+	src, err := Resolve("")
+	require.Nil(t, err)
+	require.Equal(t, SourceDefault, src.Name)
+	require.IsType(t, sina.Provider{}, src.Searcher)
+	require.IsType(t, sina.Provider{}, src.Quote)
+	require.IsType(t, sina.Provider{}, src.Stream)
+	require.IsType(t, eastmoney.Provider{}, src.History)
+
+	// 2. unknown
+	_, err = Resolve("unknown")
+	require.NotNil(t, err)
+
+	// 3. partial source falls back to default capabilities
+	require.Nil(t, Register("fake", fakeSearcher{}))
+	t.Cleanup(func() { Unregister("fake") })
+	src, err = Resolve(" Fake ")
+	require.Nil(t, err)
+	require.Equal(t, "fake", src.Name)
+	require.IsType(t, fakeSearcher{}, src.Searcher)
+	require.IsType(t, sina.Provider{}, src.Quote)
+	require.IsType(t, sina.Provider{}, src.Stream)
+	require.IsType(t, eastmoney.Provider{}, src.History)
+
+	// 3.1 a source with its own quotes but no stream must be polled
+	require.Nil(t, Register("fakequote", fakeQuote{}))
+	t.Cleanup(func() { Unregister("fakequote") })
+	src, err = Resolve("fakequote")
+	require.Nil(t, err)
+	require.IsType(t, fakeQuote{}, src.Quote)
+	require.Nil(t, src.Stream)
+
+	// 4. env
+	t.Setenv(EnvSource, "fake")
+	src, err = Resolve("")
+	require.Nil(t, err)
+	require.Equal(t, "fake", src.Name)
+}
+
+func TestMultiSearch(t *testing.T) {
+	ctx := context.TODO()
+	require.Nil(t, MultiSearch(ctx, fakeSearcher{}, nil))
+
+	res := MultiSearch(ctx, fakeSearcher{}, []string{"600036", "none", "688047"})
+	require.Equal(t, 2, len(res))
+	require.Equal(t, "600036", res[0].Code)
+	require.Equal(t, "688047", res[1].Code)
+
+	keys := make([]string, 0, sina.MAX_KEY_NUM+2)
+	for i := 0; i < sina.MAX_KEY_NUM+2; i++ {
+		keys = append(keys, "60003"+string(rune('0'+i)))
+	}
+	res = MultiSearch(ctx, fakeSearcher{}, keys)
+	require.Equal(t, sina.MAX_KEY_NUM, len(res))
+}
+
+func TestFromFlags(t *testing.T) {
+	t.Setenv(EnvSource, "")
+	root := &cobra.Command{Use: "sec"}
+	AddFlag(root)
+	sub := &cobra.Command{Use: "quote", RunE: func(cmd *cobra.Command, args []string) error {
+		src, err := FromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		cmd.Annotations = map[string]string{"source": src.Name}
+		return nil
+	}}
+	root.AddCommand(sub)
+
+	root.SetArgs([]string{"quote", "--source", "eastmoney"})
+	require.NoError(t, root.Execute())
+	require.Equal(t, "eastmoney", sub.Annotations["source"])
+
+	root.SetArgs([]string{"--source", "foo", "quote"})
+	require.ErrorContains(t, root.Execute(), `unknown source "foo"`)
+
+	// 没有全局 flag 的独立命令使用默认数据源
+	src, err := FromFlags(&cobra.Command{})
+	require.NoError(t, err)
+	require.Equal(t, SourceDefault, src.Name)
+}
@@ -57,38 +57,42 @@ func Search(ctx context.Context, key string) []*BasicSecurity {
 
 // MultiSearch 根据关键字查询多个证券信息
 // 最多支持 MAX_KEY_NUM 条证券信息查询
+//
+// Deprecated: use provider.MultiSearch, which searches with the selected source.
 func MultiSearch(ctx context.Context, keys []string) []*BasicSecurity {
-	num := len(keys)
-	if num == 0 {
+	return SearchEach(ctx, Search, keys)
+}
+
+// SearchEach 并发调用 search 查询多个关键字，每个关键字取第一条结果，结果顺序与 keys 一致
+// 最多支持 MAX_KEY_NUM 条证券信息查询
+func SearchEach(ctx context.Context, search func(context.Context, string) []*BasicSecurity, keys []string) []*BasicSecurity {
+	if len(keys) == 0 {
 		return nil
 	}
-	if num > MAX_KEY_NUM {
+	if len(keys) > MAX_KEY_NUM {
 		slog.DebugContext(ctx, "keys num exceed", "max num", MAX_KEY_NUM)
 		keys = keys[:MAX_KEY_NUM]
 	}
 
-	res := make([]*BasicSecurity, 0, num)
-	ch := make(chan *BasicSecurity, num)
-	for _, key := range keys {
-		go func(code string) {
-			secs := Search(ctx, code)
-			if len(secs) >= 1 {
-				slog.DebugContext(ctx, "MultiSearch results", "code", code, "secs", secs)
-				ch <- secs[0]
-			} else {
-				ch <- nil
+	found := make([]*BasicSecurity, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			if secs := search(ctx, key); len(secs) > 0 {
+				found[i] = secs[0]
 			}
-		}(key)
+		}(i, key)
 	}
+	wg.Wait()
 
-	for range num {
-		item := <-ch
-		if item == nil {
-			continue
+	res := make([]*BasicSecurity, 0, len(keys))
+	for _, sec := range found {
+		if sec != nil {
+			res = append(res, sec)
 		}
-		res = append(res, item)
 	}
-
 	return res
 }
 
@@ -306,6 +310,10 @@ func parseBasicSecurity(body string) []*BasicSecurity {
 			secType = types.SecurityTypeStock
 		case "21", "22", "23", "24", "25", "26":
 			secType = types.SecurityTypeFund
+			// 场内基金带交易所前缀，如 sh510300；场外基金为 of014826
+			if prefix := strings.ToLower(ss[3]); strings.HasPrefix(prefix, "sh") || strings.HasPrefix(prefix, "sz") {
+				exChange = prefix[:2]
+			}
 		case "31", "33":
 			secType = types.SecurityTypeStock
 			exChange = types.ExChangeHKex
@@ -557,3 +565,22 @@ func parseDividend(body []byte) ([]Dividend, error) {
 
 	return res, nil
 }
+
+// Provider 新浪数据源，实现 provider.Searcher、provider.QuoteSource 和
+// provider.StreamSource
+type Provider struct{}
+
+// Search 根据关键字查询证券信息
+func (Provider) Search(ctx context.Context, key string) []*BasicSecurity {
+	return Search(ctx, key)
+}
+
+// QueryQuoteList 查询多个证券行情
+func (Provider) QueryQuoteList(ctx context.Context, exCodes []string) ([]*SecurityQuote, error) {
+	return QueryQuoteList(ctx, exCodes)
+}
+
+// QuoteStream 订阅多个证券的实时行情推送
+func (Provider) QuoteStream(ctx context.Context, exCodes []string) *Stream {
+	return NewStream(ctx, exCodes...)
+}
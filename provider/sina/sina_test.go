@@ -28,6 +28,18 @@ func TestParseBasicSecuritys(t *testing.T) {
 	body2 := `var suggestvalue="汇泉兴至未来一年持有混合C,21,014826,of014826,汇泉兴至未来一年持有混合C,,汇泉兴至未来一年持有混合C,99,1,,,";`
 	res = parseBasicSecurity(body2)
 	fmt.Println(res)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, types.SecurityTypeFund, res[0].SecurityType)
+	require.Equal(t, "OF014826", res[0].ExCode)
+	require.Empty(t, res[0].ExChange)
+
+	// 场内基金
+	body3 := `var suggestvalue="沪深300ETF,22,510300,sh510300,沪深300ETF,,沪深300ETF,99,1,,,";`
+	res = parseBasicSecurity(body3)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, types.SecurityTypeFund, res[0].SecurityType)
+	require.Equal(t, "SH510300", res[0].ExCode)
+	require.Equal(t, "sh", res[0].ExChange)
 }
 
 func TestProfile(t *testing.T) {
@@ -124,6 +136,23 @@ func TestParseDividend(t *testing.T) {
 	require.EqualValues(t, 0, res[23].AddShares)
 }
 
+func TestSearchEach(t *testing.T) {
+	ctx := context.TODO()
+	require.Empty(t, SearchEach(ctx, nil, nil))
+
+	// 结果顺序与 keys 一致，无结果的关键字跳过
+	search := func(_ context.Context, key string) []*BasicSecurity {
+		if key == "none" {
+			return nil
+		}
+		return []*BasicSecurity{{Code: key}}
+	}
+	res := SearchEach(ctx, search, []string{"600036", "none", "000001"})
+	require.Len(t, res, 2)
+	require.Equal(t, "600036", res[0].Code)
+	require.Equal(t, "000001", res[1].Code)
+}
+
 func TestMultiSearch(t *testing.T) {
 	t.Skip("just test for dev/debug")
 	ctx := context.TODO()
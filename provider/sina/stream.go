@@ -0,0 +1,293 @@
+package sina
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	quoteWsURL = "wss://hq.sinajs.cn/wskt"
+
+	streamMinBackoff  = time.Second
+	streamMaxBackoff  = time.Minute
+	streamReadTimeout = time.Minute // 超过该时间没有收到消息则重连
+)
+
+// errResubscribe 订阅列表变化，需要用新列表重连
+var errResubscribe = errors.New("subscription changed")
+
+// Stream 基于 websocket 的实时行情订阅
+//
+// 新浪 wskt 接口的订阅列表在连接 URL 中指定，因此订阅或取消订阅时会用新的列表
+// 重新建立连接。连接断开后按指数退避自动重连。
+type Stream struct {
+	url         string
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	readTimeout time.Duration
+
+	out     chan *SecurityQuote
+	changed chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu    sync.Mutex
+	codes map[string]string // key: 新浪格式的代码，value: 订阅时的代码
+}
+
+// NewStream starts streaming realtime quotes of exCodes, e.g. SH600036,
+// HK00700 or $AMD. The stream runs until ctx is done or Close is called, and
+// the channel returned by Quotes is closed then.
+func NewStream(ctx context.Context, exCodes ...string) *Stream {
+	s := newStream(quoteWsURL, exCodes...)
+	s.start(ctx)
+	return s
+}
+
+func newStream(url string, exCodes ...string) *Stream {
+	s := &Stream{
+		url:         url,
+		minBackoff:  streamMinBackoff,
+		maxBackoff:  streamMaxBackoff,
+		readTimeout: streamReadTimeout,
+		out:         make(chan *SecurityQuote, 64),
+		changed:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+		codes:       make(map[string]string),
+	}
+	s.Subscribe(exCodes...)
+	return s
+}
+
+func (s *Stream) start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+}
+
+// Quotes returns the channel of quote updates.
+func (s *Stream) Quotes() <-chan *SecurityQuote {
+	return s.out
+}
+
+// Subscribe adds exCodes to the stream.
+func (s *Stream) Subscribe(exCodes ...string) {
+	s.mu.Lock()
+	n := len(s.codes)
+	for _, c := range exCodes {
+		if c == "" {
+			continue
+		}
+		s.codes[formatQuoteKeys([]string{c})[0]] = c
+	}
+	grown := len(s.codes) != n
+	s.mu.Unlock()
+	if grown {
+		s.notifyChanged()
+	}
+}
+
+// Unsubscribe removes exCodes from the stream.
+func (s *Stream) Unsubscribe(exCodes ...string) {
+	s.mu.Lock()
+	n := len(s.codes)
+	for _, c := range exCodes {
+		delete(s.codes, formatQuoteKeys([]string{c})[0])
+	}
+	shrunk := len(s.codes) != n
+	s.mu.Unlock()
+	if shrunk {
+		s.notifyChanged()
+	}
+}
+
+// Set replaces the subscribed codes with exCodes. The connection is only
+// re-established when the set actually changes.
+func (s *Stream) Set(exCodes ...string) {
+	codes := make(map[string]string, len(exCodes))
+	for _, c := range exCodes {
+		if c != "" {
+			codes[formatQuoteKeys([]string{c})[0]] = c
+		}
+	}
+
+	s.mu.Lock()
+	same := len(codes) == len(s.codes)
+	for k := range codes {
+		if _, ok := s.codes[k]; !ok {
+			same = false
+			break
+		}
+	}
+	s.codes = codes
+	s.mu.Unlock()
+	if !same {
+		s.notifyChanged()
+	}
+}
+
+// Codes returns the subscribed codes in sorted order.
+func (s *Stream) Codes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]string, 0, len(s.codes))
+	for _, c := range s.codes {
+		res = append(res, c)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Close stops the stream and waits for it to exit.
+func (s *Stream) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Stream) notifyChanged() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// keys returns the subscribed sina keys in sorted order.
+func (s *Stream) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]string, 0, len(s.codes))
+	for k := range s.codes {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// exCode maps a parsed quote back to the code it was subscribed with.
+func (s *Stream) exCode(q *SecurityQuote) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.codes[strings.ToLower(q.ExCode)]
+	return c, ok
+}
+
+func (s *Stream) run(ctx context.Context) {
+	defer close(s.done)
+	defer close(s.out)
+
+	backoff := s.minBackoff
+	for {
+		// 清除已体现在本次订阅列表中的变更通知
+		select {
+		case <-s.changed:
+		default:
+		}
+		keys := s.keys()
+		if len(keys) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.changed:
+				continue
+			}
+		}
+
+		received, err := s.session(ctx, keys)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errResubscribe) {
+			backoff = s.minBackoff
+			continue
+		}
+		if received {
+			backoff = s.minBackoff
+		}
+		slog.WarnContext(ctx, "quote stream disconnected", "error", err, "retry", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.changed:
+		case <-time.After(backoff):
+			backoff = min(backoff*2, s.maxBackoff)
+		}
+	}
+}
+
+// session dials once and forwards quotes until the connection fails, ctx is
+// done or the subscription changes. It reports whether any message arrived.
+func (s *Stream) session(ctx context.Context, keys []string) (bool, error) {
+	url := fmt.Sprintf("%s?list=%s", s.url, strings.Join(keys, ","))
+	headers := make(http.Header)
+	headers.Add("Origin", SinaReferer)
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, headers)
+	if err != nil {
+		return false, err
+	}
+	slog.DebugContext(ctx, "quote stream connected", "keys", keys)
+
+	var (
+		resub bool
+		mu    sync.Mutex
+		stop  = make(chan struct{})
+	)
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.changed:
+			mu.Lock()
+			resub = true
+			mu.Unlock()
+		case <-stop:
+		}
+		conn.Close()
+	}()
+
+	received := false
+	for {
+		conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			if resub {
+				return received, errResubscribe
+			}
+			return received, err
+		}
+		received = true
+
+		body := strings.TrimSpace(string(msg))
+		if body == "" {
+			continue
+		}
+		quotes, err := parseQuoteWsBody(body)
+		if err != nil {
+			slog.WarnContext(ctx, "invalid quote stream message", "error", err)
+			continue
+		}
+		for _, q := range quotes {
+			c, ok := s.exCode(q)
+			if !ok {
+				continue // 已取消订阅
+			}
+			q.ExCode = c
+			select {
+			case s.out <- q:
+			case <-ctx.Done():
+				return received, ctx.Err()
+			}
+		}
+	}
+}
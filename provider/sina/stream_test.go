@@ -0,0 +1,153 @@
+package sina
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+var wsLines = map[string]string{
+	"sh600036": "sh600036=招商银行,36.350,35.630,37.610,38.000,35.920,37.610,37.620,256101260,9443438268.000,690801,37.610,286600,37.600,17000,37.590,55400,37.580,12200,37.570,161925,37.620,90600,37.630,50400,37.640,104100,37.650,126000,37.660,2024-09-30,15:00:00,00,",
+	"sh688047": "sh688047=龙芯中科,106.000,99.680,119.620,119.620,104.500,119.620,0.000,8256723,938310086.000,25600,119.620,7255,119.610,3033,119.600,1767,119.570,6300,119.550,0,0.000,0,0.000,0,0.000,0,0.000,0,0.000,2024-09-30,15:00:01,00,",
+}
+
+// wsServer 模拟新浪 wskt 接口，按 list 参数推送行情，dropAfter > 0 时发送
+// dropAfter 条消息后断开连接
+type wsServer struct {
+	*httptest.Server
+	dropAfter int
+
+	mu    sync.Mutex
+	lists []string
+}
+
+func newWsServer(t *testing.T, dropAfter int) *wsServer {
+	s := &wsServer{dropAfter: dropAfter}
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list := r.URL.Query().Get("list")
+		s.mu.Lock()
+		s.lists = append(s.lists, list)
+		s.mu.Unlock()
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		for _, k := range strings.Split(list, ",") {
+			if l, ok := wsLines[k]; ok {
+				lines = append(lines, l)
+			}
+		}
+		lines = append(lines, "sys_nxkey="+strings.ToUpper(list))
+		msg := []byte(strings.Join(lines, "\n"))
+		for i := 0; s.dropAfter <= 0 || i < s.dropAfter; i++ {
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *wsServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *wsServer) connections() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lists...)
+}
+
+func recvQuote(t *testing.T, st *Stream, exCode string) *SecurityQuote {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case q, ok := <-st.Quotes():
+			require.True(t, ok, "stream closed")
+			if q.ExCode == exCode {
+				return q
+			}
+		case <-timeout:
+			t.Fatalf("no quote of %s", exCode)
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	srv := newWsServer(t, 0)
+	st := newStream(srv.url(), "SH600036")
+	st.start(context.Background())
+
+	q := recvQuote(t, st, "SH600036")
+	require.Equal(t, "招商银行", q.Name)
+	require.Equal(t, 37.61, q.Current)
+
+	st.Subscribe("SH688047")
+	require.Equal(t, []string{"SH600036", "SH688047"}, st.Codes())
+	recvQuote(t, st, "SH688047")
+	conns := srv.connections()
+	require.Equal(t, "sh600036,sh688047", conns[len(conns)-1])
+
+	st.Unsubscribe("SH600036")
+	require.Equal(t, []string{"SH688047"}, st.Codes())
+	require.Eventually(t, func() bool {
+		conns := srv.connections()
+		return conns[len(conns)-1] == "sh688047"
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// 订阅列表不变时不重连
+	n := len(srv.connections())
+	st.Set("SH688047")
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, srv.connections(), n)
+	st.Set("SH600036")
+	recvQuote(t, st, "SH600036")
+	require.Equal(t, []string{"SH600036"}, st.Codes())
+
+	st.Close()
+	for range st.Quotes() {
+	}
+}
+
+func TestStreamReconnect(t *testing.T) {
+	srv := newWsServer(t, 1)
+	st := newStream(srv.url(), "SH600036")
+	st.minBackoff = 10 * time.Millisecond
+	st.start(context.Background())
+	defer st.Close()
+
+	recvQuote(t, st, "SH600036")
+	recvQuote(t, st, "SH600036")
+	recvQuote(t, st, "SH600036")
+	require.GreaterOrEqual(t, len(srv.connections()), 2)
+}
+
+func TestStreamContextDone(t *testing.T) {
+	srv := newWsServer(t, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	st := newStream(srv.url(), "SH600036")
+	st.start(ctx)
+	recvQuote(t, st, "SH600036")
+
+	cancel()
+	select {
+	case <-st.done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("stream did not stop")
+	}
+}
@@ -0,0 +1,99 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Bar represents a single value of a bar chart.
+type Bar struct {
+	Date  time.Time
+	Value float64
+}
+
+// BarConfig holds configuration for bar chart rendering.
+type BarConfig struct {
+	Width  int    // chart width in columns, 0 = auto-detect terminal width
+	Height int    // chart height in rows, default 10
+	Title  string // title printed above the chart
+}
+
+// RenderBars renders a bar chart of bars growing from zero, positive values in
+// red and negative values in green. When there are more bars than columns only
+// the most recent ones are drawn.
+func RenderBars(w io.Writer, bars []Bar, cfg BarConfig) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	height := cfg.Height
+	if height <= 0 {
+		height = 10
+	}
+	termWidth := cfg.Width
+	if termWidth <= 0 {
+		termWidth = getTerminalWidth()
+	}
+
+	minV, maxV := 0.0, 0.0
+	for _, b := range bars {
+		minV = min(minV, b.Value)
+		maxV = max(maxV, b.Value)
+	}
+	if minV == maxV {
+		maxV = 1
+	}
+
+	yaWidth := max(yAxisLabelWidth(maxV), yAxisLabelWidth(minV))
+	leftMargin := 1
+	if minWidth := leftMargin + yaWidth + 10; termWidth < minWidth {
+		termWidth = minWidth
+	}
+	chartAreaWidth := termWidth - leftMargin - yaWidth
+
+	if len(bars) > chartAreaWidth {
+		bars = bars[len(bars)-chartAreaWidth:]
+	}
+	barWidth := max(chartAreaWidth/len(bars), 1)
+	// 留出柱间空隙
+	fill := barWidth
+	if fill > 2 {
+		fill = barWidth - 1
+	}
+
+	if cfg.Title != "" {
+		fmt.Fprintf(w, "%s%s%s\n", ansiWhite, cfg.Title, ansiReset)
+	}
+
+	grid := makeGrid(height+1, termWidth)
+	drawYAxis(grid, height, termWidth-yaWidth, yaWidth, minV, maxV)
+
+	zeroRow := priceToRow(0, minV, maxV, height)
+	for i, b := range bars {
+		if b.Value == 0 {
+			continue
+		}
+		color := ansiRed
+		if b.Value < 0 {
+			color = ansiGreen
+		}
+		row := priceToRow(b.Value, minV, maxV, height)
+		top, bot := min(row, zeroRow), max(row, zeroRow)
+		start := leftMargin + i*barWidth + (barWidth-fill)/2
+		for r := top; r <= bot; r++ {
+			for c := start; c < start+fill && c < termWidth-yaWidth; c++ {
+				grid[r][c] = cell{r: '█', fg: color}
+			}
+		}
+	}
+
+	candles := make([]Candle, len(bars))
+	for i, b := range bars {
+		candles[i].Date = b.Date
+	}
+	drawDateLabels(grid, height, candles, leftMargin, barWidth)
+
+	renderGrid(w, grid)
+	return nil
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestBars(values ...float64) []Bar {
+	base := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	bars := make([]Bar, len(values))
+	for i, v := range values {
+		bars[i] = Bar{Date: base.AddDate(0, 0, i), Value: v}
+	}
+	return bars
+}
+
+func TestRenderBarsEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderBars(&buf, nil, BarConfig{}))
+	require.Empty(t, buf.String())
+}
+
+func TestRenderBars(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderBars(&buf, makeTestBars(100, -50, 30, 0), BarConfig{Width: 60, Height: 6, Title: "净上涨"})
+	require.NoError(t, err)
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	// 标题 + 6 行图表 + 日期行
+	require.Len(t, lines, 8)
+	require.Contains(t, lines[0], "净上涨")
+	require.Contains(t, out, ansiRed+"█")
+	require.Contains(t, out, ansiGreen+"█")
+	require.Contains(t, out, "100.00")
+	require.Contains(t, out, "-50.00")
+	require.Contains(t, lines[7], "01/05")
+}
+
+func TestRenderBarsOverflow(t *testing.T) {
+	values := make([]float64, 200)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, RenderBars(&buf, makeTestBars(values...), BarConfig{Width: 50, Height: 5}))
+	for _, line := range strings.Split(buf.String(), "\n") {
+		require.LessOrEqual(t, len([]rune(stripANSI(line))), 50)
+	}
+	// 只保留最近的柱，Y 轴上限为最后一根
+	require.Contains(t, buf.String(), "200.00")
+}
+
+func TestRenderBarsAllZero(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderBars(&buf, makeTestBars(0, 0), BarConfig{Width: 40, Height: 4}))
+	require.NotContains(t, buf.String(), "█")
+}
+
+var ansiRE = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func stripANSI(s string) string {
+	return ansiRE.ReplaceAllString(s, "")
+}
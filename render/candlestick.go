@@ -19,6 +19,7 @@ type Candle struct {
 	High   float64
 	Low    float64
 	Volume int64
+	Flow   float64 // main net inflow, drawn by the flow subgraph
 }
 
 // OverlayLine defines an indicator line to overlay on the candlestick chart.
@@ -37,6 +38,7 @@ type CandlestickConfig struct {
 	Paging    bool          // fixed candle width instead of scaling to fit
 	HalfBlock bool          // use half-block characters for 2x vertical resolution
 	Overlays  []OverlayLine // indicator lines to overlay on the chart
+	Flow      bool          // show main net inflow subgraph below volume
 }
 
 // DefaultConfig returns a sensible default configuration.
@@ -139,6 +141,10 @@ func Render(w io.Writer, candles []Candle, cfg CandlestickConfig) error {
 	if cfg.Volume {
 		volHeight = 4
 	}
+	flowHeight := 0
+	if cfg.Flow {
+		flowHeight = 4
+	}
 
 	termWidth := cfg.Width
 	if termWidth <= 0 {
@@ -194,6 +200,9 @@ func Render(w io.Writer, candles []Candle, cfg CandlestickConfig) error {
 	if volHeight > 0 {
 		gridRows += 1 + volHeight // separator + volume bars
 	}
+	if flowHeight > 0 {
+		gridRows += 1 + flowHeight // separator + flow bars
+	}
 	grid := makeGrid(gridRows, gridWidth)
 
 	// Draw Y-axis (tick every logical row, label every N ticks)
@@ -219,6 +228,9 @@ func Render(w io.Writer, candles []Candle, cfg CandlestickConfig) error {
 		if volHeight > 0 {
 			legendRow += 1 + volHeight // after separator + volume
 		}
+		if flowHeight > 0 {
+			legendRow += 1 + flowHeight
+		}
 		// Extend grid if needed
 		for len(grid) <= legendRow {
 			grid = append(grid, make([]cell, gridWidth))
@@ -240,6 +252,16 @@ func Render(w io.Writer, candles []Candle, cfg CandlestickConfig) error {
 		drawVolume(grid, volStartRow, volHeight, displayCandles, leftMargin, candleWidth, maxVol)
 	}
 
+	// Draw flow subgraph
+	if flowHeight > 0 {
+		sepRow := logicalHeight + 1
+		if volHeight > 0 {
+			sepRow += 1 + volHeight
+		}
+		drawSeparator(grid, sepRow, leftMargin, numCandles*candleWidth)
+		drawFlow(grid, sepRow+1, flowHeight, displayCandles, leftMargin, candleWidth)
+	}
+
 	renderGrid(w, grid)
 	return nil
 }
@@ -285,6 +307,7 @@ func mergeCandleGroup(group []Candle) Candle {
 			c.Low = group[i].Low
 		}
 		c.Volume += group[i].Volume
+		c.Flow += group[i].Flow
 	}
 	return c
 }
@@ -450,7 +473,8 @@ func drawCandle(grid [][]cell, chartHeight, col int, c Candle, minLow, maxHigh f
 
 // drawDateLabels draws date labels below the price chart.
 // Uses adaptive formatting: "MM/DD" at month boundaries and first label,
-// "DD" within a month to reduce crowding.
+// "DD" within a month to reduce crowding. Intraday bars are labelled "MM/DD"
+// at day boundaries and "HH:MM" within a day.
 func drawDateLabels(grid [][]cell, labelRow int, candles []Candle, leftMargin, candleWidth int) {
 	if labelRow >= len(grid) {
 		return
@@ -475,17 +499,25 @@ func drawDateLabels(grid [][]cell, labelRow int, candles []Candle, leftMargin, c
 		step = 1
 	}
 
-	lastMonth := -1
+	intraday := isIntraday(candles)
+	lastMonth, lastDay := -1, -1
 	for i := 0; i < n; i += step {
 		col := leftMargin + i*candleWidth + candleWidth/2
 
-		month := int(candles[i].Date.Month())
+		d := candles[i].Date
+		month := int(d.Month())
 		var label string
-		if month != lastMonth {
-			label = candles[i].Date.Format("01/02")
+		switch {
+		case intraday && d.YearDay() != lastDay:
+			label = d.Format("01/02")
+			lastDay = d.YearDay()
+		case intraday:
+			label = d.Format("15:04")
+		case month != lastMonth:
+			label = d.Format("01/02")
 			lastMonth = month
-		} else {
-			label = candles[i].Date.Format("02")
+		default:
+			label = d.Format("02")
 		}
 
 		startCol := col - len(label)/2
@@ -498,6 +530,16 @@ func drawDateLabels(grid [][]cell, labelRow int, candles []Candle, leftMargin, c
 	}
 }
 
+// isIntraday reports whether candles carry a time of day, i.e. are minute bars.
+func isIntraday(candles []Candle) bool {
+	for _, c := range candles {
+		if c.Date.Hour() != 0 || c.Date.Minute() != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // drawSeparator draws a horizontal dotted line between chart and volume.
 func drawSeparator(grid [][]cell, row, leftMargin, chartWidth int) {
 	if row >= len(grid) {
@@ -532,6 +574,36 @@ func drawVolume(grid [][]cell, startRow, volHeight int, candles []Candle, leftMa
 	}
 }
 
+// drawFlow draws net inflow bars growing from the middle of the subgraph,
+// inflows upward in red and outflows downward in green. Bars are scaled to the
+// largest absolute flow among the displayed (possibly merged) candles.
+func drawFlow(grid [][]cell, startRow, flowHeight int, candles []Candle, leftMargin, candleWidth int) {
+	maxFlow := 0.0
+	for _, c := range candles {
+		maxFlow = max(maxFlow, math.Abs(c.Flow))
+	}
+	if maxFlow == 0 {
+		return
+	}
+	half := flowHeight / 2
+	for i, c := range candles {
+		if c.Flow == 0 {
+			continue
+		}
+		col := leftMargin + i*candleWidth + candleWidth/2
+		fill := max(int(math.Abs(c.Flow)/maxFlow*float64(half)+0.5), 1)
+		for j := 0; j < fill; j++ {
+			row, fg := startRow+half-1-j, ansiRed
+			if c.Flow < 0 {
+				row, fg = startRow+half+j, ansiGreen
+			}
+			if row < len(grid) && col < len(grid[row]) {
+				grid[row][col] = cell{r: '█', fg: fg}
+			}
+		}
+	}
+}
+
 // drawOverlays plots indicator lines (MA, Bollinger, etc.) as colored marker
 // characters on the candlestick chart grid.
 func drawOverlays(grid [][]cell, chartHeight int, overlays []OverlayLine,
@@ -564,7 +636,7 @@ func drawLegend(grid [][]cell, legendRow int, overlays []OverlayLine, leftMargin
 		if style == 0 {
 			style = '●'
 		}
-		label := fmt.Sprintf("%c %s  ", style, ol.Label)
+		label := []rune(fmt.Sprintf("%c %s  ", style, ol.Label))
 		for i, ch := range label {
 			c := col + i
 			if c < len(grid[legendRow]) {
@@ -83,6 +83,28 @@ func TestRenderWithoutVolume(t *testing.T) {
 	require.True(t, len(lines) <= 22, "expected ~21 lines without volume, got %d", len(lines))
 }
 
+func TestRenderFlow(t *testing.T) {
+	candles := makeTestCandles(10)
+	for i := range candles {
+		candles[i].Flow = float64(i-5) * 1e7
+	}
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.Volume = false
+	cfg.Flow = true
+	cfg.Width = 120
+	err := Render(&buf, candles, cfg)
+	require.Nil(t, err)
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	// Height (20) + date labels (1) + separator (1) + flow (4) = 26
+	require.Len(t, lines, 26)
+	flow := strings.Join(lines[22:], "\n")
+	require.Contains(t, flow, ansiRed+"█")
+	require.Contains(t, flow, ansiGreen+"█")
+}
+
 func TestRenderHalfBlock(t *testing.T) {
 	candles := makeTestCandles(10)
 	var buf bytes.Buffer
@@ -138,6 +160,28 @@ func TestAdaptiveDateFormat(t *testing.T) {
 	require.Contains(t, out, "03")
 }
 
+func TestIntradayDateFormat(t *testing.T) {
+	// Minute bars: "MM/DD" at day boundaries, "HH:MM" within a day
+	candles := []Candle{
+		{Date: time.Date(2026, 1, 28, 14, 30, 0, 0, time.UTC), Open: 40, Close: 41, High: 42, Low: 39, Volume: 1000},
+		{Date: time.Date(2026, 1, 28, 15, 0, 0, 0, time.UTC), Open: 41, Close: 40, High: 42, Low: 39, Volume: 2000},
+		{Date: time.Date(2026, 1, 29, 10, 0, 0, 0, time.UTC), Open: 40, Close: 41, High: 42, Low: 39, Volume: 3000},
+		{Date: time.Date(2026, 1, 29, 10, 30, 0, 0, time.UTC), Open: 41, Close: 42, High: 43, Low: 40, Volume: 4000},
+	}
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.Width = 120
+	require.Nil(t, Render(&buf, candles, cfg))
+
+	out := buf.String()
+	require.Contains(t, out, "01/28")
+	require.Contains(t, out, "15:00")
+	require.Contains(t, out, "01/29")
+	require.Contains(t, out, "10:30")
+	require.True(t, isIntraday(candles))
+	require.False(t, isIntraday(makeTestCandles(3)))
+}
+
 func TestRenderAllSamePrice(t *testing.T) {
 	candles := []Candle{
 		{
@@ -254,8 +298,8 @@ func TestFgToBG(t *testing.T) {
 func TestMergeCandleGroup(t *testing.T) {
 	group := []Candle{
 		{Date: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), Open: 40, Close: 41, High: 42, Low: 39, Volume: 1000},
-		{Date: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), Open: 41, Close: 40, High: 43, Low: 38, Volume: 2000},
-		{Date: time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), Open: 40, Close: 42, High: 44, Low: 37, Volume: 3000},
+		{Date: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), Open: 41, Close: 40, High: 43, Low: 38, Volume: 2000, Flow: -5e6},
+		{Date: time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), Open: 40, Close: 42, High: 44, Low: 37, Volume: 3000, Flow: 2e6},
 	}
 	c := mergeCandleGroup(group)
 	require.Equal(t, time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), c.Date)
@@ -264,6 +308,7 @@ func TestMergeCandleGroup(t *testing.T) {
 	require.Equal(t, 44.0, c.High)          // max high
 	require.Equal(t, 37.0, c.Low)           // min low
 	require.Equal(t, int64(6000), c.Volume) // sum
+	require.Equal(t, -3e6, c.Flow)          // sum
 }
 
 func TestMergeCandleGroupSingle(t *testing.T) {
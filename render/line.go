@@ -0,0 +1,126 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// LineSeries is one line of a line chart.
+type LineSeries struct {
+	Values []float64 // one per x label, NaN = no value at this position
+	Color  string    // ANSI foreground color
+	Label  string    // legend label
+	Style  rune      // marker char at each value, default '●'
+}
+
+// LineConfig holds configuration for line chart rendering.
+type LineConfig struct {
+	Width   int      // chart width in columns, 0 = auto-detect terminal width
+	Height  int      // chart height in rows, default 15
+	Title   string   // title printed above the chart
+	XLabels []string // labels of the evenly spaced x positions
+}
+
+// RenderLines renders series as lines over the evenly spaced x positions of
+// cfg.XLabels. Values are marked with the series style and consecutive values
+// are joined with dots; earlier series are drawn on top of later ones. A
+// legend is printed below the x labels.
+func RenderLines(w io.Writer, series []LineSeries, cfg LineConfig) error {
+	n := len(cfg.XLabels)
+	if n == 0 || len(series) == 0 {
+		return nil
+	}
+
+	height := cfg.Height
+	if height <= 0 {
+		height = 15
+	}
+	termWidth := cfg.Width
+	if termWidth <= 0 {
+		termWidth = getTerminalWidth()
+	}
+
+	minV, maxV := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, v := range s.Values {
+			if !math.IsNaN(v) {
+				minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+			}
+		}
+	}
+	if math.IsInf(minV, 1) {
+		return nil
+	}
+	if minV == maxV {
+		minV, maxV = minV-0.5, maxV+0.5
+	}
+
+	yaWidth := max(yAxisLabelWidth(maxV), yAxisLabelWidth(minV))
+	leftMargin := 1
+	if minWidth := leftMargin + yaWidth + 2*n; termWidth < minWidth {
+		termWidth = minWidth
+	}
+	chartAreaWidth := termWidth - leftMargin - yaWidth
+	step := max(chartAreaWidth/n, 1)
+	column := func(i int) int { return leftMargin + i*step + step/2 }
+
+	if cfg.Title != "" {
+		fmt.Fprintf(w, "%s%s%s\n", ansiWhite, cfg.Title, ansiReset)
+	}
+
+	// 图表 + 横轴标签 + 图例
+	grid := makeGrid(height+2, termWidth)
+	drawYAxis(grid, height, termWidth-yaWidth, yaWidth, minV, maxV)
+
+	for k := len(series) - 1; k >= 0; k-- {
+		s := series[k]
+		style := s.Style
+		if style == 0 {
+			style = '●'
+		}
+		prev := -1
+		for i := 0; i < n && i < len(s.Values); i++ {
+			v := s.Values[i]
+			if math.IsNaN(v) {
+				continue
+			}
+			if prev >= 0 {
+				drawSegment(grid, height, column(prev), s.Values[prev], column(i), v, minV, maxV, s.Color)
+			}
+			row := priceToRow(v, minV, maxV, height)
+			grid[row][column(i)] = cell{r: style, fg: s.Color}
+			prev = i
+		}
+	}
+
+	for i, label := range cfg.XLabels {
+		start := column(i) - len([]rune(label))/2
+		for j, ch := range []rune(label) {
+			if c := start + j; c >= 0 && c < termWidth-yaWidth {
+				grid[height][c] = cell{r: ch, fg: ansiDim}
+			}
+		}
+	}
+
+	overlays := make([]OverlayLine, len(series))
+	for i, s := range series {
+		overlays[i] = OverlayLine{Color: s.Color, Label: s.Label, Style: s.Style}
+	}
+	drawLegend(grid, height+1, overlays, leftMargin)
+
+	renderGrid(w, grid)
+	return nil
+}
+
+// drawSegment joins two points with dots, one per column between them,
+// leaving cells already drawn untouched.
+func drawSegment(grid [][]cell, chartHeight, col1 int, v1 float64, col2 int, v2 float64, minV, maxV float64, color string) {
+	for c := col1 + 1; c < col2; c++ {
+		v := v1 + (v2-v1)*float64(c-col1)/float64(col2-col1)
+		row := priceToRow(v, minV, maxV, chartHeight)
+		if grid[row][c].r == ' ' {
+			grid[row][c] = cell{r: '·', fg: color}
+		}
+	}
+}
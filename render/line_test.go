@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderLinesEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderLines(&buf, nil, LineConfig{XLabels: []string{"3M"}}))
+	require.NoError(t, RenderLines(&buf, []LineSeries{{Values: []float64{1}}}, LineConfig{}))
+	nan := math.NaN()
+	require.NoError(t, RenderLines(&buf, []LineSeries{{Values: []float64{nan}}}, LineConfig{XLabels: []string{"3M"}}))
+	require.Empty(t, buf.String())
+}
+
+func TestRenderLines(t *testing.T) {
+	series := []LineSeries{
+		{Values: []float64{1.5, 1.8, 2.0, math.NaN(), 2.5}, Color: ansiYellow, Label: "今日"},
+		{Values: []float64{1.2, 1.4, 1.9, 2.1, 2.3}, Color: ansiCyan, Label: "1月前", Style: '○'},
+	}
+	cfg := LineConfig{Width: 60, Height: 8, Title: "收益率曲线", XLabels: []string{"3M", "1Y", "2Y", "5Y", "10Y"}}
+	var buf bytes.Buffer
+	require.NoError(t, RenderLines(&buf, series, cfg))
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	// 标题 + 8 行图表 + 横轴标签 + 图例
+	require.Len(t, lines, 11)
+	require.Contains(t, lines[0], "收益率曲线")
+	require.Contains(t, out, "2.50")
+	require.Contains(t, out, "1.20")
+	for _, label := range cfg.XLabels {
+		require.Contains(t, stripANSI(lines[9]), label)
+	}
+	legend := stripANSI(lines[10])
+	require.Contains(t, legend, "● 今日")
+	require.Contains(t, legend, "○ 1月前")
+
+	// 最大值在首行，最小值在末行
+	require.Contains(t, stripANSI(lines[1]), "●")
+	require.Contains(t, stripANSI(lines[8]), "○")
+	require.Contains(t, out, ansiYellow)
+	require.Contains(t, out, ansiCyan)
+	require.Contains(t, out, "·")
+	for _, line := range lines {
+		require.LessOrEqual(t, len([]rune(stripANSI(line))), 60)
+	}
+}
+
+func TestRenderLinesFlat(t *testing.T) {
+	var buf bytes.Buffer
+	series := []LineSeries{{Values: []float64{2, 2}, Color: ansiYellow, Label: "今日"}}
+	require.NoError(t, RenderLines(&buf, series, LineConfig{Width: 40, Height: 5, XLabels: []string{"1Y", "10Y"}}))
+	require.Contains(t, buf.String(), "●")
+}
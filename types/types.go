@@ -9,6 +9,7 @@ type SecurityType string
 const (
 	SecurityTypeFund  SecurityType = "fund"  // 基金
 	SecurityTypeStock SecurityType = "stock" // 股票
+	SecurityTypeIndex SecurityType = "index" // 指数
 
 	// 交易所
 	ExChangeSse    = "sse"    // 上交所
@@ -17,6 +18,7 @@ const (
 	ExChangeHKex   = "hk"     // 香港交所
 	ExChangeNyse   = "ny"     // 纽约交所
 	ExChangeNasdaq = "nasdaq" // 纳斯达克
+	ExChangeCSI    = "csi"    // 中证指数公司，只用于指数
 )
 
 type InfoOptions struct {
@@ -45,3 +47,20 @@ func IsMCode(exCode string) (res bool) {
 	res = strings.HasPrefix(lowCode, "$")
 	return
 }
+
+// IsExchangeFund 判断证券代码是否是沪深场内基金（ETF、LOF、封闭式基金）
+func IsExchangeFund(exCode string) bool {
+	lowCode := strings.ToLower(exCode)
+	if len(lowCode) != 8 {
+		return false
+	}
+	prefix, code := lowCode[:2], lowCode[2:]
+	switch prefix {
+	case "sh":
+		return strings.HasPrefix(code, "50") || strings.HasPrefix(code, "51") || strings.HasPrefix(code, "52") ||
+			strings.HasPrefix(code, "56") || strings.HasPrefix(code, "58")
+	case "sz":
+		return strings.HasPrefix(code, "15") || strings.HasPrefix(code, "16") || strings.HasPrefix(code, "18")
+	}
+	return false
+}
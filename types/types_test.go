@@ -93,3 +93,26 @@ func TestIsHCode(t *testing.T) {
 		})
 	}
 }
+
+func TestIsExchangeFund(t *testing.T) {
+	testCode := []struct {
+		Name   string
+		ExCode string
+		Res    bool
+	}{
+		{Name: "1 empty excode", ExCode: ""},
+		{Name: "2 sh etf", ExCode: "SH510300", Res: true},
+		{Name: "2.1 sh sci-tech etf", ExCode: "sh588000", Res: true},
+		{Name: "2.2 sz etf", ExCode: "SZ159915", Res: true},
+		{Name: "2.3 sz lof", ExCode: "SZ161725", Res: true},
+		{Name: "3 sh stock", ExCode: "SH600036"},
+		{Name: "3.1 sz stock", ExCode: "SZ002475"},
+		{Name: "4 otc fund", ExCode: "OF014826"},
+	}
+
+	for _, tc := range testCode {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Res, IsExchangeFund(tc.ExCode))
+		})
+	}
+}
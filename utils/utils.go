@@ -8,11 +8,13 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"time"
 
 	"github.com/alwqx/sec/version"
+	"github.com/olekukonko/tablewriter"
 )
 
 const (
@@ -168,3 +170,65 @@ func HumanNum(cap float64) (res string) {
 	}
 	return
 }
+
+// SignedNum 带正负号的 HumanNum，用于净流入、增减等可正可负的金额
+func SignedNum(v float64) string {
+	switch {
+	case v > 0:
+		return "+" + HumanNum(v)
+	case v < 0:
+		return "-" + HumanNum(-v)
+	}
+	return "0"
+}
+
+// ChangeColor 涨跌颜色：涨为红色，跌为绿色，不变为默认颜色。
+// attrs 为涨跌时附加的样式，如 tablewriter.Bold
+func ChangeColor(v float64, attrs ...int) tablewriter.Colors {
+	var c tablewriter.Colors
+	switch {
+	case v > 0:
+		c = tablewriter.Colors{tablewriter.FgRedColor}
+	case v < 0:
+		c = tablewriter.Colors{tablewriter.FgGreenColor}
+	default:
+		return tablewriter.Colors{}
+	}
+	return append(c, attrs...)
+}
+
+// NewTable 创建左对齐、无边框、表头加粗的表格，列之间以制表符分隔
+func NewTable(out io.Writer, headers []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(headers)
+	hs := make([]tablewriter.Colors, len(headers))
+	for i := range headers {
+		hs[i] = tablewriter.Colors{tablewriter.Bold}
+	}
+	table.SetHeaderColor(hs...)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetAutoWrapText(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetNoWhiteSpace(true)
+	table.SetTablePadding("\t")
+	return table
+}
+
+// ClearTerm 终端清屏
+func ClearTerm() {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "cls")
+	default:
+		cmd = exec.Command("clear")
+	}
+
+	cmd.Stdout = os.Stdout
+	err := cmd.Run()
+	if err != nil {
+		slog.Error("ClearTerm", "cmd error", err)
+	}
+}
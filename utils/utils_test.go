@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/alwqx/sec/version"
+	"github.com/olekukonko/tablewriter"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/h2non/gock.v1"
 )
@@ -163,3 +165,23 @@ func TestHumanNum(t *testing.T) {
 	require.EqualValues(t, "10.09万", HumanNum(100900))
 	require.EqualValues(t, "1000.09亿", HumanNum(100009000009))
 }
+
+func TestSignedNum(t *testing.T) {
+	require.Equal(t, "+1.00万", SignedNum(10000))
+	require.Equal(t, "-2.50亿", SignedNum(-250000000))
+	require.Equal(t, "0", SignedNum(0))
+}
+
+func TestChangeColor(t *testing.T) {
+	require.Equal(t, tablewriter.Colors{tablewriter.FgRedColor}, ChangeColor(0.5))
+	require.Equal(t, tablewriter.Colors{tablewriter.FgGreenColor, tablewriter.Bold}, ChangeColor(-0.5, tablewriter.Bold))
+	require.Equal(t, tablewriter.Colors{}, ChangeColor(0, tablewriter.Bold))
+}
+
+func TestNewTable(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewTable(&buf, []string{"代码", "名称"})
+	table.Append([]string{"600036", "招商银行"})
+	table.Render()
+	require.Contains(t, buf.String(), "600036\t招商银行")
+}